@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/config"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/logger"
+)
+
+func main() {
+	genKey := flag.Bool("genkey", false, "generate a new agent key pair and exit")
+	once := flag.Bool("once", false, "run a single probe round and exit")
+	flag.Parse()
+
+	if *genKey {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			log.Fatalf("Failed to generate key: %v", err)
+		}
+		fmt.Printf("PROBE_AGENT_KEY=%s\n", hex.EncodeToString(priv.Seed()))
+		fmt.Printf("public key: %s\n", hex.EncodeToString(pub))
+		return
+	}
+
+	// Load configuration
+	cfg, err := config.LoadAgent()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize logger
+	appLogger := logger.New(cfg.Logger.Level, cfg.Logger.Format)
+
+	key, err := services.ParseProbePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid agent key")
+	}
+
+	// Initialize checkers, the same ones the tracker runs locally
+	nodeChecker := services.NewNodeChecker(
+		cfg.Monitor.ConnectionTimeout,
+		cfg.Monitor.MaxRetryAttempts,
+		appLogger,
+	)
+	grpcChecker := services.NewGRPCChecker(
+		cfg.Monitor.ConnectionTimeout,
+		cfg.Monitor.MaxRetryAttempts,
+		appLogger,
+	)
+	jsonrpcChecker := services.NewJSONRPCMonitorService(nil, nil, nil, nil, nil, nil, nil, nil, cfg.Monitor.MaxRetryAttempts, appLogger)
+
+	client := services.NewHTTPProbeClient(cfg.TrackerURL, cfg.AgentID, key, cfg.Monitor.ConnectionTimeout)
+	agent := services.NewProbeAgent(
+		cfg.AgentID,
+		cfg.Region,
+		client,
+		nodeChecker,
+		grpcChecker,
+		jsonrpcChecker,
+		appLogger,
+	)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	appLogger.WithFields(logrus.Fields{
+		"agent_id": cfg.AgentID,
+		"region":   cfg.Region,
+		"tracker":  cfg.TrackerURL,
+	}).Info("Starting probe agent")
+
+	if *once {
+		if _, err := agent.RunOnce(ctx); err != nil {
+			appLogger.WithError(err).Error("Probe round failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	agent.Run(ctx, cfg.Interval)
+
+	appLogger.Info("Probe agent exited")
+}
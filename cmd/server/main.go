@@ -14,12 +14,17 @@ import (
 
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/config"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/database"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/handlers"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/mail"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/middleware"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/rpc"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/scheduler"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/logger"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/metrics"
 )
 
 func main() {
@@ -48,6 +53,23 @@ func main() {
 	peerRepo := repositories.NewPeerRepository(db.DB)
 	jsonrpcRepo := repositories.NewJSONRPCServerRepository(db.DB)
 	snapshotRepo := repositories.NewSnapshotRepository(db.DB)
+	mapRepo := repositories.NewMapRepository(db.DB)
+	jsonrpcStatusRepo := repositories.NewJSONRPCStatusRepository(db.DB)
+	probeRepo := repositories.NewProbeRepository(db.DB)
+	certRepo := repositories.NewCertificateRepository(db.DB)
+	latencyRepo := repositories.NewLatencyRepository(db.DB)
+	operatorRepo := repositories.NewOperatorRepository(db.DB)
+	auditRepo := repositories.NewAuditRepository(db.DB)
+	maintenanceRepo := repositories.NewMaintenanceRepository(db.DB)
+	versionRepo := repositories.NewVersionRepository(db.DB)
+	topologyRepo := repositories.NewTopologyRepository(db.DB)
+	churnRepo := repositories.NewChurnRepository(db.DB)
+	addressRepo := repositories.NewAddressRepository(db.DB)
+	validatorRepo := repositories.NewValidatorRepository(db.DB)
+	chainRepo := repositories.NewChainRepository(db.DB)
+	networkRepo := repositories.NewNetworkRepository(db.DB)
+	syncRunRepo := repositories.NewSyncRunRepository(db.DB)
+	exportRepo := repositories.NewExportRepository(db.DB)
 
 	// Initialize services
 	nodeChecker := services.NewNodeChecker(
@@ -56,18 +78,55 @@ func main() {
 		appLogger,
 	)
 
-	bootstrapService := services.NewBootstrapService(appLogger, "./internal/database/bootstrap.json")
+	// Initialize the sources bootstrap nodes and gRPC servers are synced from
+	bootstrapSource, err := services.NewNodeSource(cfg.Sources.Bootstrap, models.NodeTypeBootstrap, cfg.Monitor.ConnectionTimeout, appLogger)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid bootstrap node source")
+	}
+	grpcSource, err := services.NewNodeSource(cfg.Sources.GRPC, models.NodeTypeGRPC, cfg.Monitor.ConnectionTimeout, appLogger)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid gRPC server source")
+	}
+
+	// Initialize the event bus that monitors and services publish to
+	eventBus := events.NewBus(appLogger)
+	defer eventBus.Close()
+
+	// Initialize the Prometheus metrics shared by the services
+	appMetrics := metrics.NewMetrics()
+
+	// Initialize per-attempt latency tracking
+	latencyService := services.NewLatencyService(latencyRepo, appLogger)
+
+	// Initialize advertised address and per-family endpoint tracking
+	addressService := services.NewAddressService(addressRepo, appLogger)
+
+	// Initialize the registry of tracked networks
+	networkService := services.NewNetworkService(networkRepo, appLogger)
 
 	bootstrapMonitor := services.NewBootstrapMonitor(
 		bootstrapRepo,
 		statusRepo,
 		nodeChecker,
 		appLogger,
-		bootstrapService,
+		bootstrapSource,
+		syncRunRepo,
+		latencyService,
+		addressService,
+		maintenanceRepo,
+		networkService,
+		eventBus,
 	)
 
+	// Initialize TLS certificate tracking
+	certService := services.NewCertificateService(certRepo, appMetrics, eventBus, appLogger)
+
+	// Initialize node software version tracking, the peer crawl and churn
+	versionService := services.NewVersionService(versionRepo, bootstrapRepo, appLogger)
+	topologyService := services.NewTopologyService(topologyRepo, peerRepo, bootstrapRepo, networkService, eventBus, appLogger)
+	churnService := services.NewChurnService(churnRepo, appLogger)
+
 	// Initialize gRPC services
-	grpcServerService := services.NewGRPCServerService(appLogger, "./internal/database/servers.json")
 	grpcChecker := services.NewGRPCChecker(
 		cfg.Monitor.ConnectionTimeout,
 		cfg.Monitor.MaxRetryAttempts,
@@ -78,9 +137,25 @@ func main() {
 		grpcStatusRepo,
 		grpcChecker,
 		appLogger,
-		grpcServerService,
+		grpcSource,
+		syncRunRepo,
+		certService,
+		latencyService,
+		versionService,
+		topologyService,
+		churnService,
+		addressService,
+		maintenanceRepo,
+		networkService,
+		eventBus,
 	)
 
+	// Initialize validator and committee monitoring against the trusted gRPC server
+	validatorService := services.NewValidatorService(validatorRepo, grpcChecker, cfg.Validator.GRPCAddress, cfg.Validator.Network, appLogger)
+	if !validatorService.Enabled() {
+		appLogger.Warn("No VALIDATOR_GRPC_ADDRESS configured, validator monitoring is disabled")
+	}
+
 	// Initialize Phase 2 Services
 	geoService := services.NewGeoLocationService(appLogger)
 
@@ -90,21 +165,169 @@ func main() {
 		jsonrpcRepo,
 		bootstrapRepo,
 		snapshotRepo,
+		mapRepo,
+		geoService,
+		churnService,
+		networkService,
+		eventBus,
+		appLogger,
+	)
+
+	// Scoring, geo enrichment, metrics and alerts react to bus events
+	eventHandlers := services.NewEventHandlers(bootstrapRepo, grpcRepo, jsonrpcRepo, peerRepo, geoService, appMetrics, appLogger)
+	eventHandlers.Register(eventBus)
+	eventHandlers.Start()
+	defer eventHandlers.Stop()
+
+	// Initialize probe agent ingestion for multi-region checks
+	probeAgents, err := services.ParseProbeAgents(cfg.Probe.Agents)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid probe agent configuration")
+	}
+	probeService := services.NewProbeService(
+		probeAgents,
+		probeRepo,
+		bootstrapRepo,
+		statusRepo,
+		grpcRepo,
+		grpcStatusRepo,
+		jsonrpcRepo,
+		jsonrpcStatusRepo,
+		maintenanceRepo,
+		cfg.Probe.MinRegions,
+		cfg.Probe.MaxClockSkew,
+		appLogger,
+	)
+
+	// Initialize leader election so only one replica runs scheduled jobs
+	leaderElector := services.NewLeaderElector(
+		db.DB,
+		cfg.Leader.Enabled,
+		cfg.Leader.LockID,
+		cfg.Leader.InstanceID,
+		cfg.Leader.RenewInterval,
+		appLogger,
+	)
+	leaderElector.Start()
+	defer leaderElector.Stop()
+
+	// Initialize HTTP handlers
+	healthHandler := handlers.NewHealthHandler(db.DB, leaderElector, appLogger, "1.0.0")
+
+	// Initialize JSON-RPC services and the method registry
+	jsonrpcMonitor := services.NewJSONRPCMonitorService(
+		jsonrpcRepo,
+		jsonrpcStatusRepo,
 		geoService,
+		certService,
+		latencyService,
+		versionService,
+		maintenanceRepo,
+		eventBus,
+		cfg.Monitor.MaxRetryAttempts,
 		appLogger,
 	)
 
+	// Initialize chain monitoring against the trusted gRPC and JSON-RPC servers
+	chainMonitor := services.NewChainMonitor(
+		chainRepo,
+		grpcChecker,
+		jsonrpcMonitor,
+		cfg.Chain.GRPCAddresses,
+		cfg.Chain.JSONRPCAddresses,
+		cfg.Chain.Network,
+		cfg.Chain.StallThreshold,
+		eventBus,
+		appLogger,
+	)
+	defer chainMonitor.Close()
+	if !chainMonitor.Enabled() {
+		appLogger.Warn("No CHAIN_GRPC_ADDRESSES or CHAIN_JSONRPC_ADDRESSES configured, chain monitoring is disabled")
+	}
+
 	// Initialize scheduler
-	cronScheduler := scheduler.NewCronScheduler(bootstrapMonitor, grpcMonitor, appLogger)
+	retentionService := services.NewRetentionService(probeRepo, latencyRepo, cfg.Retention.ProbeResults, cfg.Retention.CheckAttempts, appLogger)
+	cronScheduler := scheduler.NewCronScheduler(bootstrapMonitor, grpcMonitor, validatorService, chainMonitor, retentionService, leaderElector, appLogger)
 	cronScheduler.Start()
 	defer cronScheduler.Stop()
 
-	// Initialize HTTP handlers
-	healthHandler := handlers.NewHealthHandler(db.DB, appLogger, "1.0.0")
+	cronSchedulerPhase2 := scheduler.NewCronSchedulerPhase2(jsonrpcMonitor, networkStatsService, topologyService, geoService, leaderElector, appLogger)
+	cronSchedulerPhase2.Start()
+	defer cronSchedulerPhase2.Stop()
 
-	// Initialize JSON-RPC service and handler
-	jsonRPCService := services.NewJsonRPCService(grpcMonitor, bootstrapMonitor, registrationRepo, networkStatsService, appLogger)
-	jsonRPCHandler := handlers.NewJsonRPCHandler(jsonRPCService, appLogger)
+	ownershipVerifier := services.NewOwnershipVerifier(grpcChecker, cfg.Monitor.ConnectionTimeout, appLogger)
+
+	// Registrant emails are optional; without a transport registrations
+	// skip email confirmation
+	mailer, err := newMailer(cfg.Mail)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid mail configuration")
+	}
+	if mailer != nil {
+		services.NewRegistrationNotifier(registrationRepo, mailer, appLogger).Register(eventBus)
+	} else {
+		appLogger.Warn("No mail transport configured, registrations skip email confirmation")
+	}
+	registrationService := services.NewRegistrationService(
+		registrationRepo,
+		grpcRepo,
+		jsonrpcRepo,
+		grpcChecker,
+		jsonrpcMonitor,
+		ownershipVerifier,
+		cfg.Registration.AutoApproveVerified,
+		mailer,
+		cfg.Registration.PublicBaseURL,
+		eventBus,
+		appLogger,
+	)
+	maintenanceService := services.NewMaintenanceService(maintenanceRepo, bootstrapRepo, grpcRepo, jsonrpcRepo, appLogger)
+	operatorService := services.NewOperatorService(
+		operatorRepo,
+		auditRepo,
+		maintenanceService,
+		grpcRepo,
+		jsonrpcRepo,
+		registrationService,
+		ownershipVerifier,
+		mailer,
+		cfg.Registration.PublicBaseURL,
+		eventBus,
+		appLogger,
+	)
+	jsonRPCService := services.NewJsonRPCService(grpcMonitor, bootstrapMonitor, registrationRepo, syncRunRepo, networkStatsService, certService, latencyService, versionService, appLogger)
+	jsonRPCServicePhase2 := services.NewJsonRPCServicePhase2(jsonRPCService, jsonrpcMonitor, networkStatsService, registrationService, maintenanceService, topologyService, churnService, addressService, validatorService, chainMonitor, networkService, appLogger)
+
+	rpcRegistry := rpc.NewRegistry("Pactus Nodes Tracker", "1.0.0", appLogger)
+	jsonRPCServicePhase2.RegisterMethods(rpcRegistry)
+	jsonRPCHandler := handlers.NewJsonRPCHandler(rpcRegistry, appLogger)
+
+	// Registration review, maintenance and network methods are served on
+	// their own registry behind the admin API tokens
+	adminRegistry := rpc.NewRegistry("Pactus Nodes Tracker Admin", "1.0.0", appLogger)
+	jsonRPCServicePhase2.RegisterAdminMethods(adminRegistry)
+	adminJSONRPCHandler := handlers.NewJsonRPCHandler(adminRegistry, appLogger)
+
+	// Initialize REST resources
+	nodeQueryService := services.NewNodeQueryService(
+		bootstrapMonitor,
+		grpcMonitor,
+		jsonrpcMonitor,
+		bootstrapRepo,
+		statusRepo,
+		grpcRepo,
+		grpcStatusRepo,
+		jsonrpcRepo,
+		jsonrpcStatusRepo,
+		appLogger,
+	)
+	restHandler := handlers.NewRESTHandler(nodeQueryService, networkStatsService, appLogger)
+	exportService := services.NewExportService(exportRepo, appLogger)
+	exportHandler := handlers.NewExportHandler(exportService, appLogger)
+	probeHandler := handlers.NewProbeHandler(probeService, appLogger)
+	eventsHandler := handlers.NewEventsHandler(eventBus, appLogger)
+	registrationHandler := handlers.NewRegistrationHandler(registrationService, appLogger)
+	operatorHandler := handlers.NewOperatorHandler(operatorService, appLogger)
 	// Setup Gin router
 	if cfg.Logger.Level != "debug" {
 		gin.SetMode(gin.ReleaseMode)
@@ -129,9 +352,9 @@ func main() {
 	// 5. CORS
 	corsConfig := middleware.CORSConfig{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000", "https://tracker.kyvra.xyz"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
-		ExposeHeaders:    []string{"X-Request-ID"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "If-None-Match", "If-Modified-Since"},
+		ExposeHeaders:    []string{"X-Request-ID", "ETag", "Last-Modified"},
 		AllowCredentials: true,
 		MaxAge:           3600,
 	}
@@ -141,6 +364,17 @@ func main() {
 	rateLimiter := middleware.NewRateLimiter(100, time.Minute, appLogger)
 	router.Use(rateLimiter.Middleware())
 
+	// Event stream - long-lived, so registered before the request timeout
+	router.GET("/api/v1/events", eventsHandler.Stream)
+
+	// Data exports stream their rows, so they are not bound by the request
+	// timeout either
+	router.GET("/api/v1/export/nodes/:type", exportHandler.ExportNodes)
+	router.GET("/api/v1/export/status/:type", exportHandler.ExportStatuses)
+	router.GET("/api/v1/export/snapshots", exportHandler.ExportSnapshots)
+	router.GET("/api/v1/export/peers", exportHandler.ExportPeers)
+	router.GET("/api/v1/export/map", exportHandler.ExportMap)
+
 	// 7. Request Timeout - 60 seconds max
 	router.Use(middleware.Timeout(60*time.Second, appLogger))
 
@@ -150,6 +384,52 @@ func main() {
 	{
 
 		api.POST("/json-rpc", jsonRPCHandler.HandleRequest)
+		api.GET("/openrpc.json", jsonRPCHandler.OpenRPC)
+
+		// REST resources (read-only, cacheable)
+		api.GET("/nodes/:type", restHandler.ListNodes)
+		api.GET("/nodes/:type/:id", restHandler.GetNode)
+		api.GET("/nodes/:type/:id/status", restHandler.GetNodeStatus)
+		api.GET("/stats", restHandler.GetStats)
+		api.GET("/map", restHandler.GetMap)
+		api.GET("/snapshots", restHandler.GetSnapshots)
+
+		// Emailed registration links
+		api.GET("/registrations/confirm", registrationHandler.Confirm)
+
+		// Operator portal (magic link or node key sessions)
+		operator := api.Group("/operator")
+		{
+			operator.POST("/login/email", operatorHandler.RequestLoginLink)
+			operator.GET("/login/email", operatorHandler.LoginWithLink)
+			operator.POST("/login/challenge", operatorHandler.Challenge)
+			operator.POST("/login/key", operatorHandler.LoginWithKey)
+			operator.DELETE("/session", operatorHandler.Logout)
+			operator.GET("/nodes", operatorHandler.ListNodes)
+			operator.GET("/nodes/:type/:id", operatorHandler.GetNode)
+			operator.PATCH("/nodes/:type/:id", operatorHandler.UpdateNode)
+			operator.DELETE("/nodes/:type/:id", operatorHandler.Deregister)
+			operator.POST("/nodes/:type/:id/pause", operatorHandler.PauseMonitoring)
+			operator.DELETE("/nodes/:type/:id/pause", operatorHandler.ResumeMonitoring)
+			operator.GET("/nodes/:type/:id/maintenance", operatorHandler.ListMaintenance)
+			operator.DELETE("/nodes/:type/:id/maintenance/:windowId", operatorHandler.CancelMaintenance)
+			operator.GET("/nodes/:type/:id/audit", operatorHandler.GetAuditLog)
+		}
+
+		// Admin JSON-RPC methods (bearer admin tokens)
+		if len(cfg.Admin.Tokens) > 0 {
+			admin := api.Group("/admin", middleware.AdminAuth(cfg.Admin.Tokens, appLogger))
+			{
+				admin.POST("/json-rpc", adminJSONRPCHandler.HandleRequest)
+				admin.GET("/openrpc.json", adminJSONRPCHandler.OpenRPC)
+			}
+		} else {
+			appLogger.Warn("No ADMIN_API_TOKENS configured, the admin API is disabled")
+		}
+
+		// Probe agent API (signed requests)
+		api.GET("/probes/targets", probeHandler.GetTargets)
+		api.POST("/probes/results", probeHandler.SubmitResults)
 
 		// Simple health check
 		api.GET("/health", healthHandler.Health)
@@ -184,6 +464,9 @@ func main() {
 
 	appLogger.Info("Shutting down server...")
 
+	// End open event streams so that shutdown does not wait for them
+	eventBus.Close()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -193,3 +476,28 @@ func main() {
 
 	appLogger.Info("Server exited")
 }
+
+// newMailer builds the registrant mailer from cfg. It returns nil when no
+// transport is configured.
+func newMailer(cfg config.MailConfig) (*mail.Mailer, error) {
+	var transport mail.Transport
+	switch {
+	case cfg.SMTPHost != "":
+		transport = mail.NewSMTPTransport(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword)
+	case cfg.OutboxDir != "":
+		fileTransport, err := mail.NewFileTransport(cfg.OutboxDir)
+		if err != nil {
+			return nil, err
+		}
+		transport = fileTransport
+	default:
+		return nil, nil
+	}
+
+	templates, err := mail.LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	limits := mail.Limits{PerRecipient: cfg.PerRecipientMax, Total: cfg.TotalMax}
+	return mail.NewMailer(transport, templates, cfg.From, limits), nil
+}
@@ -1,18 +1,29 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	Monitor  MonitorConfig
-	Logger   LoggerConfig
+	Database     DatabaseConfig
+	Server       ServerConfig
+	Monitor      MonitorConfig
+	Logger       LoggerConfig
+	Leader       LeaderConfig
+	Probe        ProbeConfig
+	Registration RegistrationConfig
+	Mail         MailConfig
+	Validator    ValidatorConfig
+	Chain        ChainConfig
+	Sources      SourceConfig
+	Retention    RetentionConfig
+	Admin        AdminConfig
 }
 
 type DatabaseConfig struct {
@@ -40,6 +51,88 @@ type LoggerConfig struct {
 	Format string
 }
 
+type LeaderConfig struct {
+	Enabled       bool
+	InstanceID    string
+	LockID        int64
+	RenewInterval time.Duration
+}
+
+type ProbeConfig struct {
+	Agents       string
+	MinRegions   int
+	MaxClockSkew time.Duration
+}
+
+// RetentionConfig sets how long monitoring data is kept before the daily
+// cleanup job deletes it. Zero keeps it forever.
+type RetentionConfig struct {
+	ProbeResults  time.Duration
+	CheckAttempts time.Duration
+}
+
+// AdminConfig lists the bearer tokens accepted by the admin API. With none,
+// the admin API is not served.
+type AdminConfig struct {
+	Tokens []string
+}
+
+type RegistrationConfig struct {
+	AutoApproveVerified bool
+	PublicBaseURL       string
+}
+
+// MailConfig selects how registrant emails are delivered. SMTP is used
+// when SMTPHost is set, otherwise messages are written to OutboxDir; with
+// neither, registrations skip email confirmation.
+type MailConfig struct {
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string
+	SMTPPassword    string
+	From            string
+	OutboxDir       string
+	TemplateDir     string
+	PerRecipientMax int
+	TotalMax        int
+}
+
+// ValidatorConfig points validator and committee monitoring at a trusted
+// gRPC server of Network. An empty GRPCAddress disables it.
+type ValidatorConfig struct {
+	GRPCAddress string
+	Network     string
+}
+
+// ChainConfig lists the trusted servers the chain monitor reads blocks
+// from, in order of preference, and the network they belong to. With none,
+// chain monitoring is disabled.
+type ChainConfig struct {
+	GRPCAddresses    []string
+	JSONRPCAddresses []string
+	Network          string
+	StallThreshold   time.Duration
+}
+
+// SourceConfig selects where bootstrap nodes and gRPC servers are synced
+// from: "pactus" for the lists shipped with Pactus, an http(s) URL or the
+// path of a local JSON file.
+type SourceConfig struct {
+	Bootstrap string
+	GRPC      string
+}
+
+// AgentConfig configures a remote probe agent
+type AgentConfig struct {
+	TrackerURL string
+	AgentID    string
+	Region     string
+	PrivateKey string
+	Interval   time.Duration
+	Monitor    MonitorConfig
+	Logger     LoggerConfig
+}
+
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		// It's okay if .env file doesn't exist in production
@@ -52,6 +145,23 @@ func Load() (*Config, error) {
 	checkInterval, _ := time.ParseDuration(getEnv("BOOTSTRAP_CHECK_INTERVAL", "24h"))
 	connTimeout, _ := time.ParseDuration(getEnv("CONNECTION_TIMEOUT", "30s"))
 
+	leaderEnabled, _ := strconv.ParseBool(getEnv("LEADER_ELECTION_ENABLED", "false"))
+	leaderLockID, _ := strconv.ParseInt(getEnv("LEADER_LOCK_ID", "72616374"), 10, 64)
+	leaderInterval, _ := time.ParseDuration(getEnv("LEADER_RENEW_INTERVAL", "10s"))
+
+	probeMinRegions, _ := strconv.Atoi(getEnv("PROBE_QUORUM_MIN_REGIONS", "2"))
+	probeClockSkew, _ := time.ParseDuration(getEnv("PROBE_MAX_CLOCK_SKEW", "5m"))
+	probeRetention, _ := time.ParseDuration(getEnv("PROBE_RESULT_RETENTION", "720h"))
+	attemptRetention, _ := time.ParseDuration(getEnv("CHECK_ATTEMPT_RETENTION", "720h"))
+
+	autoApprove, _ := strconv.ParseBool(getEnv("REGISTRATION_AUTO_APPROVE_VERIFIED", "false"))
+
+	smtpPort, _ := strconv.Atoi(getEnv("MAIL_SMTP_PORT", "587"))
+	mailPerRecipient, _ := strconv.Atoi(getEnv("MAIL_RATE_LIMIT_PER_RECIPIENT", "3"))
+	mailTotal, _ := strconv.Atoi(getEnv("MAIL_RATE_LIMIT_TOTAL", "200"))
+
+	stallThreshold, _ := time.ParseDuration(getEnv("CHAIN_STALL_THRESHOLD", "2m"))
+
 	return &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -74,12 +184,112 @@ func Load() (*Config, error) {
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Leader: LeaderConfig{
+			Enabled:       leaderEnabled,
+			InstanceID:    getEnv("INSTANCE_ID", defaultInstanceID()),
+			LockID:        leaderLockID,
+			RenewInterval: leaderInterval,
+		},
+		Probe: ProbeConfig{
+			Agents:       getEnv("PROBE_AGENTS", ""),
+			MinRegions:   probeMinRegions,
+			MaxClockSkew: probeClockSkew,
+		},
+		Registration: RegistrationConfig{
+			AutoApproveVerified: autoApprove,
+			PublicBaseURL:       getEnv("PUBLIC_BASE_URL", "http://localhost:4622"),
+		},
+		Mail: MailConfig{
+			SMTPHost:        getEnv("MAIL_SMTP_HOST", ""),
+			SMTPPort:        smtpPort,
+			SMTPUsername:    getEnv("MAIL_SMTP_USERNAME", ""),
+			SMTPPassword:    getEnv("MAIL_SMTP_PASSWORD", ""),
+			From:            getEnv("MAIL_FROM", "Pactus Nodes Tracker <no-reply@localhost>"),
+			OutboxDir:       getEnv("MAIL_OUTBOX_DIR", ""),
+			TemplateDir:     getEnv("MAIL_TEMPLATE_DIR", ""),
+			PerRecipientMax: mailPerRecipient,
+			TotalMax:        mailTotal,
+		},
+		Validator: ValidatorConfig{
+			GRPCAddress: getEnv("VALIDATOR_GRPC_ADDRESS", ""),
+			Network:     getEnv("VALIDATOR_NETWORK", "mainnet"),
+		},
+		Chain: ChainConfig{
+			GRPCAddresses:    splitList(getEnv("CHAIN_GRPC_ADDRESSES", "")),
+			JSONRPCAddresses: splitList(getEnv("CHAIN_JSONRPC_ADDRESSES", "")),
+			Network:          getEnv("CHAIN_NETWORK", "mainnet"),
+			StallThreshold:   stallThreshold,
+		},
+		Sources: SourceConfig{
+			Bootstrap: getEnv("BOOTSTRAP_SOURCE", "pactus"),
+			GRPC:      getEnv("GRPC_SOURCE", "pactus"),
+		},
+		Admin: AdminConfig{
+			Tokens: splitList(getEnv("ADMIN_API_TOKENS", "")),
+		},
+		Retention: RetentionConfig{
+			ProbeResults:  probeRetention,
+			CheckAttempts: attemptRetention,
+		},
 	}, nil
 }
 
+// LoadAgent loads the configuration of a remote probe agent
+func LoadAgent() (*AgentConfig, error) {
+	if err := godotenv.Load(); err != nil {
+		// It's okay if .env file doesn't exist in production
+	}
+
+	maxRetry, _ := strconv.Atoi(getEnv("MAX_RETRY_ATTEMPTS", "5"))
+	connTimeout, _ := time.ParseDuration(getEnv("CONNECTION_TIMEOUT", "30s"))
+	interval, _ := time.ParseDuration(getEnv("PROBE_INTERVAL", "1h"))
+
+	cfg := &AgentConfig{
+		TrackerURL: getEnv("TRACKER_URL", "http://localhost:4622"),
+		AgentID:    getEnv("PROBE_AGENT_ID", ""),
+		Region:     getEnv("PROBE_AGENT_REGION", ""),
+		PrivateKey: getEnv("PROBE_AGENT_KEY", ""),
+		Interval:   interval,
+		Monitor: MonitorConfig{
+			ConnectionTimeout: connTimeout,
+			MaxRetryAttempts:  maxRetry,
+		},
+		Logger: LoggerConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "json"),
+		},
+	}
+
+	if cfg.AgentID == "" || cfg.Region == "" || cfg.PrivateKey == "" {
+		return nil, fmt.Errorf("PROBE_AGENT_ID, PROBE_AGENT_REGION and PROBE_AGENT_KEY are required")
+	}
+
+	return cfg, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// defaultInstanceID identifies this process when INSTANCE_ID is not set
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "tracker"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
@@ -0,0 +1,37 @@
+-- Multi-region probing - Database Migrations
+-- File: 003_probe_agents.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Check results reported by remote probe agents
+CREATE TABLE IF NOT EXISTS probe_results (
+    id SERIAL PRIMARY KEY,
+    agent_id VARCHAR(100) NOT NULL,
+    region VARCHAR(100) NOT NULL,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('bootstrap', 'grpc', 'jsonrpc')),
+    node_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    success BOOLEAN DEFAULT false,
+    attempts INTEGER DEFAULT 0,
+    response_time_ms INTEGER,
+    error_msg TEXT,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(agent_id, node_type, node_id, date)
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_probe_results_node_date ON probe_results(node_type, node_id, date);
+CREATE INDEX IF NOT EXISTS idx_probe_results_date ON probe_results(date);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
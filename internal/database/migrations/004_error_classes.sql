@@ -0,0 +1,21 @@
+-- Check error classification - Database Migrations
+-- File: 004_error_classes.sql
+
+-- ============================================
+-- ADD ERROR CLASS TO STATUS TABLES
+-- ============================================
+
+-- One of: invalid_address, dns, connection_refused, timeout, tls,
+-- http_5xx, http_error, rpc, unknown (NULL or empty on success)
+ALTER TABLE daily_status ADD COLUMN IF NOT EXISTS error_class VARCHAR(32);
+ALTER TABLE grpc_daily_status ADD COLUMN IF NOT EXISTS error_class VARCHAR(32);
+ALTER TABLE jsonrpc_daily_status ADD COLUMN IF NOT EXISTS error_class VARCHAR(32);
+ALTER TABLE probe_results ADD COLUMN IF NOT EXISTS error_class VARCHAR(32);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_daily_status_error_class ON daily_status(error_class) WHERE error_class IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_grpc_daily_status_error_class ON grpc_daily_status(error_class) WHERE error_class IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_jsonrpc_daily_status_error_class ON jsonrpc_daily_status(error_class) WHERE error_class IS NOT NULL;
@@ -0,0 +1,42 @@
+-- TLS certificate monitoring - Database Migrations
+-- File: 005_tls_certificates.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Latest TLS certificate seen on each gRPC / JSON-RPC endpoint
+CREATE TABLE IF NOT EXISTS tls_certificates (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('grpc', 'jsonrpc')),
+    node_id INTEGER NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    subject TEXT,
+    issuer TEXT,
+    sans TEXT[] DEFAULT '{}',
+    serial_number VARCHAR(100),
+    fingerprint_sha256 VARCHAR(64),
+    not_before TIMESTAMP WITH TIME ZONE,
+    not_after TIMESTAMP WITH TIME ZONE,
+    is_trusted BOOLEAN DEFAULT false,
+    is_self_signed BOOLEAN DEFAULT false,
+    is_expired BOOLEAN DEFAULT false,
+    expires_soon BOOLEAN DEFAULT false,
+    verify_error TEXT,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(node_type, node_id)
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_tls_certificates_not_after ON tls_certificates(not_after);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,56 @@
+-- Latency tracking - Database Migrations
+-- File: 006_latency_tracking.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Every connection attempt made by the checkers
+CREATE TABLE IF NOT EXISTS check_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('bootstrap', 'grpc', 'jsonrpc')),
+    node_id INTEGER NOT NULL,
+    attempt INTEGER NOT NULL,
+    success BOOLEAN DEFAULT false,
+    latency_ms INTEGER NOT NULL,
+    error_class VARCHAR(32),
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Daily latency percentiles per node (successful attempts only)
+CREATE TABLE IF NOT EXISTS latency_daily_stats (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('bootstrap', 'grpc', 'jsonrpc')),
+    node_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    samples INTEGER DEFAULT 0,
+    p50_ms DECIMAL(10, 2),
+    p95_ms DECIMAL(10, 2),
+    p99_ms DECIMAL(10, 2),
+    min_ms INTEGER,
+    max_ms INTEGER,
+    avg_ms DECIMAL(10, 2),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(node_type, node_id, date)
+);
+
+-- ============================================
+-- ADD RESPONSE TIME TO BOOTSTRAP STATUS
+-- ============================================
+
+ALTER TABLE daily_status ADD COLUMN IF NOT EXISTS response_time_ms INTEGER;
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_check_attempts_node_checked ON check_attempts(node_type, node_id, checked_at);
+CREATE INDEX IF NOT EXISTS idx_check_attempts_checked ON check_attempts(checked_at);
+CREATE INDEX IF NOT EXISTS idx_latency_daily_stats_node_date ON latency_daily_stats(node_type, node_id, date);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
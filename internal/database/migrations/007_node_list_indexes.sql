@@ -0,0 +1,25 @@
+-- Node list pagination - Database Migrations
+-- File: 007_node_list_indexes.sql
+
+-- ============================================
+-- INDEXES FOR KEYSET PAGINATION
+-- ============================================
+
+-- Sort keys of the node list APIs, with id as the tie breaker
+CREATE INDEX IF NOT EXISTS idx_bootstrap_nodes_score_id ON bootstrap_nodes(overall_score, id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_bootstrap_nodes_name_id ON bootstrap_nodes(name, id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_grpc_servers_score_id ON grpc_servers(overall_score, id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_grpc_servers_name_id ON grpc_servers(name, id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_jsonrpc_servers_score_id ON jsonrpc_servers(overall_score, id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_jsonrpc_servers_name_id ON jsonrpc_servers(name, id) WHERE is_active = true;
+
+-- Batched status history lookups for a page of nodes
+CREATE INDEX IF NOT EXISTS idx_daily_status_node_date ON daily_status(node_id, date);
+CREATE INDEX IF NOT EXISTS idx_grpc_daily_status_server_date ON grpc_daily_status(server_id, date);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
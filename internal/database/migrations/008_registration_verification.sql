@@ -0,0 +1,26 @@
+-- Registration ownership verification - Database Migrations
+-- File: 008_registration_verification.sql
+
+-- ============================================
+-- REGISTRATION CHALLENGES
+-- ============================================
+
+-- Challenge token the operator proves control of the endpoint with
+ALTER TABLE node_registrations ADD COLUMN IF NOT EXISTS verification_token VARCHAR(64);
+ALTER TABLE node_registrations ADD COLUMN IF NOT EXISTS is_verified BOOLEAN DEFAULT false;
+ALTER TABLE node_registrations ADD COLUMN IF NOT EXISTS verification_method VARCHAR(20);
+ALTER TABLE node_registrations ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE;
+
+-- ============================================
+-- VERIFIED SERVERS
+-- ============================================
+
+-- JSON-RPC servers already carry is_verified
+ALTER TABLE grpc_servers ADD COLUMN IF NOT EXISTS is_verified BOOLEAN DEFAULT false;
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
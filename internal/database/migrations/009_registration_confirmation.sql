@@ -0,0 +1,29 @@
+-- Registration email confirmation - Database Migrations
+-- File: 009_registration_confirmation.sql
+
+-- ============================================
+-- UNCONFIRMED REGISTRATIONS
+-- ============================================
+
+-- Registrations wait in 'unconfirmed' until the submitter clicks the
+-- emailed link, then move to 'pending' review
+ALTER TABLE node_registrations DROP CONSTRAINT IF EXISTS node_registrations_status_check;
+ALTER TABLE node_registrations ADD CONSTRAINT node_registrations_status_check
+    CHECK (status IN ('unconfirmed', 'pending', 'approved', 'rejected'));
+
+ALTER TABLE node_registrations ADD COLUMN IF NOT EXISTS confirmation_token VARCHAR(64);
+ALTER TABLE node_registrations ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMP WITH TIME ZONE;
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_node_registrations_confirmation_token
+    ON node_registrations(confirmation_token) WHERE confirmation_token IS NOT NULL;
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,70 @@
+-- Operator portal - Database Migrations
+-- File: 010_operator_portal.sql
+
+-- ============================================
+-- OPERATOR TOKENS
+-- ============================================
+
+-- Login links, signing challenges and sessions of node operators. Only
+-- SHA-256 hashes of the tokens are stored.
+CREATE TABLE IF NOT EXISTS operator_tokens (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('login', 'challenge', 'session')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    -- Email sessions manage every node registered with the address, key
+    -- sessions only the node that signed
+    email VARCHAR(255),
+    node_type VARCHAR(20),
+    node_id INTEGER,
+    method VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- ============================================
+-- MAINTENANCE WINDOWS
+-- ============================================
+
+-- Monitoring of a node is paused while a window is open
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL,
+    node_id INTEGER NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+-- ============================================
+-- AUDIT LOG
+-- ============================================
+
+-- Changes operators make to their nodes
+CREATE TABLE IF NOT EXISTS node_audit_log (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL,
+    node_id INTEGER NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    changes JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_operator_tokens_expires_at ON operator_tokens(expires_at);
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_node ON maintenance_windows(node_type, node_id, ends_at);
+CREATE INDEX IF NOT EXISTS idx_node_audit_log_node ON node_audit_log(node_type, node_id, created_at);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
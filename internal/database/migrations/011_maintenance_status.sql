@@ -0,0 +1,31 @@
+-- Maintenance windows in status history - Database Migrations
+-- File: 011_maintenance_status.sql
+
+-- ============================================
+-- DAILY STATUS COLORS
+-- ============================================
+
+-- Color 3 marks a check made during a maintenance window. Those days are
+-- shown in the status history but left out of the overall score.
+ALTER TABLE daily_status DROP CONSTRAINT IF EXISTS daily_status_color_check;
+ALTER TABLE daily_status ADD CONSTRAINT daily_status_color_check CHECK (color IN (0, 1, 2, 3));
+
+ALTER TABLE grpc_daily_status DROP CONSTRAINT IF EXISTS grpc_daily_status_color_check;
+ALTER TABLE grpc_daily_status ADD CONSTRAINT grpc_daily_status_color_check CHECK (color IN (0, 1, 2, 3));
+
+ALTER TABLE jsonrpc_daily_status DROP CONSTRAINT IF EXISTS jsonrpc_daily_status_color_check;
+ALTER TABLE jsonrpc_daily_status ADD CONSTRAINT jsonrpc_daily_status_color_check CHECK (color IN (0, 1, 2, 3));
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- Upcoming windows are listed and checked for overlaps by start
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_node_start ON maintenance_windows(node_type, node_id, starts_at);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,34 @@
+-- Node software versions - Database Migrations
+-- File: 012_node_versions.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Agent strings reported by monitored nodes and crawled peers. Each row is
+-- one agent a node ran between first_seen and last_seen, so the rows of a
+-- node form its upgrade history.
+CREATE TABLE IF NOT EXISTS node_versions (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('bootstrap', 'grpc', 'jsonrpc', 'peer')),
+    node_id INTEGER NOT NULL,
+    agent VARCHAR(255) NOT NULL,
+    version VARCHAR(50),
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE(node_type, node_id, agent)
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- The distribution reads the latest agent of every recently seen node
+CREATE INDEX IF NOT EXISTS idx_node_versions_last_seen ON node_versions(last_seen);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
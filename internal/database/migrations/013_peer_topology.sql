@@ -0,0 +1,33 @@
+-- Network topology - Database Migrations
+-- File: 013_peer_topology.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Peer connections found by the crawler: source_id reported being
+-- connected to target_id between first_seen and last_seen
+CREATE TABLE IF NOT EXISTS peer_edges (
+    id SERIAL PRIMARY KEY,
+    source_id INTEGER NOT NULL REFERENCES reachable_peers(id) ON DELETE CASCADE,
+    target_id INTEGER NOT NULL REFERENCES reachable_peers(id) ON DELETE CASCADE,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE(source_id, target_id),
+    CHECK (source_id <> target_id)
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- The topology is built from the edges seen in a recent window
+CREATE INDEX IF NOT EXISTS idx_peer_edges_last_seen ON peer_edges(last_seen);
+CREATE INDEX IF NOT EXISTS idx_peer_edges_target ON peer_edges(target_id);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,41 @@
+-- Peer churn analytics - Database Migrations
+-- File: 014_peer_sessions.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Continuous periods in which the crawler kept seeing a peer. A sighting
+-- after a gap longer than the crawl interval starts a new session.
+CREATE TABLE IF NOT EXISTS peer_sessions (
+    id SERIAL PRIMARY KEY,
+    peer_id INTEGER NOT NULL REFERENCES reachable_peers(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (last_seen >= started_at)
+);
+
+-- Peers crawled before sessions were tracked get one session spanning
+-- their first and last sighting
+INSERT INTO peer_sessions (peer_id, started_at, last_seen)
+SELECT id, first_seen, last_seen
+FROM reachable_peers p
+WHERE first_seen IS NOT NULL AND last_seen IS NOT NULL AND last_seen >= first_seen
+  AND NOT EXISTS (SELECT 1 FROM peer_sessions s WHERE s.peer_id = p.id);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- Sightings extend the latest session of a peer
+CREATE INDEX IF NOT EXISTS idx_peer_sessions_peer_last_seen ON peer_sessions(peer_id, last_seen DESC);
+
+-- Churn stats read the sessions overlapping a date range
+CREATE INDEX IF NOT EXISTS idx_peer_sessions_last_seen ON peer_sessions(last_seen);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,51 @@
+-- Multiaddr support - Database Migrations
+-- File: 015_node_addresses.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Every multiaddr a crawled peer advertises, with its address family and
+-- transport
+CREATE TABLE IF NOT EXISTS peer_addresses (
+    id SERIAL PRIMARY KEY,
+    peer_id INTEGER NOT NULL REFERENCES reachable_peers(id) ON DELETE CASCADE,
+    multiaddr TEXT NOT NULL,
+    family VARCHAR(10) NOT NULL CHECK (family IN ('ipv4', 'ipv6', 'dns')),
+    transport VARCHAR(10) NOT NULL CHECK (transport IN ('tcp', 'quic')),
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(peer_id, multiaddr)
+);
+
+-- The latest probe of each resolved address and transport of a node
+CREATE TABLE IF NOT EXISTS endpoint_checks (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('bootstrap')),
+    node_id INTEGER NOT NULL,
+    multiaddr TEXT NOT NULL,
+    family VARCHAR(10) NOT NULL CHECK (family IN ('ipv4', 'ipv6')),
+    transport VARCHAR(10) NOT NULL CHECK (transport IN ('tcp', 'quic')),
+    reachable BOOLEAN NOT NULL,
+    response_time_ms INTEGER NOT NULL DEFAULT 0,
+    error_class VARCHAR(30) NOT NULL DEFAULT '',
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE(node_type, node_id, multiaddr)
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- Family counts only cover recently seen addresses
+CREATE INDEX IF NOT EXISTS idx_peer_addresses_last_seen ON peer_addresses(last_seen);
+
+-- Node listings load the endpoint checks of one node type
+CREATE INDEX IF NOT EXISTS idx_endpoint_checks_node ON endpoint_checks(node_type, node_id);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
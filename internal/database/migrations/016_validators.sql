@@ -0,0 +1,84 @@
+-- Validator and committee monitoring - Database Migrations
+-- File: 016_validators.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Every validator the trusted gRPC server reported, with its latest state
+CREATE TABLE IF NOT EXISTS validators (
+    id SERIAL PRIMARY KEY,
+    address VARCHAR(100) NOT NULL UNIQUE,
+    number INTEGER NOT NULL,
+    public_key TEXT NOT NULL DEFAULT '',
+    stake BIGINT NOT NULL DEFAULT 0,
+    availability_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_bonding_height BIGINT NOT NULL DEFAULT 0,
+    last_sortition_height BIGINT NOT NULL DEFAULT 0,
+    unbonding_height BIGINT NOT NULL DEFAULT 0,
+    protocol_version INTEGER NOT NULL DEFAULT 0,
+    in_committee BOOLEAN NOT NULL DEFAULT false,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One row per validator and day with its stake, availability score and
+-- whether it sat in the committee at any sync that day
+CREATE TABLE IF NOT EXISTS validator_history (
+    validator_id INTEGER NOT NULL REFERENCES validators(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    stake BIGINT NOT NULL,
+    availability_score DOUBLE PRECISION NOT NULL,
+    in_committee BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (validator_id, date)
+);
+
+-- The committee at each sync, members as validator numbers
+CREATE TABLE IF NOT EXISTS committee_snapshots (
+    id SERIAL PRIMARY KEY,
+    height BIGINT NOT NULL,
+    total_validators INTEGER NOT NULL,
+    active_validators INTEGER NOT NULL,
+    total_power BIGINT NOT NULL,
+    committee_power BIGINT NOT NULL,
+    members INTEGER[] NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The proposer of each synced block and the certificate of the previous
+-- block it carries, committers and absentees as validator numbers
+CREATE TABLE IF NOT EXISTS validator_blocks (
+    height BIGINT PRIMARY KEY,
+    proposer_address VARCHAR(100) NOT NULL,
+    block_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    cert_round INTEGER NOT NULL DEFAULT 0,
+    cert_committers INTEGER[] NOT NULL DEFAULT '{}',
+    cert_absentees INTEGER[] NOT NULL DEFAULT '{}'
+);
+
+-- The libp2p peer a validator's consensus key was last announced by
+CREATE TABLE IF NOT EXISTS validator_peers (
+    validator_address VARCHAR(100) PRIMARY KEY,
+    peer_id VARCHAR(255) NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- Committee lookups resolve member numbers
+CREATE INDEX IF NOT EXISTS idx_validators_number ON validators(number);
+
+-- getCommittee reads the latest snapshot
+CREATE INDEX IF NOT EXISTS idx_committee_snapshots_recorded_at ON committee_snapshots(recorded_at DESC);
+
+-- Uptime only covers recent blocks
+CREATE INDEX IF NOT EXISTS idx_validator_blocks_block_time ON validator_blocks(block_time);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,59 @@
+-- Chain monitoring - Database Migrations
+-- File: 017_chain_monitor.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Block headers read from the trusted servers. gap_seconds is the time
+-- since the previous block, NULL when that block was not recorded.
+CREATE TABLE IF NOT EXISTS chain_blocks (
+    height BIGINT PRIMARY KEY,
+    hash VARCHAR(64) NOT NULL,
+    block_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    proposer_address VARCHAR(100) NOT NULL DEFAULT '',
+    tx_count INTEGER NOT NULL DEFAULT 0,
+    gap_seconds INTEGER,
+    source TEXT NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The latest poll of each trusted server
+CREATE TABLE IF NOT EXISTS chain_sources (
+    address TEXT PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('grpc', 'jsonrpc')),
+    height BIGINT NOT NULL DEFAULT 0,
+    hash VARCHAR(64) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Stalls and forks, open until ended_at is set. details holds the last
+-- block of a stall or the hash each server reported at a forked height.
+CREATE TABLE IF NOT EXISTS chain_incidents (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('stall', 'fork')),
+    height BIGINT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- Chain stats cover a recent window of blocks
+CREATE INDEX IF NOT EXISTS idx_chain_blocks_block_time ON chain_blocks(block_time);
+
+-- Polls look up the open incident of each kind
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chain_incidents_open ON chain_incidents(kind) WHERE ended_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chain_incidents_started_at ON chain_incidents(started_at DESC);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
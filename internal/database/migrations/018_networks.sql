@@ -0,0 +1,122 @@
+-- Multi-network support - Database Migrations
+-- File: 018_networks.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- The networks the tracker follows. Mainnet and testnet sync the lists
+-- shipped with Pactus; bootstrap_addresses and grpc_servers are synced for
+-- every network, which is how localnet and custom networks get their nodes.
+CREATE TABLE IF NOT EXISTS networks (
+    name VARCHAR(20) PRIMARY KEY,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('mainnet', 'testnet', 'localnet', 'custom')),
+    description TEXT NOT NULL DEFAULT '',
+    bootstrap_addresses TEXT[] NOT NULL DEFAULT '{}',
+    grpc_servers TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO networks (name, kind, description, is_active) VALUES
+    ('mainnet', 'mainnet', 'Pactus mainnet', true),
+    ('testnet', 'testnet', 'Pactus testnet', true),
+    ('localnet', 'localnet', 'Local development network', false)
+ON CONFLICT (name) DO NOTHING;
+
+-- Servers and registrations already stored under another network name
+-- keep it as a custom network
+INSERT INTO networks (name, kind)
+SELECT DISTINCT network, 'custom' FROM grpc_servers
+UNION SELECT DISTINCT network, 'custom' FROM jsonrpc_servers
+UNION SELECT DISTINCT network, 'custom' FROM node_registrations
+ON CONFLICT (name) DO NOTHING;
+
+-- ============================================
+-- NETWORK SCOPE
+-- ============================================
+
+-- Nodes, servers and registrations
+ALTER TABLE bootstrap_nodes ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet';
+
+ALTER TABLE bootstrap_nodes DROP CONSTRAINT IF EXISTS bootstrap_nodes_network_fkey;
+ALTER TABLE bootstrap_nodes ADD CONSTRAINT bootstrap_nodes_network_fkey FOREIGN KEY (network) REFERENCES networks(name);
+
+ALTER TABLE grpc_servers DROP CONSTRAINT IF EXISTS grpc_servers_network_fkey;
+ALTER TABLE grpc_servers ADD CONSTRAINT grpc_servers_network_fkey FOREIGN KEY (network) REFERENCES networks(name);
+
+ALTER TABLE jsonrpc_servers DROP CONSTRAINT IF EXISTS jsonrpc_servers_network_fkey;
+ALTER TABLE jsonrpc_servers ADD CONSTRAINT jsonrpc_servers_network_fkey FOREIGN KEY (network) REFERENCES networks(name);
+
+ALTER TABLE node_registrations DROP CONSTRAINT IF EXISTS node_registrations_network_fkey;
+ALTER TABLE node_registrations ADD CONSTRAINT node_registrations_network_fkey FOREIGN KEY (network) REFERENCES networks(name);
+
+-- Crawled peers. A peer ID is only unique within its network; edges,
+-- sessions and addresses follow the network of their peers.
+ALTER TABLE reachable_peers ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE reachable_peers DROP CONSTRAINT IF EXISTS reachable_peers_peer_id_key;
+ALTER TABLE reachable_peers DROP CONSTRAINT IF EXISTS reachable_peers_network_peer_id_key;
+ALTER TABLE reachable_peers ADD CONSTRAINT reachable_peers_network_peer_id_key UNIQUE (network, peer_id);
+
+-- Snapshots
+ALTER TABLE network_snapshots ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+-- Validators and committees. Validator history follows its validator.
+ALTER TABLE validators ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE validators DROP CONSTRAINT IF EXISTS validators_address_key;
+ALTER TABLE validators DROP CONSTRAINT IF EXISTS validators_network_address_key;
+ALTER TABLE validators ADD CONSTRAINT validators_network_address_key UNIQUE (network, address);
+
+ALTER TABLE committee_snapshots ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE validator_blocks ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE validator_blocks DROP CONSTRAINT IF EXISTS validator_blocks_pkey;
+ALTER TABLE validator_blocks ADD CONSTRAINT validator_blocks_pkey PRIMARY KEY (network, height);
+
+ALTER TABLE validator_peers ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE validator_peers DROP CONSTRAINT IF EXISTS validator_peers_pkey;
+ALTER TABLE validator_peers ADD CONSTRAINT validator_peers_pkey PRIMARY KEY (network, validator_address);
+
+-- Chain monitoring
+ALTER TABLE chain_blocks ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE chain_blocks DROP CONSTRAINT IF EXISTS chain_blocks_pkey;
+ALTER TABLE chain_blocks ADD CONSTRAINT chain_blocks_pkey PRIMARY KEY (network, height);
+
+ALTER TABLE chain_sources ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+ALTER TABLE chain_sources DROP CONSTRAINT IF EXISTS chain_sources_pkey;
+ALTER TABLE chain_sources ADD CONSTRAINT chain_sources_pkey PRIMARY KEY (network, address);
+
+ALTER TABLE chain_incidents ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT 'mainnet' REFERENCES networks(name);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+-- Stats, maps and syncs select the nodes of one network
+CREATE INDEX IF NOT EXISTS idx_bootstrap_nodes_network ON bootstrap_nodes(network);
+CREATE INDEX IF NOT EXISTS idx_grpc_servers_network ON grpc_servers(network);
+CREATE INDEX IF NOT EXISTS idx_reachable_peers_network ON reachable_peers(network);
+
+-- getSnapshots reads the latest snapshots of a network
+DROP INDEX IF EXISTS idx_network_snapshots_timestamp;
+CREATE INDEX IF NOT EXISTS idx_network_snapshots_network_timestamp ON network_snapshots(network, timestamp DESC);
+
+-- Each network has at most one open incident of a kind
+DROP INDEX IF EXISTS idx_chain_incidents_open;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chain_incidents_open ON chain_incidents(network, kind) WHERE ended_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_committee_snapshots_network_recorded_at ON committee_snapshots(network, recorded_at DESC);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,34 @@
+-- Node list sync reports - Database Migrations
+-- File: 019_sync_runs.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- One row per bootstrap node or gRPC server sync. added, updated and
+-- deactivated hold the changed nodes with their network, address and name.
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id SERIAL PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL CHECK (node_type IN ('bootstrap', 'grpc')),
+    source TEXT NOT NULL,
+    added JSONB NOT NULL DEFAULT '[]',
+    updated JSONB NOT NULL DEFAULT '[]',
+    deactivated JSONB NOT NULL DEFAULT '[]',
+    errors INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_sync_runs_node_type_started_at ON sync_runs(node_type, started_at DESC);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
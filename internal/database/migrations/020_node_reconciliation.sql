@@ -0,0 +1,28 @@
+-- Node list reconciliation - Database Migrations
+-- File: 020_node_reconciliation.sql
+
+-- ============================================
+-- ALTER TABLES
+-- ============================================
+
+-- gRPC servers added by syncs are curated: syncs deactivate them when they
+-- leave their list and reactivate them when they return. Registered
+-- servers and servers deregistered by their operator are left alone.
+ALTER TABLE grpc_servers ADD COLUMN IF NOT EXISTS is_curated BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE grpc_servers SET is_curated = true
+WHERE is_active = true
+  AND address NOT IN (
+      SELECT address FROM node_registrations
+      WHERE node_type = 'grpc' AND status = 'approved'
+  );
+
+-- Nodes a sync brought back after they had left their list
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS reactivated JSONB NOT NULL DEFAULT '[]';
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,29 @@
+-- Probe request replay protection - Database Migrations
+-- File: 021_probe_nonces.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Nonces of authenticated probe agent requests, shared by every replica.
+-- A nonce is refused until expires_at, when its request timestamp leaves
+-- the allowed clock skew.
+CREATE TABLE IF NOT EXISTS probe_nonces (
+    agent_id VARCHAR(100) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (agent_id, nonce)
+);
+
+-- ============================================
+-- INDEXES FOR PERFORMANCE
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_probe_nonces_expires_at ON probe_nonces(expires_at);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,23 @@
+-- Bootstrap node centrality - Database Migrations
+-- File: 022_bootstrap_centrality.sql
+
+-- ============================================
+-- NEW TABLES
+-- ============================================
+
+-- Betweenness of each bootstrap node in the peer graph of its network over
+-- the default topology window, replaced by a scheduled job
+CREATE TABLE IF NOT EXISTS bootstrap_centrality (
+    network VARCHAR(20) NOT NULL REFERENCES networks(name),
+    bootstrap_id INTEGER NOT NULL REFERENCES bootstrap_nodes(id) ON DELETE CASCADE,
+    betweenness DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (network, bootstrap_id)
+);
+
+-- ============================================
+-- GRANT PERMISSIONS
+-- ============================================
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO pactus_user;
+GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO pactus_user;
@@ -0,0 +1,258 @@
+// Package events implements the in-process event bus that monitors and
+// services publish state changes to. Streams subscribe to it for delivery
+// to clients; handlers registered with Handle or On react to events inside
+// the process.
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Type names an event
+type Type string
+
+// Event types published by the tracker
+const (
+	CheckCompleted        Type = "check.completed"
+	NodeStatusChanged     Type = "node.status_changed"
+	NodeAdded             Type = "node.added"
+	NodeDeactivated       Type = "node.deactivated"
+	NodeUpdated           Type = "node.updated"
+	SyncFinished          Type = "sync.finished"
+	RegistrationSubmitted Type = "registration.submitted"
+	RegistrationApproved  Type = "registration.approved"
+	RegistrationRejected  Type = "registration.rejected"
+	SnapshotCreated       Type = "snapshot.created"
+	ChainStalled          Type = "chain.stalled"
+	ChainRecovered        Type = "chain.recovered"
+	ChainForked           Type = "chain.forked"
+	ChainForkResolved     Type = "chain.fork_resolved"
+	CertificateExpiring   Type = "certificate.expiring"
+)
+
+// Bus limits
+const (
+	DefaultBufferSize     = 64
+	DefaultMaxSubscribers = 1000
+)
+
+// ErrTooManySubscribers is returned when the bus is at its subscriber limit
+var ErrTooManySubscribers = errors.New("too many event subscribers")
+
+// ErrClosed is returned when subscribing to a closed bus
+var ErrClosed = errors.New("event bus closed")
+
+// Event is a single published event. ID and Timestamp are assigned by the
+// bus.
+type Event struct {
+	ID        uint64      `json:"id"`
+	Type      Type        `json:"type"`
+	NodeType  string      `json:"nodeType,omitempty"`
+	NodeID    int         `json:"nodeId,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Filter selects the events a subscriber receives. Empty fields match
+// every event.
+type Filter struct {
+	Types    []Type
+	NodeType string
+	NodeID   int
+}
+
+// Matches reports whether an event passes the filter
+func (f Filter) Matches(e *Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			if t == e.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.NodeType != "" && f.NodeType != e.NodeType {
+		return false
+	}
+	if f.NodeID != 0 && f.NodeID != e.NodeID {
+		return false
+	}
+	return true
+}
+
+// Subscription delivers matching events on C until it is unsubscribed or
+// the bus is closed, at which point C is closed. Events are dropped, not
+// queued, when the subscriber falls behind.
+type Subscription struct {
+	C       <-chan Event
+	ch      chan Event
+	filter  Filter
+	dropped atomic.Uint64
+}
+
+// Dropped returns the number of events lost because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Bus fans published events out to subscribers and handlers. A nil *Bus is
+// valid and discards every event, so publishers need not check for one.
+type Bus struct {
+	mu             sync.RWMutex
+	nextID         uint64
+	subscribers    map[*Subscription]struct{}
+	handlers       map[Type][]*handler
+	workers        sync.WaitGroup
+	ctx            context.Context
+	cancel         context.CancelFunc
+	bufferSize     int
+	maxSubscribers int
+	closed         bool
+	logger         *logrus.Logger
+}
+
+// NewBus creates an event bus with the default buffer size and subscriber
+// limit
+func NewBus(logger *logrus.Logger) *Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bus{
+		subscribers:    make(map[*Subscription]struct{}),
+		handlers:       make(map[Type][]*handler),
+		ctx:            ctx,
+		cancel:         cancel,
+		bufferSize:     DefaultBufferSize,
+		maxSubscribers: DefaultMaxSubscribers,
+		logger:         logger,
+	}
+}
+
+// Publish delivers an event to every matching subscriber and async handler
+// without blocking, then runs the sync handlers for its type
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+
+	// Sends never block, so holding the lock keeps delivery in ID order
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.nextID++
+	e.ID = b.nextID
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(&e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+
+	handlers := b.handlers[e.Type]
+	for _, h := range handlers {
+		if h.queue == nil {
+			continue
+		}
+		select {
+		case h.queue <- e:
+		default:
+			b.logger.WithFields(logrus.Fields{
+				"handler": h.name,
+				"type":    e.Type,
+			}).Warn("Event handler fell behind, dropping event")
+		}
+	}
+	b.mu.Unlock()
+
+	// Sync handlers may publish in turn, so they run without the lock
+	for _, h := range handlers {
+		if h.queue == nil {
+			b.run(ctx, h, e)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for events matching filter
+func (b *Bus) Subscribe(filter Filter) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if len(b.subscribers) >= b.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+
+	ch := make(chan Event, b.bufferSize)
+	sub := &Subscription{C: ch, ch: ch, filter: filter}
+	b.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe removes a subscriber and closes its channel
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+		if dropped := sub.Dropped(); dropped > 0 {
+			b.logger.WithField("dropped", dropped).Debug("Event subscriber fell behind")
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Bus) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
+
+// Close ends every subscription so that open streams finish, stops the
+// async handlers once their queued events are handled, and discards events
+// published afterwards
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for sub := range b.subscribers {
+		close(sub.ch)
+	}
+	b.subscribers = make(map[*Subscription]struct{})
+	for _, handlers := range b.handlers {
+		for _, h := range handlers {
+			if h.queue != nil {
+				close(h.queue)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	// Handlers see a cancelled context, so pending work finishes quickly
+	b.cancel()
+	b.workers.Wait()
+}
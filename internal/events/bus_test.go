@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestBus() *Bus {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return NewBus(logger)
+}
+
+func TestFilter_Matches(t *testing.T) {
+	event := &Event{Type: CheckCompleted, NodeType: "grpc", NodeID: 7}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		expect bool
+	}{
+		{"Empty filter", Filter{}, true},
+		{"Matching type", Filter{Types: []Type{SnapshotCreated, CheckCompleted}}, true},
+		{"Other type", Filter{Types: []Type{SnapshotCreated}}, false},
+		{"Matching node", Filter{NodeType: "grpc", NodeID: 7}, true},
+		{"Other node type", Filter{NodeType: "jsonrpc"}, false},
+		{"Other node id", Filter{NodeType: "grpc", NodeID: 8}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(event); got != tt.expect {
+				t.Errorf("Expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestBus_PublishDeliversInOrder(t *testing.T) {
+	bus := newTestBus()
+	sub, err := bus.Subscribe(Filter{NodeType: "grpc"})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	bus.Publish(context.Background(), Event{Type: CheckCompleted, NodeType: "grpc", NodeID: 1})
+	bus.Publish(context.Background(), Event{Type: CheckCompleted, NodeType: "bootstrap", NodeID: 1})
+	bus.Publish(context.Background(), Event{Type: NodeStatusChanged, NodeType: "grpc", NodeID: 1})
+
+	first, second := <-sub.C, <-sub.C
+	if first.Type != CheckCompleted || second.Type != NodeStatusChanged {
+		t.Fatalf("Unexpected events: %+v, %+v", first, second)
+	}
+	if first.ID >= second.ID || first.Timestamp.IsZero() {
+		t.Errorf("Expected increasing IDs and a timestamp, got %+v, %+v", first, second)
+	}
+	if len(sub.C) != 0 {
+		t.Errorf("Expected the bootstrap event to be filtered out")
+	}
+}
+
+func TestBus_DropsWhenSubscriberFallsBehind(t *testing.T) {
+	bus := newTestBus()
+	sub, _ := bus.Subscribe(Filter{})
+
+	for i := 0; i < DefaultBufferSize+5; i++ {
+		bus.Publish(context.Background(), Event{Type: CheckCompleted})
+	}
+
+	if sub.Dropped() != 5 {
+		t.Errorf("Expected 5 dropped events, got %d", sub.Dropped())
+	}
+}
+
+func TestBus_CloseEndsSubscriptions(t *testing.T) {
+	bus := newTestBus()
+	sub, _ := bus.Subscribe(Filter{})
+
+	bus.Close()
+	if _, ok := <-sub.C; ok {
+		t.Error("Expected the subscription channel to be closed")
+	}
+	if _, err := bus.Subscribe(Filter{}); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+
+	// Publishing and unsubscribing after close are no-ops
+	bus.Publish(context.Background(), Event{Type: CheckCompleted})
+	bus.Unsubscribe(sub)
+}
+
+func TestBus_NilIsSafe(t *testing.T) {
+	var bus *Bus
+	bus.Publish(context.Background(), Event{Type: CheckCompleted})
+}
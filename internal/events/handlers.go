@@ -0,0 +1,99 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mode selects how a handler is run
+type Mode int
+
+const (
+	// Sync handlers run in the publisher's goroutine before Publish returns
+	Sync Mode = iota
+	// Async handlers run on their own goroutine, one event at a time
+	Async
+)
+
+// DefaultQueueSize is the number of events an async handler may fall
+// behind before further events are dropped
+const DefaultQueueSize = 256
+
+// HandlerFunc reacts to an event. Errors are logged by the bus.
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// handler is a registered HandlerFunc. Async handlers own a queue that is
+// drained by a worker goroutine.
+type handler struct {
+	name  string
+	fn    HandlerFunc
+	queue chan Event
+}
+
+// Handle registers fn for one event type. Sync handlers receive the
+// publisher's context; async handlers receive a context that is cancelled
+// when the bus closes.
+func (b *Bus) Handle(eventType Type, name string, mode Mode, fn HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	h := &handler{name: name, fn: fn}
+	if mode == Async {
+		h.queue = make(chan Event, DefaultQueueSize)
+		b.workers.Add(1)
+		go b.drain(h)
+	}
+
+	// Copy on write so Publish can run handlers without holding the lock
+	handlers := make([]*handler, 0, len(b.handlers[eventType])+1)
+	handlers = append(handlers, b.handlers[eventType]...)
+	b.handlers[eventType] = append(handlers, h)
+}
+
+// On registers a handler that receives the event data as a T. Events
+// carrying other data are logged and skipped.
+func On[T any](b *Bus, eventType Type, name string, mode Mode, fn func(ctx context.Context, event Event, data T) error) {
+	b.Handle(eventType, name, mode, func(ctx context.Context, event Event) error {
+		data, ok := event.Data.(T)
+		if !ok {
+			return fmt.Errorf("unexpected data %T for %s", event.Data, event.Type)
+		}
+		return fn(ctx, event, data)
+	})
+}
+
+// drain runs an async handler until its queue is closed
+func (b *Bus) drain(h *handler) {
+	defer b.workers.Done()
+	for event := range h.queue {
+		b.run(b.ctx, h, event)
+	}
+}
+
+// run calls a handler, logging its error or panic
+func (b *Bus) run(ctx context.Context, h *handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.WithFields(logrus.Fields{
+				"handler": h.name,
+				"type":    event.Type,
+				"panic":   r,
+			}).Error("Event handler panicked")
+		}
+	}()
+
+	if err := h.fn(ctx, event); err != nil {
+		b.logger.WithError(err).WithFields(logrus.Fields{
+			"handler":   h.name,
+			"type":      event.Type,
+			"node_type": event.NodeType,
+			"node_id":   event.NodeID,
+		}).Error("Event handler failed")
+	}
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestBus_SyncHandlerRunsBeforePublishReturns(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	var got []CheckResult
+	On(bus, CheckCompleted, "test", Sync, func(ctx context.Context, event Event, data CheckResult) error {
+		got = append(got, data)
+		return nil
+	})
+
+	bus.Publish(context.Background(), Event{Type: CheckCompleted, Data: CheckResult{Color: 1}})
+	bus.Publish(context.Background(), Event{Type: SnapshotCreated})
+
+	if len(got) != 1 || got[0].Color != 1 {
+		t.Fatalf("Expected one check result, got %+v", got)
+	}
+}
+
+func TestBus_SyncHandlerMayPublish(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	var added int
+	On(bus, CheckCompleted, "chain", Sync, func(ctx context.Context, event Event, data CheckResult) error {
+		bus.Publish(ctx, Event{Type: NodeAdded, Data: Node{Name: "follow-up"}})
+		return nil
+	})
+	On(bus, NodeAdded, "count", Sync, func(ctx context.Context, event Event, data Node) error {
+		added++
+		return nil
+	})
+
+	bus.Publish(context.Background(), Event{Type: CheckCompleted, Data: CheckResult{}})
+
+	if added != 1 {
+		t.Errorf("Expected the nested event to be handled once, got %d", added)
+	}
+}
+
+func TestBus_AsyncHandlersFinishOnClose(t *testing.T) {
+	bus := newTestBus()
+
+	var mu sync.Mutex
+	var ids []int
+	bus.Handle(NodeAdded, "test", Async, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ids = append(ids, event.NodeID)
+		return nil
+	})
+
+	for i := 1; i <= 3; i++ {
+		bus.Publish(context.Background(), Event{Type: NodeAdded, NodeID: i})
+	}
+	bus.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("Expected events 1-3 in order, got %v", ids)
+	}
+}
+
+func TestBus_HandlerFailuresAreContained(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	calls := 0
+	bus.Handle(CheckCompleted, "failing", Sync, func(ctx context.Context, event Event) error {
+		return errors.New("boom")
+	})
+	bus.Handle(CheckCompleted, "panicking", Sync, func(ctx context.Context, event Event) error {
+		panic("boom")
+	})
+	On(bus, CheckCompleted, "typed", Sync, func(ctx context.Context, event Event, data CheckResult) error {
+		calls++
+		return nil
+	})
+
+	// Wrong data is skipped by typed handlers
+	bus.Publish(context.Background(), Event{Type: CheckCompleted, Data: Node{}})
+	bus.Publish(context.Background(), Event{Type: CheckCompleted, Data: CheckResult{}})
+
+	if calls != 1 {
+		t.Errorf("Expected the typed handler to run once, got %d", calls)
+	}
+}
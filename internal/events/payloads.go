@@ -0,0 +1,68 @@
+package events
+
+import "time"
+
+// CheckResult is the data of a CheckCompleted event
+type CheckResult struct {
+	Date           string `json:"date"`
+	Color          int    `json:"color"`
+	Success        bool   `json:"success"`
+	ResponseTimeMs int    `json:"responseTimeMs"`
+	ErrorClass     string `json:"errorClass,omitempty"`
+}
+
+// StatusChange is the data of a NodeStatusChanged event. Colors follow the
+// daily status colors.
+type StatusChange struct {
+	Date string `json:"date"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// Registration is the data of registration events
+type Registration struct {
+	ID       int    `json:"id"`
+	NodeType string `json:"nodeType"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Status   string `json:"status"`
+}
+
+// Node is the data of NodeAdded, NodeUpdated and NodeDeactivated events
+type Node struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Network string `json:"network,omitempty"`
+}
+
+// SyncResult is the data of a SyncFinished event
+type SyncResult struct {
+	Source      string `json:"source"`
+	Added       int    `json:"added"`
+	Updated     int    `json:"updated"`
+	Deactivated int    `json:"deactivated"`
+	Reactivated int    `json:"reactivated"`
+	Errors      int    `json:"errors"`
+}
+
+// Certificate is the data of a CertificateExpiring event, published when a
+// certificate has expired or is inside the expiry warning window
+type Certificate struct {
+	Address       string    `json:"address"`
+	Issuer        string    `json:"issuer"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Expired       bool      `json:"expired"`
+}
+
+// ChainIncident is the data of chain stall and fork events of a network.
+// Seconds is how long the chain has gone without a block; Hashes maps each
+// trusted server to the hash it reported at Height.
+type ChainIncident struct {
+	Network       string            `json:"network"`
+	Kind          string            `json:"kind"`
+	Height        uint32            `json:"height"`
+	LastBlockTime *time.Time        `json:"lastBlockTime,omitempty"`
+	Seconds       int               `json:"seconds,omitempty"`
+	Hashes        map[string]string `json:"hashes,omitempty"`
+}
@@ -40,32 +40,17 @@ func (h *BootstrapHandler) GetBootstrapNodes(c *gin.Context) {
 func (h *BootstrapHandler) SyncBootstrapNodes(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	err := h.monitor.SyncBootstrapNodes(ctx)
+	run, err := h.monitor.SyncBootstrapNodes(ctx, c.Query("dry_run") == "true")
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to sync bootstrap nodes from Pactus")
+		h.logger.WithError(err).Error("Failed to sync bootstrap nodes")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to sync bootstrap nodes from Pactus",
+			"error":   "Failed to sync bootstrap nodes",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	// Get updated count
-	count, err := h.monitor.GetBootstrapNodeCount(ctx)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to get bootstrap node count")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get updated count",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message":     "Bootstrap nodes synced successfully from file",
-		"total_nodes": count,
-		"source":      "local file",
-		"timestamp":   time.Now().UTC(),
-	})
+	c.JSON(http.StatusOK, run)
 }
 
 func (h *BootstrapHandler) GetBootstrapNodeCount(c *gin.Context) {
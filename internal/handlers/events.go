@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// eventHeartbeatInterval keeps idle streams open through proxies
+const eventHeartbeatInterval = 15 * time.Second
+
+// streamableEvents are the event types clients may subscribe to
+var streamableEvents = map[events.Type]bool{
+	events.CheckCompleted:        true,
+	events.NodeStatusChanged:     true,
+	events.NodeAdded:             true,
+	events.NodeDeactivated:       true,
+	events.NodeUpdated:           true,
+	events.SyncFinished:          true,
+	events.RegistrationSubmitted: true,
+	events.RegistrationApproved:  true,
+	events.RegistrationRejected:  true,
+	events.SnapshotCreated:       true,
+	events.ChainStalled:          true,
+	events.ChainRecovered:        true,
+	events.ChainForked:           true,
+	events.ChainForkResolved:     true,
+	events.CertificateExpiring:   true,
+}
+
+// EventsHandler streams bus events to clients as Server-Sent Events
+type EventsHandler struct {
+	bus       *events.Bus
+	heartbeat time.Duration
+	logger    *logrus.Logger
+}
+
+func NewEventsHandler(bus *events.Bus, logger *logrus.Logger) *EventsHandler {
+	return &EventsHandler{
+		bus:       bus,
+		heartbeat: eventHeartbeatInterval,
+		logger:    logger,
+	}
+}
+
+// Stream handles GET /events?types=&nodeType=&nodeId=. Types is a comma
+// separated list of event types; every filter is optional.
+func (h *EventsHandler) Stream(c *gin.Context) {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		appErr := models.NewValidationError("invalid event filter", err.Error())
+		c.AbortWithStatusJSON(appErr.StatusCode, appErr)
+		return
+	}
+
+	sub, err := h.bus.Subscribe(filter)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManySubscribers) {
+			h.logger.Warn("Rejected event stream: subscriber limit reached")
+		}
+		appErr := models.NewServiceUnavailableError("event stream unavailable")
+		c.AbortWithStatusJSON(appErr.StatusCode, appErr)
+		return
+	}
+	defer h.bus.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprint(c.Writer, "retry: 5000\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.WithError(err).WithField("type", event.Type).Error("Failed to encode event")
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func parseEventFilter(c *gin.Context) (events.Filter, error) {
+	var filter events.Filter
+
+	if value := c.Query("types"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			eventType := events.Type(strings.TrimSpace(name))
+			if !streamableEvents[eventType] {
+				return filter, fmt.Errorf("unknown event type %q", eventType)
+			}
+			filter.Types = append(filter.Types, eventType)
+		}
+	}
+
+	switch nodeType := c.Query("nodeType"); nodeType {
+	case "", models.NodeTypeBootstrap, models.NodeTypeGRPC, models.NodeTypeJSONRPC:
+		filter.NodeType = nodeType
+	default:
+		return filter, fmt.Errorf("nodeType must be one of bootstrap, grpc or jsonrpc")
+	}
+
+	if value := c.Query("nodeId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("nodeId must be a positive integer")
+		}
+		if filter.NodeType == "" {
+			return filter, fmt.Errorf("nodeId requires nodeType")
+		}
+		filter.NodeID = id
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+)
+
+func TestEventsHandler_Stream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	bus := events.NewBus(logger)
+	h := NewEventsHandler(bus, logger)
+	router := gin.New()
+	router.GET("/events", h.Stream)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events?types=check.completed&nodeType=grpc&nodeId=3")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Unexpected content type: %s", got)
+	}
+
+	// Wait for the handler to subscribe before publishing
+	deadline := time.Now().Add(2 * time.Second)
+	for bus.SubscriberCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	bus.Publish(context.Background(), events.Event{Type: events.CheckCompleted, NodeType: "grpc", NodeID: 4})
+	bus.Publish(context.Background(), events.Event{Type: events.SnapshotCreated})
+	bus.Publish(context.Background(), events.Event{Type: events.CheckCompleted, NodeType: "grpc", NodeID: 3})
+	bus.Close()
+
+	var frames []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "event: ") || strings.HasPrefix(line, "id: ") {
+			frames = append(frames, line)
+		}
+	}
+
+	expected := []string{"id: 3", "event: check.completed"}
+	if strings.Join(frames, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected frames %v, got %v", expected, frames)
+	}
+}
+
+func TestEventsHandler_RejectsInvalidFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	h := NewEventsHandler(events.NewBus(logger), logger)
+	router := gin.New()
+	router.GET("/events", h.Stream)
+
+	for _, query := range []string{"types=unknown", "nodeType=peer", "nodeId=5", "nodeType=grpc&nodeId=x"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
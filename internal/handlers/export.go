@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+)
+
+// exportFlushRows is how many rows an export writes between flushes
+const exportFlushRows = 100
+
+// ExportHandler streams data exports as CSV, NDJSON or GeoJSON
+type ExportHandler struct {
+	exports *services.ExportService
+	logger  *logrus.Logger
+}
+
+// NewExportHandler creates a new data export handler
+func NewExportHandler(exports *services.ExportService, logger *logrus.Logger) *ExportHandler {
+	return &ExportHandler{
+		exports: exports,
+		logger:  logger,
+	}
+}
+
+// ExportNodes handles GET /export/nodes/:type?format= with the filter and
+// sort query parameters of ListNodes
+func (h *ExportHandler) ExportNodes(c *gin.Context) {
+	filter, ok := nodeFilterQuery(c, h.logger)
+	if !ok {
+		return
+	}
+	nodeType := c.Param("type")
+	stream, ok := newExportStream(c, h.logger, nodeType+"-nodes", models.ExportFormatCSV, exportNodeColumns, exportNodeFeature)
+	if !ok {
+		return
+	}
+
+	stream.Finish(h.exports.ExportNodes(c.Request.Context(), nodeType, filter, stream.Write))
+}
+
+// ExportStatuses handles GET /export/status/:type?format=&from=&to= with
+// the filter query parameters of ListNodes. Dates are YYYY-MM-DD; the
+// range defaults to the last 30 days.
+func (h *ExportHandler) ExportStatuses(c *gin.Context) {
+	filter, ok := nodeFilterQuery(c, h.logger)
+	if !ok {
+		return
+	}
+	from, to, ok := statusRangeQuery(c, h.logger)
+	if !ok {
+		return
+	}
+	nodeType := c.Param("type")
+	stream, ok := newExportStream(c, h.logger, nodeType+"-status", models.ExportFormatCSV, exportStatusColumns, nil)
+	if !ok {
+		return
+	}
+
+	stream.Finish(h.exports.ExportStatuses(c.Request.Context(), nodeType, filter, from, to, stream.Write))
+}
+
+// ExportSnapshots handles GET /export/snapshots?format=&network=&from=&to=.
+// Dates are YYYY-MM-DD; without them every snapshot is exported.
+func (h *ExportHandler) ExportSnapshots(c *gin.Context) {
+	from, ok := dateQuery(c, h.logger, "from")
+	if !ok {
+		return
+	}
+	to, ok := dateQuery(c, h.logger, "to")
+	if !ok {
+		return
+	}
+	stream, ok := newExportStream(c, h.logger, "snapshots", models.ExportFormatCSV, exportSnapshotColumns, nil)
+	if !ok {
+		return
+	}
+
+	stream.Finish(h.exports.ExportSnapshots(c.Request.Context(), c.Query("network"), from, to, stream.Write))
+}
+
+// ExportPeers handles GET /export/peers?format= with the network, country,
+// status, search and minScore query parameters of ListNodes
+func (h *ExportHandler) ExportPeers(c *gin.Context) {
+	filter, ok := nodeFilterQuery(c, h.logger)
+	if !ok {
+		return
+	}
+	stream, ok := newExportStream(c, h.logger, "peers", models.ExportFormatCSV, exportPeerColumns, exportPeerFeature)
+	if !ok {
+		return
+	}
+
+	stream.Finish(h.exports.ExportPeers(c.Request.Context(), filter, stream.Write))
+}
+
+// ExportMap handles GET /export/map?format= with the filter query
+// parameters of GetMap. GeoJSON is the default format.
+func (h *ExportHandler) ExportMap(c *gin.Context) {
+	filter := models.MapFilter{
+		Network: c.Query("network"),
+		Type:    c.Query("type"),
+		Country: c.Query("country"),
+		Status:  c.Query("status"),
+		Search:  c.Query("search"),
+	}
+	stream, ok := newExportStream(c, h.logger, "map", models.ExportFormatGeoJSON, exportMapColumns, exportMapFeature)
+	if !ok {
+		return
+	}
+
+	stream.Finish(h.exports.ExportMapNodes(c.Request.Context(), filter, stream.Write))
+}
+
+// exportColumn is a CSV column of an export
+type exportColumn[T any] struct {
+	name  string
+	value func(T) string
+}
+
+// geoFeature is a GeoJSON point feature
+type geoFeature struct {
+	Type       string      `json:"type"`
+	Geometry   geoPoint    `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+type geoPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// newGeoFeature returns a point feature, or nil for a row without a
+// location
+func newGeoFeature(latitude, longitude float64, properties interface{}) *geoFeature {
+	if latitude == 0 && longitude == 0 {
+		return nil
+	}
+	return &geoFeature{
+		Type:       "Feature",
+		Geometry:   geoPoint{Type: "Point", Coordinates: [2]float64{longitude, latitude}},
+		Properties: properties,
+	}
+}
+
+// exportStream writes the rows of an export as they are produced, as CSV,
+// NDJSON or a GeoJSON FeatureCollection. The response starts with the
+// first row, so an export that fails before it is still answered with an
+// error status; a failure after it ends the response early.
+type exportStream[T any] struct {
+	c       *gin.Context
+	logger  *logrus.Logger
+	name    string
+	format  string
+	columns []exportColumn[T]
+	feature func(T) *geoFeature
+	csv     *csv.Writer
+	started bool
+	rows    int
+}
+
+// newExportStream reads the format query parameter, answering 400 for an
+// unknown format or GeoJSON for rows without a location (nil feature)
+func newExportStream[T any](c *gin.Context, logger *logrus.Logger, name, defaultFormat string, columns []exportColumn[T], feature func(T) *geoFeature) (*exportStream[T], bool) {
+	format := c.DefaultQuery("format", defaultFormat)
+	switch {
+	case format == models.ExportFormatCSV, format == models.ExportFormatNDJSON:
+	case format == models.ExportFormatGeoJSON && feature != nil:
+	default:
+		formats := "csv or ndjson"
+		if feature != nil {
+			formats = "csv, ndjson or geojson"
+		}
+		respondError(c, logger, models.NewValidationError("invalid format", "format must be "+formats))
+		return nil, false
+	}
+
+	return &exportStream[T]{
+		c:       c,
+		logger:  logger,
+		name:    name,
+		format:  format,
+		columns: columns,
+		feature: feature,
+	}, true
+}
+
+// start sends the headers and whatever precedes the first row
+func (s *exportStream[T]) start() error {
+	s.started = true
+
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	switch s.format {
+	case models.ExportFormatNDJSON:
+		contentType, extension = "application/x-ndjson", "ndjson"
+	case models.ExportFormatGeoJSON:
+		contentType, extension = "application/geo+json", "geojson"
+	}
+	s.c.Header("Content-Type", contentType)
+	s.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, s.name, extension))
+	s.c.Header("X-Accel-Buffering", "no")
+	s.c.Status(http.StatusOK)
+
+	switch s.format {
+	case models.ExportFormatCSV:
+		s.csv = csv.NewWriter(s.c.Writer)
+		header := make([]string, len(s.columns))
+		for i, column := range s.columns {
+			header[i] = column.name
+		}
+		return s.csv.Write(header)
+	case models.ExportFormatGeoJSON:
+		_, err := s.c.Writer.WriteString(`{"type":"FeatureCollection","features":[`)
+		return err
+	}
+	return nil
+}
+
+// Write writes one row. Its error, such as a client that went away, stops
+// the export.
+func (s *exportStream[T]) Write(row T) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	switch s.format {
+	case models.ExportFormatCSV:
+		record := make([]string, len(s.columns))
+		for i, column := range s.columns {
+			record[i] = column.value(row)
+		}
+		if err := s.csv.Write(record); err != nil {
+			return err
+		}
+	case models.ExportFormatNDJSON:
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if _, err := s.c.Writer.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	case models.ExportFormatGeoJSON:
+		feature := s.feature(row)
+		if feature == nil {
+			return nil
+		}
+		data, err := json.Marshal(feature)
+		if err != nil {
+			return err
+		}
+		if s.rows > 0 {
+			data = append([]byte{','}, data...)
+		}
+		if _, err := s.c.Writer.Write(data); err != nil {
+			return err
+		}
+	}
+
+	s.rows++
+	if s.rows%exportFlushRows == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// Finish completes the export, or reports err: as an error response when
+// no row was sent yet, otherwise by ending the response early
+func (s *exportStream[T]) Finish(err error) {
+	if err != nil {
+		if !s.started {
+			respondError(s.c, s.logger, err)
+			return
+		}
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"export": s.name,
+			"rows":   s.rows,
+		}).Warn("Export ended early")
+		return
+	}
+
+	if !s.started {
+		if err := s.start(); err != nil {
+			return
+		}
+	}
+	if s.format == models.ExportFormatGeoJSON {
+		if _, err := s.c.Writer.WriteString("]}\n"); err != nil {
+			return
+		}
+	}
+	s.flush()
+}
+
+func (s *exportStream[T]) flush() error {
+	if s.csv != nil {
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+func formatInt(value int) string {
+	return strconv.Itoa(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+var exportNodeColumns = []exportColumn[*models.ExportNode]{
+	{"type", func(n *models.ExportNode) string { return n.Type }},
+	{"id", func(n *models.ExportNode) string { return formatInt(n.ID) }},
+	{"name", func(n *models.ExportNode) string { return n.Name }},
+	{"address", func(n *models.ExportNode) string { return n.Address }},
+	{"network", func(n *models.ExportNode) string { return n.Network }},
+	{"email", func(n *models.ExportNode) string { return n.Email }},
+	{"website", func(n *models.ExportNode) string { return n.Website }},
+	{"overallScore", func(n *models.ExportNode) string { return formatFloat(n.OverallScore) }},
+	{"isVerified", func(n *models.ExportNode) string { return strconv.FormatBool(n.IsVerified) }},
+	{"country", func(n *models.ExportNode) string { return n.Country }},
+	{"countryCode", func(n *models.ExportNode) string { return n.CountryCode }},
+	{"city", func(n *models.ExportNode) string { return n.City }},
+	{"latitude", func(n *models.ExportNode) string { return formatFloat(n.Latitude) }},
+	{"longitude", func(n *models.ExportNode) string { return formatFloat(n.Longitude) }},
+	{"createdAt", func(n *models.ExportNode) string { return formatTime(n.CreatedAt) }},
+	{"updatedAt", func(n *models.ExportNode) string { return formatTime(n.UpdatedAt) }},
+}
+
+func exportNodeFeature(n *models.ExportNode) *geoFeature {
+	return newGeoFeature(n.Latitude, n.Longitude, n)
+}
+
+var exportStatusColumns = []exportColumn[*models.ExportStatus]{
+	{"type", func(s *models.ExportStatus) string { return s.Type }},
+	{"nodeId", func(s *models.ExportStatus) string { return formatInt(s.NodeID) }},
+	{"name", func(s *models.ExportStatus) string { return s.Name }},
+	{"network", func(s *models.ExportStatus) string { return s.Network }},
+	{"date", func(s *models.ExportStatus) string { return s.Date.Format("2006-01-02") }},
+	{"color", func(s *models.ExportStatus) string { return formatInt(s.Color) }},
+	{"attempts", func(s *models.ExportStatus) string { return formatInt(s.Attempts) }},
+	{"success", func(s *models.ExportStatus) string { return strconv.FormatBool(s.Success) }},
+	{"responseTimeMs", func(s *models.ExportStatus) string { return formatInt(s.ResponseTimeMs) }},
+	{"errorMsg", func(s *models.ExportStatus) string { return s.ErrorMsg }},
+	{"errorClass", func(s *models.ExportStatus) string { return string(s.ErrorClass) }},
+}
+
+var exportSnapshotColumns = []exportColumn[*models.NetworkSnapshot]{
+	{"id", func(s *models.NetworkSnapshot) string { return formatInt(s.ID) }},
+	{"network", func(s *models.NetworkSnapshot) string { return s.Network }},
+	{"timestamp", func(s *models.NetworkSnapshot) string { return formatTime(s.Timestamp) }},
+	{"totalNodes", func(s *models.NetworkSnapshot) string { return formatInt(s.TotalNodes) }},
+	{"reachableNodes", func(s *models.NetworkSnapshot) string { return formatInt(s.ReachableNodes) }},
+	{"countriesCount", func(s *models.NetworkSnapshot) string { return formatInt(s.CountriesCount) }},
+	{"grpcNodes", func(s *models.NetworkSnapshot) string { return formatInt(s.GRPCNodes) }},
+	{"jsonrpcNodes", func(s *models.NetworkSnapshot) string { return formatInt(s.JSONRPCNodes) }},
+	{"bootstrapNodes", func(s *models.NetworkSnapshot) string { return formatInt(s.BootstrapNodes) }},
+}
+
+var exportPeerColumns = []exportColumn[*models.ReachablePeer]{
+	{"id", func(p *models.ReachablePeer) string { return formatInt(p.ID) }},
+	{"network", func(p *models.ReachablePeer) string { return p.Network }},
+	{"peerId", func(p *models.ReachablePeer) string { return p.PeerID }},
+	{"address", func(p *models.ReachablePeer) string { return p.Address }},
+	{"protocol", func(p *models.ReachablePeer) string { return p.Protocol }},
+	{"userAgent", func(p *models.ReachablePeer) string { return p.UserAgent }},
+	{"lastSeen", func(p *models.ReachablePeer) string { return formatTime(p.LastSeen) }},
+	{"firstSeen", func(p *models.ReachablePeer) string { return formatTime(p.FirstSeen) }},
+	{"ipAddress", func(p *models.ReachablePeer) string { return p.IPAddress }},
+	{"country", func(p *models.ReachablePeer) string { return p.Country }},
+	{"countryCode", func(p *models.ReachablePeer) string { return p.CountryCode }},
+	{"city", func(p *models.ReachablePeer) string { return p.City }},
+	{"latitude", func(p *models.ReachablePeer) string { return formatFloat(p.Latitude) }},
+	{"longitude", func(p *models.ReachablePeer) string { return formatFloat(p.Longitude) }},
+	{"timezone", func(p *models.ReachablePeer) string { return p.Timezone }},
+	{"asn", func(p *models.ReachablePeer) string { return p.ASN }},
+	{"organization", func(p *models.ReachablePeer) string { return p.Organization }},
+	{"isReachable", func(p *models.ReachablePeer) string { return strconv.FormatBool(p.IsReachable) }},
+	{"connectionAttempts", func(p *models.ReachablePeer) string { return formatInt(p.ConnectionAttempts) }},
+	{"successfulConnections", func(p *models.ReachablePeer) string { return formatInt(p.SuccessfulConnections) }},
+	{"overallScore", func(p *models.ReachablePeer) string { return formatFloat(p.OverallScore) }},
+}
+
+func exportPeerFeature(p *models.ReachablePeer) *geoFeature {
+	return newGeoFeature(p.Latitude, p.Longitude, p)
+}
+
+var exportMapColumns = []exportColumn[*models.MapNode]{
+	{"type", func(n *models.MapNode) string { return n.Type }},
+	{"id", func(n *models.MapNode) string { return formatInt(n.ID) }},
+	{"name", func(n *models.MapNode) string { return n.Name }},
+	{"latitude", func(n *models.MapNode) string { return formatFloat(n.Coordinates[0]) }},
+	{"longitude", func(n *models.MapNode) string { return formatFloat(n.Coordinates[1]) }},
+	{"status", func(n *models.MapNode) string { return n.Status }},
+	{"country", func(n *models.MapNode) string { return n.Country }},
+	{"city", func(n *models.MapNode) string { return n.City }},
+}
+
+// exportMapFeature places a map node at its [latitude, longitude]
+// coordinates, which GeoJSON orders longitude first
+func exportMapFeature(n *models.MapNode) *geoFeature {
+	return newGeoFeature(n.Coordinates[0], n.Coordinates[1], map[string]interface{}{
+		"type":    n.Type,
+		"id":      n.ID,
+		"name":    n.Name,
+		"status":  n.Status,
+		"country": n.Country,
+		"city":    n.City,
+	})
+}
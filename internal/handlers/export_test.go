@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// newTestExportRouter serves the map nodes through an export stream and
+// fails with exportErr after them
+func newTestExportRouter(nodes []*models.MapNode, exportErr error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	router := gin.New()
+	router.GET("/export", func(c *gin.Context) {
+		stream, ok := newExportStream(c, logger, "map", models.ExportFormatGeoJSON, exportMapColumns, exportMapFeature)
+		if !ok {
+			return
+		}
+		for _, node := range nodes {
+			if err := stream.Write(node); err != nil {
+				stream.Finish(err)
+				return
+			}
+		}
+		stream.Finish(exportErr)
+	})
+	return router
+}
+
+func TestExportStream(t *testing.T) {
+	nodes := []*models.MapNode{
+		{ID: 1, Name: "a, \"b\"", Type: "grpc", Coordinates: []float64{52.52, 13.4}, Status: "online", Country: "Germany"},
+		{ID: 2, Name: "unlocated", Type: "peer", Coordinates: []float64{0, 0}, Status: "offline"},
+	}
+	router := newTestExportRouter(nodes, nil)
+
+	tests := []struct {
+		format      string
+		contentType string
+		body        string
+	}{
+		{
+			format:      "csv",
+			contentType: "text/csv; charset=utf-8",
+			body:        "type,id,name,latitude,longitude,status,country,city\ngrpc,1,\"a, \"\"b\"\"\",52.52,13.4,online,Germany,\npeer,2,unlocated,0,0,offline,,\n",
+		},
+		{
+			format:      "ndjson",
+			contentType: "application/x-ndjson",
+			body: `{"id":1,"name":"a, \"b\"","type":"grpc","coordinates":[52.52,13.4],"status":"online","country":"Germany"}` + "\n" +
+				`{"id":2,"name":"unlocated","type":"peer","coordinates":[0,0],"status":"offline","country":""}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export?format="+tt.format, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected %s, got %s", tt.contentType, got)
+			}
+			if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="map.`+tt.format+`"` {
+				t.Errorf("Unexpected Content-Disposition: %s", got)
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("Unexpected body:\n%s", rec.Body.String())
+			}
+		})
+	}
+
+	// GeoJSON is the default and skips nodes without a location
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("Expected a GeoJSON document: %v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 1 {
+		t.Fatalf("Expected a collection of 1 feature, got %+v", collection)
+	}
+	feature := collection.Features[0]
+	if feature.Geometry.Coordinates[0] != 13.4 || feature.Geometry.Coordinates[1] != 52.52 {
+		t.Errorf("Expected longitude first, got %v", feature.Geometry.Coordinates)
+	}
+	if feature.Properties["name"] != "a, \"b\"" {
+		t.Errorf("Unexpected properties: %v", feature.Properties)
+	}
+}
+
+func TestExportStream_Errors(t *testing.T) {
+	// An empty export is a document without rows
+	rec := httptest.NewRecorder()
+	newTestExportRouter(nil, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"type\":\"FeatureCollection\",\"features\":[]}\n" {
+		t.Errorf("Expected an empty collection, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A failure before the first row is answered with its status
+	rec = httptest.NewRecorder()
+	newTestExportRouter(nil, models.NewValidationError("invalid map filter", "bad")).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export?format=csv", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+
+	// A failure after it ends the document early
+	nodes := []*models.MapNode{{ID: 1, Type: "grpc", Coordinates: []float64{1, 2}}}
+	rec = httptest.NewRecorder()
+	newTestExportRouter(nodes, errors.New("connection reset")).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+	if rec.Code != http.StatusOK || strings.HasSuffix(rec.Body.String(), "]}\n") {
+		t.Errorf("Expected a truncated document, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	newTestExportRouter(nil, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export?format=xml", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", rec.Code)
+	}
+}
@@ -40,7 +40,7 @@ func (h *GRPCHandler) GetGRPCServers(c *gin.Context) {
 func (h *GRPCHandler) SyncGRPCServers(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	err := h.monitor.SyncGRPCServers(ctx)
+	run, err := h.monitor.SyncGRPCServers(ctx, c.Query("dry_run") == "true")
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to sync gRPC servers")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -50,16 +50,7 @@ func (h *GRPCHandler) SyncGRPCServers(c *gin.Context) {
 		return
 	}
 
-	count, err := h.monitor.GetGRPCServerCount(ctx)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to get server count")
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message":       "gRPC servers synced successfully",
-		"total_servers": count,
-		"timestamp":     time.Now().UTC(),
-	})
+	c.JSON(http.StatusOK, run)
 }
 
 func (h *GRPCHandler) CheckAllServers(c *gin.Context) {
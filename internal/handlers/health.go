@@ -7,17 +7,21 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
 )
 
 type HealthHandler struct {
 	db      *sql.DB
+	leader  *services.LeaderElector
 	logger  *logrus.Logger
 	version string
 }
 
-func NewHealthHandler(db *sql.DB, logger *logrus.Logger, version string) *HealthHandler {
+func NewHealthHandler(db *sql.DB, leader *services.LeaderElector, logger *logrus.Logger, version string) *HealthHandler {
 	return &HealthHandler{
 		db:      db,
+		leader:  leader,
 		logger:  logger,
 		version: version,
 	}
@@ -35,6 +39,7 @@ func (h *HealthHandler) Health(c *gin.Context) {
 			"timestamp": time.Now().UTC(),
 			"version":   h.version,
 			"error":     "database unavailable",
+			"leader":    h.leader.Status(),
 		})
 		return
 	}
@@ -43,5 +48,6 @@ func (h *HealthHandler) Health(c *gin.Context) {
 		"status":    "healthy",
 		"timestamp": time.Now().UTC(),
 		"version":   h.version,
+		"leader":    h.leader.Status(),
 	})
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/middleware"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/rpc"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+)
+
+// stubMaintenanceRepository lists one window for every node
+type stubMaintenanceRepository struct {
+	repositories.MaintenanceRepository
+}
+
+func (r *stubMaintenanceRepository) ListForNode(ctx context.Context, nodeType string, nodeID int, endsAfter time.Time) ([]*models.MaintenanceWindow, error) {
+	return []*models.MaintenanceWindow{{ID: 3, NodeType: nodeType, NodeID: nodeID, Reason: "upgrade"}}, nil
+}
+
+// newTestJSONRPCRouter mounts the public and admin registries the way the
+// server does
+func newTestJSONRPCRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	maintenance := services.NewMaintenanceService(&stubMaintenanceRepository{}, nil, nil, nil, logger)
+	service := services.NewJsonRPCServicePhase2(nil, nil, nil, nil, maintenance, nil, nil, nil, nil, nil, nil, logger)
+
+	registry := rpc.NewRegistry("test", "1.0.0", logger)
+	service.RegisterMethods(registry)
+	adminRegistry := rpc.NewRegistry("test admin", "1.0.0", logger)
+	service.RegisterAdminMethods(adminRegistry)
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.POST("/json-rpc", NewJsonRPCHandler(registry, logger).HandleRequest)
+	admin := api.Group("/admin", middleware.AdminAuth([]string{"s3cret"}, logger))
+	admin.POST("/json-rpc", NewJsonRPCHandler(adminRegistry, logger).HandleRequest)
+	admin.GET("/openrpc.json", NewJsonRPCHandler(adminRegistry, logger).OpenRPC)
+	return router
+}
+
+func TestJsonRPCHandler_AdminMethods(t *testing.T) {
+	router := newTestJSONRPCRouter()
+	body := `{"jsonrpc":"2.0","method":"getMaintenanceWindows","params":{"nodeType":"grpc","nodeId":7},"id":1}`
+
+	call := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call("/api/v1/admin/json-rpc", "s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var response struct {
+		Result []*models.MaintenanceWindow `json:"result"`
+		Error  *rpc.Error                  `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if response.Error != nil || len(response.Result) != 1 || response.Result[0].NodeID != 7 {
+		t.Errorf("Expected the node's maintenance window, got %s", rec.Body.String())
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		if rec := call("/api/v1/admin/json-rpc", token); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for token %q, got %d", token, rec.Code)
+		}
+	}
+
+	// Admin methods are not served on the public endpoint
+	rec = call("/api/v1/json-rpc", "s3cret")
+	if !strings.Contains(rec.Body.String(), `"code":-32601`) {
+		t.Errorf("Expected method not found on the public endpoint, got %s", rec.Body.String())
+	}
+
+	// Neither are the methods that run checks and syncs on demand
+	for _, method := range []string{"checkAllNodes", "checkAllBootstrapNodes", "checkAllJSONRPCNodes", "syncNodes", "syncBootstrapNodes", "updateGeoLocations"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/json-rpc", strings.NewReader(`{"jsonrpc":"2.0","method":"`+method+`","id":1}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if !strings.Contains(rec.Body.String(), `"code":-32601`) {
+			t.Errorf("Expected %s not to be served on the public endpoint, got %s", method, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/openrpc.json", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"url":"/api/v1/admin/json-rpc"`) {
+		t.Errorf("Expected the admin endpoint as OpenRPC server, got %s", rec.Body.String())
+	}
+}
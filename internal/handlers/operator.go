@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+)
+
+// OperatorHandler serves the operator portal API. Node routes take the
+// session token as "Authorization: Bearer <token>".
+type OperatorHandler struct {
+	service *services.OperatorService
+	logger  *logrus.Logger
+}
+
+// NewOperatorHandler creates a new operator portal handler
+func NewOperatorHandler(service *services.OperatorService, logger *logrus.Logger) *OperatorHandler {
+	return &OperatorHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RequestLoginLink handles POST /operator/login/email
+func (h *OperatorHandler) RequestLoginLink(c *gin.Context) {
+	var req models.OperatorLoginRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.service.RequestLoginLink(c.Request.Context(), req.Email); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If nodes are registered with this address, a login link has been sent to it.",
+	})
+}
+
+// LoginWithLink handles GET /operator/login/email?token=..., the link sent
+// by RequestLoginLink
+func (h *OperatorHandler) LoginWithLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondError(c, h.logger, models.NewValidationError("invalid login link", "token is required"))
+		return
+	}
+
+	session, err := h.service.LoginWithLink(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Challenge handles POST /operator/login/challenge
+func (h *OperatorHandler) Challenge(c *gin.Context) {
+	var req models.OperatorChallengeRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	challenge, err := h.service.Challenge(c.Request.Context(), req.NodeType, req.NodeID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, challenge)
+}
+
+// LoginWithKey handles POST /operator/login/key
+func (h *OperatorHandler) LoginWithKey(c *gin.Context) {
+	var req models.OperatorKeyLoginRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	session, err := h.service.LoginWithKey(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Logout handles DELETE /operator/session
+func (h *OperatorHandler) Logout(c *gin.Context) {
+	token, ok := h.bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), token); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListNodes handles GET /operator/nodes
+func (h *OperatorHandler) ListNodes(c *gin.Context) {
+	session, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	nodes, err := h.service.ListNodes(c.Request.Context(), session)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, nodes)
+}
+
+// GetNode handles GET /operator/nodes/:type/:id
+func (h *OperatorHandler) GetNode(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+
+	node, err := h.service.GetNode(c.Request.Context(), session, nodeType, nodeID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, node)
+}
+
+// UpdateNode handles PATCH /operator/nodes/:type/:id
+func (h *OperatorHandler) UpdateNode(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+	var req models.NodeUpdateRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	node, err := h.service.UpdateNode(c.Request.Context(), session, nodeType, nodeID, &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, node)
+}
+
+// Deregister handles DELETE /operator/nodes/:type/:id
+func (h *OperatorHandler) Deregister(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Deregister(c.Request.Context(), session, nodeType, nodeID); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PauseMonitoring handles POST /operator/nodes/:type/:id/pause
+func (h *OperatorHandler) PauseMonitoring(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+	var req models.PauseMonitoringRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	window, err := h.service.PauseMonitoring(c.Request.Context(), session, nodeType, nodeID, &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusCreated, window)
+}
+
+// ResumeMonitoring handles DELETE /operator/nodes/:type/:id/pause
+func (h *OperatorHandler) ResumeMonitoring(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.ResumeMonitoring(c.Request.Context(), session, nodeType, nodeID); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListMaintenance handles GET /operator/nodes/:type/:id/maintenance
+func (h *OperatorHandler) ListMaintenance(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+
+	windows, err := h.service.ListMaintenance(c.Request.Context(), session, nodeType, nodeID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, windows)
+}
+
+// CancelMaintenance handles DELETE /operator/nodes/:type/:id/maintenance/:windowId
+func (h *OperatorHandler) CancelMaintenance(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+	windowID, err := strconv.Atoi(c.Param("windowId"))
+	if err != nil || windowID <= 0 {
+		respondError(c, h.logger, models.NewValidationError("invalid maintenance window id", "windowId must be a positive integer"))
+		return
+	}
+
+	if err := h.service.CancelMaintenance(c.Request.Context(), session, nodeType, nodeID, windowID); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetAuditLog handles GET /operator/nodes/:type/:id/audit
+func (h *OperatorHandler) GetAuditLog(c *gin.Context) {
+	session, nodeType, nodeID, ok := h.authorizeNode(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.GetAuditLog(c.Request.Context(), session, nodeType, nodeID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// authorizeNode authenticates the request and parses the node route
+// parameters. Whether the session may manage the node is checked by the
+// service.
+func (h *OperatorHandler) authorizeNode(c *gin.Context) (*models.OperatorToken, string, int, bool) {
+	session, ok := h.authenticate(c)
+	if !ok {
+		return nil, "", 0, false
+	}
+
+	nodeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || nodeID <= 0 {
+		respondError(c, h.logger, models.NewValidationError("invalid node id", "id must be a positive integer"))
+		return nil, "", 0, false
+	}
+	return session, c.Param("type"), nodeID, true
+}
+
+// authenticate resolves the bearer token to a session
+func (h *OperatorHandler) authenticate(c *gin.Context) (*models.OperatorToken, bool) {
+	token, ok := h.bearerToken(c)
+	if !ok {
+		return nil, false
+	}
+
+	session, err := h.service.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return nil, false
+	}
+	return session, true
+}
+
+func (h *OperatorHandler) bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || strings.TrimSpace(token) == "" {
+		respondError(c, h.logger, models.NewUnauthorizedError("missing bearer token"))
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func (h *OperatorHandler) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		respondError(c, h.logger, models.NewValidationError("invalid request body", err.Error()))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+)
+
+// maxProbeReportSize bounds the body a probe agent may submit
+const maxProbeReportSize = 4 << 20
+
+type ProbeHandler struct {
+	service *services.ProbeService
+	logger  *logrus.Logger
+}
+
+func NewProbeHandler(service *services.ProbeService, logger *logrus.Logger) *ProbeHandler {
+	return &ProbeHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetTargets returns the nodes a probe agent should check
+func (h *ProbeHandler) GetTargets(c *gin.Context) {
+	if _, _, ok := h.authenticate(c); !ok {
+		return
+	}
+
+	targets, err := h.service.GetTargets(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get probe targets")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve probe targets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+// SubmitResults accepts a signed report from a probe agent
+func (h *ProbeHandler) SubmitResults(c *gin.Context) {
+	agent, body, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+
+	var report models.ProbeReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid probe report",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.SubmitReport(c.Request.Context(), agent, &report)
+	if err != nil {
+		h.logger.WithError(err).WithField("agent_id", agent.ID).Error("Failed to store probe report")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to store probe report",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// authenticate reads the request body and verifies the agent signature over it
+func (h *ProbeHandler) authenticate(c *gin.Context) (*services.ProbeAgentIdentity, []byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxProbeReportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read request body",
+		})
+		return nil, nil, false
+	}
+
+	agent, err := h.service.Authenticate(
+		c.Request.Context(),
+		c.GetHeader(services.ProbeHeaderAgent),
+		c.GetHeader(services.ProbeHeaderTimestamp),
+		c.GetHeader(services.ProbeHeaderNonce),
+		c.GetHeader(services.ProbeHeaderSignature),
+		body,
+	)
+	if err != nil {
+		h.logger.WithError(err).WithField("client_ip", c.ClientIP()).Warn("Rejected probe agent request")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Unauthorized probe agent",
+		})
+		return nil, nil, false
+	}
+
+	return agent, body, true
+}
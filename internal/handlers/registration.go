@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+)
+
+// RegistrationHandler serves the links emailed to registrants
+type RegistrationHandler struct {
+	service *services.RegistrationService
+	logger  *logrus.Logger
+}
+
+// NewRegistrationHandler creates a new registration handler
+func NewRegistrationHandler(service *services.RegistrationService, logger *logrus.Logger) *RegistrationHandler {
+	return &RegistrationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Confirm handles GET /registrations/confirm?token=..., sending the
+// registration to review
+func (h *RegistrationHandler) Confirm(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondError(c, h.logger, models.NewValidationError("invalid confirmation link", "token is required"))
+		return
+	}
+
+	registration, err := h.service.ConfirmRegistration(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RegistrationResponse{
+		ID:      registration.ID,
+		Status:  registration.Status,
+		Message: "Your email address is confirmed and the registration is pending review.",
+	})
+}
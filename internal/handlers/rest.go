@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
+)
+
+// restCacheControl lets browsers and CDNs reuse read responses briefly
+const restCacheControl = "public, max-age=60"
+
+// RESTHandler serves read-only REST resources for nodes and network data
+type RESTHandler struct {
+	nodes        *services.NodeQueryService
+	networkStats *services.NetworkStatsService
+	logger       *logrus.Logger
+}
+
+// NewRESTHandler creates a new REST resource handler
+func NewRESTHandler(nodes *services.NodeQueryService, networkStats *services.NetworkStatsService, logger *logrus.Logger) *RESTHandler {
+	return &RESTHandler{
+		nodes:        nodes,
+		networkStats: networkStats,
+		logger:       logger,
+	}
+}
+
+// ListNodes handles GET /nodes/:type with the filter, sort and cursor
+// query parameters of models.NodeFilter
+func (h *RESTHandler) ListNodes(c *gin.Context) {
+	filter, ok := nodeFilterQuery(c, h.logger)
+	if !ok {
+		return
+	}
+
+	page, err := h.nodes.ListNodes(c.Request.Context(), c.Param("type"), filter)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.respondCached(c, time.Time{}, page)
+}
+
+// GetNode handles GET /nodes/:type/:id
+func (h *RESTHandler) GetNode(c *gin.Context) {
+	id, ok := h.nodeID(c)
+	if !ok {
+		return
+	}
+
+	node, updatedAt, err := h.nodes.GetNode(c.Request.Context(), c.Param("type"), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.respondCached(c, updatedAt, node)
+}
+
+// GetNodeStatus handles GET /nodes/:type/:id/status?from=&to=. Dates are
+// YYYY-MM-DD; the range defaults to the last 30 days.
+func (h *RESTHandler) GetNodeStatus(c *gin.Context) {
+	id, ok := h.nodeID(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := statusRangeQuery(c, h.logger)
+	if !ok {
+		return
+	}
+
+	statuses, lastModified, err := h.nodes.GetNodeStatuses(c.Request.Context(), c.Param("type"), id, from, to)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.respondCached(c, lastModified, statuses)
+}
+
+// GetStats handles GET /stats?network=
+func (h *RESTHandler) GetStats(c *gin.Context) {
+	network, ok := h.networkQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.networkStats.GetNetworkStats(c.Request.Context(), network)
+	if err != nil {
+		h.respondError(c, models.NewDatabaseError("failed to get network stats", err))
+		return
+	}
+	h.respondCached(c, time.Time{}, stats)
+}
+
+// GetMap handles GET /map with the query parameters of models.MapFilter
+func (h *RESTHandler) GetMap(c *gin.Context) {
+	filter := models.MapFilter{
+		Network: c.Query("network"),
+		Type:    c.Query("type"),
+		Country: c.Query("country"),
+		Status:  c.Query("status"),
+		Search:  c.Query("search"),
+		Cursor:  c.Query("cursor"),
+	}
+
+	var ok bool
+	if filter.Limit, ok = intQuery(c, h.logger, "limit"); !ok {
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		h.respondError(c, models.NewValidationError("invalid map filter", err.Error()))
+		return
+	}
+
+	page, err := h.networkStats.ListMapNodes(c.Request.Context(), filter)
+	if err != nil {
+		h.respondError(c, models.NewDatabaseError("failed to get map nodes", err))
+		return
+	}
+	h.respondCached(c, time.Time{}, page)
+}
+
+// GetSnapshots handles GET /snapshots?network=&limit=
+func (h *RESTHandler) GetSnapshots(c *gin.Context) {
+	network, ok := h.networkQuery(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			h.respondError(c, models.NewValidationError("invalid limit", "limit must be between 1 and 100"))
+			return
+		}
+		limit = parsed
+	}
+
+	snapshots, err := h.networkStats.GetSnapshots(c.Request.Context(), network, limit)
+	if err != nil {
+		h.respondError(c, models.NewDatabaseError("failed to get snapshots", err))
+		return
+	}
+
+	var lastModified time.Time
+	for _, snapshot := range snapshots {
+		if snapshot.Timestamp.After(lastModified) {
+			lastModified = snapshot.Timestamp
+		}
+	}
+	if snapshots == nil {
+		snapshots = []*models.NetworkSnapshot{}
+	}
+	h.respondCached(c, lastModified, snapshots)
+}
+
+// nodeFilterQuery parses the query parameters of models.NodeFilter,
+// answering 400 when one is malformed
+func nodeFilterQuery(c *gin.Context, logger *logrus.Logger) (models.NodeFilter, bool) {
+	filter := models.NodeFilter{
+		Network: c.Query("network"),
+		Country: c.Query("country"),
+		Status:  c.Query("status"),
+		Search:  c.Query("search"),
+		Sort:    c.Query("sort"),
+		Order:   c.Query("order"),
+		Cursor:  c.Query("cursor"),
+	}
+
+	var ok bool
+	if filter.MinScore, ok = floatQuery(c, logger, "minScore"); !ok {
+		return filter, false
+	}
+	if filter.Limit, ok = intQuery(c, logger, "limit"); !ok {
+		return filter, false
+	}
+	return filter, true
+}
+
+// statusRangeQuery parses the from and to dates of a status history query.
+// Dates are YYYY-MM-DD; the range defaults to the last 30 days.
+func statusRangeQuery(c *gin.Context, logger *logrus.Logger) (time.Time, time.Time, bool) {
+	to, ok := dateQuery(c, logger, "to")
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	from, ok := dateQuery(c, logger, "from")
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -29)
+	}
+	return from, to, true
+}
+
+// dateQuery parses an optional YYYY-MM-DD query parameter, answering 400
+// when it is malformed
+func dateQuery(c *gin.Context, logger *logrus.Logger, name string) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, true
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		respondError(c, logger, models.NewValidationError("invalid "+name+" date", "expected YYYY-MM-DD"))
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+func (h *RESTHandler) nodeID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, models.NewValidationError("invalid node id", "id must be a positive integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+// intQuery parses an optional integer query parameter, answering 400 when
+// it is malformed
+func intQuery(c *gin.Context, logger *logrus.Logger, name string) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		respondError(c, logger, models.NewValidationError("invalid "+name, name+" must be an integer"))
+		return 0, false
+	}
+	return parsed, true
+}
+
+// networkQuery returns the network query parameter, mainnet by default,
+// answering 400 when it is malformed
+func (h *RESTHandler) networkQuery(c *gin.Context) (string, bool) {
+	network := models.NetworkOrDefault(c.Query("network"))
+	if err := models.ValidateNetworkName(network); err != nil {
+		h.respondError(c, models.NewValidationError("invalid network", err.Error()))
+		return "", false
+	}
+	return network, true
+}
+
+// floatQuery parses an optional number query parameter, answering 400 when
+// it is malformed
+func floatQuery(c *gin.Context, logger *logrus.Logger, name string) (float64, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		respondError(c, logger, models.NewValidationError("invalid "+name, name+" must be a number"))
+		return 0, false
+	}
+	return parsed, true
+}
+
+// respondCached writes body with an ETag derived from its encoding and, when
+// known, a Last-Modified header. Conditional requests that still match get
+// 304 Not Modified without a body.
+func (h *RESTHandler) respondCached(c *gin.Context, lastModified time.Time, body interface{}) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		h.respondError(c, models.NewInternalError("failed to encode response", err))
+		return
+	}
+
+	sum := sha256.Sum256(encoded)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", restCacheControl)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", encoded)
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tag was sent, as RFC 9110 requires
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+func (h *RESTHandler) respondError(c *gin.Context, err error) {
+	respondError(c, h.logger, err)
+}
+
+// respondError writes an AppError body with its status code. Errors that
+// are not AppErrors are reported as internal errors without their details.
+func respondError(c *gin.Context, logger *logrus.Logger, err error) {
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) {
+		appErr = models.NewInternalError("internal server error", err)
+	}
+
+	if appErr.StatusCode >= http.StatusInternalServerError {
+		logger.WithError(err).WithField("path", c.Request.URL.Path).Error("REST request failed")
+	}
+
+	c.AbortWithStatusJSON(appErr.StatusCode, appErr)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func newTestRESTRouter(lastModified time.Time, body interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	h := &RESTHandler{logger: logger}
+	router := gin.New()
+	router.GET("/cached", func(c *gin.Context) {
+		h.respondCached(c, lastModified, body)
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		h.respondError(c, models.NewNotFoundError("grpc node 7 not found"))
+	})
+	return router
+}
+
+func TestRESTHandler_RespondCached(t *testing.T) {
+	lastModified := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	router := newTestRESTRouter(lastModified, []string{"a", "b"})
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/cached", nil))
+
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag header")
+	}
+	if got := first.Header().Get("Last-Modified"); got != "Thu, 01 Oct 2026 12:00:00 GMT" {
+		t.Errorf("Unexpected Last-Modified: %s", got)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		expect int
+	}{
+		{"Matching ETag", "If-None-Match", etag, http.StatusNotModified},
+		{"Weak matching ETag", "If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		{"Stale ETag", "If-None-Match", `"other"`, http.StatusOK},
+		{"Not modified since", "If-Modified-Since", "Thu, 01 Oct 2026 12:00:00 GMT", http.StatusNotModified},
+		{"Modified since", "If-Modified-Since", "Wed, 30 Sep 2026 12:00:00 GMT", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/cached", nil)
+			req.Header.Set(tt.header, tt.value)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expect {
+				t.Errorf("Expected %d, got %d", tt.expect, rec.Code)
+			}
+			if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("Expected empty body on 304, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRESTHandler_RespondError(t *testing.T) {
+	router := newTestRESTRouter(time.Time{}, nil)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404, got %d", rec.Code)
+	}
+
+	var body models.AppError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if body.Code != models.ErrCodeNotFound || body.Message != "grpc node 7 not found" {
+		t.Errorf("Unexpected error body: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,127 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a send would exceed the configured limits
+var ErrRateLimited = errors.New("mail rate limit exceeded")
+
+// Limits caps how many messages are sent per hour. Zero disables a cap.
+type Limits struct {
+	PerRecipient int
+	Total        int
+}
+
+// Mailer renders templates and sends them through a transport
+type Mailer struct {
+	transport Transport
+	templates *Templates
+	from      string
+	limiter   *limiter
+}
+
+// NewMailer creates a mailer sending from the given address
+func NewMailer(transport Transport, templates *Templates, from string, limits Limits) *Mailer {
+	return &Mailer{
+		transport: transport,
+		templates: templates,
+		from:      from,
+		limiter:   newLimiter(limits, time.Hour),
+	}
+}
+
+// Send renders the named template and sends it to to
+func (m *Mailer) Send(ctx context.Context, to, name string, data any) error {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+
+	msg, err := m.templates.Render(name, to, data)
+	if err != nil {
+		return err
+	}
+	msg.From = m.from
+
+	if !m.limiter.allow(strings.ToLower(to)) {
+		return ErrRateLimited
+	}
+	return m.transport.Send(ctx, msg)
+}
+
+// maxTrackedRecipients is how many recipients the limiter tracks before it
+// sweeps expired entries
+const maxTrackedRecipients = 1024
+
+// limiter counts sends in a sliding window, per recipient and overall
+type limiter struct {
+	limits Limits
+	window time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	total []time.Time
+	sent  map[string][]time.Time
+}
+
+func newLimiter(limits Limits, window time.Duration) *limiter {
+	return &limiter{
+		limits: limits,
+		window: window,
+		now:    time.Now,
+		sent:   make(map[string][]time.Time),
+	}
+}
+
+// allow records a send to recipient if it stays within the limits
+func (l *limiter) allow(recipient string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	cutoff := now.Add(-l.window)
+
+	// Forget recipients that went quiet so the map does not grow unbounded
+	if len(l.sent) > maxTrackedRecipients {
+		for key, times := range l.sent {
+			if times = prune(times, cutoff); len(times) == 0 {
+				delete(l.sent, key)
+			} else {
+				l.sent[key] = times
+			}
+		}
+	}
+
+	l.total = prune(l.total, cutoff)
+	recent := prune(l.sent[recipient], cutoff)
+	if len(recent) == 0 {
+		delete(l.sent, recipient)
+	}
+
+	if l.limits.Total > 0 && len(l.total) >= l.limits.Total {
+		return false
+	}
+	if l.limits.PerRecipient > 0 && len(recent) >= l.limits.PerRecipient {
+		l.sent[recipient] = recent
+		return false
+	}
+
+	l.total = append(l.total, now)
+	l.sent[recipient] = append(recent, now)
+	return true
+}
+
+// prune drops timestamps before cutoff; times are in send order
+func prune(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
@@ -0,0 +1,182 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testData struct {
+	NodeType   string
+	Name       string
+	Address    string
+	ConfirmURL string
+	ExpiresIn  string
+	Reason     string
+}
+
+func TestTemplates_RenderDefaults(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     testData
+		contains string
+	}{
+		{"Confirm", TemplateRegistrationConfirm, testData{ConfirmURL: "https://tracker/confirm?token=abc"}, "https://tracker/confirm?token=abc"},
+		{"Approved", TemplateRegistrationApproved, testData{Name: "node-1"}, `"node-1"`},
+		{"Rejected", TemplateRegistrationRejected, testData{Reason: "endpoint is a proxy"}, "Reason: endpoint is a proxy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := templates.Render(tt.template, "op@example.org", tt.data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if msg.Subject == "" || strings.Contains(msg.Subject, "\n") {
+				t.Errorf("Expected a one line subject, got %q", msg.Subject)
+			}
+			if !strings.Contains(msg.Body, tt.contains) {
+				t.Errorf("Expected body to contain %q, got %q", tt.contains, msg.Body)
+			}
+		})
+	}
+}
+
+func TestTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{{define "subject"}}Welcome {{.Name}}{{end}}{{define "body"}}Listed.{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, TemplateRegistrationApproved+".tmpl"), []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := templates.Render(TemplateRegistrationApproved, "op@example.org", testData{Name: "node-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "Welcome node-1" || msg.Body != "Listed." {
+		t.Errorf("Expected the custom template, got %q / %q", msg.Subject, msg.Body)
+	}
+
+	// Templates that are not overridden keep the built-in version
+	if _, err := templates.Render(TemplateRegistrationRejected, "op@example.org", testData{}); err != nil {
+		t.Errorf("Expected the built-in rejected template, got %v", err)
+	}
+}
+
+func TestTemplates_OverrideMustDefineBlocks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, TemplateRegistrationConfirm+".tmpl"), []byte("just a body"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadTemplates(dir); err == nil {
+		t.Error("Expected an error for a template without subject and body blocks")
+	}
+}
+
+func TestMailer_Send(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := NewMemoryTransport()
+	mailer := NewMailer(transport, templates, "Tracker <no-reply@example.org>", Limits{})
+
+	if err := mailer.Send(context.Background(), "op@example.org", TemplateRegistrationApproved, testData{}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := mailer.Send(context.Background(), "not an address", TemplateRegistrationApproved, testData{}); err == nil {
+		t.Error("Expected an invalid recipient to be refused")
+	}
+
+	messages := transport.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	if messages[0].From != "Tracker <no-reply@example.org>" || messages[0].To != "op@example.org" {
+		t.Errorf("Expected sender and recipient to be set, got %q -> %q", messages[0].From, messages[0].To)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	l := newLimiter(Limits{PerRecipient: 2, Total: 3}, time.Hour)
+	l.now = func() time.Time { return now }
+
+	steps := []struct {
+		recipient string
+		advance   time.Duration
+		want      bool
+	}{
+		{"a", 0, true},
+		{"a", time.Minute, true},
+		{"a", time.Minute, false}, // per recipient cap
+		{"b", time.Minute, true},
+		{"c", time.Minute, false}, // total cap
+		{"a", time.Hour, true},    // first sends left the window
+	}
+
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if got := l.allow(step.recipient); got != step.want {
+			t.Errorf("Step %d: expected %v, got %v", i, step.want, got)
+		}
+	}
+}
+
+func TestMailer_RateLimited(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailer := NewMailer(NewMemoryTransport(), templates, "no-reply@example.org", Limits{PerRecipient: 1})
+
+	ctx := context.Background()
+	if err := mailer.Send(ctx, "op@example.org", TemplateRegistrationApproved, testData{}); err != nil {
+		t.Fatal(err)
+	}
+	// Recipients are compared case-insensitively
+	err = mailer.Send(ctx, "OP@example.org", TemplateRegistrationApproved, testData{})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestFileTransport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	transport, err := NewFileTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := &Message{From: "no-reply@example.org", To: "op@example.org", Subject: "Hello", Body: "line one\nline two\n"}
+	if err := transport.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one .eml file, got %v (%v)", files, err)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "Subject: Hello\r\n") || !strings.Contains(string(content), "line one\r\nline two") {
+		t.Errorf("Unexpected message file:\n%s", content)
+	}
+}
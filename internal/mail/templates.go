@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Template names
+const (
+	TemplateRegistrationConfirm  = "registration_confirm"
+	TemplateRegistrationApproved = "registration_approved"
+	TemplateRegistrationRejected = "registration_rejected"
+	TemplateOperatorLogin        = "operator_login"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Templates renders messages from text templates. Every template defines
+// a "subject" and a "body" block.
+type Templates struct {
+	sets map[string]*template.Template
+}
+
+// LoadTemplates parses the built-in templates. A file named <name>.tmpl in
+// dir replaces the built-in template of the same name; dir may be empty.
+func LoadTemplates(dir string) (*Templates, error) {
+	entries, err := fs.ReadDir(defaultTemplates, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("read built-in mail templates: %w", err)
+	}
+
+	t := &Templates{sets: make(map[string]*template.Template)}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+
+		source, err := fs.ReadFile(defaultTemplates, "templates/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read mail template %s: %w", name, err)
+		}
+		if dir != "" {
+			custom, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			switch {
+			case err == nil:
+				source = custom
+			case !errors.Is(err, fs.ErrNotExist):
+				return nil, fmt.Errorf("read mail template %s: %w", name, err)
+			}
+		}
+
+		set, err := template.New(name).Option("missingkey=error").Parse(string(source))
+		if err != nil {
+			return nil, fmt.Errorf("parse mail template %s: %w", name, err)
+		}
+		for _, block := range []string{"subject", "body"} {
+			if set.Lookup(block) == nil {
+				return nil, fmt.Errorf("mail template %s does not define %q", name, block)
+			}
+		}
+		t.sets[name] = set
+	}
+	return t, nil
+}
+
+// Render executes a template into a message addressed to to
+func (t *Templates) Render(name, to string, data any) (*Message, error) {
+	set, ok := t.sets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown mail template %s", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := set.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := set.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, fmt.Errorf("render %s body: %w", name, err)
+	}
+
+	return &Message{
+		To: to,
+		// Headers must stay on one line
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Body:    strings.TrimLeft(body.String(), "\n"),
+	}, nil
+}
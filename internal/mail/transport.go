@@ -0,0 +1,134 @@
+// Package mail renders and sends the emails the tracker sends to node
+// registrants.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a rendered plain text email
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Bytes returns the message in RFC 5322 form
+func (m *Message) Bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// Transport delivers rendered messages
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPTransport delivers messages through an SMTP relay, using STARTTLS
+// when the server offers it
+type SMTPTransport struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPTransport creates an SMTP transport. Authentication is skipped
+// when username is empty.
+func NewSMTPTransport(host string, port int, username, password string) *SMTPTransport {
+	t := &SMTPTransport{addr: net.JoinHostPort(host, strconv.Itoa(port))}
+	if username != "" {
+		t.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return t
+}
+
+// Send delivers a message. smtp.SendMail does not take a context, so ctx
+// is only checked before connecting.
+func (t *SMTPTransport) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// The envelope takes bare addresses, the From header may carry a name
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", msg.From, err)
+	}
+	if err := smtp.SendMail(t.addr, t.auth, from.Address, []string{msg.To}, msg.Bytes()); err != nil {
+		return fmt.Errorf("send mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// FileTransport writes every message to a directory as an .eml file
+// instead of sending it, for development and inspection
+type FileTransport struct {
+	dir string
+	mu  sync.Mutex
+	seq int
+}
+
+// NewFileTransport creates a file transport, creating dir if needed
+func NewFileTransport(dir string) (*FileTransport, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create mail outbox: %w", err)
+	}
+	return &FileTransport{dir: dir}, nil
+}
+
+// Send writes the message to the outbox directory
+func (t *FileTransport) Send(ctx context.Context, msg *Message) error {
+	t.mu.Lock()
+	t.seq++
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().UTC().Format("20060102T150405"), t.seq)
+	t.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(t.dir, name), msg.Bytes(), 0o640); err != nil {
+		return fmt.Errorf("write mail to outbox: %w", err)
+	}
+	return nil
+}
+
+// MemoryTransport keeps sent messages in memory for tests
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+// NewMemoryTransport creates an empty memory transport
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+// Send records the message
+func (t *MemoryTransport) Send(ctx context.Context, msg *Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = append(t.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (t *MemoryTransport) Messages() []*Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Message(nil), t.messages...)
+}
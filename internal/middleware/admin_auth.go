@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminAuth only lets through requests that present one of the admin tokens
+// as "Authorization: Bearer <token>". Without tokens every request is refused.
+func AdminAuth(tokens []string, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if found && validAdminToken(tokens, strings.TrimSpace(token)) {
+			c.Next()
+			return
+		}
+
+		logger.WithFields(logrus.Fields{
+			"request_id": GetRequestID(c),
+			"path":       c.Request.URL.Path,
+			"client_ip":  c.ClientIP(),
+		}).Warn("Rejected admin request")
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": GetRequestID(c),
+		})
+	}
+}
+
+// validAdminToken compares the token against every admin token in
+// constant time
+func validAdminToken(tokens []string, token string) bool {
+	valid := 0
+	for _, candidate := range tokens {
+		if candidate != "" {
+			valid |= subtle.ConstantTimeCompare([]byte(candidate), []byte(token))
+		}
+	}
+	return token != "" && valid == 1
+}
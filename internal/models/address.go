@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Address families of node endpoints. A /dns/ address can resolve to
+// either IP family, so until it is resolved its family is AddressFamilyDNS.
+const (
+	AddressFamilyIPv4 = "ipv4"
+	AddressFamilyIPv6 = "ipv6"
+	AddressFamilyDNS  = "dns"
+)
+
+// Transports of node endpoints
+const (
+	TransportTCP  = "tcp"
+	TransportQUIC = "quic"
+)
+
+// Endpoint is a node multiaddr broken into the parts needed to reach it,
+// such as /ip6/2a01:4f9::1/udp/21888/quic-v1/p2p/12D3KooW...
+type Endpoint struct {
+	Multiaddr string `json:"multiaddr"`
+	Family    string `json:"family"`
+	Transport string `json:"transport"`
+	Host      string `json:"host"`
+	Port      string `json:"port"`
+	PeerID    string `json:"peerId,omitempty"`
+}
+
+// EndpointCheck is the latest probe of one resolved address and transport
+// of a node
+type EndpointCheck struct {
+	ID             int        `json:"-" db:"id"`
+	NodeType       string     `json:"-" db:"node_type"`
+	NodeID         int        `json:"-" db:"node_id"`
+	Multiaddr      string     `json:"multiaddr" db:"multiaddr"`
+	Family         string     `json:"family" db:"family"`
+	Transport      string     `json:"transport" db:"transport"`
+	Reachable      bool       `json:"reachable" db:"reachable"`
+	ResponseTimeMs int        `json:"responseTimeMs" db:"response_time_ms"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty" db:"error_class"`
+	CheckedAt      time.Time  `json:"checkedAt" db:"checked_at"`
+}
+
+// PeerAddress is a multiaddr a crawled peer advertises
+type PeerAddress struct {
+	PeerID    int       `json:"peerId" db:"peer_id"`
+	Multiaddr string    `json:"multiaddr" db:"multiaddr"`
+	Family    string    `json:"family" db:"family"`
+	Transport string    `json:"transport" db:"transport"`
+	FirstSeen time.Time `json:"firstSeen" db:"first_seen"`
+	LastSeen  time.Time `json:"lastSeen" db:"last_seen"`
+}
+
+// FamilyReachability counts the latest endpoint checks of one address
+// family and transport, and how many of them reached the node
+type FamilyReachability struct {
+	Family    string  `json:"family"`
+	Transport string  `json:"transport"`
+	Checked   int     `json:"checked"`
+	Reachable int     `json:"reachable"`
+	Share     float64 `json:"share"`
+}
+
+// AddressFamilyCount counts the crawled peers advertising an address
+// family and transport
+type AddressFamilyCount struct {
+	Family    string `json:"family"`
+	Transport string `json:"transport"`
+	Peers     int    `json:"peers"`
+}
+
+// AddressFamilyStats is the response of the getAddressFamilyStats API
+type AddressFamilyStats struct {
+	Network string                `json:"network"`
+	Checks  []*FamilyReachability `json:"checks"`
+	Peers   []*AddressFamilyCount `json:"peers"`
+	Since   time.Time             `json:"since"`
+}
@@ -10,6 +10,7 @@ type BootstrapNode struct {
 	Email        string    `json:"email" db:"email"`
 	Website      string    `json:"website" db:"website"`
 	Address      string    `json:"address" db:"address"`
+	Network      string    `json:"network" db:"network"`
 	OverallScore float64   `json:"overallScore" db:"overall_score"`
 	IsActive     bool      `json:"isActive" db:"is_active"`
 	// Geographic fields (Phase 2)
@@ -23,21 +24,25 @@ type BootstrapNode struct {
 }
 
 type DailyStatus struct {
-	ID        int       `json:"id" db:"id"`
-	NodeID    int       `json:"nodeId" db:"node_id"`
-	Date      time.Time `json:"date" db:"date"`
-	Color     int       `json:"color" db:"color"` // 0 = red/gray, 1,2 = green
-	Attempts  int       `json:"attempts" db:"attempts"`
-	Success   bool      `json:"success" db:"success"`
-	ErrorMsg  string    `json:"errorMsg" db:"error_msg"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	ID             int        `json:"id" db:"id"`
+	NodeID         int        `json:"nodeId" db:"node_id"`
+	Date           time.Time  `json:"date" db:"date"`
+	Color          int        `json:"color" db:"color"` // 0 = red/gray, 1,2 = green, 3 = maintenance
+	Attempts       int        `json:"attempts" db:"attempts"`
+	Success        bool       `json:"success" db:"success"`
+	ResponseTimeMs int        `json:"responseTimeMs" db:"response_time_ms"`
+	ErrorMsg       string     `json:"errorMsg" db:"error_msg"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty" db:"error_class"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
 }
 
 type BootstrapNodeResponse struct {
+	ID           int          `json:"id"`
 	Name         string       `json:"name"`
 	Email        string       `json:"email"`
 	Website      string       `json:"website"`
 	Address      string       `json:"address"`
+	Network      string       `json:"network"`
 	Status       []StatusItem `json:"status"`
 	OverallScore float64      `json:"overallScore"`
 	// Geographic fields (Phase 2)
@@ -45,6 +50,10 @@ type BootstrapNodeResponse struct {
 	City        string  `json:"city"`
 	Latitude    float64 `json:"latitude"`
 	Longitude   float64 `json:"longitude"`
+	// Latest daily latency percentiles
+	Latency *LatencySummary `json:"latency,omitempty"`
+	// Latest probes of each IPv4/IPv6 address over TCP and QUIC
+	Endpoints []*EndpointCheck `json:"endpoints,omitempty"`
 }
 
 type StatusItem struct {
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// CertificateInfo describes the TLS certificate served by a monitored endpoint
+type CertificateInfo struct {
+	ID                int       `json:"id" db:"id"`
+	NodeType          string    `json:"nodeType" db:"node_type"`
+	NodeID            int       `json:"nodeId" db:"node_id"`
+	Address           string    `json:"address" db:"address"`
+	Subject           string    `json:"subject" db:"subject"`
+	Issuer            string    `json:"issuer" db:"issuer"`
+	SANs              []string  `json:"sans" db:"sans"`
+	SerialNumber      string    `json:"serialNumber" db:"serial_number"`
+	FingerprintSHA256 string    `json:"fingerprintSha256" db:"fingerprint_sha256"`
+	NotBefore         time.Time `json:"notBefore" db:"not_before"`
+	NotAfter          time.Time `json:"notAfter" db:"not_after"`
+	DaysRemaining     int       `json:"daysRemaining" db:"-"`
+	IsTrusted         bool      `json:"isTrusted" db:"is_trusted"`
+	IsSelfSigned      bool      `json:"isSelfSigned" db:"is_self_signed"`
+	IsExpired         bool      `json:"isExpired" db:"is_expired"`
+	ExpiresSoon       bool      `json:"expiresSoon" db:"expires_soon"`
+	VerifyError       string    `json:"verifyError,omitempty" db:"verify_error"`
+	CheckedAt         time.Time `json:"checkedAt" db:"checked_at"`
+}
+
+// HasProblem reports whether the certificate needs operator attention
+func (c *CertificateInfo) HasProblem() bool {
+	return c.IsExpired || c.IsSelfSigned || c.ExpiresSoon || !c.IsTrusted
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Chain incident kinds
+const (
+	ChainIncidentStall = "stall"
+	ChainIncidentFork  = "fork"
+)
+
+// ChainBlock is a block header read from a trusted server. GapSeconds is
+// the time since the previous block, nil when it was not recorded.
+type ChainBlock struct {
+	Height          uint32    `json:"height" db:"height"`
+	Hash            string    `json:"hash" db:"hash"`
+	BlockTime       time.Time `json:"blockTime" db:"block_time"`
+	ProposerAddress string    `json:"proposerAddress" db:"proposer_address"`
+	TxCount         int       `json:"txCount" db:"tx_count"`
+	GapSeconds      *int      `json:"gapSeconds,omitempty" db:"gap_seconds"`
+	Source          string    `json:"source" db:"source"`
+}
+
+// ChainSource is the latest poll of a trusted server. Lag is how many
+// blocks it is behind the highest server.
+type ChainSource struct {
+	Address   string    `json:"address" db:"address"`
+	Kind      string    `json:"kind" db:"kind"`
+	Height    uint32    `json:"height" db:"height"`
+	Hash      string    `json:"hash" db:"hash"`
+	Lag       uint32    `json:"lag" db:"-"`
+	Error     string    `json:"error,omitempty" db:"error"`
+	CheckedAt time.Time `json:"checkedAt" db:"checked_at"`
+}
+
+// ChainIncident is a stall or fork. A stall records the last block before
+// it; a fork records the hash each server reported at Height. Open
+// incidents have no EndedAt.
+type ChainIncident struct {
+	ID            int               `json:"id" db:"id"`
+	Kind          string            `json:"kind" db:"kind"`
+	Height        uint32            `json:"height" db:"height"`
+	LastBlockTime *time.Time        `json:"lastBlockTime,omitempty" db:"-"`
+	Hashes        map[string]string `json:"hashes,omitempty" db:"-"`
+	StartedAt     time.Time         `json:"startedAt" db:"started_at"`
+	EndedAt       *time.Time        `json:"endedAt,omitempty" db:"ended_at"`
+}
+
+// BlockStats summarizes the recorded blocks of a window
+type BlockStats struct {
+	Blocks          int     `json:"blocks"`
+	AvgBlockSeconds float64 `json:"avgBlockSeconds"`
+	P95BlockSeconds float64 `json:"p95BlockSeconds"`
+	MaxGapSeconds   int     `json:"maxGapSeconds"`
+	Transactions    int     `json:"transactions"`
+	AvgTxPerBlock   float64 `json:"avgTxPerBlock"`
+	Proposers       int     `json:"proposers"`
+}
+
+// ChainStats is the response of the getChainStats API
+type ChainStats struct {
+	Network               string           `json:"network"`
+	Height                uint32           `json:"height"`
+	Hash                  string           `json:"hash"`
+	LastBlockTime         *time.Time       `json:"lastBlockTime,omitempty"`
+	SecondsSinceLastBlock int              `json:"secondsSinceLastBlock"`
+	Stalled               bool             `json:"stalled"`
+	Forked                bool             `json:"forked"`
+	Since                 time.Time        `json:"since"`
+	Window                BlockStats       `json:"window"`
+	Sources               []*ChainSource   `json:"sources"`
+	Incidents             []*ChainIncident `json:"incidents"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// PeerSession is a continuous period in which the crawler kept seeing a
+// peer. First marks the peer's first session; FirstSeen is when the peer
+// was first seen at all.
+type PeerSession struct {
+	PeerID    int       `json:"peerId" db:"peer_id"`
+	StartedAt time.Time `json:"startedAt" db:"started_at"`
+	LastSeen  time.Time `json:"lastSeen" db:"last_seen"`
+	First     bool      `json:"first" db:"-"`
+	FirstSeen time.Time `json:"firstSeen" db:"-"`
+}
+
+// ChurnDay counts the peers seen on one day and the sessions that started
+// or ended on it. Joins are first sessions, rejoins are later ones.
+type ChurnDay struct {
+	Date    string `json:"date"`
+	Active  int    `json:"active"`
+	Joins   int    `json:"joins"`
+	Leaves  int    `json:"leaves"`
+	Rejoins int    `json:"rejoins"`
+}
+
+// SessionBucket counts the sessions whose length falls in [MinHours,
+// MaxHours). A MaxHours of 0 is unbounded.
+type SessionBucket struct {
+	Label    string  `json:"label"`
+	MinHours float64 `json:"minHours"`
+	MaxHours float64 `json:"maxHours"`
+	Sessions int     `json:"sessions"`
+}
+
+// ChurnStats is the response of the getChurnStats API. Lifespans are
+// measured from a peer's first to its last sighting; session lengths only
+// cover sessions that ended in the range.
+type ChurnStats struct {
+	Network             string           `json:"network"`
+	From                string           `json:"from"`
+	To                  string           `json:"to"`
+	Peers               int              `json:"peers"`
+	Joins               int              `json:"joins"`
+	Leaves              int              `json:"leaves"`
+	Rejoins             int              `json:"rejoins"`
+	MedianLifespanHours float64          `json:"medianLifespanHours"`
+	MedianSessionHours  float64          `json:"medianSessionHours"`
+	SessionLengths      []*SessionBucket `json:"sessionLengths"`
+	Days                []*ChurnDay      `json:"days"`
+}
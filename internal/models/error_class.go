@@ -0,0 +1,17 @@
+package models
+
+// ErrorClass categorizes why a node check failed
+type ErrorClass string
+
+const (
+	ErrorClassNone              ErrorClass = ""
+	ErrorClassInvalidAddress    ErrorClass = "invalid_address"
+	ErrorClassDNS               ErrorClass = "dns"
+	ErrorClassConnectionRefused ErrorClass = "connection_refused"
+	ErrorClassTimeout           ErrorClass = "timeout"
+	ErrorClassTLS               ErrorClass = "tls"
+	ErrorClassHTTP5xx           ErrorClass = "http_5xx"
+	ErrorClassHTTP              ErrorClass = "http_error"
+	ErrorClassRPC               ErrorClass = "rpc"
+	ErrorClassUnknown           ErrorClass = "unknown"
+)
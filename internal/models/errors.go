@@ -99,6 +99,22 @@ func NewBadRequestError(message string) *AppError {
 	}
 }
 
+func NewUnauthorizedError(message string) *AppError {
+	return &AppError{
+		Code:       ErrCodeUnauthorized,
+		Message:    message,
+		StatusCode: http.StatusUnauthorized,
+	}
+}
+
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Code:       ErrCodeForbidden,
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
 func NewValidationError(message string, details string) *AppError {
 	return &AppError{
 		Code:       ErrCodeValidation,
@@ -108,6 +124,14 @@ func NewValidationError(message string, details string) *AppError {
 	}
 }
 
+func NewConflictError(message string) *AppError {
+	return &AppError{
+		Code:       ErrCodeConflict,
+		Message:    message,
+		StatusCode: http.StatusConflict,
+	}
+}
+
 func NewDatabaseError(message string, err error) *AppError {
 	return &AppError{
 		Code:       ErrCodeDatabaseQuery,
@@ -129,6 +153,14 @@ func NewNodeNotReachableError(address string, err error) *AppError {
 	}
 }
 
+func NewServiceUnavailableError(message string) *AppError {
+	return &AppError{
+		Code:       ErrCodeServiceUnavailable,
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+	}
+}
+
 func NewRateLimitError(message string) *AppError {
 	return &AppError{
 		Code:       ErrCodeRateLimitExceeded,
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Formats of data exports. GeoJSON is offered for located data only.
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatGeoJSON = "geojson"
+)
+
+// ExportNode is a node of any type in a data export
+type ExportNode struct {
+	Type         string    `json:"type"`
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Address      string    `json:"address"`
+	Network      string    `json:"network"`
+	Email        string    `json:"email"`
+	Website      string    `json:"website"`
+	OverallScore float64   `json:"overallScore"`
+	IsVerified   bool      `json:"isVerified"`
+	Country      string    `json:"country"`
+	CountryCode  string    `json:"countryCode"`
+	City         string    `json:"city"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// ExportStatus is one day of the status history of a node in a data export
+type ExportStatus struct {
+	Type           string     `json:"type"`
+	NodeID         int        `json:"nodeId"`
+	Name           string     `json:"name"`
+	Network        string     `json:"network"`
+	Date           time.Time  `json:"date"`
+	Color          int        `json:"color"`
+	Attempts       int        `json:"attempts"`
+	Success        bool       `json:"success"`
+	ResponseTimeMs int        `json:"responseTimeMs"`
+	ErrorMsg       string     `json:"errorMsg"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty"`
+}
@@ -11,6 +11,8 @@ type GRPCServer struct {
 	Network      string    `json:"network" db:"network"` // mainnet or testnet
 	OverallScore float64   `json:"overallScore" db:"overall_score"`
 	IsActive     bool      `json:"isActive" db:"is_active"`
+	IsVerified   bool      `json:"isVerified" db:"is_verified"`
+	IsCurated    bool      `json:"isCurated" db:"is_curated"` // added by syncs from a node list
 	Email        string    `json:"email" db:"email"`
 	Website      string    `json:"website" db:"website"`
 	// Geographic fields (Phase 2)
@@ -24,18 +26,20 @@ type GRPCServer struct {
 }
 
 type GRPCDailyStatus struct {
-	ID             int       `json:"id" db:"id"`
-	ServerID       int       `json:"serverId" db:"server_id"`
-	Date           time.Time `json:"date" db:"date"`
-	Color          int       `json:"color" db:"color"` // 0 = grey, 1 = green
-	Attempts       int       `json:"attempts" db:"attempts"`
-	Success        bool      `json:"success" db:"success"`
-	ErrorMsg       string    `json:"errorMsg" db:"error_msg"`
-	ResponseTimeMs int       `json:"responseTimeMs" db:"response_time_ms"`
-	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+	ID             int        `json:"id" db:"id"`
+	ServerID       int        `json:"serverId" db:"server_id"`
+	Date           time.Time  `json:"date" db:"date"`
+	Color          int        `json:"color" db:"color"` // 0 = grey, 1 = green, 3 = maintenance
+	Attempts       int        `json:"attempts" db:"attempts"`
+	Success        bool       `json:"success" db:"success"`
+	ErrorMsg       string     `json:"errorMsg" db:"error_msg"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty" db:"error_class"`
+	ResponseTimeMs int        `json:"responseTimeMs" db:"response_time_ms"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
 }
 
 type GRPCServerResponse struct {
+	ID           int          `json:"id"`
 	Name         string       `json:"name"`
 	Address      string       `json:"address"`
 	Network      string       `json:"network"`
@@ -43,9 +47,12 @@ type GRPCServerResponse struct {
 	Website      string       `json:"website"`
 	Status       []StatusItem `json:"status"`
 	OverallScore float64      `json:"overallScore"`
+	IsVerified   bool         `json:"isVerified"`
 	// Geographic fields (Phase 2)
 	Country     string  `json:"country"`
 	City        string  `json:"city"`
 	Latitude    float64 `json:"latitude"`
 	Longitude   float64 `json:"longitude"`
+	// Latest daily latency percentiles
+	Latency *LatencySummary `json:"latency,omitempty"`
 }
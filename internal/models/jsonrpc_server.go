@@ -24,30 +24,33 @@ type JSONRPCServer struct {
 
 // JSONRPCDailyStatus represents daily status for a JSON-RPC server
 type JSONRPCDailyStatus struct {
-	ID               int       `json:"id" db:"id"`
-	ServerID         int       `json:"serverId" db:"server_id"`
-	Date             time.Time `json:"date" db:"date"`
-	Color            int       `json:"color" db:"color"`
-	Attempts         int       `json:"attempts" db:"attempts"`
-	Success          bool      `json:"success" db:"success"`
-	ResponseTimeMs   int       `json:"responseTimeMs" db:"response_time_ms"`
-	ErrorMsg         string    `json:"errorMsg" db:"error_msg"`
-	BlockchainHeight int64     `json:"blockchainHeight" db:"blockchain_height"`
-	CreatedAt        time.Time `json:"createdAt" db:"created_at"`
+	ID               int        `json:"id" db:"id"`
+	ServerID         int        `json:"serverId" db:"server_id"`
+	Date             time.Time  `json:"date" db:"date"`
+	Color            int        `json:"color" db:"color"`
+	Attempts         int        `json:"attempts" db:"attempts"`
+	Success          bool       `json:"success" db:"success"`
+	ResponseTimeMs   int        `json:"responseTimeMs" db:"response_time_ms"`
+	ErrorMsg         string     `json:"errorMsg" db:"error_msg"`
+	ErrorClass       ErrorClass `json:"errorClass,omitempty" db:"error_class"`
+	BlockchainHeight int64      `json:"blockchainHeight" db:"blockchain_height"`
+	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
 }
 
 // JSONRPCServerResponse is the API response format for JSON-RPC servers
 type JSONRPCServerResponse struct {
-	ID           int          `json:"id"`
-	Name         string       `json:"name"`
-	Address      string       `json:"address"`
-	Network      string       `json:"network"`
-	Email        string       `json:"email"`
-	Website      string       `json:"website"`
-	Country      string       `json:"country"`
-	City         string       `json:"city"`
-	Latitude     float64      `json:"latitude"`
-	Longitude    float64      `json:"longitude"`
-	Status       []StatusItem `json:"status"`
-	OverallScore float64      `json:"overallScore"`
+	ID           int             `json:"id"`
+	Name         string          `json:"name"`
+	Address      string          `json:"address"`
+	Network      string          `json:"network"`
+	Email        string          `json:"email"`
+	Website      string          `json:"website"`
+	Country      string          `json:"country"`
+	City         string          `json:"city"`
+	Latitude     float64         `json:"latitude"`
+	Longitude    float64         `json:"longitude"`
+	Status       []StatusItem    `json:"status"`
+	OverallScore float64         `json:"overallScore"`
+	IsVerified   bool            `json:"isVerified"`
+	Latency      *LatencySummary `json:"latency,omitempty"`
 }
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// CheckAttempt is a single connection attempt made while checking a node
+type CheckAttempt struct {
+	ID         int        `json:"id" db:"id"`
+	NodeType   string     `json:"nodeType" db:"node_type"`
+	NodeID     int        `json:"nodeId" db:"node_id"`
+	Attempt    int        `json:"attempt" db:"attempt"`
+	Success    bool       `json:"success" db:"success"`
+	LatencyMs  int        `json:"latencyMs" db:"latency_ms"`
+	ErrorClass ErrorClass `json:"errorClass,omitempty" db:"error_class"`
+	CheckedAt  time.Time  `json:"checkedAt" db:"checked_at"`
+}
+
+// LatencyStats holds the latency distribution of a node for one day
+type LatencyStats struct {
+	NodeType string  `json:"nodeType" db:"node_type"`
+	NodeID   int     `json:"nodeId" db:"node_id"`
+	Date     string  `json:"date" db:"date"`
+	Samples  int     `json:"samples" db:"samples"`
+	P50Ms    float64 `json:"p50Ms" db:"p50_ms"`
+	P95Ms    float64 `json:"p95Ms" db:"p95_ms"`
+	P99Ms    float64 `json:"p99Ms" db:"p99_ms"`
+	MinMs    int     `json:"minMs" db:"min_ms"`
+	MaxMs    int     `json:"maxMs" db:"max_ms"`
+	AvgMs    float64 `json:"avgMs" db:"avg_ms"`
+}
+
+// LatencySummary is the latest latency percentiles shown in node lists
+type LatencySummary struct {
+	Date  string  `json:"date"`
+	P50Ms float64 `json:"p50Ms"`
+	P95Ms float64 `json:"p95Ms"`
+	P99Ms float64 `json:"p99Ms"`
+}
+
+// LatencyHistoryResponse is the response of the getLatencyHistory API
+type LatencyHistoryResponse struct {
+	NodeType string          `json:"nodeType"`
+	NodeID   int             `json:"nodeId"`
+	Days     int             `json:"days"`
+	History  []*LatencyStats `json:"history"`
+}
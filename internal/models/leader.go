@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LeaderStatus describes the leader election state of a tracker instance
+type LeaderStatus struct {
+	Enabled       bool       `json:"enabled"`
+	InstanceID    string     `json:"instanceId"`
+	IsLeader      bool       `json:"isLeader"`
+	LeaderSince   *time.Time `json:"leaderSince,omitempty"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// StatusColorMaintenance marks a daily status recorded while the node was
+// in a maintenance window. These days are left out of the overall score.
+const StatusColorMaintenance = 3
+
+// MaxMaintenanceDuration bounds the length of a single maintenance window
+const MaxMaintenanceDuration = 7 * 24 * time.Hour
+
+// MaintenancePeriod is the span of status history the overall score covers.
+// A node may spend at most MaxMaintenanceDuration of any such span in
+// maintenance, so back to back windows cannot hide its downtime.
+const MaintenancePeriod = 30 * 24 * time.Hour
+
+// MaintenanceWindow is planned downtime of a node between StartsAt and
+// EndsAt. Checks made during the window are recorded with
+// StatusColorMaintenance.
+type MaintenanceWindow struct {
+	ID        int       `json:"id" db:"id"`
+	NodeType  string    `json:"nodeType" db:"node_type"`
+	NodeID    int       `json:"nodeId" db:"node_id"`
+	StartsAt  time.Time `json:"startsAt" db:"starts_at"`
+	EndsAt    time.Time `json:"endsAt" db:"ends_at"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedBy string    `json:"createdBy" db:"created_by"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// IsActive reports whether the window is open at t
+func (w *MaintenanceWindow) IsActive(t time.Time) bool {
+	return !w.StartsAt.After(t) && w.EndsAt.After(t)
+}
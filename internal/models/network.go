@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Network kinds. Mainnet and testnet read their bootstrap nodes and server
+// list from the lists shipped with Pactus; localnet and custom networks
+// only use the addresses configured on the network.
+const (
+	NetworkKindMainnet  = "mainnet"
+	NetworkKindTestnet  = "testnet"
+	NetworkKindLocalnet = "localnet"
+	NetworkKindCustom   = "custom"
+)
+
+// DefaultNetwork is the network APIs use when none is given
+const DefaultNetwork = "mainnet"
+
+// networkNamePattern matches the names of the networks table: lowercase
+// letters, digits and dashes, at most 20 characters
+var networkNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,19}$`)
+
+// Network is a Pactus network the tracker follows. BootstrapAddresses and
+// GRPCServers are synced in addition to the lists shipped with Pactus.
+type Network struct {
+	Name               string    `json:"name" db:"name"`
+	Kind               string    `json:"kind" db:"kind"`
+	Description        string    `json:"description" db:"description"`
+	BootstrapAddresses []string  `json:"bootstrapAddresses" db:"bootstrap_addresses"`
+	GRPCServers        []string  `json:"grpcServers" db:"grpc_servers"`
+	IsActive           bool      `json:"isActive" db:"is_active"`
+	CreatedAt          time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt          time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ValidateNetworkName checks that a network name can be stored
+func ValidateNetworkName(name string) error {
+	if !networkNamePattern.MatchString(name) {
+		return fmt.Errorf("network must be 1 to 20 lowercase letters, digits or dashes")
+	}
+	return nil
+}
+
+// ValidateNetworkKind checks that a network kind is known
+func ValidateNetworkKind(kind string) error {
+	switch kind {
+	case NetworkKindMainnet, NetworkKindTestnet, NetworkKindLocalnet, NetworkKindCustom:
+		return nil
+	}
+	return fmt.Errorf("kind must be one of mainnet, testnet, localnet or custom")
+}
+
+// NetworkOrDefault returns the network, or DefaultNetwork when it is empty
+func NetworkOrDefault(network string) string {
+	if network == "" {
+		return DefaultNetwork
+	}
+	return network
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Lifetimes of operator tokens
+const (
+	OperatorLinkTTL      = 30 * time.Minute
+	OperatorChallengeTTL = 10 * time.Minute
+	OperatorSessionTTL   = 24 * time.Hour
+)
+
+// Operator token kinds
+const (
+	OperatorTokenLogin     = "login"
+	OperatorTokenChallenge = "challenge"
+	OperatorTokenSession   = "session"
+)
+
+// OperatorMethodEmail marks tokens issued through an emailed login link.
+// Key based tokens carry the ownership verification method.
+const OperatorMethodEmail = "email"
+
+// OperatorToken is a login link, signing challenge or session of a node
+// operator. Email tokens are scoped to every node registered with Email;
+// key tokens to the node that signed.
+type OperatorToken struct {
+	ID        int        `json:"id" db:"id"`
+	Kind      string     `json:"kind" db:"kind"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	Email     string     `json:"email,omitempty" db:"email"`
+	NodeType  string     `json:"nodeType,omitempty" db:"node_type"`
+	NodeID    int        `json:"nodeId,omitempty" db:"node_id"`
+	Method    string     `json:"method" db:"method"`
+	ExpiresAt time.Time  `json:"expiresAt" db:"expires_at"`
+	UsedAt    *time.Time `json:"usedAt,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// Actor names the operator in the audit log
+func (t *OperatorToken) Actor() string {
+	if t.Email != "" {
+		return OperatorMethodEmail + ":" + t.Email
+	}
+	return t.Method + ":" + t.NodeType + "/" + strconv.Itoa(t.NodeID)
+}
+
+// OperatorLoginRequest asks for a login link for the nodes registered with
+// an email address
+type OperatorLoginRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// OperatorChallengeRequest asks for a message to sign with a node key
+type OperatorChallengeRequest struct {
+	NodeType string `json:"nodeType" binding:"required,oneof=grpc"`
+	NodeID   int    `json:"nodeId" binding:"required,min=1"`
+}
+
+// OperatorChallenge is the message an operator signs to log in with a key
+type OperatorChallenge struct {
+	Challenge   string    `json:"challenge"`
+	SignMessage string    `json:"signMessage"`
+	Methods     []string  `json:"methods"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// OperatorKeyLoginRequest logs in with a signed challenge
+type OperatorKeyLoginRequest struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Method    string `json:"method" binding:"required,oneof=peer_key validator_key"`
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// OperatorSession is returned on login. Token goes into the Authorization
+// header as a bearer token.
+type OperatorSession struct {
+	Token     string    `json:"token"`
+	Method    string    `json:"method"`
+	Email     string    `json:"email,omitempty"`
+	NodeType  string    `json:"nodeType,omitempty"`
+	NodeID    int       `json:"nodeId,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// OperatorNode is a gRPC or JSON-RPC server as its operator sees it
+type OperatorNode struct {
+	NodeType    string             `json:"nodeType"`
+	ID          int                `json:"id"`
+	Name        string             `json:"name"`
+	Address     string             `json:"address"`
+	Network     string             `json:"network"`
+	Email       string             `json:"email"`
+	Website     string             `json:"website"`
+	IsActive    bool               `json:"isActive"`
+	IsVerified  bool               `json:"isVerified"`
+	Maintenance *MaintenanceWindow `json:"maintenance,omitempty"`
+}
+
+// NodeUpdateRequest changes an operator's node. Omitted fields keep their
+// value.
+type NodeUpdateRequest struct {
+	Name    *string `json:"name" binding:"omitempty,min=2,max=255"`
+	Address *string `json:"address" binding:"omitempty,min=1"`
+	Email   *string `json:"email" binding:"omitempty,email"`
+	Website *string `json:"website" binding:"omitempty,max=255"`
+}
+
+// PauseMonitoringRequest schedules a maintenance window that ends at Until.
+// It starts now unless StartsAt is set.
+type PauseMonitoringRequest struct {
+	StartsAt *time.Time `json:"startsAt"`
+	Until    time.Time  `json:"until" binding:"required"`
+	Reason   string     `json:"reason" binding:"max=500"`
+}
+
+// Audit log actions
+const (
+	AuditNodeUpdated          = "node.updated"
+	AuditNodeMoved            = "node.moved"
+	AuditMonitoringPaused     = "monitoring.paused"
+	AuditMonitoringResumed    = "monitoring.resumed"
+	AuditMaintenanceCancelled = "maintenance.cancelled"
+	AuditNodeDeregistered     = "node.deregistered"
+)
+
+// AuditEntry records a change an operator made to a node. Changes holds
+// the details of the action; for updates it maps each changed field to a
+// FieldChange.
+type AuditEntry struct {
+	ID        int             `json:"id" db:"id"`
+	NodeType  string          `json:"nodeType" db:"node_type"`
+	NodeID    int             `json:"nodeId" db:"node_id"`
+	Actor     string          `json:"actor" db:"actor"`
+	Action    string          `json:"action" db:"action"`
+	Changes   json.RawMessage `json:"changes,omitempty" db:"changes"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// FieldChange is one field of an audit entry's changes
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
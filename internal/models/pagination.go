@@ -0,0 +1,200 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Page size limits for list APIs
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// Sort keys accepted by node list APIs
+const (
+	SortByID      = "id"
+	SortByName    = "name"
+	SortByScore   = "score"
+	SortByCountry = "country"
+)
+
+// Node status filter values. A node is online when its 30-day score is at
+// least OnlineScoreThreshold, matching the map status.
+const (
+	NodeStatusOnline     = "online"
+	NodeStatusOffline    = "offline"
+	OnlineScoreThreshold = 50
+)
+
+// NodeFilter selects, sorts and paginates a node list. An empty Network
+// lists the nodes of every network.
+type NodeFilter struct {
+	Network  string  `json:"network"`
+	Country  string  `json:"country"`
+	MinScore float64 `json:"minScore"`
+	Status   string  `json:"status"`
+	Search   string  `json:"search"`
+	Sort     string  `json:"sort"`
+	Order    string  `json:"order"`
+	Cursor   string  `json:"cursor"`
+	Limit    int     `json:"limit"`
+}
+
+// Normalize fills in default sorting and page size
+func (f *NodeFilter) Normalize() {
+	if f.Sort == "" {
+		f.Sort = SortByID
+	}
+	if f.Order == "" {
+		f.Order = "asc"
+		if f.Sort == SortByScore {
+			f.Order = "desc"
+		}
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultPageSize
+	}
+}
+
+// Validate normalizes the filter and checks its values and cursor
+func (f *NodeFilter) Validate() error {
+	f.Normalize()
+
+	switch f.Sort {
+	case SortByID, SortByName, SortByScore, SortByCountry:
+	default:
+		return fmt.Errorf("sort must be one of id, name, score or country")
+	}
+	if f.Order != "asc" && f.Order != "desc" {
+		return fmt.Errorf("order must be asc or desc")
+	}
+	if f.Status != "" && f.Status != NodeStatusOnline && f.Status != NodeStatusOffline {
+		return fmt.Errorf("status must be %s or %s", NodeStatusOnline, NodeStatusOffline)
+	}
+	if f.MinScore < 0 || f.MinScore > 100 {
+		return fmt.Errorf("minScore must be between 0 and 100")
+	}
+	if f.Limit > MaxPageSize {
+		return fmt.Errorf("limit must not exceed %d", MaxPageSize)
+	}
+	if f.Cursor != "" {
+		cursor, err := DecodeCursor(f.Cursor)
+		if err != nil {
+			return err
+		}
+		if cursor.Sort != f.Sort || cursor.Order != f.Order {
+			return fmt.Errorf("cursor was issued for a different sort order")
+		}
+	}
+	return nil
+}
+
+// NextCursor returns the cursor that continues the list after a node
+func (f *NodeFilter) NextCursor(id int, name, country string, score float64) string {
+	cursor := PageCursor{Sort: f.Sort, Order: f.Order, ID: id}
+	switch f.Sort {
+	case SortByName:
+		cursor.Value = name
+	case SortByScore:
+		cursor.Value = strconv.FormatFloat(score, 'f', -1, 64)
+	case SortByCountry:
+		cursor.Value = country
+	}
+	return cursor.Encode()
+}
+
+// MapFilter selects and paginates the map nodes of a network, which are
+// ordered by type and id
+type MapFilter struct {
+	Network string `json:"network"`
+	Type    string `json:"type"`
+	Country string `json:"country"`
+	Status  string `json:"status"`
+	Search  string `json:"search"`
+	Cursor  string `json:"cursor"`
+	Limit   int    `json:"limit"`
+}
+
+// Validate fills in the default network and page size and checks the
+// filter values
+func (f *MapFilter) Validate() error {
+	f.Network = NetworkOrDefault(f.Network)
+	if err := ValidateNetworkName(f.Network); err != nil {
+		return err
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		return fmt.Errorf("limit must not exceed %d", MaxPageSize)
+	}
+	switch f.Type {
+	case "", NodeTypeBootstrap, NodeTypeGRPC, NodeTypeJSONRPC, "peer":
+	default:
+		return fmt.Errorf("type must be one of bootstrap, grpc, jsonrpc or peer")
+	}
+	if f.Status != "" && f.Status != NodeStatusOnline && f.Status != NodeStatusOffline {
+		return fmt.Errorf("status must be %s or %s", NodeStatusOnline, NodeStatusOffline)
+	}
+	if f.Cursor != "" {
+		if _, err := DecodeCursor(f.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PageCursor is the position of the last item of a page. It is handed to
+// clients as an opaque string.
+type PageCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+// Encode returns the opaque form of the cursor
+func (c PageCursor) Encode() string {
+	encoded, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// DecodeCursor parses an opaque cursor
+func DecodeCursor(value string) (*PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	cursor := &PageCursor{}
+	if err := json.Unmarshal(raw, cursor); err != nil || cursor.ID <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// BootstrapNodePage is one page of bootstrap nodes
+type BootstrapNodePage struct {
+	Items      []*BootstrapNodeResponse `json:"items"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+}
+
+// GRPCServerPage is one page of gRPC servers
+type GRPCServerPage struct {
+	Items      []*GRPCServerResponse `json:"items"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+// JSONRPCServerPage is one page of JSON-RPC servers
+type JSONRPCServerPage struct {
+	Items      []*JSONRPCServerResponse `json:"items"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+}
+
+// MapNodePage is one page of map nodes
+type MapNodePage struct {
+	Items      []MapNode `json:"items"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
@@ -5,10 +5,12 @@ import "time"
 // ReachablePeer represents a discovered network peer
 type ReachablePeer struct {
 	ID                    int       `json:"id" db:"id"`
+	Network               string    `json:"network" db:"network"`
 	PeerID                string    `json:"peerId" db:"peer_id"`
 	Address               string    `json:"address" db:"address"`
 	Protocol              string    `json:"protocol" db:"protocol"`
 	UserAgent             string    `json:"userAgent" db:"user_agent"`
+	Addresses             []string  `json:"addresses,omitempty" db:"-"`
 	LastSeen              time.Time `json:"lastSeen" db:"last_seen"`
 	FirstSeen             time.Time `json:"firstSeen" db:"first_seen"`
 
@@ -63,6 +65,7 @@ type PeerResponse struct {
 
 // NetworkStats represents network statistics
 type NetworkStats struct {
+	Network        string         `json:"network"`
 	TotalNodes     int            `json:"totalNodes"`
 	ReachableNodes int            `json:"reachableNodes"`
 	CountriesCount int            `json:"countriesCount"`
@@ -82,16 +85,22 @@ type CountryStats struct {
 
 // NetworkSnapshot represents a point-in-time snapshot of the network
 type NetworkSnapshot struct {
-	ID             int       `json:"id" db:"id"`
-	Timestamp      time.Time `json:"timestamp" db:"timestamp"`
-	TotalNodes     int       `json:"totalNodes" db:"total_nodes"`
-	ReachableNodes int       `json:"reachableNodes" db:"reachable_nodes"`
-	CountriesCount int       `json:"countriesCount" db:"countries_count"`
-	GRPCNodes      int       `json:"grpcNodes" db:"grpc_nodes"`
-	JSONRPCNodes   int       `json:"jsonrpcNodes" db:"jsonrpc_nodes"`
-	BootstrapNodes int       `json:"bootstrapNodes" db:"bootstrap_nodes"`
-	SnapshotData   []byte    `json:"snapshotData" db:"snapshot_data"`
-	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+	ID             int           `json:"id" db:"id"`
+	Network        string        `json:"network" db:"network"`
+	Timestamp      time.Time     `json:"timestamp" db:"timestamp"`
+	TotalNodes     int           `json:"totalNodes" db:"total_nodes"`
+	ReachableNodes int           `json:"reachableNodes" db:"reachable_nodes"`
+	CountriesCount int           `json:"countriesCount" db:"countries_count"`
+	GRPCNodes      int           `json:"grpcNodes" db:"grpc_nodes"`
+	JSONRPCNodes   int           `json:"jsonrpcNodes" db:"jsonrpc_nodes"`
+	BootstrapNodes int           `json:"bootstrapNodes" db:"bootstrap_nodes"`
+	SnapshotData   *SnapshotData `json:"snapshotData" db:"snapshot_data"`
+	CreatedAt      time.Time     `json:"createdAt" db:"created_at"`
+}
+
+// SnapshotData is the detail stored with a network snapshot
+type SnapshotData struct {
+	Churn *ChurnStats `json:"churn,omitempty"`
 }
 
 // MapNode represents a node for map display
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Node types probed by the tracker and its agents
+const (
+	NodeTypeBootstrap = "bootstrap"
+	NodeTypeGRPC      = "grpc"
+	NodeTypeJSONRPC   = "jsonrpc"
+)
+
+// ProbeTarget is a node a remote probe agent should check
+type ProbeTarget struct {
+	NodeType string `json:"nodeType"`
+	NodeID   int    `json:"nodeId"`
+	Address  string `json:"address"`
+}
+
+// ProbeResult is the outcome of a single check made by a probe agent
+type ProbeResult struct {
+	ID             int        `json:"id" db:"id"`
+	AgentID        string     `json:"agentId" db:"agent_id"`
+	Region         string     `json:"region" db:"region"`
+	NodeType       string     `json:"nodeType" db:"node_type"`
+	NodeID         int        `json:"nodeId" db:"node_id"`
+	Date           time.Time  `json:"date" db:"date"`
+	Success        bool       `json:"success" db:"success"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	ResponseTimeMs int        `json:"responseTimeMs" db:"response_time_ms"`
+	ErrorMsg       string     `json:"errorMsg" db:"error_msg"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty" db:"error_class"`
+	CheckedAt      time.Time  `json:"checkedAt" db:"checked_at"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// ProbeReport is the signed payload a probe agent submits to the tracker
+type ProbeReport struct {
+	AgentID string         `json:"agentId"`
+	Region  string         `json:"region"`
+	Results []*ProbeResult `json:"results"`
+}
+
+// ProbeReportResponse acknowledges a submitted probe report
+type ProbeReportResponse struct {
+	Accepted  int       `json:"accepted"`
+	Rejected  int       `json:"rejected"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// QuorumResult is the cross-region verdict for a node on a given day
+type QuorumResult struct {
+	Color          int        `json:"color"`
+	Decided        bool       `json:"decided"`
+	RegionsUp      int        `json:"regionsUp"`
+	RegionsTotal   int        `json:"regionsTotal"`
+	Probes         int        `json:"probes"`
+	ResponseTimeMs int        `json:"responseTimeMs"`
+	ErrorClass     ErrorClass `json:"errorClass,omitempty"`
+}
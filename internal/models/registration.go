@@ -11,26 +11,83 @@ type NodeRegistration struct {
 	Network         string     `json:"network" db:"network"`
 	Email           string     `json:"email" db:"email"`
 	Website         string     `json:"website" db:"website"`
-	Status          string     `json:"status" db:"status"` // pending, approved, rejected
+	Status          string     `json:"status" db:"status"` // unconfirmed, pending, approved, rejected
 	RejectionReason string     `json:"rejectionReason" db:"rejection_reason"`
 	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
 	ReviewedAt      *time.Time `json:"reviewedAt" db:"reviewed_at"`
 	ReviewedBy      string     `json:"reviewedBy" db:"reviewed_by"`
+
+	// Ownership verification. The token is only handed to the submitter.
+	VerificationToken  string     `json:"-" db:"verification_token"`
+	IsVerified         bool       `json:"isVerified" db:"is_verified"`
+	VerificationMethod string     `json:"verificationMethod,omitempty" db:"verification_method"`
+	VerifiedAt         *time.Time `json:"verifiedAt,omitempty" db:"verified_at"`
+
+	// Email confirmation. The token is only sent to the registrant.
+	ConfirmationToken string     `json:"-" db:"confirmation_token"`
+	ConfirmedAt       *time.Time `json:"confirmedAt,omitempty" db:"confirmed_at"`
 }
 
+// RegistrationConfirmationTTL is how long an emailed confirmation link
+// stays valid. Unconfirmed registrations older than this are ignored.
+const RegistrationConfirmationTTL = 48 * time.Hour
+
 // RegistrationRequest is the API request for node registration
 type RegistrationRequest struct {
 	NodeType string `json:"nodeType" binding:"required,oneof=grpc jsonrpc"`
 	Name     string `json:"name" binding:"required,min=2,max=255"`
 	Address  string `json:"address" binding:"required"`
-	Network  string `json:"network" binding:"required,oneof=mainnet testnet"`
+	Network  string `json:"network" binding:"required,max=20"`
 	Email    string `json:"email" binding:"required,email"`
 	Website  string `json:"website"`
 }
 
 // RegistrationResponse is the API response for registration
 type RegistrationResponse struct {
-	ID      int    `json:"id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	ID           int                    `json:"id"`
+	Status       string                 `json:"status"`
+	Message      string                 `json:"message"`
+	Verification *VerificationChallenge `json:"verification,omitempty"`
+}
+
+// Ownership verification methods. JSON-RPC endpoints are verified over
+// HTTP or DNS, gRPC nodes with a signature from their peer or a validator
+// key.
+const (
+	VerificationHTTP         = "http"
+	VerificationDNS          = "dns"
+	VerificationPeerKey      = "peer_key"
+	VerificationValidatorKey = "validator_key"
+)
+
+// Where operators publish the challenge token
+const (
+	VerificationPath      = "/.well-known/pactus-tracker-verification.txt"
+	VerificationDNSPrefix = "_pactus-tracker."
+	VerificationTXTPrefix = "pactus-tracker-verification="
+)
+
+// VerificationChallenge tells the submitter how to prove they operate the
+// registered endpoint
+type VerificationChallenge struct {
+	Token       string   `json:"token"`
+	Methods     []string `json:"methods"`
+	HTTPURL     string   `json:"httpUrl,omitempty"`
+	DNSName     string   `json:"dnsName,omitempty"`
+	DNSValue    string   `json:"dnsValue,omitempty"`
+	SignMessage string   `json:"signMessage,omitempty"`
+}
+
+// VerificationMessage is the message gRPC operators sign to prove they
+// control a node
+func VerificationMessage(address, token string) string {
+	return "pactus-tracker-verification:" + address + ":" + token
+}
+
+// VerificationResult is the API response for an ownership check
+type VerificationResult struct {
+	ID       int    `json:"id"`
+	Verified bool   `json:"verified"`
+	Method   string `json:"method"`
+	Status   string `json:"status"`
 }
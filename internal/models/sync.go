@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// SyncRun is the report of a bootstrap node or gRPC server sync: the nodes
+// it added, updated, deactivated and reactivated across all networks.
+// Errors counts the nodes that could not be stored and the networks whose
+// list could not be loaded; Error describes the failures. A dry run only
+// reports the changes a sync would make.
+type SyncRun struct {
+	ID          int          `json:"id" db:"id"`
+	NodeType    string       `json:"nodeType" db:"node_type"`
+	Source      string       `json:"source" db:"source"`
+	Added       []SyncChange `json:"added" db:"added"`
+	Updated     []SyncChange `json:"updated" db:"updated"`
+	Deactivated []SyncChange `json:"deactivated" db:"deactivated"`
+	Reactivated []SyncChange `json:"reactivated" db:"reactivated"`
+	DryRun      bool         `json:"dryRun" db:"-"`
+	Errors      int          `json:"errors" db:"errors"`
+	Error       string       `json:"error,omitempty" db:"error"`
+	StartedAt   time.Time    `json:"startedAt" db:"started_at"`
+	FinishedAt  time.Time    `json:"finishedAt" db:"finished_at"`
+}
+
+// SyncChange is a node a sync added, updated, deactivated or reactivated.
+// Fields lists the fields an update changed.
+type SyncChange struct {
+	Network string   `json:"network"`
+	Address string   `json:"address"`
+	Name    string   `json:"name"`
+	Fields  []string `json:"fields,omitempty"`
+}
+
+// Fail counts a failure of the run and adds it to Error
+func (r *SyncRun) Fail(err error) {
+	r.Errors++
+	if r.Error != "" {
+		r.Error += "; "
+	}
+	r.Error += err.Error()
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// TopologyNode is a crawled peer in the network graph
+type TopologyNode struct {
+	ID           int    `json:"id"`
+	PeerID       string `json:"peerId"`
+	Address      string `json:"address"`
+	CountryCode  string `json:"countryCode"`
+	ASN          string `json:"asn"`
+	Organization string `json:"organization"`
+	UserAgent    string `json:"userAgent"`
+	BootstrapID  int    `json:"bootstrapId,omitempty"`
+	Degree       int    `json:"degree"`
+	Component    int    `json:"component"`
+}
+
+// TopologyEdge is a connection the source peer reported to the target peer
+type TopologyEdge struct {
+	Source    int       `json:"source"`
+	Target    int       `json:"target"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// DegreeStats summarizes how many connections the peers in a graph have
+type DegreeStats struct {
+	Min      int     `json:"min"`
+	Max      int     `json:"max"`
+	Mean     float64 `json:"mean"`
+	Median   float64 `json:"median"`
+	Isolated int     `json:"isolated"`
+}
+
+// TopologyComponent is a set of peers connected to each other but not to
+// the rest of the graph. Component 1 is the largest.
+type TopologyComponent struct {
+	ID             int `json:"id"`
+	Size           int `json:"size"`
+	BootstrapNodes int `json:"bootstrapNodes"`
+}
+
+// BootstrapCentrality describes how central a bootstrap node is in the
+// graph. Betweenness is the normalized share of shortest paths between
+// other peers that pass through it, in the whole graph of the network over
+// the default window, as last computed by the scheduled job.
+type BootstrapCentrality struct {
+	BootstrapID int     `json:"bootstrapId"`
+	Name        string  `json:"name"`
+	PeerID      string  `json:"peerId"`
+	InGraph     bool    `json:"inGraph"`
+	Degree      int     `json:"degree"`
+	Component   int     `json:"component"`
+	Betweenness float64 `json:"betweenness"`
+}
+
+// Topology is the response of the getTopology API
+type Topology struct {
+	Network    string                 `json:"network"`
+	Nodes      []*TopologyNode        `json:"nodes"`
+	Edges      []*TopologyEdge        `json:"edges"`
+	Degree     DegreeStats            `json:"degree"`
+	Components []*TopologyComponent   `json:"components"`
+	Bootstrap  []*BootstrapCentrality `json:"bootstrap"`
+	Since      time.Time              `json:"since"`
+	// CentralityComputedAt is when the betweenness values were computed,
+	// nil before the first run
+	CentralityComputedAt *time.Time `json:"centralityComputedAt,omitempty"`
+}
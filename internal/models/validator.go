@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Validator is the latest state of a validator as reported by the trusted
+// gRPC server. Stake is in NanoPAC. PeerID is the libp2p peer that last
+// announced the validator's consensus key; Peer is set when the crawler
+// also knows that peer.
+type Validator struct {
+	ID                  int            `json:"-" db:"id"`
+	Address             string         `json:"address" db:"address"`
+	Number              int32          `json:"number" db:"number"`
+	PublicKey           string         `json:"publicKey" db:"public_key"`
+	Stake               int64          `json:"stake" db:"stake"`
+	AvailabilityScore   float64        `json:"availabilityScore" db:"availability_score"`
+	LastBondingHeight   uint32         `json:"lastBondingHeight" db:"last_bonding_height"`
+	LastSortitionHeight uint32         `json:"lastSortitionHeight" db:"last_sortition_height"`
+	UnbondingHeight     uint32         `json:"unbondingHeight" db:"unbonding_height"`
+	ProtocolVersion     int32          `json:"protocolVersion" db:"protocol_version"`
+	InCommittee         bool           `json:"inCommittee" db:"in_committee"`
+	PeerID              string         `json:"peerId,omitempty" db:"-"`
+	Peer                *ValidatorPeer `json:"peer,omitempty" db:"-"`
+	FirstSeen           time.Time      `json:"firstSeen" db:"first_seen"`
+	LastSeen            time.Time      `json:"lastSeen" db:"last_seen"`
+}
+
+// ValidatorPeer is the crawled peer a validator runs on
+type ValidatorPeer struct {
+	ID          int       `json:"id"`
+	PeerID      string    `json:"peerId"`
+	CountryCode string    `json:"countryCode"`
+	ASN         string    `json:"asn"`
+	Reachable   bool      `json:"reachable"`
+	LastSeen    time.Time `json:"lastSeen"`
+}
+
+// ValidatorBlock is a synced block with its proposer and the certificate
+// of the previous block it carries. Committers and absentees are validator
+// numbers.
+type ValidatorBlock struct {
+	Height          uint32    `json:"height" db:"height"`
+	ProposerAddress string    `json:"proposerAddress" db:"proposer_address"`
+	BlockTime       time.Time `json:"blockTime" db:"block_time"`
+	CertRound       int32     `json:"certRound" db:"cert_round"`
+	CertCommitters  []int32   `json:"certCommitters" db:"cert_committers"`
+	CertAbsentees   []int32   `json:"certAbsentees" db:"cert_absentees"`
+}
+
+// Committee is the response of the getCommittee API: the committee of a
+// network at the latest sync. Powers are in NanoPAC.
+type Committee struct {
+	Network          string       `json:"network"`
+	Height           uint32       `json:"height"`
+	TotalValidators  int32        `json:"totalValidators"`
+	ActiveValidators int32        `json:"activeValidators"`
+	TotalPower       int64        `json:"totalPower"`
+	CommitteePower   int64        `json:"committeePower"`
+	Members          []*Validator `json:"members"`
+	RecordedAt       time.Time    `json:"recordedAt"`
+}
+
+// ValidatorList is the response of the getValidators API
+type ValidatorList struct {
+	Network    string       `json:"network"`
+	Validators []*Validator `json:"validators"`
+	Total      int          `json:"total"`
+}
+
+// ValidatorDay is a validator's stake, availability score and committee
+// membership on one day
+type ValidatorDay struct {
+	Date              string  `json:"date"`
+	Stake             int64   `json:"stake"`
+	AvailabilityScore float64 `json:"availabilityScore"`
+	InCommittee       bool    `json:"inCommittee"`
+}
+
+// ValidatorUptime is the response of the getValidatorUptime API.
+// Certificates counts the synced certificates the validator was a committee
+// member in, Signed and Missed how many of them it signed or was absent
+// from, and Uptime the signed share in percent.
+type ValidatorUptime struct {
+	Validator    *Validator      `json:"validator"`
+	Since        time.Time       `json:"since"`
+	Certificates int             `json:"certificates"`
+	Signed       int             `json:"signed"`
+	Missed       int             `json:"missed"`
+	Uptime       float64         `json:"uptime"`
+	Proposed     int             `json:"proposed"`
+	History      []*ValidatorDay `json:"history"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// NodeTypePeer identifies a network peer found while crawling the peers
+// connected to a gRPC server
+const NodeTypePeer = "peer"
+
+// UnknownVersion groups nodes whose agent string could not be parsed
+const UnknownVersion = "unknown"
+
+// NodeVersion is an agent a node reported between FirstSeen and LastSeen
+type NodeVersion struct {
+	ID        int       `json:"id" db:"id"`
+	NodeType  string    `json:"nodeType" db:"node_type"`
+	NodeID    int       `json:"nodeId" db:"node_id"`
+	Name      string    `json:"name,omitempty" db:"-"`
+	Address   string    `json:"address,omitempty" db:"-"`
+	Agent     string    `json:"agent" db:"agent"`
+	Version   string    `json:"version" db:"version"`
+	FirstSeen time.Time `json:"firstSeen" db:"first_seen"`
+	LastSeen  time.Time `json:"lastSeen" db:"last_seen"`
+}
+
+// VersionCount is the number of nodes running one software version
+type VersionCount struct {
+	Version  string         `json:"version"`
+	Nodes    int            `json:"nodes"`
+	Share    float64        `json:"share"`
+	Outdated bool           `json:"outdated"`
+	ByType   map[string]int `json:"byType"`
+}
+
+// VersionDistribution is the response of the getVersionDistribution API.
+// Adoption is the share of nodes running LatestVersion.
+type VersionDistribution struct {
+	Network         string          `json:"network"`
+	LatestVersion   string          `json:"latestVersion"`
+	TotalNodes      int             `json:"totalNodes"`
+	Adoption        float64         `json:"adoption"`
+	Versions        []*VersionCount `json:"versions"`
+	OutdatedServers []*NodeVersion  `json:"outdatedServers"`
+	Since           time.Time       `json:"since"`
+}
+
+// VersionHistoryResponse is the response of the getVersionHistory API
+type VersionHistoryResponse struct {
+	NodeType string         `json:"nodeType"`
+	NodeID   int            `json:"nodeId"`
+	History  []*NodeVersion `json:"history"`
+}
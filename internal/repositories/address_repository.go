@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// AddressRepository defines the interface for peer address and endpoint
+// check data access
+type AddressRepository interface {
+	RecordPeerAddresses(ctx context.Context, addresses []*models.PeerAddress) error
+	RecordEndpointChecks(ctx context.Context, nodeType string, nodeID int, checks []*models.EndpointCheck) error
+	GetEndpointChecks(ctx context.Context, nodeType string) (map[int][]*models.EndpointCheck, error)
+	GetFamilyReachability(ctx context.Context, network string, since time.Time) ([]*models.FamilyReachability, error)
+	GetPeerAddressFamilies(ctx context.Context, network string, since time.Time) ([]*models.AddressFamilyCount, error)
+}
+
+type addressRepository struct {
+	db *sql.DB
+}
+
+// NewAddressRepository creates a new address repository
+func NewAddressRepository(db *sql.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// RecordPeerAddresses stores the advertised addresses of crawled peers.
+// Known addresses only move last_seen forward.
+func (r *addressRepository) RecordPeerAddresses(ctx context.Context, addresses []*models.PeerAddress) error {
+	query := `
+		INSERT INTO peer_addresses (peer_id, multiaddr, family, transport, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (peer_id, multiaddr)
+		DO UPDATE SET last_seen = GREATEST(peer_addresses.last_seen, EXCLUDED.last_seen)
+	`
+
+	for _, a := range addresses {
+		if _, err := r.db.ExecContext(ctx, query, a.PeerID, a.Multiaddr, a.Family, a.Transport, a.LastSeen); err != nil {
+			return fmt.Errorf("record peer address: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RecordEndpointChecks replaces the endpoint checks of a node with the
+// latest ones. Endpoints the node no longer resolves to are removed.
+func (r *addressRepository) RecordEndpointChecks(ctx context.Context, nodeType string, nodeID int, checks []*models.EndpointCheck) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM endpoint_checks WHERE node_type = $1 AND node_id = $2`, nodeType, nodeID); err != nil {
+		return fmt.Errorf("delete endpoint checks: %w", err)
+	}
+
+	query := `
+		INSERT INTO endpoint_checks (node_type, node_id, multiaddr, family, transport, reachable, response_time_ms, error_class, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (node_type, node_id, multiaddr) DO NOTHING
+	`
+	for _, c := range checks {
+		if _, err := tx.ExecContext(ctx, query,
+			nodeType, nodeID, c.Multiaddr, c.Family, c.Transport,
+			c.Reachable, c.ResponseTimeMs, string(c.ErrorClass), c.CheckedAt,
+		); err != nil {
+			return fmt.Errorf("insert endpoint check: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit endpoint checks: %w", err)
+	}
+	return nil
+}
+
+// GetEndpointChecks returns the endpoint checks of every node of a type,
+// keyed by node ID
+func (r *addressRepository) GetEndpointChecks(ctx context.Context, nodeType string) (map[int][]*models.EndpointCheck, error) {
+	query := `
+		SELECT id, node_type, node_id, multiaddr, family, transport, reachable, response_time_ms, error_class, checked_at
+		FROM endpoint_checks
+		WHERE node_type = $1
+		ORDER BY node_id, family, transport, multiaddr
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType)
+	if err != nil {
+		return nil, fmt.Errorf("query endpoint checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := make(map[int][]*models.EndpointCheck)
+	for rows.Next() {
+		c := &models.EndpointCheck{}
+		var errorClass string
+		if err := rows.Scan(
+			&c.ID, &c.NodeType, &c.NodeID, &c.Multiaddr, &c.Family, &c.Transport,
+			&c.Reachable, &c.ResponseTimeMs, &errorClass, &c.CheckedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan endpoint check: %w", err)
+		}
+		c.ErrorClass = models.ErrorClass(errorClass)
+		checks[c.NodeID] = append(checks[c.NodeID], c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return checks, nil
+}
+
+// GetFamilyReachability counts the endpoint checks of the bootstrap nodes
+// of a network since a time per address family and transport
+func (r *addressRepository) GetFamilyReachability(ctx context.Context, network string, since time.Time) ([]*models.FamilyReachability, error) {
+	query := `
+		SELECT c.family, c.transport, COUNT(*), COUNT(*) FILTER (WHERE c.reachable)
+		FROM endpoint_checks c
+		JOIN bootstrap_nodes b ON c.node_type = 'bootstrap' AND b.id = c.node_id
+		WHERE c.checked_at >= $1 AND b.network = $2
+		GROUP BY c.family, c.transport
+		ORDER BY c.family, c.transport
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since, network)
+	if err != nil {
+		return nil, fmt.Errorf("query family reachability: %w", err)
+	}
+	defer rows.Close()
+
+	var families []*models.FamilyReachability
+	for rows.Next() {
+		f := &models.FamilyReachability{}
+		if err := rows.Scan(&f.Family, &f.Transport, &f.Checked, &f.Reachable); err != nil {
+			return nil, fmt.Errorf("scan family reachability: %w", err)
+		}
+		families = append(families, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return families, nil
+}
+
+// GetPeerAddressFamilies counts the peers of a network that advertised an
+// address of each family and transport since a time
+func (r *addressRepository) GetPeerAddressFamilies(ctx context.Context, network string, since time.Time) ([]*models.AddressFamilyCount, error) {
+	query := `
+		SELECT a.family, a.transport, COUNT(DISTINCT a.peer_id)
+		FROM peer_addresses a
+		JOIN reachable_peers p ON p.id = a.peer_id
+		WHERE a.last_seen >= $1 AND p.network = $2
+		GROUP BY a.family, a.transport
+		ORDER BY a.family, a.transport
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since, network)
+	if err != nil {
+		return nil, fmt.Errorf("query peer address families: %w", err)
+	}
+	defer rows.Close()
+
+	var families []*models.AddressFamilyCount
+	for rows.Next() {
+		f := &models.AddressFamilyCount{}
+		if err := rows.Scan(&f.Family, &f.Transport, &f.Peers); err != nil {
+			return nil, fmt.Errorf("scan peer address family: %w", err)
+		}
+		families = append(families, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return families, nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// AuditRepository defines the interface for node audit log data access
+type AuditRepository interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	ListForNode(ctx context.Context, nodeType string, nodeID, limit int) ([]*models.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO node_audit_log (node_type, node_id, actor, action, changes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	var changes interface{}
+	if len(entry.Changes) > 0 {
+		changes = []byte(entry.Changes)
+	}
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.NodeType, entry.NodeID, entry.Actor, entry.Action, changes,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *auditRepository) ListForNode(ctx context.Context, nodeType string, nodeID, limit int) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, node_type, node_id, actor, action, changes, created_at
+		FROM node_audit_log
+		WHERE node_type = $1 AND node_id = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, nodeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		var changes []byte
+		err := rows.Scan(&entry.ID, &entry.NodeType, &entry.NodeID, &entry.Actor, &entry.Action, &changes, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		entry.Changes = changes
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return entries, nil
+}
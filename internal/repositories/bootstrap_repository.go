@@ -16,6 +16,8 @@ type BootstrapRepository interface {
 	GetAllNodes(ctx context.Context) ([]*models.BootstrapNode, error)
 	GetNodeByID(ctx context.Context, id int) (*models.BootstrapNode, error)
 	GetNodeByAddress(ctx context.Context, address string) (*models.BootstrapNode, error)
+	GetNodesByNetwork(ctx context.Context, network string) ([]*models.BootstrapNode, error)
+	ListNodes(ctx context.Context, filter models.NodeFilter) ([]*models.BootstrapNode, error)
 
 	// CRUD operations
 	CreateNode(ctx context.Context, node *models.BootstrapNode) error
@@ -23,11 +25,13 @@ type BootstrapRepository interface {
 	UpdateNodeScore(ctx context.Context, nodeID int, score float64) error
 	UpdateNodeGeo(ctx context.Context, nodeID int, country, countryCode, city string, lat, lon float64) error
 	DeactivateNodes(ctx context.Context, addresses []string) error
+	ReactivateNodes(ctx context.Context, addresses []string) error
 
 	// Aggregations
 	GetNodeCount(ctx context.Context, activeOnly bool) (int, error)
 	GetActiveCount(ctx context.Context) (int, error)
 	UpdateAllScores(ctx context.Context) error
+	RefreshNodeScore(ctx context.Context, nodeID int) error
 }
 
 type bootstrapRepository struct {
@@ -41,7 +45,7 @@ func NewBootstrapRepository(db *sql.DB) BootstrapRepository {
 
 func (r *bootstrapRepository) GetActiveNodes(ctx context.Context) ([]*models.BootstrapNode, error) {
 	query := `
-		SELECT id, name, email, website, address, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+		SELECT id, name, email, website, address, network, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 		FROM bootstrap_nodes 
 		WHERE is_active = true
 		ORDER BY id
@@ -58,7 +62,7 @@ func (r *bootstrapRepository) GetActiveNodes(ctx context.Context) ([]*models.Boo
 
 func (r *bootstrapRepository) GetAllNodes(ctx context.Context) ([]*models.BootstrapNode, error) {
 	query := `
-		SELECT id, name, email, website, address, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+		SELECT id, name, email, website, address, network, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 		FROM bootstrap_nodes 
 		ORDER BY id
 	`
@@ -74,21 +78,21 @@ func (r *bootstrapRepository) GetAllNodes(ctx context.Context) ([]*models.Bootst
 
 func (r *bootstrapRepository) GetNodeByID(ctx context.Context, id int) (*models.BootstrapNode, error) {
 	query := `
-		SELECT id, name, email, website, address, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+		SELECT id, name, email, website, address, network, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 		FROM bootstrap_nodes 
 		WHERE id = $1
 	`
 
 	node := &models.BootstrapNode{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&node.ID, &node.Name, &node.Email, &node.Website, &node.Address,
+		&node.ID, &node.Name, &node.Email, &node.Website, &node.Address, &node.Network,
 		&node.OverallScore, &node.IsActive,
 		&node.Country, &node.CountryCode, &node.City, &node.Latitude, &node.Longitude,
 		&node.CreatedAt, &node.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("node not found: %d", id)
+		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get node by id: %w", err)
@@ -99,14 +103,14 @@ func (r *bootstrapRepository) GetNodeByID(ctx context.Context, id int) (*models.
 
 func (r *bootstrapRepository) GetNodeByAddress(ctx context.Context, address string) (*models.BootstrapNode, error) {
 	query := `
-		SELECT id, name, email, website, address, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+		SELECT id, name, email, website, address, network, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 		FROM bootstrap_nodes 
 		WHERE address = $1
 	`
 
 	node := &models.BootstrapNode{}
 	err := r.db.QueryRowContext(ctx, query, address).Scan(
-		&node.ID, &node.Name, &node.Email, &node.Website, &node.Address,
+		&node.ID, &node.Name, &node.Email, &node.Website, &node.Address, &node.Network,
 		&node.OverallScore, &node.IsActive,
 		&node.Country, &node.CountryCode, &node.City, &node.Latitude, &node.Longitude,
 		&node.CreatedAt, &node.UpdatedAt,
@@ -122,16 +126,49 @@ func (r *bootstrapRepository) GetNodeByAddress(ctx context.Context, address stri
 	return node, nil
 }
 
+// GetNodesByNetwork returns the active nodes of a network
+func (r *bootstrapRepository) GetNodesByNetwork(ctx context.Context, network string) ([]*models.BootstrapNode, error) {
+	query := `
+		SELECT id, name, email, website, address, network, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+		FROM bootstrap_nodes
+		WHERE network = $1 AND is_active = true
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, network)
+	if err != nil {
+		return nil, fmt.Errorf("query nodes by network: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanNodes(rows)
+}
+
+// ListNodes returns one page of active nodes plus one extra row when
+// another page follows
+func (r *bootstrapRepository) ListNodes(ctx context.Context, filter models.NodeFilter) ([]*models.BootstrapNode, error) {
+	columns := `id, name, email, website, address, network, overall_score, is_active, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at`
+	query, args := buildNodeListQuery(columns, "bootstrap_nodes", true, filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanNodes(rows)
+}
+
 func (r *bootstrapRepository) CreateNode(ctx context.Context, node *models.BootstrapNode) error {
 	query := `
-		INSERT INTO bootstrap_nodes (name, email, website, address, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		INSERT INTO bootstrap_nodes (name, email, website, address, network, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
 		ON CONFLICT (address) DO NOTHING
 		RETURNING id, created_at, updated_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
-		node.Name, node.Email, node.Website, node.Address, node.IsActive,
+		node.Name, node.Email, node.Website, node.Address, models.NetworkOrDefault(node.Network), node.IsActive,
 	).Scan(&node.ID, &node.CreatedAt, &node.UpdatedAt)
 
 	if err == sql.ErrNoRows {
@@ -209,6 +246,26 @@ func (r *bootstrapRepository) DeactivateNodes(ctx context.Context, addresses []s
 	return nil
 }
 
+// ReactivateNodes reactivates nodes that returned to their list
+func (r *bootstrapRepository) ReactivateNodes(ctx context.Context, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE bootstrap_nodes
+		SET is_active = true, updated_at = NOW()
+		WHERE address = ANY($1)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(addresses))
+	if err != nil {
+		return fmt.Errorf("reactivate nodes: %w", err)
+	}
+
+	return nil
+}
+
 func (r *bootstrapRepository) GetNodeCount(ctx context.Context, activeOnly bool) (int, error) {
 	query := `SELECT COUNT(*) FROM bootstrap_nodes`
 	if activeOnly {
@@ -230,18 +287,20 @@ func (r *bootstrapRepository) UpdateAllScores(ctx context.Context) error {
 		SET overall_score = (
 			SELECT COALESCE(
 				ROUND(
-					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / COUNT(*))::numeric, 2
+					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0))::numeric, 2
 				), 0
 			)
 			FROM daily_status 
 			WHERE node_id = bootstrap_nodes.id 
 			AND date >= CURRENT_DATE - INTERVAL '30 days'
+			AND color <> $1
 		),
 		updated_at = NOW()
 		WHERE is_active = true
 	`
 
-	_, err := r.db.ExecContext(ctx, query)
+	// Days in maintenance count neither for nor against the node
+	_, err := r.db.ExecContext(ctx, query, models.StatusColorMaintenance)
 	if err != nil {
 		return fmt.Errorf("update all scores: %w", err)
 	}
@@ -249,6 +308,32 @@ func (r *bootstrapRepository) UpdateAllScores(ctx context.Context) error {
 	return nil
 }
 
+// RefreshNodeScore recomputes the 30-day score of one node from its daily history
+func (r *bootstrapRepository) RefreshNodeScore(ctx context.Context, nodeID int) error {
+	query := `
+		UPDATE bootstrap_nodes
+		SET overall_score = (
+			SELECT COALESCE(
+				ROUND(
+					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0))::numeric, 2
+				), 0
+			)
+			FROM daily_status
+			WHERE node_id = bootstrap_nodes.id
+			AND date >= CURRENT_DATE - INTERVAL '30 days'
+			AND color <> $2
+		),
+		updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, nodeID, models.StatusColorMaintenance); err != nil {
+		return fmt.Errorf("refresh node score: %w", err)
+	}
+
+	return nil
+}
+
 func (r *bootstrapRepository) GetActiveCount(ctx context.Context) (int, error) {
 	return r.GetNodeCount(ctx, true)
 }
@@ -284,7 +369,7 @@ func (r *bootstrapRepository) scanNodes(rows *sql.Rows) ([]*models.BootstrapNode
 	for rows.Next() {
 		node := &models.BootstrapNode{}
 		err := rows.Scan(
-			&node.ID, &node.Name, &node.Email, &node.Website, &node.Address,
+			&node.ID, &node.Name, &node.Email, &node.Website, &node.Address, &node.Network,
 			&node.OverallScore, &node.IsActive,
 			&node.Country, &node.CountryCode, &node.City, &node.Latitude, &node.Longitude,
 			&node.CreatedAt, &node.UpdatedAt,
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// CertificateRepository defines the interface for TLS certificate data access
+type CertificateRepository interface {
+	UpsertCertificate(ctx context.Context, cert *models.CertificateInfo) error
+	GetCertificate(ctx context.Context, nodeType string, nodeID int) (*models.CertificateInfo, error)
+	GetAllCertificates(ctx context.Context) ([]*models.CertificateInfo, error)
+	DeleteCertificate(ctx context.Context, nodeType string, nodeID int) error
+}
+
+type certificateRepository struct {
+	db *sql.DB
+}
+
+// NewCertificateRepository creates a new certificate repository
+func NewCertificateRepository(db *sql.DB) CertificateRepository {
+	return &certificateRepository{db: db}
+}
+
+const certificateColumns = `
+	id, node_type, node_id, address, COALESCE(subject, ''), COALESCE(issuer, ''), COALESCE(sans, '{}'),
+	COALESCE(serial_number, ''), COALESCE(fingerprint_sha256, ''), not_before, not_after,
+	is_trusted, is_self_signed, is_expired, expires_soon, COALESCE(verify_error, ''), checked_at
+`
+
+func (r *certificateRepository) UpsertCertificate(ctx context.Context, cert *models.CertificateInfo) error {
+	query := `
+		INSERT INTO tls_certificates (
+			node_type, node_id, address, subject, issuer, sans, serial_number, fingerprint_sha256,
+			not_before, not_after, is_trusted, is_self_signed, is_expired, expires_soon, verify_error, checked_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (node_type, node_id)
+		DO UPDATE SET
+			address = EXCLUDED.address,
+			subject = EXCLUDED.subject,
+			issuer = EXCLUDED.issuer,
+			sans = EXCLUDED.sans,
+			serial_number = EXCLUDED.serial_number,
+			fingerprint_sha256 = EXCLUDED.fingerprint_sha256,
+			not_before = EXCLUDED.not_before,
+			not_after = EXCLUDED.not_after,
+			is_trusted = EXCLUDED.is_trusted,
+			is_self_signed = EXCLUDED.is_self_signed,
+			is_expired = EXCLUDED.is_expired,
+			expires_soon = EXCLUDED.expires_soon,
+			verify_error = EXCLUDED.verify_error,
+			checked_at = EXCLUDED.checked_at
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		cert.NodeType, cert.NodeID, cert.Address, cert.Subject, cert.Issuer, pq.Array(cert.SANs),
+		cert.SerialNumber, cert.FingerprintSHA256, cert.NotBefore, cert.NotAfter,
+		cert.IsTrusted, cert.IsSelfSigned, cert.IsExpired, cert.ExpiresSoon, cert.VerifyError, cert.CheckedAt,
+	).Scan(&cert.ID)
+
+	if err != nil {
+		return fmt.Errorf("upsert certificate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *certificateRepository) GetCertificate(ctx context.Context, nodeType string, nodeID int) (*models.CertificateInfo, error) {
+	query := `SELECT ` + certificateColumns + ` FROM tls_certificates WHERE node_type = $1 AND node_id = $2`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("query certificate: %w", err)
+	}
+	defer rows.Close()
+
+	certs, err := r.scanCertificates(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, nil // Not found is not an error
+	}
+
+	return certs[0], nil
+}
+
+func (r *certificateRepository) GetAllCertificates(ctx context.Context) ([]*models.CertificateInfo, error) {
+	query := `SELECT ` + certificateColumns + ` FROM tls_certificates ORDER BY not_after ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query certificates: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanCertificates(rows)
+}
+
+func (r *certificateRepository) DeleteCertificate(ctx context.Context, nodeType string, nodeID int) error {
+	query := `DELETE FROM tls_certificates WHERE node_type = $1 AND node_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, nodeType, nodeID); err != nil {
+		return fmt.Errorf("delete certificate: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function to scan multiple certificates
+func (r *certificateRepository) scanCertificates(rows *sql.Rows) ([]*models.CertificateInfo, error) {
+	var certs []*models.CertificateInfo
+	now := time.Now()
+
+	for rows.Next() {
+		cert := &models.CertificateInfo{}
+		err := rows.Scan(
+			&cert.ID, &cert.NodeType, &cert.NodeID, &cert.Address, &cert.Subject, &cert.Issuer,
+			pq.Array(&cert.SANs), &cert.SerialNumber, &cert.FingerprintSHA256, &cert.NotBefore, &cert.NotAfter,
+			&cert.IsTrusted, &cert.IsSelfSigned, &cert.IsExpired, &cert.ExpiresSoon, &cert.VerifyError, &cert.CheckedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan certificate: %w", err)
+		}
+		cert.DaysRemaining = int(cert.NotAfter.Sub(now).Hours() / 24)
+		certs = append(certs, cert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return certs, nil
+}
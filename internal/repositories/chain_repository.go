@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// ChainRepository defines the interface for block header, trusted server
+// and chain incident data access
+type ChainRepository interface {
+	GetLastBlock(ctx context.Context, network string) (*models.ChainBlock, error)
+	RecordBlocks(ctx context.Context, network string, blocks []*models.ChainBlock) error
+	GetBlockStats(ctx context.Context, network string, since time.Time) (*models.BlockStats, error)
+	RecordSources(ctx context.Context, network string, sources []*models.ChainSource) error
+	GetSources(ctx context.Context, network string) ([]*models.ChainSource, error)
+	GetOpenIncident(ctx context.Context, network, kind string) (*models.ChainIncident, error)
+	OpenIncident(ctx context.Context, network string, incident *models.ChainIncident) error
+	CloseIncident(ctx context.Context, id int, endedAt time.Time) error
+	GetIncidents(ctx context.Context, network string, since time.Time) ([]*models.ChainIncident, error)
+}
+
+type chainRepository struct {
+	db *sql.DB
+}
+
+// NewChainRepository creates a new chain repository
+func NewChainRepository(db *sql.DB) ChainRepository {
+	return &chainRepository{db: db}
+}
+
+// incidentDetails is the details column of chain_incidents
+type incidentDetails struct {
+	LastBlockTime *time.Time        `json:"lastBlockTime,omitempty"`
+	Hashes        map[string]string `json:"hashes,omitempty"`
+}
+
+// GetLastBlock returns the highest recorded block of a network
+func (r *chainRepository) GetLastBlock(ctx context.Context, network string) (*models.ChainBlock, error) {
+	query := `
+		SELECT height, hash, block_time, proposer_address, tx_count, gap_seconds, source
+		FROM chain_blocks
+		WHERE network = $1
+		ORDER BY height DESC
+		LIMIT 1
+	`
+
+	b := &models.ChainBlock{}
+	var gap sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, network).Scan(
+		&b.Height, &b.Hash, &b.BlockTime, &b.ProposerAddress, &b.TxCount, &gap, &b.Source,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query last block: %w", err)
+	}
+	if gap.Valid {
+		seconds := int(gap.Int64)
+		b.GapSeconds = &seconds
+	}
+	return b, nil
+}
+
+// RecordBlocks stores block headers of a network. Blocks recorded before
+// are left as is.
+func (r *chainRepository) RecordBlocks(ctx context.Context, network string, blocks []*models.ChainBlock) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO chain_blocks (height, hash, block_time, proposer_address, tx_count, gap_seconds, source, network)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (network, height) DO NOTHING
+	`
+	for _, b := range blocks {
+		if _, err := tx.ExecContext(ctx, query,
+			b.Height, b.Hash, b.BlockTime, b.ProposerAddress, b.TxCount, b.GapSeconds, b.Source, network,
+		); err != nil {
+			return fmt.Errorf("insert chain block: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit chain blocks: %w", err)
+	}
+	return nil
+}
+
+// GetBlockStats summarizes the blocks of a network produced since a time
+func (r *chainRepository) GetBlockStats(ctx context.Context, network string, since time.Time) (*models.BlockStats, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COALESCE(AVG(gap_seconds), 0),
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY gap_seconds), 0),
+			COALESCE(MAX(gap_seconds), 0),
+			COALESCE(SUM(tx_count), 0),
+			COALESCE(AVG(tx_count), 0),
+			COUNT(DISTINCT proposer_address)
+		FROM chain_blocks
+		WHERE block_time >= $1 AND network = $2
+	`
+
+	stats := &models.BlockStats{}
+	if err := r.db.QueryRowContext(ctx, query, since, network).Scan(
+		&stats.Blocks, &stats.AvgBlockSeconds, &stats.P95BlockSeconds, &stats.MaxGapSeconds,
+		&stats.Transactions, &stats.AvgTxPerBlock, &stats.Proposers,
+	); err != nil {
+		return nil, fmt.Errorf("query block stats: %w", err)
+	}
+	return stats, nil
+}
+
+// RecordSources stores the latest poll of each trusted server of a network
+func (r *chainRepository) RecordSources(ctx context.Context, network string, sources []*models.ChainSource) error {
+	query := `
+		INSERT INTO chain_sources (address, kind, height, hash, error, checked_at, network)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (network, address) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			height = EXCLUDED.height,
+			hash = EXCLUDED.hash,
+			error = EXCLUDED.error,
+			checked_at = EXCLUDED.checked_at
+	`
+
+	for _, s := range sources {
+		if _, err := r.db.ExecContext(ctx, query, s.Address, s.Kind, s.Height, s.Hash, s.Error, s.CheckedAt, network); err != nil {
+			return fmt.Errorf("record chain source: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetSources returns the latest poll of every trusted server of a network
+func (r *chainRepository) GetSources(ctx context.Context, network string) ([]*models.ChainSource, error) {
+	query := `
+		SELECT address, kind, height, hash, error, checked_at
+		FROM chain_sources
+		WHERE network = $1
+		ORDER BY address
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, network)
+	if err != nil {
+		return nil, fmt.Errorf("query chain sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []*models.ChainSource
+	for rows.Next() {
+		s := &models.ChainSource{}
+		if err := rows.Scan(&s.Address, &s.Kind, &s.Height, &s.Hash, &s.Error, &s.CheckedAt); err != nil {
+			return nil, fmt.Errorf("scan chain source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return sources, nil
+}
+
+// GetOpenIncident returns the open incident of a kind on a network
+func (r *chainRepository) GetOpenIncident(ctx context.Context, network, kind string) (*models.ChainIncident, error) {
+	query := `
+		SELECT id, kind, height, details, started_at, ended_at
+		FROM chain_incidents
+		WHERE network = $1 AND kind = $2 AND ended_at IS NULL
+	`
+
+	incident, err := scanIncident(r.db.QueryRowContext(ctx, query, network, kind))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return incident, err
+}
+
+// OpenIncident stores a new incident of a network and sets its ID
+func (r *chainRepository) OpenIncident(ctx context.Context, network string, incident *models.ChainIncident) error {
+	details, err := json.Marshal(incidentDetails{LastBlockTime: incident.LastBlockTime, Hashes: incident.Hashes})
+	if err != nil {
+		return fmt.Errorf("marshal incident details: %w", err)
+	}
+
+	query := `
+		INSERT INTO chain_incidents (kind, height, details, started_at, network)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	if err := r.db.QueryRowContext(ctx, query, incident.Kind, incident.Height, details, incident.StartedAt, network).Scan(&incident.ID); err != nil {
+		return fmt.Errorf("insert chain incident: %w", err)
+	}
+	return nil
+}
+
+// CloseIncident ends an open incident
+func (r *chainRepository) CloseIncident(ctx context.Context, id int, endedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE chain_incidents SET ended_at = $2 WHERE id = $1 AND ended_at IS NULL`, id, endedAt); err != nil {
+		return fmt.Errorf("close chain incident: %w", err)
+	}
+	return nil
+}
+
+// GetIncidents returns the incidents of a network started since a time and
+// those still open, newest first
+func (r *chainRepository) GetIncidents(ctx context.Context, network string, since time.Time) ([]*models.ChainIncident, error) {
+	query := `
+		SELECT id, kind, height, details, started_at, ended_at
+		FROM chain_incidents
+		WHERE network = $1 AND (started_at >= $2 OR ended_at IS NULL)
+		ORDER BY started_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, network, since)
+	if err != nil {
+		return nil, fmt.Errorf("query chain incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []*models.ChainIncident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// scanIncident scans a chain_incidents row and decodes its details
+func scanIncident(row interface{ Scan(dest ...any) error }) (*models.ChainIncident, error) {
+	incident := &models.ChainIncident{}
+	var raw []byte
+	var endedAt sql.NullTime
+	err := row.Scan(&incident.ID, &incident.Kind, &incident.Height, &raw, &incident.StartedAt, &endedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan chain incident: %w", err)
+	}
+
+	var details incidentDetails
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, fmt.Errorf("decode chain incident details: %w", err)
+	}
+	incident.LastBlockTime = details.LastBlockTime
+	incident.Hashes = details.Hashes
+	if endedAt.Valid {
+		incident.EndedAt = &endedAt.Time
+	}
+	return incident, nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// ChurnRepository defines the interface for peer session data access
+type ChurnRepository interface {
+	RecordSighting(ctx context.Context, peerID int, seenAt time.Time, gap time.Duration) error
+	GetSessions(ctx context.Context, network string, from, to time.Time) ([]*models.PeerSession, error)
+}
+
+type churnRepository struct {
+	db *sql.DB
+}
+
+// NewChurnRepository creates a new churn repository
+func NewChurnRepository(db *sql.DB) ChurnRepository {
+	return &churnRepository{db: db}
+}
+
+// RecordSighting extends the latest session of a peer when it was seen
+// within gap of seenAt, and starts a new session otherwise
+func (r *churnRepository) RecordSighting(ctx context.Context, peerID int, seenAt time.Time, gap time.Duration) error {
+	query := `
+		WITH extended AS (
+			UPDATE peer_sessions SET last_seen = GREATEST(last_seen, $2)
+			WHERE id = (
+				SELECT id FROM peer_sessions WHERE peer_id = $1
+				ORDER BY last_seen DESC LIMIT 1
+			)
+			AND last_seen >= $2 - make_interval(secs => $3)
+			RETURNING id
+		)
+		INSERT INTO peer_sessions (peer_id, started_at, last_seen)
+		SELECT $1, $2, $2
+		WHERE NOT EXISTS (SELECT 1 FROM extended)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, peerID, seenAt, gap.Seconds()); err != nil {
+		return fmt.Errorf("record peer sighting: %w", err)
+	}
+
+	return nil
+}
+
+// GetSessions returns the sessions of the peers of a network overlapping
+// [from, to), each marked with whether it is the peer's first
+func (r *churnRepository) GetSessions(ctx context.Context, network string, from, to time.Time) ([]*models.PeerSession, error) {
+	query := `
+		SELECT s.peer_id, s.started_at, s.last_seen, s.first, COALESCE(p.first_seen, s.started_at)
+		FROM (
+			SELECT peer_id, started_at, last_seen,
+				   ROW_NUMBER() OVER (PARTITION BY peer_id ORDER BY started_at) = 1 AS first
+			FROM peer_sessions
+			WHERE peer_id IN (SELECT peer_id FROM peer_sessions WHERE last_seen >= $1 AND started_at < $2)
+		) s
+		JOIN reachable_peers p ON p.id = s.peer_id
+		WHERE s.last_seen >= $1 AND s.started_at < $2 AND p.network = $3
+		ORDER BY s.started_at, s.peer_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, network)
+	if err != nil {
+		return nil, fmt.Errorf("query peer sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.PeerSession
+	for rows.Next() {
+		s := &models.PeerSession{}
+		if err := rows.Scan(&s.PeerID, &s.StartedAt, &s.LastSeen, &s.First, &s.FirstSeen); err != nil {
+			return nil, fmt.Errorf("scan peer session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,242 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// ExportRepository streams the rows of data exports. Each method calls fn
+// with one row at a time as it is read, so exports never hold a whole
+// result in memory; an error returned by fn stops the export.
+type ExportRepository interface {
+	ExportNodes(ctx context.Context, nodeType string, filter models.NodeFilter, fn func(*models.ExportNode) error) error
+	ExportStatuses(ctx context.Context, nodeType string, filter models.NodeFilter, from, to time.Time, fn func(*models.ExportStatus) error) error
+	ExportSnapshots(ctx context.Context, network string, from, before time.Time, fn func(*models.NetworkSnapshot) error) error
+	ExportPeers(ctx context.Context, filter models.NodeFilter, fn func(*models.ReachablePeer) error) error
+	ExportMapNodes(ctx context.Context, filter models.MapFilter, fn func(*models.MapNode) error) error
+}
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// exportNodeTable describes the tables of a node type
+type exportNodeTable struct {
+	table       string
+	statusTable string
+	statusNode  string
+	verified    string
+}
+
+var exportNodeTables = map[string]exportNodeTable{
+	models.NodeTypeBootstrap: {"bootstrap_nodes", "daily_status", "node_id", "false"},
+	models.NodeTypeGRPC:      {"grpc_servers", "grpc_daily_status", "server_id", "COALESCE(is_verified, false)"},
+	models.NodeTypeJSONRPC:   {"jsonrpc_servers", "jsonrpc_daily_status", "server_id", "COALESCE(is_verified, false)"},
+}
+
+func nodeTable(nodeType string) (exportNodeTable, error) {
+	table, ok := exportNodeTables[nodeType]
+	if !ok {
+		return exportNodeTable{}, fmt.Errorf("unknown node type %q", nodeType)
+	}
+	return table, nil
+}
+
+// ExportNodes streams the active nodes of a type the filter selects, in
+// list order
+func (r *exportRepository) ExportNodes(ctx context.Context, nodeType string, filter models.NodeFilter, fn func(*models.ExportNode) error) error {
+	table, err := nodeTable(nodeType)
+	if err != nil {
+		return err
+	}
+
+	columns := fmt.Sprintf(`id, name, address, network, COALESCE(email, ''), COALESCE(website, ''), overall_score, %s, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at`, table.verified)
+	query, args := buildNodeExportQuery(columns, table.table, true, filter)
+
+	return exportRows(ctx, r.db, query, args, func(row rowScanner) (*models.ExportNode, error) {
+		node := &models.ExportNode{Type: nodeType}
+		err := row.Scan(
+			&node.ID, &node.Name, &node.Address, &node.Network, &node.Email, &node.Website,
+			&node.OverallScore, &node.IsVerified, &node.Country, &node.CountryCode, &node.City,
+			&node.Latitude, &node.Longitude, &node.CreatedAt, &node.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan export node: %w", err)
+		}
+		return node, nil
+	}, fn)
+}
+
+// ExportStatuses streams the daily statuses between from and to
+// (inclusive) of the nodes of a type the filter selects, ordered by node
+// and date
+func (r *exportRepository) ExportStatuses(ctx context.Context, nodeType string, filter models.NodeFilter, from, to time.Time, fn func(*models.ExportStatus) error) error {
+	table, err := nodeTable(nodeType)
+	if err != nil {
+		return err
+	}
+
+	nodes, args := buildNodeExportQuery("id, name, network", table.table, true, filter)
+	args = append(args, from, to)
+	query := fmt.Sprintf(`
+		SELECT n.id, n.name, n.network, s.date, s.color, COALESCE(s.attempts, 0), COALESCE(s.success, false),
+			   COALESCE(s.response_time_ms, 0), COALESCE(s.error_msg, ''), COALESCE(s.error_class, '')
+		FROM (%s) AS n
+		JOIN %s AS s ON s.%s = n.id
+		WHERE s.date >= $%d AND s.date <= $%d
+		ORDER BY n.id, s.date
+	`, nodes, table.statusTable, table.statusNode, len(args)-1, len(args))
+
+	return exportRows(ctx, r.db, query, args, func(row rowScanner) (*models.ExportStatus, error) {
+		status := &models.ExportStatus{Type: nodeType}
+		err := row.Scan(
+			&status.NodeID, &status.Name, &status.Network, &status.Date, &status.Color, &status.Attempts,
+			&status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.ErrorClass,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan export status: %w", err)
+		}
+		return status, nil
+	}, fn)
+}
+
+// ExportSnapshots streams the snapshots of a network taken from from
+// (inclusive) until before (exclusive), oldest first. A zero from or before
+// leaves that end of the range open.
+func (r *exportRepository) ExportSnapshots(ctx context.Context, network string, from, before time.Time, fn func(*models.NetworkSnapshot) error) error {
+	args := []interface{}{network}
+	conditions := []string{"network = $1"}
+	if !from.IsZero() {
+		args = append(args, from)
+		conditions = append(conditions, fmt.Sprintf("timestamp >= $%d", len(args)))
+	}
+	if !before.IsZero() {
+		args = append(args, before)
+		conditions = append(conditions, fmt.Sprintf("timestamp < $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, network, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, COALESCE(snapshot_data, '{}'), created_at
+		FROM network_snapshots
+		WHERE %s
+		ORDER BY timestamp, id
+	`, strings.Join(conditions, " AND "))
+
+	return exportRows(ctx, r.db, query, args, func(row rowScanner) (*models.NetworkSnapshot, error) {
+		snapshot, err := scanSnapshot(row)
+		if err != nil {
+			return nil, fmt.Errorf("scan snapshot: %w", err)
+		}
+		return snapshot, nil
+	}, fn)
+}
+
+// ExportPeers streams the peers the filter selects, ordered by id. The
+// status filter matches reachable peers as online; sorting and the cursor
+// are ignored.
+func (r *exportRepository) ExportPeers(ctx context.Context, filter models.NodeFilter, fn func(*models.ReachablePeer) error) error {
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"true"}
+	if filter.Network != "" {
+		conditions = append(conditions, "network = "+arg(filter.Network))
+	}
+	if filter.Country != "" {
+		p := arg(escapeLike(filter.Country))
+		conditions = append(conditions, fmt.Sprintf("(country_code ILIKE %s OR country ILIKE %s)", p, p))
+	}
+	if filter.MinScore > 0 {
+		conditions = append(conditions, "overall_score >= "+arg(filter.MinScore))
+	}
+	switch filter.Status {
+	case models.NodeStatusOnline:
+		conditions = append(conditions, "is_reachable = true")
+	case models.NodeStatusOffline:
+		conditions = append(conditions, "is_reachable = false")
+	}
+	if filter.Search != "" {
+		p := arg("%" + escapeLike(filter.Search) + "%")
+		conditions = append(conditions, fmt.Sprintf("(peer_id ILIKE %s OR address ILIKE %s)", p, p))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, network, peer_id, address, protocol, user_agent, last_seen, first_seen,
+			   ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
+			   is_reachable, connection_attempts, successful_connections, overall_score,
+			   created_at, updated_at
+		FROM reachable_peers
+		WHERE %s
+		ORDER BY id
+	`, strings.Join(conditions, " AND "))
+
+	return exportRows(ctx, r.db, query, args, func(row rowScanner) (*models.ReachablePeer, error) {
+		peer, err := scanPeer(row)
+		if err != nil {
+			return nil, fmt.Errorf("scan peer: %w", err)
+		}
+		return peer, nil
+	}, fn)
+}
+
+// ExportMapNodes streams the map nodes the filter selects, ordered by type
+// and id. The cursor and page size are ignored.
+func (r *exportRepository) ExportMapNodes(ctx context.Context, filter models.MapFilter, fn func(*models.MapNode) error) error {
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	source := mapNodesSource(arg(models.OnlineScoreThreshold))
+	query := fmt.Sprintf(`
+		SELECT type, id, name, latitude, longitude, status, country, city
+		FROM (%s) AS map_nodes
+		WHERE %s
+		ORDER BY type, id
+	`, source, strings.Join(mapFilterConditions(filter, arg), " AND "))
+
+	return exportRows(ctx, r.db, query, args, scanMapNode, fn)
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// exportRows runs a query and calls fn with each scanned row
+func exportRows[T any](ctx context.Context, db *sql.DB, query string, args []interface{}, scan func(row rowScanner) (T, error), fn func(T) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration: %w", err)
+	}
+	return nil
+}
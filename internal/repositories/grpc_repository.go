@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 )
 
@@ -16,18 +18,24 @@ type GRPCRepository interface {
 	GetServerByID(ctx context.Context, id int) (*models.GRPCServer, error)
 	GetServerByAddress(ctx context.Context, address string) (*models.GRPCServer, error)
 	GetServersByNetwork(ctx context.Context, network string) ([]*models.GRPCServer, error)
+	ListServers(ctx context.Context, filter models.NodeFilter) ([]*models.GRPCServer, error)
 
 	// CRUD operations
 	CreateServer(ctx context.Context, server *models.GRPCServer) error
 	UpdateServer(ctx context.Context, server *models.GRPCServer) error
 	UpdateServerScore(ctx context.Context, serverID int, score float64) error
 	UpdateServerGeo(ctx context.Context, serverID int, country, countryCode, city string, lat, lon float64) error
+	UpdateServerByID(ctx context.Context, server *models.GRPCServer) error
 	DeactivateServer(ctx context.Context, address string) error
+	DeactivateServers(ctx context.Context, addresses []string) error
+	ReactivateServers(ctx context.Context, addresses []string) error
 	ServerExists(ctx context.Context, address string) (bool, error)
+	GetServersByEmail(ctx context.Context, email string) ([]*models.GRPCServer, error)
 
 	// Aggregations
 	GetServerCount(ctx context.Context, activeOnly bool) (int, error)
 	UpdateAllScores(ctx context.Context) error
+	RefreshServerScore(ctx context.Context, serverID int) error
 }
 
 type grpcRepository struct {
@@ -41,7 +49,7 @@ func NewGRPCRepository(db *sql.DB) GRPCRepository {
 
 func (r *grpcRepository) GetActiveServers(ctx context.Context) ([]*models.GRPCServer, error) {
 	query := `
-SELECT id, name, address, network, overall_score, is_active, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+SELECT id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 FROM grpc_servers 
 WHERE is_active = true
 ORDER BY network, id
@@ -58,7 +66,7 @@ ORDER BY network, id
 
 func (r *grpcRepository) GetAllServers(ctx context.Context) ([]*models.GRPCServer, error) {
 	query := `
-SELECT id, name, address, network, overall_score, is_active, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+SELECT id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 FROM grpc_servers 
 ORDER BY network, id
 	`
@@ -74,7 +82,7 @@ ORDER BY network, id
 
 func (r *grpcRepository) GetServerByID(ctx context.Context, id int) (*models.GRPCServer, error) {
 	query := `
-SELECT id, name, address, network, overall_score, is_active, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+SELECT id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 FROM grpc_servers 
 WHERE id = $1
 	`
@@ -82,13 +90,13 @@ WHERE id = $1
 	server := &models.GRPCServer{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&server.ID, &server.Name, &server.Address, &server.Network,
-		&server.OverallScore, &server.IsActive, &server.Email, &server.Website,
+		&server.OverallScore, &server.IsActive, &server.IsVerified, &server.IsCurated, &server.Email, &server.Website,
 		&server.Country, &server.CountryCode, &server.City, &server.Latitude, &server.Longitude,
 		&server.CreatedAt, &server.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("server not found: %d", id)
+		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get server by id: %w", err)
@@ -99,7 +107,7 @@ WHERE id = $1
 
 func (r *grpcRepository) GetServerByAddress(ctx context.Context, address string) (*models.GRPCServer, error) {
 	query := `
-SELECT id, name, address, network, overall_score, is_active, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+SELECT id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 FROM grpc_servers 
 WHERE address = $1
 	`
@@ -107,7 +115,7 @@ WHERE address = $1
 	server := &models.GRPCServer{}
 	err := r.db.QueryRowContext(ctx, query, address).Scan(
 		&server.ID, &server.Name, &server.Address, &server.Network,
-		&server.OverallScore, &server.IsActive, &server.Email, &server.Website,
+		&server.OverallScore, &server.IsActive, &server.IsVerified, &server.IsCurated, &server.Email, &server.Website,
 		&server.Country, &server.CountryCode, &server.City, &server.Latitude, &server.Longitude,
 		&server.CreatedAt, &server.UpdatedAt,
 	)
@@ -124,7 +132,7 @@ WHERE address = $1
 
 func (r *grpcRepository) GetServersByNetwork(ctx context.Context, network string) ([]*models.GRPCServer, error) {
 	query := `
-SELECT id, name, address, network, overall_score, is_active, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+SELECT id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
 FROM grpc_servers 
 WHERE network = $1 AND is_active = true
 ORDER BY id
@@ -139,16 +147,31 @@ ORDER BY id
 	return r.scanServers(rows)
 }
 
+// ListServers returns one page of active servers plus one extra row when
+// another page follows
+func (r *grpcRepository) ListServers(ctx context.Context, filter models.NodeFilter) ([]*models.GRPCServer, error) {
+	columns := `id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at`
+	query, args := buildNodeListQuery(columns, "grpc_servers", true, filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list servers: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanServers(rows)
+}
+
 func (r *grpcRepository) CreateServer(ctx context.Context, server *models.GRPCServer) error {
 	query := `
-        INSERT INTO grpc_servers (name, address, network, email, website, is_active, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+        INSERT INTO grpc_servers (name, address, network, email, website, is_active, is_verified, is_curated, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
         ON CONFLICT (address) DO NOTHING
         RETURNING id, created_at, updated_at
     `
 
 	err := r.db.QueryRowContext(ctx, query,
-		server.Name, server.Address, server.Network, server.Email, server.Website, server.IsActive,
+		server.Name, server.Address, server.Network, server.Email, server.Website, server.IsActive, server.IsVerified, server.IsCurated,
 	).Scan(&server.ID, &server.CreatedAt, &server.UpdatedAt)
 
 	if err == sql.ErrNoRows {
@@ -183,6 +206,32 @@ func (r *grpcRepository) UpdateServer(ctx context.Context, server *models.GRPCSe
 	return nil
 }
 
+// UpdateServerByID changes the operator editable fields of a server,
+// including its address. An operator edited server is no longer curated,
+// so syncs do not overwrite its details or deactivate it after a move.
+func (r *grpcRepository) UpdateServerByID(ctx context.Context, server *models.GRPCServer) error {
+	query := `
+	UPDATE grpc_servers
+	SET name = $1, address = $2, email = $3, website = $4, is_verified = $5,
+		is_curated = false, updated_at = NOW()
+	WHERE id = $6
+	RETURNING is_curated, updated_at
+`
+
+	err := r.db.QueryRowContext(ctx, query,
+		server.Name, server.Address, server.Email, server.Website, server.IsVerified, server.ID,
+	).Scan(&server.IsCurated, &server.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("server not found: %d", server.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("update server: %w", err)
+	}
+
+	return nil
+}
+
 func (r *grpcRepository) UpdateServerScore(ctx context.Context, serverID int, score float64) error {
 	query := `
 		UPDATE grpc_servers 
@@ -231,10 +280,12 @@ func (r *grpcRepository) UpdateServerGeo(ctx context.Context, serverID int, coun
 	return nil
 }
 
+// DeactivateServer deregisters a server. It is no longer curated, so
+// syncs do not reactivate it.
 func (r *grpcRepository) DeactivateServer(ctx context.Context, address string) error {
 	query := `
 		UPDATE grpc_servers 
-		SET is_active = false, updated_at = NOW() 
+		SET is_active = false, is_curated = false, updated_at = NOW() 
 		WHERE address = $1
 	`
 
@@ -246,6 +297,34 @@ func (r *grpcRepository) DeactivateServer(ctx context.Context, address string) e
 	return nil
 }
 
+// DeactivateServers deactivates curated servers that left their list
+func (r *grpcRepository) DeactivateServers(ctx context.Context, addresses []string) error {
+	return r.setCuratedActive(ctx, addresses, false)
+}
+
+// ReactivateServers reactivates curated servers that returned to their list
+func (r *grpcRepository) ReactivateServers(ctx context.Context, addresses []string) error {
+	return r.setCuratedActive(ctx, addresses, true)
+}
+
+func (r *grpcRepository) setCuratedActive(ctx context.Context, addresses []string, active bool) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE grpc_servers
+		SET is_active = $2, updated_at = NOW()
+		WHERE address = ANY($1) AND is_curated = true
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(addresses), active); err != nil {
+		return fmt.Errorf("set servers active: %w", err)
+	}
+
+	return nil
+}
+
 func (r *grpcRepository) ServerExists(ctx context.Context, address string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM grpc_servers WHERE address = $1)`
 
@@ -258,6 +337,25 @@ func (r *grpcRepository) ServerExists(ctx context.Context, address string) (bool
 	return exists, nil
 }
 
+// GetServersByEmail returns the active servers registered with an email
+// address, ignoring case
+func (r *grpcRepository) GetServersByEmail(ctx context.Context, email string) ([]*models.GRPCServer, error) {
+	query := `
+SELECT id, name, address, network, overall_score, is_active, COALESCE(is_verified, false), is_curated, email, website, COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''), COALESCE(latitude, 0), COALESCE(longitude, 0), created_at, updated_at
+FROM grpc_servers
+WHERE LOWER(email) = LOWER($1) AND is_active = true
+ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("query servers by email: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanServers(rows)
+}
+
 func (r *grpcRepository) GetServerCount(ctx context.Context, activeOnly bool) (int, error) {
 	query := `SELECT COUNT(*) FROM grpc_servers`
 	if activeOnly {
@@ -279,18 +377,20 @@ func (r *grpcRepository) UpdateAllScores(ctx context.Context) error {
 		SET overall_score = (
 			SELECT COALESCE(
 				ROUND(
-					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / COUNT(*))::numeric, 2
+					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0))::numeric, 2
 				), 0
 			)
 			FROM grpc_daily_status 
 			WHERE server_id = grpc_servers.id 
 			AND date >= CURRENT_DATE - INTERVAL '30 days'
+			AND color <> $1
 		),
 		updated_at = NOW()
 		WHERE is_active = true
 	`
 
-	_, err := r.db.ExecContext(ctx, query)
+	// Days in maintenance count neither for nor against the node
+	_, err := r.db.ExecContext(ctx, query, models.StatusColorMaintenance)
 	if err != nil {
 		return fmt.Errorf("update all scores: %w", err)
 	}
@@ -298,6 +398,32 @@ func (r *grpcRepository) UpdateAllScores(ctx context.Context) error {
 	return nil
 }
 
+// RefreshServerScore recomputes the 30-day score of one server from its daily history
+func (r *grpcRepository) RefreshServerScore(ctx context.Context, serverID int) error {
+	query := `
+		UPDATE grpc_servers
+		SET overall_score = (
+			SELECT COALESCE(
+				ROUND(
+					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0))::numeric, 2
+				), 0
+			)
+			FROM grpc_daily_status
+			WHERE server_id = grpc_servers.id
+			AND date >= CURRENT_DATE - INTERVAL '30 days'
+			AND color <> $2
+		),
+		updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, serverID, models.StatusColorMaintenance); err != nil {
+		return fmt.Errorf("refresh server score: %w", err)
+	}
+
+	return nil
+}
+
 // Helper function to scan multiple servers
 func (r *grpcRepository) scanServers(rows *sql.Rows) ([]*models.GRPCServer, error) {
 	var servers []*models.GRPCServer
@@ -306,7 +432,7 @@ func (r *grpcRepository) scanServers(rows *sql.Rows) ([]*models.GRPCServer, erro
 		server := &models.GRPCServer{}
 		err := rows.Scan(
 			&server.ID, &server.Name, &server.Address, &server.Network,
-			&server.OverallScore, &server.IsActive, &server.Email, &server.Website,
+			&server.OverallScore, &server.IsActive, &server.IsVerified, &server.IsCurated, &server.Email, &server.Website,
 			&server.Country, &server.CountryCode, &server.City, &server.Latitude, &server.Longitude,
 			&server.CreatedAt, &server.UpdatedAt,
 		)
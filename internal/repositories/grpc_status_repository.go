@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/lib/pq"
 )
 
 // GRPCStatusRepository defines the interface for gRPC daily status data access
@@ -15,7 +16,9 @@ type GRPCStatusRepository interface {
 	CreateStatus(ctx context.Context, status *models.GRPCDailyStatus) error
 	GetStatusByServerAndDate(ctx context.Context, serverID int, date time.Time) (*models.GRPCDailyStatus, error)
 	GetRecentStatusesByServer(ctx context.Context, serverID int, days int) ([]models.StatusItem, error)
+	GetRecentStatusesByServers(ctx context.Context, serverIDs []int, days int) (map[int][]models.StatusItem, error)
 	HasStatusForDate(ctx context.Context, serverID int, date time.Time) (bool, error)
+	GetStatusesByServerAndDateRange(ctx context.Context, serverID int, startDate, endDate time.Time) ([]*models.GRPCDailyStatus, error)
 
 	// Batch operations
 	GetStatusesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.GRPCDailyStatus, error)
@@ -33,14 +36,15 @@ func NewGRPCStatusRepository(db *sql.DB) GRPCStatusRepository {
 
 func (r *grpcStatusRepository) CreateStatus(ctx context.Context, status *models.GRPCDailyStatus) error {
 	query := `
-		INSERT INTO grpc_daily_status (server_id, date, color, attempts, success, error_msg, response_time_ms, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		INSERT INTO grpc_daily_status (server_id, date, color, attempts, success, error_msg, error_class, response_time_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
 		ON CONFLICT (server_id, date) 
 		DO UPDATE SET 
 			color = EXCLUDED.color,
 			attempts = EXCLUDED.attempts,
 			success = EXCLUDED.success,
 			error_msg = EXCLUDED.error_msg,
+			error_class = EXCLUDED.error_class,
 			response_time_ms = EXCLUDED.response_time_ms,
 			created_at = NOW()
 		RETURNING id, created_at
@@ -48,7 +52,7 @@ func (r *grpcStatusRepository) CreateStatus(ctx context.Context, status *models.
 
 	err := r.db.QueryRowContext(ctx, query,
 		status.ServerID, status.Date, status.Color,
-		status.Attempts, status.Success, status.ErrorMsg, status.ResponseTimeMs,
+		status.Attempts, status.Success, status.ErrorMsg, status.ErrorClass, status.ResponseTimeMs,
 	).Scan(&status.ID, &status.CreatedAt)
 
 	if err != nil {
@@ -60,7 +64,7 @@ func (r *grpcStatusRepository) CreateStatus(ctx context.Context, status *models.
 
 func (r *grpcStatusRepository) GetStatusByServerAndDate(ctx context.Context, serverID int, date time.Time) (*models.GRPCDailyStatus, error) {
 	query := `
-		SELECT id, server_id, date, color, attempts, success, error_msg, response_time_ms, created_at
+		SELECT id, server_id, date, color, attempts, success, error_msg, COALESCE(error_class, ''), response_time_ms, created_at
 		FROM grpc_daily_status
 		WHERE server_id = $1 AND date = $2
 	`
@@ -68,7 +72,7 @@ func (r *grpcStatusRepository) GetStatusByServerAndDate(ctx context.Context, ser
 	status := &models.GRPCDailyStatus{}
 	err := r.db.QueryRowContext(ctx, query, serverID, date).Scan(
 		&status.ID, &status.ServerID, &status.Date, &status.Color,
-		&status.Attempts, &status.Success, &status.ErrorMsg, &status.ResponseTimeMs, &status.CreatedAt,
+		&status.Attempts, &status.Success, &status.ErrorMsg, &status.ErrorClass, &status.ResponseTimeMs, &status.CreatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -117,6 +121,48 @@ func (r *grpcStatusRepository) GetRecentStatusesByServer(ctx context.Context, se
 	return statuses, nil
 }
 
+// GetRecentStatusesByServers loads the recent statuses of several servers in
+// one query, keyed by server ID
+func (r *grpcStatusRepository) GetRecentStatusesByServers(ctx context.Context, serverIDs []int, days int) (map[int][]models.StatusItem, error) {
+	statuses := make(map[int][]models.StatusItem, len(serverIDs))
+	if len(serverIDs) == 0 {
+		return statuses, nil
+	}
+
+	query := `
+		SELECT server_id, color, date
+		FROM grpc_daily_status
+		WHERE server_id = ANY($1) AND date >= CURRENT_DATE - INTERVAL '1 day' * $2
+		ORDER BY server_id, date DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(serverIDs), days)
+	if err != nil {
+		return nil, fmt.Errorf("query recent grpc statuses by servers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var serverID, color int
+		var date time.Time
+
+		if err := rows.Scan(&serverID, &color, &date); err != nil {
+			return nil, fmt.Errorf("scan grpc status: %w", err)
+		}
+
+		statuses[serverID] = append(statuses[serverID], models.StatusItem{
+			Color: color,
+			Date:  date.Format("2006-01-02"),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return statuses, nil
+}
+
 func (r *grpcStatusRepository) HasStatusForDate(ctx context.Context, serverID int, date time.Time) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM grpc_daily_status WHERE server_id = $1 AND date = $2)`
 
@@ -131,7 +177,7 @@ func (r *grpcStatusRepository) HasStatusForDate(ctx context.Context, serverID in
 
 func (r *grpcStatusRepository) GetStatusesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.GRPCDailyStatus, error) {
 	query := `
-		SELECT id, server_id, date, color, attempts, success, error_msg, response_time_ms, created_at
+		SELECT id, server_id, date, color, attempts, success, error_msg, COALESCE(error_class, ''), response_time_ms, created_at
 		FROM grpc_daily_status
 		WHERE date >= $1 AND date <= $2
 		ORDER BY date DESC, server_id
@@ -148,7 +194,7 @@ func (r *grpcStatusRepository) GetStatusesByDateRange(ctx context.Context, start
 		status := &models.GRPCDailyStatus{}
 		err := rows.Scan(
 			&status.ID, &status.ServerID, &status.Date, &status.Color,
-			&status.Attempts, &status.Success, &status.ErrorMsg, &status.ResponseTimeMs, &status.CreatedAt,
+			&status.Attempts, &status.Success, &status.ErrorMsg, &status.ErrorClass, &status.ResponseTimeMs, &status.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan grpc status: %w", err)
@@ -178,3 +224,37 @@ func (r *grpcStatusRepository) DeleteOldStatuses(ctx context.Context, beforeDate
 
 	return nil
 }
+
+func (r *grpcStatusRepository) GetStatusesByServerAndDateRange(ctx context.Context, serverID int, startDate, endDate time.Time) ([]*models.GRPCDailyStatus, error) {
+	query := `
+		SELECT id, server_id, date, color, attempts, success, error_msg, COALESCE(error_class, ''), response_time_ms, created_at
+		FROM grpc_daily_status
+		WHERE server_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, serverID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query grpc server statuses by date range: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []*models.GRPCDailyStatus
+	for rows.Next() {
+		status := &models.GRPCDailyStatus{}
+		err := rows.Scan(
+			&status.ID, &status.ServerID, &status.Date, &status.Color,
+			&status.Attempts, &status.Success, &status.ErrorMsg, &status.ErrorClass, &status.ResponseTimeMs, &status.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan grpc status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return statuses, nil
+}
@@ -16,18 +16,22 @@ type JSONRPCServerRepository interface {
 	GetServerByID(ctx context.Context, id int) (*models.JSONRPCServer, error)
 	GetServerByAddress(ctx context.Context, address string) (*models.JSONRPCServer, error)
 	GetServersByNetwork(ctx context.Context, network string) ([]*models.JSONRPCServer, error)
+	ListServers(ctx context.Context, filter models.NodeFilter) ([]*models.JSONRPCServer, error)
 
 	// CRUD operations
 	CreateServer(ctx context.Context, server *models.JSONRPCServer) error
 	UpdateServer(ctx context.Context, server *models.JSONRPCServer) error
 	UpdateServerGeo(ctx context.Context, id int, geo *models.GeoLocation) error
 	UpdateServerScore(ctx context.Context, serverID int, score float64) error
+	UpdateServerByID(ctx context.Context, server *models.JSONRPCServer) error
 	DeactivateServer(ctx context.Context, address string) error
 	ExistsByAddress(ctx context.Context, address string) (bool, error)
+	GetServersByEmail(ctx context.Context, email string) ([]*models.JSONRPCServer, error)
 
 	// Aggregations
 	GetServerCount(ctx context.Context, activeOnly bool) (int, error)
 	UpdateAllScores(ctx context.Context) error
+	RefreshServerScore(ctx context.Context, serverID int) error
 }
 
 type jsonrpcServerRepository struct {
@@ -142,6 +146,22 @@ func (r *jsonrpcServerRepository) GetServersByNetwork(ctx context.Context, netwo
 	return r.scanServers(rows)
 }
 
+// ListServers returns one page of active servers plus one extra row when
+// another page follows
+func (r *jsonrpcServerRepository) ListServers(ctx context.Context, filter models.NodeFilter) ([]*models.JSONRPCServer, error) {
+	columns := `id, name, address, network, email, website, country, country_code, city, latitude, longitude,
+			   overall_score, is_active, is_verified, created_at, updated_at`
+	query, args := buildNodeListQuery(columns, "jsonrpc_servers", true, filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list servers: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanServers(rows)
+}
+
 func (r *jsonrpcServerRepository) CreateServer(ctx context.Context, server *models.JSONRPCServer) error {
 	query := `
 		INSERT INTO jsonrpc_servers (name, address, network, email, website, country, country_code, city, latitude, longitude, is_active, is_verified)
@@ -192,6 +212,31 @@ func (r *jsonrpcServerRepository) UpdateServer(ctx context.Context, server *mode
 	return nil
 }
 
+// UpdateServerByID changes the operator editable fields of a server,
+// including its address
+func (r *jsonrpcServerRepository) UpdateServerByID(ctx context.Context, server *models.JSONRPCServer) error {
+	query := `
+		UPDATE jsonrpc_servers SET
+			name = $1, address = $2, email = $3, website = $4, is_verified = $5,
+			updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		server.Name, server.Address, server.Email, server.Website, server.IsVerified, server.ID,
+	).Scan(&server.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("server not found: %d", server.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("update server: %w", err)
+	}
+
+	return nil
+}
+
 func (r *jsonrpcServerRepository) UpdateServerGeo(ctx context.Context, id int, geo *models.GeoLocation) error {
 	query := `
 		UPDATE jsonrpc_servers SET
@@ -253,6 +298,26 @@ func (r *jsonrpcServerRepository) ExistsByAddress(ctx context.Context, address s
 	return exists, nil
 }
 
+// GetServersByEmail returns the active servers registered with an email
+// address, ignoring case
+func (r *jsonrpcServerRepository) GetServersByEmail(ctx context.Context, email string) ([]*models.JSONRPCServer, error) {
+	query := `
+		SELECT id, name, address, network, email, website, country, country_code, city, latitude, longitude,
+			   overall_score, is_active, is_verified, created_at, updated_at
+		FROM jsonrpc_servers
+		WHERE LOWER(email) = LOWER($1) AND is_active = true
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("query servers by email: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanServers(rows)
+}
+
 func (r *jsonrpcServerRepository) GetServerCount(ctx context.Context, activeOnly bool) (int, error) {
 	query := `SELECT COUNT(*) FROM jsonrpc_servers`
 	if activeOnly {
@@ -280,12 +345,14 @@ func (r *jsonrpcServerRepository) UpdateAllScores(ctx context.Context) error {
 			FROM jsonrpc_daily_status 
 			WHERE server_id = jsonrpc_servers.id 
 			AND date >= CURRENT_DATE - INTERVAL '30 days'
+			AND color <> $1
 		),
 		updated_at = NOW()
 		WHERE is_active = true
 	`
 
-	_, err := r.db.ExecContext(ctx, query)
+	// Days in maintenance count neither for nor against the node
+	_, err := r.db.ExecContext(ctx, query, models.StatusColorMaintenance)
 	if err != nil {
 		return fmt.Errorf("update all scores: %w", err)
 	}
@@ -293,6 +360,32 @@ func (r *jsonrpcServerRepository) UpdateAllScores(ctx context.Context) error {
 	return nil
 }
 
+// RefreshServerScore recomputes the 30-day score of one server from its daily history
+func (r *jsonrpcServerRepository) RefreshServerScore(ctx context.Context, serverID int) error {
+	query := `
+		UPDATE jsonrpc_servers
+		SET overall_score = (
+			SELECT COALESCE(
+				ROUND(
+					(COUNT(CASE WHEN success = true THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0))::numeric, 2
+				), 0
+			)
+			FROM jsonrpc_daily_status
+			WHERE server_id = jsonrpc_servers.id
+			AND date >= CURRENT_DATE - INTERVAL '30 days'
+			AND color <> $2
+		),
+		updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, serverID, models.StatusColorMaintenance); err != nil {
+		return fmt.Errorf("refresh server score: %w", err)
+	}
+
+	return nil
+}
+
 // Helper function to scan multiple servers
 func (r *jsonrpcServerRepository) scanServers(rows *sql.Rows) ([]*models.JSONRPCServer, error) {
 	var servers []*models.JSONRPCServer
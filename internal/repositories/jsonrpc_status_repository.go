@@ -7,13 +7,16 @@ import (
 	"time"
 
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/lib/pq"
 )
 
 // JSONRPCStatusRepository defines the interface for JSON-RPC status data access
 type JSONRPCStatusRepository interface {
 	GetRecentStatusesByServer(ctx context.Context, serverID int, days int) ([]models.StatusItem, error)
+	GetRecentStatusesByServers(ctx context.Context, serverIDs []int, days int) (map[int][]models.StatusItem, error)
 	GetStatusByServerAndDate(ctx context.Context, serverID int, date time.Time) (*models.JSONRPCDailyStatus, error)
 	HasStatusForDate(ctx context.Context, serverID int, date time.Time) (bool, error)
+	GetStatusesByServerAndDateRange(ctx context.Context, serverID int, startDate, endDate time.Time) ([]*models.JSONRPCDailyStatus, error)
 	CreateStatus(ctx context.Context, status *models.JSONRPCDailyStatus) error
 	UpdateStatus(ctx context.Context, status *models.JSONRPCDailyStatus) error
 }
@@ -77,9 +80,63 @@ func (r *jsonrpcStatusRepository) GetRecentStatusesByServer(ctx context.Context,
 	return statuses, nil
 }
 
+// GetRecentStatusesByServers loads the last N days of several servers in one
+// query, keyed by server ID. Like GetRecentStatusesByServer, days without a
+// status are filled in grey.
+func (r *jsonrpcStatusRepository) GetRecentStatusesByServers(ctx context.Context, serverIDs []int, days int) (map[int][]models.StatusItem, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	startDate := today.AddDate(0, 0, -(days - 1))
+
+	statuses := make(map[int][]models.StatusItem, len(serverIDs))
+	index := make(map[string]int, days)
+	for _, serverID := range serverIDs {
+		items := make([]models.StatusItem, days)
+		for i := range items {
+			items[i] = models.StatusItem{
+				Date:  startDate.AddDate(0, 0, i).Format("2006-01-02"),
+				Color: 0, // grey
+			}
+			index[items[i].Date] = i
+		}
+		statuses[serverID] = items
+	}
+	if len(serverIDs) == 0 {
+		return statuses, nil
+	}
+
+	query := `
+		SELECT server_id, date, color
+		FROM jsonrpc_daily_status
+		WHERE server_id = ANY($1) AND date >= $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(serverIDs), startDate)
+	if err != nil {
+		return nil, fmt.Errorf("query statuses by servers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var serverID, color int
+		var date time.Time
+		if err := rows.Scan(&serverID, &date, &color); err != nil {
+			return nil, fmt.Errorf("scan status: %w", err)
+		}
+		if i, ok := index[date.Format("2006-01-02")]; ok {
+			statuses[serverID][i].Color = color
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return statuses, nil
+}
+
 func (r *jsonrpcStatusRepository) GetStatusByServerAndDate(ctx context.Context, serverID int, date time.Time) (*models.JSONRPCDailyStatus, error) {
 	query := `
-		SELECT id, server_id, date, color, attempts, success, response_time_ms, error_msg, blockchain_height, created_at
+		SELECT id, server_id, date, color, attempts, success, response_time_ms, error_msg, COALESCE(error_class, ''), blockchain_height, created_at
 		FROM jsonrpc_daily_status
 		WHERE server_id = $1 AND date = $2
 	`
@@ -87,7 +144,7 @@ func (r *jsonrpcStatusRepository) GetStatusByServerAndDate(ctx context.Context,
 	status := &models.JSONRPCDailyStatus{}
 	err := r.db.QueryRowContext(ctx, query, serverID, date).Scan(
 		&status.ID, &status.ServerID, &status.Date, &status.Color, &status.Attempts,
-		&status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.BlockchainHeight, &status.CreatedAt,
+		&status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.ErrorClass, &status.BlockchainHeight, &status.CreatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -114,21 +171,22 @@ func (r *jsonrpcStatusRepository) HasStatusForDate(ctx context.Context, serverID
 
 func (r *jsonrpcStatusRepository) CreateStatus(ctx context.Context, status *models.JSONRPCDailyStatus) error {
 	query := `
-		INSERT INTO jsonrpc_daily_status (server_id, date, color, attempts, success, response_time_ms, error_msg, blockchain_height)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO jsonrpc_daily_status (server_id, date, color, attempts, success, response_time_ms, error_msg, error_class, blockchain_height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (server_id, date) DO UPDATE SET
 			color = EXCLUDED.color,
 			attempts = EXCLUDED.attempts,
 			success = EXCLUDED.success,
 			response_time_ms = EXCLUDED.response_time_ms,
 			error_msg = EXCLUDED.error_msg,
+			error_class = EXCLUDED.error_class,
 			blockchain_height = EXCLUDED.blockchain_height
 		RETURNING id, created_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
 		status.ServerID, status.Date, status.Color, status.Attempts,
-		status.Success, status.ResponseTimeMs, status.ErrorMsg, status.BlockchainHeight,
+		status.Success, status.ResponseTimeMs, status.ErrorMsg, status.ErrorClass, status.BlockchainHeight,
 	).Scan(&status.ID, &status.CreatedAt)
 
 	if err != nil {
@@ -141,12 +199,12 @@ func (r *jsonrpcStatusRepository) CreateStatus(ctx context.Context, status *mode
 func (r *jsonrpcStatusRepository) UpdateStatus(ctx context.Context, status *models.JSONRPCDailyStatus) error {
 	query := `
 		UPDATE jsonrpc_daily_status SET
-			color = $1, attempts = $2, success = $3, response_time_ms = $4, error_msg = $5, blockchain_height = $6
-		WHERE id = $7
+			color = $1, attempts = $2, success = $3, response_time_ms = $4, error_msg = $5, error_class = $6, blockchain_height = $7
+		WHERE id = $8
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		status.Color, status.Attempts, status.Success, status.ResponseTimeMs, status.ErrorMsg, status.BlockchainHeight,
+		status.Color, status.Attempts, status.Success, status.ResponseTimeMs, status.ErrorMsg, status.ErrorClass, status.BlockchainHeight,
 		status.ID,
 	)
 
@@ -156,3 +214,37 @@ func (r *jsonrpcStatusRepository) UpdateStatus(ctx context.Context, status *mode
 
 	return nil
 }
+
+func (r *jsonrpcStatusRepository) GetStatusesByServerAndDateRange(ctx context.Context, serverID int, startDate, endDate time.Time) ([]*models.JSONRPCDailyStatus, error) {
+	query := `
+		SELECT id, server_id, date, color, attempts, success, response_time_ms, error_msg, COALESCE(error_class, ''), blockchain_height, created_at
+		FROM jsonrpc_daily_status
+		WHERE server_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, serverID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query jsonrpc server statuses by date range: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []*models.JSONRPCDailyStatus
+	for rows.Next() {
+		status := &models.JSONRPCDailyStatus{}
+		err := rows.Scan(
+			&status.ID, &status.ServerID, &status.Date, &status.Color, &status.Attempts,
+			&status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.ErrorClass, &status.BlockchainHeight, &status.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan jsonrpc status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return statuses, nil
+}
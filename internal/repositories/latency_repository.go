@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// LatencyRepository defines the interface for check attempt and latency data access
+type LatencyRepository interface {
+	// Attempt operations
+	CreateAttempts(ctx context.Context, attempts []models.CheckAttempt) error
+	DeleteOldAttempts(ctx context.Context, before time.Time) error
+
+	// Daily rollups
+	RollupDay(ctx context.Context, nodeType string, date time.Time) error
+	GetLatencyHistory(ctx context.Context, nodeType string, nodeID int, days int) ([]*models.LatencyStats, error)
+	GetLatestByNodes(ctx context.Context, nodeType string, nodeIDs []int) (map[int]*models.LatencySummary, error)
+}
+
+type latencyRepository struct {
+	db *sql.DB
+}
+
+// NewLatencyRepository creates a new latency repository
+func NewLatencyRepository(db *sql.DB) LatencyRepository {
+	return &latencyRepository{db: db}
+}
+
+func (r *latencyRepository) CreateAttempts(ctx context.Context, attempts []models.CheckAttempt) error {
+	query := `
+		INSERT INTO check_attempts (node_type, node_id, attempt, success, latency_ms, error_class, checked_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+	`
+
+	for _, a := range attempts {
+		_, err := r.db.ExecContext(ctx, query,
+			a.NodeType, a.NodeID, a.Attempt, a.Success, a.LatencyMs, a.ErrorClass, a.CheckedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("create check attempt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *latencyRepository) DeleteOldAttempts(ctx context.Context, before time.Time) error {
+	query := `DELETE FROM check_attempts WHERE checked_at < $1`
+
+	if _, err := r.db.ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("delete old check attempts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *latencyRepository) RollupDay(ctx context.Context, nodeType string, date time.Time) error {
+	query := `
+		INSERT INTO latency_daily_stats (node_type, node_id, date, samples, p50_ms, p95_ms, p99_ms, min_ms, max_ms, avg_ms, updated_at)
+		SELECT
+			node_type, node_id, $2::date, COUNT(*),
+			percentile_cont(0.50) WITHIN GROUP (ORDER BY latency_ms),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms),
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY latency_ms),
+			MIN(latency_ms), MAX(latency_ms), AVG(latency_ms),
+			NOW()
+		FROM check_attempts
+		WHERE node_type = $1 AND success = true
+		  AND checked_at >= $2::date AND checked_at < $2::date + INTERVAL '1 day'
+		GROUP BY node_type, node_id
+		ON CONFLICT (node_type, node_id, date)
+		DO UPDATE SET
+			samples = EXCLUDED.samples,
+			p50_ms = EXCLUDED.p50_ms,
+			p95_ms = EXCLUDED.p95_ms,
+			p99_ms = EXCLUDED.p99_ms,
+			min_ms = EXCLUDED.min_ms,
+			max_ms = EXCLUDED.max_ms,
+			avg_ms = EXCLUDED.avg_ms,
+			updated_at = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, nodeType, date); err != nil {
+		return fmt.Errorf("rollup latency: %w", err)
+	}
+
+	return nil
+}
+
+func (r *latencyRepository) GetLatencyHistory(ctx context.Context, nodeType string, nodeID int, days int) ([]*models.LatencyStats, error) {
+	query := `
+		SELECT node_type, node_id, date, samples,
+		       COALESCE(p50_ms, 0), COALESCE(p95_ms, 0), COALESCE(p99_ms, 0),
+		       COALESCE(min_ms, 0), COALESCE(max_ms, 0), COALESCE(avg_ms, 0)
+		FROM latency_daily_stats
+		WHERE node_type = $1 AND node_id = $2 AND date >= CURRENT_DATE - INTERVAL '1 day' * $3
+		ORDER BY date ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, nodeID, days)
+	if err != nil {
+		return nil, fmt.Errorf("query latency history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*models.LatencyStats
+	for rows.Next() {
+		stats := &models.LatencyStats{}
+		var date time.Time
+
+		err := rows.Scan(
+			&stats.NodeType, &stats.NodeID, &date, &stats.Samples,
+			&stats.P50Ms, &stats.P95Ms, &stats.P99Ms,
+			&stats.MinMs, &stats.MaxMs, &stats.AvgMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan latency stats: %w", err)
+		}
+
+		stats.Date = date.Format("2006-01-02")
+		history = append(history, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return history, nil
+}
+
+func (r *latencyRepository) GetLatestByNodes(ctx context.Context, nodeType string, nodeIDs []int) (map[int]*models.LatencySummary, error) {
+	latest := make(map[int]*models.LatencySummary)
+	if len(nodeIDs) == 0 {
+		return latest, nil
+	}
+
+	query := `
+		SELECT DISTINCT ON (node_id) node_id, date,
+		       COALESCE(p50_ms, 0), COALESCE(p95_ms, 0), COALESCE(p99_ms, 0)
+		FROM latency_daily_stats
+		WHERE node_type = $1 AND node_id = ANY($2) AND samples > 0
+		ORDER BY node_id, date DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, pq.Array(nodeIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query latest latency: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nodeID int
+		var date time.Time
+		summary := &models.LatencySummary{}
+
+		if err := rows.Scan(&nodeID, &date, &summary.P50Ms, &summary.P95Ms, &summary.P99Ms); err != nil {
+			return nil, fmt.Errorf("scan latency summary: %w", err)
+		}
+
+		summary.Date = date.Format("2006-01-02")
+		latest[nodeID] = summary
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return latest, nil
+}
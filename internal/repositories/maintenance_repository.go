@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// MaintenanceRepository defines the interface for maintenance window data
+// access
+type MaintenanceRepository interface {
+	Create(ctx context.Context, window *models.MaintenanceWindow) error
+	GetActive(ctx context.Context, nodeType string, nodeID int, at time.Time) (*models.MaintenanceWindow, error)
+	GetActiveByType(ctx context.Context, nodeType string, at time.Time) (map[int]*models.MaintenanceWindow, error)
+	EndActive(ctx context.Context, nodeType string, nodeID int, at time.Time) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.MaintenanceWindow, error)
+	ListForNode(ctx context.Context, nodeType string, nodeID int, endsAfter time.Time) ([]*models.MaintenanceWindow, error)
+	HasOverlap(ctx context.Context, nodeType string, nodeID int, startsAt, endsAt time.Time) (bool, error)
+	End(ctx context.Context, id int, at time.Time) error
+	Delete(ctx context.Context, id int) error
+}
+
+type maintenanceRepository struct {
+	db *sql.DB
+}
+
+// NewMaintenanceRepository creates a new maintenance window repository
+func NewMaintenanceRepository(db *sql.DB) MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+func (r *maintenanceRepository) Create(ctx context.Context, window *models.MaintenanceWindow) error {
+	query := `
+		INSERT INTO maintenance_windows (node_type, node_id, starts_at, ends_at, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		window.NodeType, window.NodeID, window.StartsAt, window.EndsAt, window.Reason, window.CreatedBy,
+	).Scan(&window.ID, &window.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create maintenance window: %w", err)
+	}
+
+	return nil
+}
+
+// GetActive returns the window open for a node at the given time, or nil
+func (r *maintenanceRepository) GetActive(ctx context.Context, nodeType string, nodeID int, at time.Time) (*models.MaintenanceWindow, error) {
+	query := `
+		SELECT id, node_type, node_id, starts_at, ends_at, COALESCE(reason, ''), created_by, created_at
+		FROM maintenance_windows
+		WHERE node_type = $1 AND node_id = $2 AND starts_at <= $3 AND ends_at > $3
+		ORDER BY ends_at DESC
+		LIMIT 1
+	`
+
+	window := &models.MaintenanceWindow{}
+	err := r.db.QueryRowContext(ctx, query, nodeType, nodeID, at).Scan(
+		&window.ID, &window.NodeType, &window.NodeID, &window.StartsAt, &window.EndsAt,
+		&window.Reason, &window.CreatedBy, &window.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get active maintenance window: %w", err)
+	}
+
+	return window, nil
+}
+
+// GetActiveByType returns the windows open at the given time for nodes of
+// one type, keyed by node ID
+func (r *maintenanceRepository) GetActiveByType(ctx context.Context, nodeType string, at time.Time) (map[int]*models.MaintenanceWindow, error) {
+	query := `
+		SELECT id, node_type, node_id, starts_at, ends_at, COALESCE(reason, ''), created_by, created_at
+		FROM maintenance_windows
+		WHERE node_type = $1 AND starts_at <= $2 AND ends_at > $2
+		ORDER BY node_id, ends_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, at)
+	if err != nil {
+		return nil, fmt.Errorf("query active maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := make(map[int]*models.MaintenanceWindow)
+	for rows.Next() {
+		window := &models.MaintenanceWindow{}
+		err := rows.Scan(
+			&window.ID, &window.NodeType, &window.NodeID, &window.StartsAt, &window.EndsAt,
+			&window.Reason, &window.CreatedBy, &window.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan maintenance window: %w", err)
+		}
+		// Ordered by end, so the longest window of a node wins
+		windows[window.NodeID] = window
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return windows, nil
+}
+
+// EndActive closes the windows open for a node at the given time
+func (r *maintenanceRepository) EndActive(ctx context.Context, nodeType string, nodeID int, at time.Time) (int64, error) {
+	query := `
+		UPDATE maintenance_windows SET ends_at = $3
+		WHERE node_type = $1 AND node_id = $2 AND starts_at < $3 AND ends_at > $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, nodeType, nodeID, at)
+	if err != nil {
+		return 0, fmt.Errorf("end maintenance window: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *maintenanceRepository) GetByID(ctx context.Context, id int) (*models.MaintenanceWindow, error) {
+	query := `
+		SELECT id, node_type, node_id, starts_at, ends_at, COALESCE(reason, ''), created_by, created_at
+		FROM maintenance_windows
+		WHERE id = $1
+	`
+
+	window := &models.MaintenanceWindow{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&window.ID, &window.NodeType, &window.NodeID, &window.StartsAt, &window.EndsAt,
+		&window.Reason, &window.CreatedBy, &window.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get maintenance window: %w", err)
+	}
+
+	return window, nil
+}
+
+// ListForNode returns a node's windows that end after the given time, so
+// the open and upcoming ones, earliest first
+func (r *maintenanceRepository) ListForNode(ctx context.Context, nodeType string, nodeID int, endsAfter time.Time) ([]*models.MaintenanceWindow, error) {
+	query := `
+		SELECT id, node_type, node_id, starts_at, ends_at, COALESCE(reason, ''), created_by, created_at
+		FROM maintenance_windows
+		WHERE node_type = $1 AND node_id = $2 AND ends_at > $3
+		ORDER BY starts_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, nodeID, endsAfter)
+	if err != nil {
+		return nil, fmt.Errorf("query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*models.MaintenanceWindow
+	for rows.Next() {
+		window := &models.MaintenanceWindow{}
+		err := rows.Scan(
+			&window.ID, &window.NodeType, &window.NodeID, &window.StartsAt, &window.EndsAt,
+			&window.Reason, &window.CreatedBy, &window.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return windows, nil
+}
+
+// HasOverlap reports whether a window of the node intersects
+// [startsAt, endsAt)
+func (r *maintenanceRepository) HasOverlap(ctx context.Context, nodeType string, nodeID int, startsAt, endsAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM maintenance_windows
+			WHERE node_type = $1 AND node_id = $2 AND starts_at < $4 AND ends_at > $3
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, nodeType, nodeID, startsAt, endsAt).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check maintenance overlap: %w", err)
+	}
+
+	return exists, nil
+}
+
+// End closes an open window at the given time
+func (r *maintenanceRepository) End(ctx context.Context, id int, at time.Time) error {
+	query := `UPDATE maintenance_windows SET ends_at = $2 WHERE id = $1 AND starts_at < $2 AND ends_at > $2`
+
+	if _, err := r.db.ExecContext(ctx, query, id, at); err != nil {
+		return fmt.Errorf("end maintenance window: %w", err)
+	}
+
+	return nil
+}
+
+func (r *maintenanceRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete maintenance window: %w", err)
+	}
+
+	return nil
+}
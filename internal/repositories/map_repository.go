@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// MapRepository defines the interface for map node data access
+type MapRepository interface {
+	ListMapNodes(ctx context.Context, filter models.MapFilter) ([]models.MapNode, error)
+}
+
+type mapRepository struct {
+	db *sql.DB
+}
+
+// NewMapRepository creates a new map repository
+func NewMapRepository(db *sql.DB) MapRepository {
+	return &mapRepository{db: db}
+}
+
+// mapNodesSource lists every located node of every type and network in map
+// form. Nodes are online when their score reaches threshold, the
+// placeholder of models.OnlineScoreThreshold; peers are online while
+// reachable.
+func mapNodesSource(threshold string) string {
+	return fmt.Sprintf(`
+	SELECT 'bootstrap' AS type, network, id, name, latitude, longitude,
+		   CASE WHEN overall_score >= %[1]s THEN 'online' ELSE 'offline' END AS status,
+		   COALESCE(country, '') AS country, COALESCE(country_code, '') AS country_code, COALESCE(city, '') AS city,
+		   name || ' ' || address AS search_key
+	FROM bootstrap_nodes
+	WHERE is_active = true AND (COALESCE(latitude, 0) <> 0 OR COALESCE(longitude, 0) <> 0)
+	UNION ALL
+	SELECT 'grpc', network, id, name, latitude, longitude,
+		   CASE WHEN overall_score >= %[1]s THEN 'online' ELSE 'offline' END,
+		   COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''),
+		   name || ' ' || address
+	FROM grpc_servers
+	WHERE is_active = true AND (COALESCE(latitude, 0) <> 0 OR COALESCE(longitude, 0) <> 0)
+	UNION ALL
+	SELECT 'jsonrpc', network, id, name, latitude, longitude,
+		   CASE WHEN overall_score >= %[1]s THEN 'online' ELSE 'offline' END,
+		   COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''),
+		   name || ' ' || address
+	FROM jsonrpc_servers
+	WHERE is_active = true AND (COALESCE(latitude, 0) <> 0 OR COALESCE(longitude, 0) <> 0)
+	UNION ALL
+	SELECT 'peer', network, id, LEFT(peer_id, 12) || '...', latitude, longitude,
+		   CASE WHEN is_reachable THEN 'online' ELSE 'offline' END,
+		   COALESCE(country, ''), COALESCE(country_code, ''), COALESCE(city, ''),
+		   peer_id || ' ' || COALESCE(address, '')
+	FROM reachable_peers
+	WHERE is_reachable = true AND (COALESCE(latitude, 0) <> 0 OR COALESCE(longitude, 0) <> 0)
+`, threshold)
+}
+
+// ListMapNodes returns one page of map nodes ordered by type and id, plus
+// one extra row when another page follows
+func (r *mapRepository) ListMapNodes(ctx context.Context, filter models.MapFilter) ([]models.MapNode, error) {
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	source := mapNodesSource(arg(models.OnlineScoreThreshold))
+	conditions := mapFilterConditions(filter, arg)
+	if cursor, err := models.DecodeCursor(filter.Cursor); err == nil {
+		conditions = append(conditions, fmt.Sprintf("(type, id) > (%s::text, %s)", arg(cursor.Value), arg(cursor.ID)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT type, id, name, latitude, longitude, status, country, city
+		FROM (%s) AS map_nodes
+		WHERE %s
+		ORDER BY type, id
+		LIMIT %s
+	`, source, strings.Join(conditions, " AND "), arg(filter.Limit+1))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list map nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []models.MapNode
+	for rows.Next() {
+		node, err := scanMapNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// mapFilterConditions returns the conditions of a map filter on the
+// columns of mapNodesSource, without its cursor
+func mapFilterConditions(filter models.MapFilter, arg func(value interface{}) string) []string {
+	conditions := []string{"network = " + arg(models.NetworkOrDefault(filter.Network))}
+
+	if filter.Type != "" {
+		conditions = append(conditions, "type = "+arg(filter.Type))
+	}
+	if filter.Country != "" {
+		p := arg(escapeLike(filter.Country))
+		conditions = append(conditions, fmt.Sprintf("(country_code ILIKE %s OR country ILIKE %s)", p, p))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+	if filter.Search != "" {
+		conditions = append(conditions, "search_key ILIKE "+arg("%"+escapeLike(filter.Search)+"%"))
+	}
+	return conditions
+}
+
+// scanMapNode scans one row of type, id, name, latitude, longitude,
+// status, country and city
+func scanMapNode(row rowScanner) (*models.MapNode, error) {
+	var node models.MapNode
+	var lat, lon float64
+	if err := row.Scan(&node.Type, &node.ID, &node.Name, &lat, &lon, &node.Status, &node.Country, &node.City); err != nil {
+		return nil, fmt.Errorf("scan map node: %w", err)
+	}
+	node.Coordinates = []float64{lat, lon}
+	return &node, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// NetworkRepository defines the interface for network data access
+type NetworkRepository interface {
+	ListNetworks(ctx context.Context, activeOnly bool) ([]*models.Network, error)
+	GetNetwork(ctx context.Context, name string) (*models.Network, error)
+	SaveNetwork(ctx context.Context, network *models.Network) error
+}
+
+type networkRepository struct {
+	db *sql.DB
+}
+
+// NewNetworkRepository creates a new network repository
+func NewNetworkRepository(db *sql.DB) NetworkRepository {
+	return &networkRepository{db: db}
+}
+
+// ListNetworks returns the networks by name, optionally only active ones
+func (r *networkRepository) ListNetworks(ctx context.Context, activeOnly bool) ([]*models.Network, error) {
+	query := `
+		SELECT name, kind, description, bootstrap_addresses, grpc_servers, is_active, created_at, updated_at
+		FROM networks
+		WHERE is_active OR NOT $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("query networks: %w", err)
+	}
+	defer rows.Close()
+
+	var networks []*models.Network
+	for rows.Next() {
+		n, err := scanNetwork(rows)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return networks, nil
+}
+
+// GetNetwork returns a network by name
+func (r *networkRepository) GetNetwork(ctx context.Context, name string) (*models.Network, error) {
+	query := `
+		SELECT name, kind, description, bootstrap_addresses, grpc_servers, is_active, created_at, updated_at
+		FROM networks
+		WHERE name = $1
+	`
+
+	n, err := scanNetwork(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return n, err
+}
+
+// SaveNetwork creates a network or replaces the settings of an existing one
+func (r *networkRepository) SaveNetwork(ctx context.Context, network *models.Network) error {
+	query := `
+		INSERT INTO networks (name, kind, description, bootstrap_addresses, grpc_servers, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			description = EXCLUDED.description,
+			bootstrap_addresses = EXCLUDED.bootstrap_addresses,
+			grpc_servers = EXCLUDED.grpc_servers,
+			is_active = EXCLUDED.is_active,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		network.Name, network.Kind, network.Description,
+		pq.Array(network.BootstrapAddresses), pq.Array(network.GRPCServers), network.IsActive,
+	).Scan(&network.CreatedAt, &network.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save network: %w", err)
+	}
+	return nil
+}
+
+// scanNetwork scans a networks row
+func scanNetwork(row interface{ Scan(dest ...any) error }) (*models.Network, error) {
+	n := &models.Network{}
+	err := row.Scan(
+		&n.Name, &n.Kind, &n.Description,
+		pq.Array(&n.BootstrapAddresses), pq.Array(&n.GRPCServers),
+		&n.IsActive, &n.CreatedAt, &n.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan network: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// nodeSortColumns maps node list sort keys to SQL expressions
+var nodeSortColumns = map[string]string{
+	models.SortByID:      "id",
+	models.SortByName:    "name",
+	models.SortByScore:   "overall_score",
+	models.SortByCountry: "COALESCE(country, '')",
+}
+
+// nodeSortCasts types cursor values for comparison with the sort column
+var nodeSortCasts = map[string]string{
+	models.SortByName:    "::text",
+	models.SortByScore:   "::double precision",
+	models.SortByCountry: "::text",
+}
+
+// buildNodeListQuery builds a keyset-paginated query over a node table.
+// The filter must have been validated. One row more than the page size is
+// requested so callers can tell whether another page follows.
+func buildNodeListQuery(columns, table string, hasNetwork bool, filter models.NodeFilter) (string, []interface{}) {
+	return buildNodeQuery(columns, table, hasNetwork, filter, true)
+}
+
+// buildNodeExportQuery builds a query over every node of a table the
+// filter selects, in list order. The cursor and page size are ignored.
+func buildNodeExportQuery(columns, table string, hasNetwork bool, filter models.NodeFilter) (string, []interface{}) {
+	return buildNodeQuery(columns, table, hasNetwork, filter, false)
+}
+
+func buildNodeQuery(columns, table string, hasNetwork bool, filter models.NodeFilter, paginate bool) (string, []interface{}) {
+	conditions := []string{"is_active = true"}
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if hasNetwork && filter.Network != "" {
+		conditions = append(conditions, "network = "+arg(filter.Network))
+	}
+	if filter.Country != "" {
+		p := arg(escapeLike(filter.Country))
+		conditions = append(conditions, fmt.Sprintf("(country_code ILIKE %s OR country ILIKE %s)", p, p))
+	}
+	if filter.MinScore > 0 {
+		conditions = append(conditions, "overall_score >= "+arg(filter.MinScore))
+	}
+	switch filter.Status {
+	case models.NodeStatusOnline:
+		conditions = append(conditions, "overall_score >= "+arg(models.OnlineScoreThreshold))
+	case models.NodeStatusOffline:
+		conditions = append(conditions, "overall_score < "+arg(models.OnlineScoreThreshold))
+	}
+	if filter.Search != "" {
+		p := arg("%" + escapeLike(filter.Search) + "%")
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE %s OR address ILIKE %s)", p, p))
+	}
+
+	column := nodeSortColumns[filter.Sort]
+	direction, comparison := "ASC", ">"
+	if filter.Order == "desc" {
+		direction, comparison = "DESC", "<"
+	}
+
+	if cursor, err := models.DecodeCursor(filter.Cursor); paginate && err == nil {
+		if filter.Sort == models.SortByID {
+			conditions = append(conditions, fmt.Sprintf("id %s %s", comparison, arg(cursor.ID)))
+		} else {
+			value := arg(cursor.Value) + nodeSortCasts[filter.Sort]
+			conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)", column, comparison, value, arg(cursor.ID)))
+		}
+	}
+
+	order := fmt.Sprintf("id %s", direction)
+	if filter.Sort != models.SortByID {
+		order = fmt.Sprintf("%s %s, id %s", column, direction, direction)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s",
+		columns, table, strings.Join(conditions, " AND "), order)
+	if paginate {
+		query += " LIMIT " + arg(filter.Limit+1)
+	}
+	return query, args
+}
+
+// escapeLike escapes the LIKE wildcards in a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func TestBuildNodeListQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		hasNetwork bool
+		filter     models.NodeFilter
+		contains   []string
+		args       []interface{}
+	}{
+		{
+			name:     "Defaults",
+			filter:   models.NodeFilter{},
+			contains: []string{"WHERE is_active = true ORDER BY id ASC LIMIT $1"},
+			args:     []interface{}{models.DefaultPageSize + 1},
+		},
+		{
+			name:       "Filters",
+			hasNetwork: true,
+			filter:     models.NodeFilter{Network: "mainnet", Country: "DE", MinScore: 80, Status: "online", Search: "50%_off"},
+			contains: []string{
+				"network = $1",
+				"(country_code ILIKE $2 OR country ILIKE $2)",
+				"overall_score >= $3",
+				"overall_score >= $4",
+				"(name ILIKE $5 OR address ILIKE $5)",
+			},
+			args: []interface{}{"mainnet", "DE", float64(80), models.OnlineScoreThreshold, `%50\%\_off%`, models.DefaultPageSize + 1},
+		},
+		{
+			name:     "Country is not a pattern",
+			filter:   models.NodeFilter{Country: "%"},
+			contains: []string{"(country_code ILIKE $1 OR country ILIKE $1)"},
+			args:     []interface{}{`\%`, models.DefaultPageSize + 1},
+		},
+		{
+			name:     "Network ignored without column",
+			filter:   models.NodeFilter{Network: "mainnet", Limit: 10},
+			contains: []string{"WHERE is_active = true ORDER BY"},
+			args:     []interface{}{11},
+		},
+		{
+			name: "Score cursor descending",
+			filter: models.NodeFilter{
+				Sort:   models.SortByScore,
+				Order:  "desc",
+				Limit:  20,
+				Cursor: models.PageCursor{Sort: models.SortByScore, Order: "desc", Value: "97.5", ID: 42}.Encode(),
+			},
+			contains: []string{
+				"(overall_score, id) < ($1::double precision, $2)",
+				"ORDER BY overall_score DESC, id DESC LIMIT $3",
+			},
+			args: []interface{}{"97.5", 42, 21},
+		},
+		{
+			name: "ID cursor",
+			filter: models.NodeFilter{
+				Cursor: models.PageCursor{Sort: models.SortByID, Order: "asc", ID: 7}.Encode(),
+			},
+			contains: []string{"id > $1", "ORDER BY id ASC"},
+			args:     []interface{}{7, models.DefaultPageSize + 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			if err := filter.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+
+			query, args := buildNodeListQuery("id, name", "grpc_servers", tt.hasNetwork, filter)
+			for _, fragment := range tt.contains {
+				if !strings.Contains(query, fragment) {
+					t.Errorf("Expected query to contain %q, got %s", fragment, query)
+				}
+			}
+			if !tt.hasNetwork && strings.Contains(query, "network") {
+				t.Errorf("Expected no network condition, got %s", query)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("Expected args %v, got %v", tt.args, args)
+			}
+		})
+	}
+}
+
+func TestNodeFilter_RejectsForeignCursor(t *testing.T) {
+	filter := models.NodeFilter{
+		Sort:   models.SortByName,
+		Cursor: models.PageCursor{Sort: models.SortByScore, Order: "desc", Value: "90", ID: 3}.Encode(),
+	}
+	if err := filter.Validate(); err == nil {
+		t.Error("Expected a cursor from another sort order to be rejected")
+	}
+
+	filter = models.NodeFilter{Cursor: "not-a-cursor"}
+	if err := filter.Validate(); err == nil {
+		t.Error("Expected a malformed cursor to be rejected")
+	}
+}
+
+func TestBuildNodeExportQuery(t *testing.T) {
+	filter := models.NodeFilter{
+		Network: "testnet",
+		Sort:    models.SortByScore,
+		Cursor:  models.PageCursor{Sort: models.SortByScore, Order: "desc", Value: "97.5", ID: 42}.Encode(),
+	}
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	query, args := buildNodeExportQuery("id, name", "grpc_servers", true, filter)
+	if !strings.HasSuffix(query, "WHERE is_active = true AND network = $1 ORDER BY overall_score DESC, id DESC") {
+		t.Errorf("Expected every matching node in list order, got %s", query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"testnet"}) {
+		t.Errorf("Expected only the network argument, got %v", args)
+	}
+}
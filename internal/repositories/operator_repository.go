@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// OperatorRepository defines the interface for operator token data access
+type OperatorRepository interface {
+	CreateToken(ctx context.Context, token *models.OperatorToken) error
+	ConsumeToken(ctx context.Context, kind, tokenHash string) (*models.OperatorToken, error)
+	GetSession(ctx context.Context, tokenHash string) (*models.OperatorToken, error)
+	RevokeToken(ctx context.Context, tokenHash string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type operatorRepository struct {
+	db *sql.DB
+}
+
+// NewOperatorRepository creates a new operator repository
+func NewOperatorRepository(db *sql.DB) OperatorRepository {
+	return &operatorRepository{db: db}
+}
+
+func (r *operatorRepository) CreateToken(ctx context.Context, token *models.OperatorToken) error {
+	query := `
+		INSERT INTO operator_tokens (kind, token_hash, email, node_type, node_id, method, expires_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, 0), $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		token.Kind, token.TokenHash, token.Email, token.NodeType, token.NodeID, token.Method, token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create operator token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeToken marks an unused, unexpired token of the given kind as used
+// and returns it. Concurrent calls cannot both succeed.
+func (r *operatorRepository) ConsumeToken(ctx context.Context, kind, tokenHash string) (*models.OperatorToken, error) {
+	query := `
+		UPDATE operator_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND kind = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, kind, token_hash, COALESCE(email, ''), COALESCE(node_type, ''), COALESCE(node_id, 0), method, expires_at, used_at, created_at
+	`
+
+	token, err := r.scanToken(r.db.QueryRowContext(ctx, query, tokenHash, kind))
+	if err != nil {
+		return nil, fmt.Errorf("consume operator token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *operatorRepository) GetSession(ctx context.Context, tokenHash string) (*models.OperatorToken, error) {
+	query := `
+		SELECT id, kind, token_hash, COALESCE(email, ''), COALESCE(node_type, ''), COALESCE(node_id, 0), method, expires_at, used_at, created_at
+		FROM operator_tokens
+		WHERE token_hash = $1 AND kind = 'session' AND expires_at > NOW()
+	`
+
+	token, err := r.scanToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		return nil, fmt.Errorf("get operator session: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *operatorRepository) RevokeToken(ctx context.Context, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM operator_tokens WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("revoke operator token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *operatorRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM operator_tokens WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired operator tokens: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// scanToken scans a single token, returning nil when there is no row
+func (r *operatorRepository) scanToken(row *sql.Row) (*models.OperatorToken, error) {
+	token := &models.OperatorToken{}
+	err := row.Scan(
+		&token.ID, &token.Kind, &token.TokenHash, &token.Email, &token.NodeType, &token.NodeID,
+		&token.Method, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
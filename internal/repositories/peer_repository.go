@@ -12,21 +12,22 @@ import (
 type PeerRepository interface {
 	// Peer operations
 	GetAllPeers(ctx context.Context) ([]*models.ReachablePeer, error)
-	GetReachablePeers(ctx context.Context) ([]*models.ReachablePeer, error)
+	GetReachablePeers(ctx context.Context, network string) ([]*models.ReachablePeer, error)
 	GetPeerByID(ctx context.Context, id int) (*models.ReachablePeer, error)
-	GetPeerByPeerID(ctx context.Context, peerID string) (*models.ReachablePeer, error)
+	GetPeerByPeerID(ctx context.Context, network, peerID string) (*models.ReachablePeer, error)
 	
 	// CRUD operations
 	CreatePeer(ctx context.Context, peer *models.ReachablePeer) error
 	UpsertPeer(ctx context.Context, peer *models.ReachablePeer) error
+	RecordSeenPeer(ctx context.Context, peer *models.ReachablePeer) (bool, error)
 	UpdatePeer(ctx context.Context, peer *models.ReachablePeer) error
 	UpdatePeerGeo(ctx context.Context, id int, geo *models.GeoLocation) error
 	
 	// Aggregations
-	CountReachable(ctx context.Context) (int, error)
-	CountCountries(ctx context.Context) (int, error)
-	GetTopCountries(ctx context.Context, limit int) ([]models.CountryStats, error)
-	GetAvgUptime(ctx context.Context) (float64, error)
+	CountReachable(ctx context.Context, network string) (int, error)
+	CountCountries(ctx context.Context, network string) (int, error)
+	GetTopCountries(ctx context.Context, network string, limit int) ([]models.CountryStats, error)
+	GetAvgUptime(ctx context.Context, network string) (float64, error)
 }
 
 type peerRepository struct {
@@ -40,7 +41,7 @@ func NewPeerRepository(db *sql.DB) PeerRepository {
 
 func (r *peerRepository) GetAllPeers(ctx context.Context) ([]*models.ReachablePeer, error) {
 	query := `
-		SELECT id, peer_id, address, protocol, user_agent, last_seen, first_seen,
+		SELECT id, network, peer_id, address, protocol, user_agent, last_seen, first_seen,
 			   ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
 			   is_reachable, connection_attempts, successful_connections, overall_score,
 			   created_at, updated_at
@@ -57,18 +58,18 @@ func (r *peerRepository) GetAllPeers(ctx context.Context) ([]*models.ReachablePe
 	return r.scanPeers(rows)
 }
 
-func (r *peerRepository) GetReachablePeers(ctx context.Context) ([]*models.ReachablePeer, error) {
+func (r *peerRepository) GetReachablePeers(ctx context.Context, network string) ([]*models.ReachablePeer, error) {
 	query := `
-		SELECT id, peer_id, address, protocol, user_agent, last_seen, first_seen,
+		SELECT id, network, peer_id, address, protocol, user_agent, last_seen, first_seen,
 			   ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
 			   is_reachable, connection_attempts, successful_connections, overall_score,
 			   created_at, updated_at
 		FROM reachable_peers
-		WHERE is_reachable = true
+		WHERE is_reachable = true AND network = $1
 		ORDER BY last_seen DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, network)
 	if err != nil {
 		return nil, fmt.Errorf("query reachable peers: %w", err)
 	}
@@ -79,7 +80,7 @@ func (r *peerRepository) GetReachablePeers(ctx context.Context) ([]*models.Reach
 
 func (r *peerRepository) GetPeerByID(ctx context.Context, id int) (*models.ReachablePeer, error) {
 	query := `
-		SELECT id, peer_id, address, protocol, user_agent, last_seen, first_seen,
+		SELECT id, network, peer_id, address, protocol, user_agent, last_seen, first_seen,
 			   ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
 			   is_reachable, connection_attempts, successful_connections, overall_score,
 			   created_at, updated_at
@@ -89,7 +90,7 @@ func (r *peerRepository) GetPeerByID(ctx context.Context, id int) (*models.Reach
 
 	peer := &models.ReachablePeer{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&peer.ID, &peer.PeerID, &peer.Address, &peer.Protocol, &peer.UserAgent,
+		&peer.ID, &peer.Network, &peer.PeerID, &peer.Address, &peer.Protocol, &peer.UserAgent,
 		&peer.LastSeen, &peer.FirstSeen, &peer.IPAddress, &peer.Country, &peer.CountryCode,
 		&peer.City, &peer.Latitude, &peer.Longitude, &peer.Timezone, &peer.ASN, &peer.Organization,
 		&peer.IsReachable, &peer.ConnectionAttempts, &peer.SuccessfulConnections, &peer.OverallScore,
@@ -106,19 +107,19 @@ func (r *peerRepository) GetPeerByID(ctx context.Context, id int) (*models.Reach
 	return peer, nil
 }
 
-func (r *peerRepository) GetPeerByPeerID(ctx context.Context, peerID string) (*models.ReachablePeer, error) {
+func (r *peerRepository) GetPeerByPeerID(ctx context.Context, network, peerID string) (*models.ReachablePeer, error) {
 	query := `
-		SELECT id, peer_id, address, protocol, user_agent, last_seen, first_seen,
+		SELECT id, network, peer_id, address, protocol, user_agent, last_seen, first_seen,
 			   ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
 			   is_reachable, connection_attempts, successful_connections, overall_score,
 			   created_at, updated_at
 		FROM reachable_peers
-		WHERE peer_id = $1
+		WHERE network = $1 AND peer_id = $2
 	`
 
 	peer := &models.ReachablePeer{}
-	err := r.db.QueryRowContext(ctx, query, peerID).Scan(
-		&peer.ID, &peer.PeerID, &peer.Address, &peer.Protocol, &peer.UserAgent,
+	err := r.db.QueryRowContext(ctx, query, network, peerID).Scan(
+		&peer.ID, &peer.Network, &peer.PeerID, &peer.Address, &peer.Protocol, &peer.UserAgent,
 		&peer.LastSeen, &peer.FirstSeen, &peer.IPAddress, &peer.Country, &peer.CountryCode,
 		&peer.City, &peer.Latitude, &peer.Longitude, &peer.Timezone, &peer.ASN, &peer.Organization,
 		&peer.IsReachable, &peer.ConnectionAttempts, &peer.SuccessfulConnections, &peer.OverallScore,
@@ -140,8 +141,8 @@ func (r *peerRepository) CreatePeer(ctx context.Context, peer *models.ReachableP
 		INSERT INTO reachable_peers (
 			peer_id, address, protocol, user_agent, last_seen, first_seen,
 			ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
-			is_reachable, connection_attempts, successful_connections, overall_score
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			is_reachable, connection_attempts, successful_connections, overall_score, network
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -149,7 +150,7 @@ func (r *peerRepository) CreatePeer(ctx context.Context, peer *models.ReachableP
 		peer.PeerID, peer.Address, peer.Protocol, peer.UserAgent, peer.LastSeen, peer.FirstSeen,
 		peer.IPAddress, peer.Country, peer.CountryCode, peer.City, peer.Latitude, peer.Longitude,
 		peer.Timezone, peer.ASN, peer.Organization, peer.IsReachable, peer.ConnectionAttempts,
-		peer.SuccessfulConnections, peer.OverallScore,
+		peer.SuccessfulConnections, peer.OverallScore, models.NetworkOrDefault(peer.Network),
 	).Scan(&peer.ID, &peer.CreatedAt, &peer.UpdatedAt)
 
 	if err != nil {
@@ -164,9 +165,9 @@ func (r *peerRepository) UpsertPeer(ctx context.Context, peer *models.ReachableP
 		INSERT INTO reachable_peers (
 			peer_id, address, protocol, user_agent, last_seen, first_seen,
 			ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
-			is_reachable, connection_attempts, successful_connections, overall_score
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
-		ON CONFLICT (peer_id) DO UPDATE SET
+			is_reachable, connection_attempts, successful_connections, overall_score, network
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		ON CONFLICT (network, peer_id) DO UPDATE SET
 			address = EXCLUDED.address,
 			protocol = EXCLUDED.protocol,
 			user_agent = EXCLUDED.user_agent,
@@ -191,7 +192,7 @@ func (r *peerRepository) UpsertPeer(ctx context.Context, peer *models.ReachableP
 		peer.PeerID, peer.Address, peer.Protocol, peer.UserAgent, peer.LastSeen, peer.FirstSeen,
 		peer.IPAddress, peer.Country, peer.CountryCode, peer.City, peer.Latitude, peer.Longitude,
 		peer.Timezone, peer.ASN, peer.Organization, peer.IsReachable, peer.ConnectionAttempts,
-		peer.SuccessfulConnections, peer.OverallScore,
+		peer.SuccessfulConnections, peer.OverallScore, models.NetworkOrDefault(peer.Network),
 	).Scan(&peer.ID, &peer.CreatedAt, &peer.UpdatedAt)
 
 	if err != nil {
@@ -201,6 +202,37 @@ func (r *peerRepository) UpsertPeer(ctx context.Context, peer *models.ReachableP
 	return nil
 }
 
+// RecordSeenPeer stores a peer found by the crawler on its network and
+// reports whether it was new. Only the address, user agent and last_seen of a known peer are
+// updated, so its geo data and connection counters are kept.
+func (r *peerRepository) RecordSeenPeer(ctx context.Context, peer *models.ReachablePeer) (bool, error) {
+	query := `
+		INSERT INTO reachable_peers (
+			peer_id, address, protocol, user_agent, last_seen, first_seen,
+			ip_address, country, country_code, city, latitude, longitude, timezone, asn, organization,
+			is_reachable, network
+		) VALUES ($1, $2, '', $3, $4, $4, '', '', '', '', 0, 0, '', '', '', true, $5)
+		ON CONFLICT (network, peer_id) DO UPDATE SET
+			address = COALESCE(NULLIF(EXCLUDED.address, ''), reachable_peers.address),
+			user_agent = COALESCE(NULLIF(EXCLUDED.user_agent, ''), reachable_peers.user_agent),
+			last_seen = GREATEST(reachable_peers.last_seen, EXCLUDED.last_seen),
+			is_reachable = true,
+			updated_at = NOW()
+		RETURNING id, first_seen, created_at, updated_at, (xmax = 0)
+	`
+
+	var created bool
+	err := r.db.QueryRowContext(ctx, query,
+		peer.PeerID, peer.Address, peer.UserAgent, peer.LastSeen, models.NetworkOrDefault(peer.Network),
+	).Scan(&peer.ID, &peer.FirstSeen, &peer.CreatedAt, &peer.UpdatedAt, &created)
+
+	if err != nil {
+		return false, fmt.Errorf("record seen peer: %w", err)
+	}
+
+	return created, nil
+}
+
 func (r *peerRepository) UpdatePeer(ctx context.Context, peer *models.ReachablePeer) error {
 	query := `
 		UPDATE reachable_peers SET
@@ -244,11 +276,11 @@ func (r *peerRepository) UpdatePeerGeo(ctx context.Context, id int, geo *models.
 	return nil
 }
 
-func (r *peerRepository) CountReachable(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM reachable_peers WHERE is_reachable = true`
+func (r *peerRepository) CountReachable(ctx context.Context, network string) (int, error) {
+	query := `SELECT COUNT(*) FROM reachable_peers WHERE is_reachable = true AND network = $1`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, network).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count reachable: %w", err)
 	}
@@ -256,11 +288,11 @@ func (r *peerRepository) CountReachable(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-func (r *peerRepository) CountCountries(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(DISTINCT country_code) FROM reachable_peers WHERE country_code IS NOT NULL AND country_code != ''`
+func (r *peerRepository) CountCountries(ctx context.Context, network string) (int, error) {
+	query := `SELECT COUNT(DISTINCT country_code) FROM reachable_peers WHERE country_code IS NOT NULL AND country_code != '' AND network = $1`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, network).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count countries: %w", err)
 	}
@@ -268,17 +300,17 @@ func (r *peerRepository) CountCountries(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-func (r *peerRepository) GetTopCountries(ctx context.Context, limit int) ([]models.CountryStats, error) {
+func (r *peerRepository) GetTopCountries(ctx context.Context, network string, limit int) ([]models.CountryStats, error) {
 	query := `
 		SELECT country, country_code, COUNT(*) as count
 		FROM reachable_peers
-		WHERE country IS NOT NULL AND country != '' AND is_reachable = true
+		WHERE country IS NOT NULL AND country != '' AND is_reachable = true AND network = $1
 		GROUP BY country, country_code
 		ORDER BY count DESC
-		LIMIT $1
+		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, network, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get top countries: %w", err)
 	}
@@ -296,7 +328,7 @@ func (r *peerRepository) GetTopCountries(ctx context.Context, limit int) ([]mode
 	return stats, nil
 }
 
-func (r *peerRepository) GetAvgUptime(ctx context.Context) (float64, error) {
+func (r *peerRepository) GetAvgUptime(ctx context.Context, network string) (float64, error) {
 	query := `
 		SELECT COALESCE(AVG(
 			CASE WHEN connection_attempts > 0 
@@ -305,11 +337,11 @@ func (r *peerRepository) GetAvgUptime(ctx context.Context) (float64, error) {
 			END
 		), 0)
 		FROM reachable_peers
-		WHERE is_reachable = true
+		WHERE is_reachable = true AND network = $1
 	`
 
 	var avg float64
-	err := r.db.QueryRowContext(ctx, query).Scan(&avg)
+	err := r.db.QueryRowContext(ctx, query, network).Scan(&avg)
 	if err != nil {
 		return 0, fmt.Errorf("get avg uptime: %w", err)
 	}
@@ -322,14 +354,7 @@ func (r *peerRepository) scanPeers(rows *sql.Rows) ([]*models.ReachablePeer, err
 	var peers []*models.ReachablePeer
 
 	for rows.Next() {
-		peer := &models.ReachablePeer{}
-		err := rows.Scan(
-			&peer.ID, &peer.PeerID, &peer.Address, &peer.Protocol, &peer.UserAgent,
-			&peer.LastSeen, &peer.FirstSeen, &peer.IPAddress, &peer.Country, &peer.CountryCode,
-			&peer.City, &peer.Latitude, &peer.Longitude, &peer.Timezone, &peer.ASN, &peer.Organization,
-			&peer.IsReachable, &peer.ConnectionAttempts, &peer.SuccessfulConnections, &peer.OverallScore,
-			&peer.CreatedAt, &peer.UpdatedAt,
-		)
+		peer, err := scanPeer(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan peer: %w", err)
 		}
@@ -342,3 +367,19 @@ func (r *peerRepository) scanPeers(rows *sql.Rows) ([]*models.ReachablePeer, err
 
 	return peers, nil
 }
+
+// scanPeer scans one row of the reachable_peers columns selected above
+func scanPeer(row interface{ Scan(dest ...any) error }) (*models.ReachablePeer, error) {
+	peer := &models.ReachablePeer{}
+	err := row.Scan(
+		&peer.ID, &peer.Network, &peer.PeerID, &peer.Address, &peer.Protocol, &peer.UserAgent,
+		&peer.LastSeen, &peer.FirstSeen, &peer.IPAddress, &peer.Country, &peer.CountryCode,
+		&peer.City, &peer.Latitude, &peer.Longitude, &peer.Timezone, &peer.ASN, &peer.Organization,
+		&peer.IsReachable, &peer.ConnectionAttempts, &peer.SuccessfulConnections, &peer.OverallScore,
+		&peer.CreatedAt, &peer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
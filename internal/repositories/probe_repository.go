@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// ProbeRepository defines the interface for probe agent result data access
+type ProbeRepository interface {
+	CreateResult(ctx context.Context, result *models.ProbeResult) error
+	GetResultsByNodeAndDate(ctx context.Context, nodeType string, nodeID int, date time.Time) ([]*models.ProbeResult, error)
+	DeleteOldResults(ctx context.Context, beforeDate time.Time) error
+	MarkNonce(ctx context.Context, agentID, nonce string, expiresAt time.Time) (bool, error)
+	DeleteExpiredNonces(ctx context.Context) error
+}
+
+type probeRepository struct {
+	db *sql.DB
+}
+
+// NewProbeRepository creates a new probe result repository
+func NewProbeRepository(db *sql.DB) ProbeRepository {
+	return &probeRepository{db: db}
+}
+
+func (r *probeRepository) CreateResult(ctx context.Context, result *models.ProbeResult) error {
+	query := `
+		INSERT INTO probe_results (agent_id, region, node_type, node_id, date, success, attempts, response_time_ms, error_msg, error_class, checked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+		ON CONFLICT (agent_id, node_type, node_id, date)
+		DO UPDATE SET
+			region = EXCLUDED.region,
+			success = EXCLUDED.success,
+			attempts = EXCLUDED.attempts,
+			response_time_ms = EXCLUDED.response_time_ms,
+			error_msg = EXCLUDED.error_msg,
+			error_class = EXCLUDED.error_class,
+			checked_at = EXCLUDED.checked_at,
+			created_at = NOW()
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		result.AgentID, result.Region, result.NodeType, result.NodeID, result.Date,
+		result.Success, result.Attempts, result.ResponseTimeMs, result.ErrorMsg, result.ErrorClass, result.CheckedAt,
+	).Scan(&result.ID, &result.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("create probe result: %w", err)
+	}
+
+	return nil
+}
+
+func (r *probeRepository) GetResultsByNodeAndDate(ctx context.Context, nodeType string, nodeID int, date time.Time) ([]*models.ProbeResult, error) {
+	query := `
+		SELECT id, agent_id, region, node_type, node_id, date, success, attempts,
+		       COALESCE(response_time_ms, 0), COALESCE(error_msg, ''), COALESCE(error_class, ''), checked_at, created_at
+		FROM probe_results
+		WHERE node_type = $1 AND node_id = $2 AND date = $3
+		ORDER BY region, agent_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, nodeID, date)
+	if err != nil {
+		return nil, fmt.Errorf("query probe results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.ProbeResult
+	for rows.Next() {
+		result := &models.ProbeResult{}
+		err := rows.Scan(
+			&result.ID, &result.AgentID, &result.Region, &result.NodeType, &result.NodeID,
+			&result.Date, &result.Success, &result.Attempts, &result.ResponseTimeMs,
+			&result.ErrorMsg, &result.ErrorClass, &result.CheckedAt, &result.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan probe result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *probeRepository) DeleteOldResults(ctx context.Context, beforeDate time.Time) error {
+	query := `DELETE FROM probe_results WHERE date < $1`
+
+	if _, err := r.db.ExecContext(ctx, query, beforeDate); err != nil {
+		return fmt.Errorf("delete old probe results: %w", err)
+	}
+
+	return nil
+}
+
+// MarkNonce records an agent request nonce until expiresAt and reports
+// whether it was new. An expired nonce counts as new again.
+func (r *probeRepository) MarkNonce(ctx context.Context, agentID, nonce string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO probe_nonces (agent_id, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (agent_id, nonce)
+		DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE probe_nonces.expires_at < NOW()
+	`
+
+	result, err := r.db.ExecContext(ctx, query, agentID, nonce, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("mark probe nonce: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("check rows affected: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+func (r *probeRepository) DeleteExpiredNonces(ctx context.Context) error {
+	query := `DELETE FROM probe_nonces WHERE expires_at < NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("delete expired probe nonces: %w", err)
+	}
+
+	return nil
+}
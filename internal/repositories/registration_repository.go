@@ -16,7 +16,11 @@ type RegistrationRepository interface {
 	GetByStatus(ctx context.Context, status string) ([]*models.NodeRegistration, error)
 	GetAll(ctx context.Context) ([]*models.NodeRegistration, error)
 	UpdateStatus(ctx context.Context, id int, status, reason, reviewedBy string, reviewedAt *time.Time) error
+	Approve(ctx context.Context, id int, reviewedBy string, reviewedAt time.Time) (bool, error)
 	ExistsByAddress(ctx context.Context, address string) (bool, error)
+	MarkVerified(ctx context.Context, id int, method string, verifiedAt time.Time) error
+	Confirm(ctx context.Context, token string, issuedAfter time.Time) (int, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type registrationRepository struct {
@@ -30,14 +34,15 @@ func NewRegistrationRepository(db *sql.DB) RegistrationRepository {
 
 func (r *registrationRepository) Create(ctx context.Context, registration *models.NodeRegistration) error {
 	query := `
-		INSERT INTO node_registrations (node_type, name, address, network, email, website, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO node_registrations (node_type, name, address, network, email, website, status, verification_token, confirmation_token)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
 		RETURNING id, created_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
 		registration.NodeType, registration.Name, registration.Address,
 		registration.Network, registration.Email, registration.Website, registration.Status,
+		registration.VerificationToken, registration.ConfirmationToken,
 	).Scan(&registration.ID, &registration.CreatedAt)
 
 	if err != nil {
@@ -49,7 +54,9 @@ func (r *registrationRepository) Create(ctx context.Context, registration *model
 
 func (r *registrationRepository) GetByID(ctx context.Context, id int) (*models.NodeRegistration, error) {
 	query := `
-		SELECT id, node_type, name, address, network, email, website, status, rejection_reason, created_at, reviewed_at, reviewed_by
+		SELECT id, node_type, name, address, network, email, website, status, COALESCE(rejection_reason, ''), created_at, reviewed_at, COALESCE(reviewed_by, ''),
+		       COALESCE(verification_token, ''), COALESCE(is_verified, false), COALESCE(verification_method, ''), verified_at,
+		       COALESCE(confirmation_token, ''), confirmed_at
 		FROM node_registrations
 		WHERE id = $1
 	`
@@ -59,6 +66,8 @@ func (r *registrationRepository) GetByID(ctx context.Context, id int) (*models.N
 		&registration.ID, &registration.NodeType, &registration.Name, &registration.Address,
 		&registration.Network, &registration.Email, &registration.Website, &registration.Status,
 		&registration.RejectionReason, &registration.CreatedAt, &registration.ReviewedAt, &registration.ReviewedBy,
+		&registration.VerificationToken, &registration.IsVerified, &registration.VerificationMethod, &registration.VerifiedAt,
+		&registration.ConfirmationToken, &registration.ConfirmedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -73,7 +82,9 @@ func (r *registrationRepository) GetByID(ctx context.Context, id int) (*models.N
 
 func (r *registrationRepository) GetByStatus(ctx context.Context, status string) ([]*models.NodeRegistration, error) {
 	query := `
-		SELECT id, node_type, name, address, network, email, website, status, rejection_reason, created_at, reviewed_at, reviewed_by
+		SELECT id, node_type, name, address, network, email, website, status, COALESCE(rejection_reason, ''), created_at, reviewed_at, COALESCE(reviewed_by, ''),
+		       COALESCE(verification_token, ''), COALESCE(is_verified, false), COALESCE(verification_method, ''), verified_at,
+		       COALESCE(confirmation_token, ''), confirmed_at
 		FROM node_registrations
 		WHERE status = $1
 		ORDER BY created_at DESC
@@ -90,7 +101,9 @@ func (r *registrationRepository) GetByStatus(ctx context.Context, status string)
 
 func (r *registrationRepository) GetAll(ctx context.Context) ([]*models.NodeRegistration, error) {
 	query := `
-		SELECT id, node_type, name, address, network, email, website, status, rejection_reason, created_at, reviewed_at, reviewed_by
+		SELECT id, node_type, name, address, network, email, website, status, COALESCE(rejection_reason, ''), created_at, reviewed_at, COALESCE(reviewed_by, ''),
+		       COALESCE(verification_token, ''), COALESCE(is_verified, false), COALESCE(verification_method, ''), verified_at,
+		       COALESCE(confirmation_token, ''), confirmed_at
 		FROM node_registrations
 		ORDER BY created_at DESC
 	`
@@ -119,11 +132,41 @@ func (r *registrationRepository) UpdateStatus(ctx context.Context, id int, statu
 	return nil
 }
 
+// Approve marks the registration approved if it is still pending. It
+// reports false when another review got there first.
+func (r *registrationRepository) Approve(ctx context.Context, id int, reviewedBy string, reviewedAt time.Time) (bool, error) {
+	query := `
+		UPDATE node_registrations SET
+			status = 'approved', rejection_reason = '', reviewed_by = $1, reviewed_at = $2
+		WHERE id = $3 AND status = 'pending'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, reviewedBy, reviewedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("approve registration: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("approve registration: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
 func (r *registrationRepository) ExistsByAddress(ctx context.Context, address string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM node_registrations WHERE address = $1 AND status != 'rejected')`
+	// Unconfirmed registrations whose link expired no longer hold the address
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM node_registrations
+			WHERE address = $1 AND status != 'rejected'
+			  AND NOT (status = 'unconfirmed' AND created_at <= $2)
+		)
+	`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, address).Scan(&exists)
+	expired := time.Now().Add(-models.RegistrationConfirmationTTL)
+	err := r.db.QueryRowContext(ctx, query, address, expired).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check registration exists: %w", err)
 	}
@@ -131,6 +174,53 @@ func (r *registrationRepository) ExistsByAddress(ctx context.Context, address st
 	return exists, nil
 }
 
+func (r *registrationRepository) MarkVerified(ctx context.Context, id int, method string, verifiedAt time.Time) error {
+	query := `
+		UPDATE node_registrations SET
+			is_verified = true, verification_method = $1, verified_at = $2
+		WHERE id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, method, verifiedAt, id)
+	if err != nil {
+		return fmt.Errorf("mark registration verified: %w", err)
+	}
+
+	return nil
+}
+
+// Confirm moves the unconfirmed registration holding token to pending
+// review if it was created after issuedAfter. The token is cleared so that
+// the link works once. It returns 0 when no registration matched.
+func (r *registrationRepository) Confirm(ctx context.Context, token string, issuedAfter time.Time) (int, error) {
+	query := `
+		UPDATE node_registrations SET
+			status = 'pending', confirmation_token = NULL, confirmed_at = NOW()
+		WHERE confirmation_token = $1 AND status = 'unconfirmed' AND created_at > $2
+		RETURNING id
+	`
+
+	var id int
+	err := r.db.QueryRowContext(ctx, query, token, issuedAfter).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("confirm registration: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *registrationRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM node_registrations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete registration: %w", err)
+	}
+
+	return nil
+}
+
 // Helper function to scan multiple registrations
 func (r *registrationRepository) scanRegistrations(rows *sql.Rows) ([]*models.NodeRegistration, error) {
 	var registrations []*models.NodeRegistration
@@ -141,6 +231,8 @@ func (r *registrationRepository) scanRegistrations(rows *sql.Rows) ([]*models.No
 			&registration.ID, &registration.NodeType, &registration.Name, &registration.Address,
 			&registration.Network, &registration.Email, &registration.Website, &registration.Status,
 			&registration.RejectionReason, &registration.CreatedAt, &registration.ReviewedAt, &registration.ReviewedBy,
+			&registration.VerificationToken, &registration.IsVerified, &registration.VerificationMethod, &registration.VerifiedAt,
+			&registration.ConfirmationToken, &registration.ConfirmedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan registration: %w", err)
@@ -13,9 +13,9 @@ import (
 // SnapshotRepository defines the interface for network snapshot data access
 type SnapshotRepository interface {
 	CreateSnapshot(ctx context.Context, snapshot *models.NetworkSnapshot) error
-	GetLatestSnapshot(ctx context.Context) (*models.NetworkSnapshot, error)
-	GetSnapshots(ctx context.Context, limit int) ([]*models.NetworkSnapshot, error)
-	GetSnapshotsByDateRange(ctx context.Context, start, end time.Time) ([]*models.NetworkSnapshot, error)
+	GetLatestSnapshot(ctx context.Context, network string) (*models.NetworkSnapshot, error)
+	GetSnapshots(ctx context.Context, network string, limit int) ([]*models.NetworkSnapshot, error)
+	GetSnapshotsByDateRange(ctx context.Context, network string, start, end time.Time) ([]*models.NetworkSnapshot, error)
 }
 
 type snapshotRepository struct {
@@ -29,20 +29,22 @@ func NewSnapshotRepository(db *sql.DB) SnapshotRepository {
 
 func (r *snapshotRepository) CreateSnapshot(ctx context.Context, snapshot *models.NetworkSnapshot) error {
 	query := `
-		INSERT INTO network_snapshots (timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, snapshot_data)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO network_snapshots (network, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, snapshot_data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at
 	`
 
-	var snapshotData []byte
+	snapshotData := []byte("{}")
 	if snapshot.SnapshotData != nil {
-		snapshotData = snapshot.SnapshotData
-	} else {
-		snapshotData, _ = json.Marshal(map[string]interface{}{})
+		data, err := json.Marshal(snapshot.SnapshotData)
+		if err != nil {
+			return fmt.Errorf("marshal snapshot data: %w", err)
+		}
+		snapshotData = data
 	}
 
 	err := r.db.QueryRowContext(ctx, query,
-		snapshot.Timestamp, snapshot.TotalNodes, snapshot.ReachableNodes,
+		models.NetworkOrDefault(snapshot.Network), snapshot.Timestamp, snapshot.TotalNodes, snapshot.ReachableNodes,
 		snapshot.CountriesCount, snapshot.GRPCNodes, snapshot.JSONRPCNodes,
 		snapshot.BootstrapNodes, snapshotData,
 	).Scan(&snapshot.ID, &snapshot.CreatedAt)
@@ -54,21 +56,16 @@ func (r *snapshotRepository) CreateSnapshot(ctx context.Context, snapshot *model
 	return nil
 }
 
-func (r *snapshotRepository) GetLatestSnapshot(ctx context.Context) (*models.NetworkSnapshot, error) {
+func (r *snapshotRepository) GetLatestSnapshot(ctx context.Context, network string) (*models.NetworkSnapshot, error) {
 	query := `
-		SELECT id, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, snapshot_data, created_at
+		SELECT id, network, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, COALESCE(snapshot_data, '{}'), created_at
 		FROM network_snapshots
+		WHERE network = $1
 		ORDER BY timestamp DESC
 		LIMIT 1
 	`
 
-	snapshot := &models.NetworkSnapshot{}
-	err := r.db.QueryRowContext(ctx, query).Scan(
-		&snapshot.ID, &snapshot.Timestamp, &snapshot.TotalNodes, &snapshot.ReachableNodes,
-		&snapshot.CountriesCount, &snapshot.GRPCNodes, &snapshot.JSONRPCNodes,
-		&snapshot.BootstrapNodes, &snapshot.SnapshotData, &snapshot.CreatedAt,
-	)
-
+	snapshot, err := scanSnapshot(r.db.QueryRowContext(ctx, query, network))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -79,15 +76,16 @@ func (r *snapshotRepository) GetLatestSnapshot(ctx context.Context) (*models.Net
 	return snapshot, nil
 }
 
-func (r *snapshotRepository) GetSnapshots(ctx context.Context, limit int) ([]*models.NetworkSnapshot, error) {
+func (r *snapshotRepository) GetSnapshots(ctx context.Context, network string, limit int) ([]*models.NetworkSnapshot, error) {
 	query := `
-		SELECT id, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, snapshot_data, created_at
+		SELECT id, network, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, COALESCE(snapshot_data, '{}'), created_at
 		FROM network_snapshots
+		WHERE network = $1
 		ORDER BY timestamp DESC
-		LIMIT $1
+		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, network, limit)
 	if err != nil {
 		return nil, fmt.Errorf("query snapshots: %w", err)
 	}
@@ -96,15 +94,15 @@ func (r *snapshotRepository) GetSnapshots(ctx context.Context, limit int) ([]*mo
 	return r.scanSnapshots(rows)
 }
 
-func (r *snapshotRepository) GetSnapshotsByDateRange(ctx context.Context, start, end time.Time) ([]*models.NetworkSnapshot, error) {
+func (r *snapshotRepository) GetSnapshotsByDateRange(ctx context.Context, network string, start, end time.Time) ([]*models.NetworkSnapshot, error) {
 	query := `
-		SELECT id, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, snapshot_data, created_at
+		SELECT id, network, timestamp, total_nodes, reachable_nodes, countries_count, grpc_nodes, jsonrpc_nodes, bootstrap_nodes, COALESCE(snapshot_data, '{}'), created_at
 		FROM network_snapshots
-		WHERE timestamp >= $1 AND timestamp <= $2
+		WHERE network = $1 AND timestamp >= $2 AND timestamp <= $3
 		ORDER BY timestamp DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, start, end)
+	rows, err := r.db.QueryContext(ctx, query, network, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("query snapshots by date range: %w", err)
 	}
@@ -118,12 +116,7 @@ func (r *snapshotRepository) scanSnapshots(rows *sql.Rows) ([]*models.NetworkSna
 	var snapshots []*models.NetworkSnapshot
 
 	for rows.Next() {
-		snapshot := &models.NetworkSnapshot{}
-		err := rows.Scan(
-			&snapshot.ID, &snapshot.Timestamp, &snapshot.TotalNodes, &snapshot.ReachableNodes,
-			&snapshot.CountriesCount, &snapshot.GRPCNodes, &snapshot.JSONRPCNodes,
-			&snapshot.BootstrapNodes, &snapshot.SnapshotData, &snapshot.CreatedAt,
-		)
+		snapshot, err := scanSnapshot(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan snapshot: %w", err)
 		}
@@ -136,3 +129,23 @@ func (r *snapshotRepository) scanSnapshots(rows *sql.Rows) ([]*models.NetworkSna
 
 	return snapshots, nil
 }
+
+// scanSnapshot scans one snapshot row and decodes its snapshot data
+func scanSnapshot(row interface{ Scan(dest ...any) error }) (*models.NetworkSnapshot, error) {
+	snapshot := &models.NetworkSnapshot{}
+	var data []byte
+	err := row.Scan(
+		&snapshot.ID, &snapshot.Network, &snapshot.Timestamp, &snapshot.TotalNodes, &snapshot.ReachableNodes,
+		&snapshot.CountriesCount, &snapshot.GRPCNodes, &snapshot.JSONRPCNodes,
+		&snapshot.BootstrapNodes, &data, &snapshot.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.SnapshotData = &models.SnapshotData{}
+	if err := json.Unmarshal(data, snapshot.SnapshotData); err != nil {
+		return nil, fmt.Errorf("decode snapshot data: %w", err)
+	}
+	return snapshot, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/lib/pq"
 )
 
 // StatusRepository defines the interface for daily status data access
@@ -15,7 +16,9 @@ type StatusRepository interface {
 	CreateStatus(ctx context.Context, status *models.DailyStatus) error
 	GetStatusByNodeAndDate(ctx context.Context, nodeID int, date time.Time) (*models.DailyStatus, error)
 	GetRecentStatusesByNode(ctx context.Context, nodeID int, days int) ([]models.StatusItem, error)
+	GetRecentStatusesByNodes(ctx context.Context, nodeIDs []int, days int) (map[int][]models.StatusItem, error)
 	HasStatusForDate(ctx context.Context, nodeID int, date time.Time) (bool, error)
+	GetStatusesByNodeAndDateRange(ctx context.Context, nodeID int, startDate, endDate time.Time) ([]*models.DailyStatus, error)
 
 	// Batch operations
 	GetStatusesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.DailyStatus, error)
@@ -33,21 +36,23 @@ func NewStatusRepository(db *sql.DB) StatusRepository {
 
 func (r *statusRepository) CreateStatus(ctx context.Context, status *models.DailyStatus) error {
 	query := `
-		INSERT INTO daily_status (node_id, date, color, attempts, success, error_msg, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		INSERT INTO daily_status (node_id, date, color, attempts, success, response_time_ms, error_msg, error_class, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
 		ON CONFLICT (node_id, date) 
 		DO UPDATE SET 
 			color = EXCLUDED.color,
 			attempts = EXCLUDED.attempts,
 			success = EXCLUDED.success,
+			response_time_ms = EXCLUDED.response_time_ms,
 			error_msg = EXCLUDED.error_msg,
+			error_class = EXCLUDED.error_class,
 			created_at = NOW()
 		RETURNING id, created_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
 		status.NodeID, status.Date, status.Color,
-		status.Attempts, status.Success, status.ErrorMsg,
+		status.Attempts, status.Success, status.ResponseTimeMs, status.ErrorMsg, status.ErrorClass,
 	).Scan(&status.ID, &status.CreatedAt)
 
 	if err != nil {
@@ -59,7 +64,7 @@ func (r *statusRepository) CreateStatus(ctx context.Context, status *models.Dail
 
 func (r *statusRepository) GetStatusByNodeAndDate(ctx context.Context, nodeID int, date time.Time) (*models.DailyStatus, error) {
 	query := `
-		SELECT id, node_id, date, color, attempts, success, error_msg, created_at
+		SELECT id, node_id, date, color, attempts, success, COALESCE(response_time_ms, 0), error_msg, COALESCE(error_class, ''), created_at
 		FROM daily_status
 		WHERE node_id = $1 AND date = $2
 	`
@@ -67,7 +72,7 @@ func (r *statusRepository) GetStatusByNodeAndDate(ctx context.Context, nodeID in
 	status := &models.DailyStatus{}
 	err := r.db.QueryRowContext(ctx, query, nodeID, date).Scan(
 		&status.ID, &status.NodeID, &status.Date, &status.Color,
-		&status.Attempts, &status.Success, &status.ErrorMsg, &status.CreatedAt,
+		&status.Attempts, &status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.ErrorClass, &status.CreatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -116,6 +121,48 @@ func (r *statusRepository) GetRecentStatusesByNode(ctx context.Context, nodeID i
 	return statuses, nil
 }
 
+// GetRecentStatusesByNodes loads the recent statuses of several nodes in one
+// query, keyed by node ID
+func (r *statusRepository) GetRecentStatusesByNodes(ctx context.Context, nodeIDs []int, days int) (map[int][]models.StatusItem, error) {
+	statuses := make(map[int][]models.StatusItem, len(nodeIDs))
+	if len(nodeIDs) == 0 {
+		return statuses, nil
+	}
+
+	query := `
+		SELECT node_id, color, date
+		FROM daily_status
+		WHERE node_id = ANY($1) AND date >= CURRENT_DATE - INTERVAL '1 day' * $2
+		ORDER BY node_id, date DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(nodeIDs), days)
+	if err != nil {
+		return nil, fmt.Errorf("query recent statuses by nodes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nodeID, color int
+		var date time.Time
+
+		if err := rows.Scan(&nodeID, &color, &date); err != nil {
+			return nil, fmt.Errorf("scan status: %w", err)
+		}
+
+		statuses[nodeID] = append(statuses[nodeID], models.StatusItem{
+			Color: color,
+			Date:  date.Format("2006-01-02"),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return statuses, nil
+}
+
 func (r *statusRepository) HasStatusForDate(ctx context.Context, nodeID int, date time.Time) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM daily_status WHERE node_id = $1 AND date = $2)`
 
@@ -130,7 +177,7 @@ func (r *statusRepository) HasStatusForDate(ctx context.Context, nodeID int, dat
 
 func (r *statusRepository) GetStatusesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.DailyStatus, error) {
 	query := `
-		SELECT id, node_id, date, color, attempts, success, error_msg, created_at
+		SELECT id, node_id, date, color, attempts, success, COALESCE(response_time_ms, 0), error_msg, COALESCE(error_class, ''), created_at
 		FROM daily_status
 		WHERE date >= $1 AND date <= $2
 		ORDER BY date DESC, node_id
@@ -147,7 +194,7 @@ func (r *statusRepository) GetStatusesByDateRange(ctx context.Context, startDate
 		status := &models.DailyStatus{}
 		err := rows.Scan(
 			&status.ID, &status.NodeID, &status.Date, &status.Color,
-			&status.Attempts, &status.Success, &status.ErrorMsg, &status.CreatedAt,
+			&status.Attempts, &status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.ErrorClass, &status.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan status: %w", err)
@@ -177,3 +224,37 @@ func (r *statusRepository) DeleteOldStatuses(ctx context.Context, beforeDate tim
 
 	return nil
 }
+
+func (r *statusRepository) GetStatusesByNodeAndDateRange(ctx context.Context, nodeID int, startDate, endDate time.Time) ([]*models.DailyStatus, error) {
+	query := `
+		SELECT id, node_id, date, color, attempts, success, COALESCE(response_time_ms, 0), error_msg, COALESCE(error_class, ''), created_at
+		FROM daily_status
+		WHERE node_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query node statuses by date range: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []*models.DailyStatus
+	for rows.Next() {
+		status := &models.DailyStatus{}
+		err := rows.Scan(
+			&status.ID, &status.NodeID, &status.Date, &status.Color,
+			&status.Attempts, &status.Success, &status.ResponseTimeMs, &status.ErrorMsg, &status.ErrorClass, &status.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return statuses, nil
+}
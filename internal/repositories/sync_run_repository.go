@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// SyncRunRepository defines the interface for node list sync reports
+type SyncRunRepository interface {
+	CreateRun(ctx context.Context, run *models.SyncRun) error
+	ListRuns(ctx context.Context, nodeType string, limit int) ([]*models.SyncRun, error)
+}
+
+type syncRunRepository struct {
+	db *sql.DB
+}
+
+// NewSyncRunRepository creates a new sync run repository
+func NewSyncRunRepository(db *sql.DB) SyncRunRepository {
+	return &syncRunRepository{db: db}
+}
+
+// CreateRun stores a sync report and sets its ID
+func (r *syncRunRepository) CreateRun(ctx context.Context, run *models.SyncRun) error {
+	added, err := marshalChanges(run.Added)
+	if err != nil {
+		return err
+	}
+	updated, err := marshalChanges(run.Updated)
+	if err != nil {
+		return err
+	}
+	deactivated, err := marshalChanges(run.Deactivated)
+	if err != nil {
+		return err
+	}
+	reactivated, err := marshalChanges(run.Reactivated)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO sync_runs (node_type, source, added, updated, deactivated, reactivated, errors, error, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	err = r.db.QueryRowContext(ctx, query,
+		run.NodeType, run.Source, added, updated, deactivated, reactivated,
+		run.Errors, run.Error, run.StartedAt, run.FinishedAt,
+	).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("insert sync run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns the latest sync reports, optionally of one node type
+func (r *syncRunRepository) ListRuns(ctx context.Context, nodeType string, limit int) ([]*models.SyncRun, error) {
+	query := `
+		SELECT id, node_type, source, added, updated, deactivated, reactivated, errors, error, started_at, finished_at
+		FROM sync_runs
+		WHERE $1 = '' OR node_type = $1
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*models.SyncRun
+	for rows.Next() {
+		run := &models.SyncRun{}
+		var added, updated, deactivated, reactivated []byte
+		if err := rows.Scan(
+			&run.ID, &run.NodeType, &run.Source, &added, &updated, &deactivated, &reactivated,
+			&run.Errors, &run.Error, &run.StartedAt, &run.FinishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan sync run: %w", err)
+		}
+		for _, column := range []struct {
+			raw  []byte
+			dest *[]models.SyncChange
+		}{{added, &run.Added}, {updated, &run.Updated}, {deactivated, &run.Deactivated}, {reactivated, &run.Reactivated}} {
+			if err := json.Unmarshal(column.raw, column.dest); err != nil {
+				return nil, fmt.Errorf("unmarshal sync changes: %w", err)
+			}
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return runs, nil
+}
+
+// marshalChanges encodes the changes of a run, an empty list for none
+func marshalChanges(changes []models.SyncChange) ([]byte, error) {
+	if changes == nil {
+		changes = []models.SyncChange{}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal sync changes: %w", err)
+	}
+	return data, nil
+}
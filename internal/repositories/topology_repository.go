@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// TopologyRepository defines the interface for peer connection data access
+type TopologyRepository interface {
+	RecordEdges(ctx context.Context, sourceID int, targetIDs []int, seenAt time.Time) error
+	GetEdges(ctx context.Context, network string, since time.Time) ([]*models.TopologyEdge, error)
+	GetNodes(ctx context.Context, network string, since time.Time) ([]*models.TopologyNode, error)
+	ReplaceCentrality(ctx context.Context, network string, betweenness map[int]float64, computedAt time.Time) error
+	GetCentrality(ctx context.Context, network string) (map[int]float64, time.Time, error)
+}
+
+type topologyRepository struct {
+	db *sql.DB
+}
+
+// NewTopologyRepository creates a new topology repository
+func NewTopologyRepository(db *sql.DB) TopologyRepository {
+	return &topologyRepository{db: db}
+}
+
+// RecordEdges stores that the source peer was connected to each target.
+// Known edges only move last_seen forward.
+func (r *topologyRepository) RecordEdges(ctx context.Context, sourceID int, targetIDs []int, seenAt time.Time) error {
+	query := `
+		INSERT INTO peer_edges (source_id, target_id, first_seen, last_seen)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (source_id, target_id)
+		DO UPDATE SET last_seen = GREATEST(peer_edges.last_seen, EXCLUDED.last_seen)
+	`
+
+	for _, targetID := range targetIDs {
+		if targetID == sourceID {
+			continue
+		}
+		if _, err := r.db.ExecContext(ctx, query, sourceID, targetID, seenAt); err != nil {
+			return fmt.Errorf("record peer edge: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetEdges returns the edges of a network seen since the given time
+func (r *topologyRepository) GetEdges(ctx context.Context, network string, since time.Time) ([]*models.TopologyEdge, error) {
+	query := `
+		SELECT e.source_id, e.target_id, e.first_seen, e.last_seen
+		FROM peer_edges e
+		JOIN reachable_peers p ON p.id = e.source_id
+		WHERE e.last_seen >= $2 AND p.network = $1
+		ORDER BY e.source_id, e.target_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, network, since)
+	if err != nil {
+		return nil, fmt.Errorf("query peer edges: %w", err)
+	}
+	defer rows.Close()
+
+	var edges []*models.TopologyEdge
+	for rows.Next() {
+		e := &models.TopologyEdge{}
+		if err := rows.Scan(&e.Source, &e.Target, &e.FirstSeen, &e.LastSeen); err != nil {
+			return nil, fmt.Errorf("scan peer edge: %w", err)
+		}
+		edges = append(edges, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return edges, nil
+}
+
+// GetNodes returns the peers of a network on either end of an edge seen
+// since the given time
+func (r *topologyRepository) GetNodes(ctx context.Context, network string, since time.Time) ([]*models.TopologyNode, error) {
+	query := `
+		SELECT p.id, p.peer_id, p.address, COALESCE(p.country_code, ''), COALESCE(p.asn, ''),
+			   COALESCE(p.organization, ''), COALESCE(p.user_agent, '')
+		FROM reachable_peers p
+		WHERE p.network = $1 AND p.id IN (
+			SELECT source_id FROM peer_edges WHERE last_seen >= $2
+			UNION
+			SELECT target_id FROM peer_edges WHERE last_seen >= $2
+		)
+		ORDER BY p.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, network, since)
+	if err != nil {
+		return nil, fmt.Errorf("query topology nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*models.TopologyNode
+	for rows.Next() {
+		n := &models.TopologyNode{}
+		err := rows.Scan(&n.ID, &n.PeerID, &n.Address, &n.CountryCode, &n.ASN, &n.Organization, &n.UserAgent)
+		if err != nil {
+			return nil, fmt.Errorf("scan topology node: %w", err)
+		}
+		nodes = append(nodes, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// ReplaceCentrality stores the betweenness of the bootstrap nodes of a
+// network, keyed by bootstrap node ID, in place of the previous values
+func (r *topologyRepository) ReplaceCentrality(ctx context.Context, network string, betweenness map[int]float64, computedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bootstrap_centrality WHERE network = $1`, network); err != nil {
+		return fmt.Errorf("delete bootstrap centrality: %w", err)
+	}
+
+	query := `
+		INSERT INTO bootstrap_centrality (network, bootstrap_id, betweenness, computed_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	for bootstrapID, value := range betweenness {
+		if _, err := tx.ExecContext(ctx, query, network, bootstrapID, value, computedAt); err != nil {
+			return fmt.Errorf("insert bootstrap centrality: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit bootstrap centrality: %w", err)
+	}
+	return nil
+}
+
+// GetCentrality returns the stored betweenness of the bootstrap nodes of a
+// network, keyed by bootstrap node ID, and when it was computed. The time
+// is zero when nothing is stored.
+func (r *topologyRepository) GetCentrality(ctx context.Context, network string) (map[int]float64, time.Time, error) {
+	query := `
+		SELECT bootstrap_id, betweenness, computed_at
+		FROM bootstrap_centrality
+		WHERE network = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, network)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query bootstrap centrality: %w", err)
+	}
+	defer rows.Close()
+
+	betweenness := make(map[int]float64)
+	var computedAt time.Time
+	for rows.Next() {
+		var bootstrapID int
+		var value float64
+		if err := rows.Scan(&bootstrapID, &value, &computedAt); err != nil {
+			return nil, time.Time{}, fmt.Errorf("scan bootstrap centrality: %w", err)
+		}
+		betweenness[bootstrapID] = value
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return betweenness, computedAt, nil
+}
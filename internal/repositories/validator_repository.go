@@ -0,0 +1,387 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// ValidatorRepository defines the interface for validator, committee and
+// block proposer data access
+type ValidatorRepository interface {
+	UpsertValidators(ctx context.Context, network string, validators []*models.Validator) error
+	RecordCommittee(ctx context.Context, network string, committee *models.Committee) error
+	GetLatestCommittee(ctx context.Context, network string) (*models.Committee, error)
+	ListValidators(ctx context.Context, network string, committeeOnly bool, limit, offset int) ([]*models.Validator, int, error)
+	GetValidator(ctx context.Context, network, address string) (*models.Validator, error)
+	GetValidatorHistory(ctx context.Context, validatorID int, since time.Time) ([]*models.ValidatorDay, error)
+	GetLastBlockHeight(ctx context.Context, network string) (uint32, error)
+	RecordBlocks(ctx context.Context, network string, blocks []*models.ValidatorBlock) error
+	GetCertificateCounts(ctx context.Context, network string, number int32, address string, since time.Time) (certificates, missed, proposed int, err error)
+	RecordValidatorPeers(ctx context.Context, network string, peers map[string]string, seenAt time.Time) error
+}
+
+type validatorRepository struct {
+	db *sql.DB
+}
+
+// NewValidatorRepository creates a new validator repository
+func NewValidatorRepository(db *sql.DB) ValidatorRepository {
+	return &validatorRepository{db: db}
+}
+
+const validatorColumns = `
+	v.id, v.address, v.number, v.public_key, v.stake, v.availability_score,
+	v.last_bonding_height, v.last_sortition_height, v.unbonding_height,
+	v.protocol_version, v.in_committee, v.first_seen, v.last_seen,
+	COALESCE(vp.peer_id, ''), rp.id, COALESCE(rp.country_code, ''), COALESCE(rp.asn, ''),
+	COALESCE(rp.is_reachable, false), rp.last_seen
+`
+
+const validatorJoins = `
+	FROM validators v
+	LEFT JOIN validator_peers vp ON vp.network = v.network AND vp.validator_address = v.address
+	LEFT JOIN reachable_peers rp ON rp.network = v.network AND rp.peer_id = vp.peer_id
+`
+
+// UpsertValidators stores the latest state of the validators of a network
+// and folds it into today's history row. Committee membership of the day is
+// kept once seen.
+func (r *validatorRepository) UpsertValidators(ctx context.Context, network string, validators []*models.Validator) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	upsert := `
+		INSERT INTO validators (
+			address, number, public_key, stake, availability_score, last_bonding_height,
+			last_sortition_height, unbonding_height, protocol_version, in_committee, first_seen, last_seen, network
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11, $12)
+		ON CONFLICT (network, address) DO UPDATE SET
+			number = EXCLUDED.number,
+			public_key = EXCLUDED.public_key,
+			stake = EXCLUDED.stake,
+			availability_score = EXCLUDED.availability_score,
+			last_bonding_height = EXCLUDED.last_bonding_height,
+			last_sortition_height = EXCLUDED.last_sortition_height,
+			unbonding_height = EXCLUDED.unbonding_height,
+			protocol_version = EXCLUDED.protocol_version,
+			in_committee = EXCLUDED.in_committee,
+			last_seen = EXCLUDED.last_seen
+		RETURNING id
+	`
+	history := `
+		INSERT INTO validator_history (validator_id, date, stake, availability_score, in_committee)
+		VALUES ($1, $2::date, $3, $4, $5)
+		ON CONFLICT (validator_id, date) DO UPDATE SET
+			stake = EXCLUDED.stake,
+			availability_score = EXCLUDED.availability_score,
+			in_committee = validator_history.in_committee OR EXCLUDED.in_committee
+	`
+
+	for _, v := range validators {
+		if err := tx.QueryRowContext(ctx, upsert,
+			v.Address, v.Number, v.PublicKey, v.Stake, v.AvailabilityScore, v.LastBondingHeight,
+			v.LastSortitionHeight, v.UnbondingHeight, v.ProtocolVersion, v.InCommittee, v.LastSeen, network,
+		).Scan(&v.ID); err != nil {
+			return fmt.Errorf("upsert validator: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, history,
+			v.ID, v.LastSeen.UTC().Format("2006-01-02"), v.Stake, v.AvailabilityScore, v.InCommittee,
+		); err != nil {
+			return fmt.Errorf("record validator history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit validators: %w", err)
+	}
+	return nil
+}
+
+// RecordCommittee stores a committee snapshot of a network and moves the
+// committee flag to its members
+func (r *validatorRepository) RecordCommittee(ctx context.Context, network string, committee *models.Committee) error {
+	numbers := make([]int64, 0, len(committee.Members))
+	for _, m := range committee.Members {
+		numbers = append(numbers, int64(m.Number))
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO committee_snapshots (height, total_validators, active_validators, total_power, committee_power, members, recorded_at, network)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		committee.Height, committee.TotalValidators, committee.ActiveValidators,
+		committee.TotalPower, committee.CommitteePower, pq.Array(numbers), committee.RecordedAt, network,
+	); err != nil {
+		return fmt.Errorf("insert committee snapshot: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE validators SET in_committee = (number = ANY($1)) WHERE network = $2`, pq.Array(numbers), network); err != nil {
+		return fmt.Errorf("update committee members: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit committee: %w", err)
+	}
+	return nil
+}
+
+// GetLatestCommittee returns the latest committee snapshot of a network
+// with its members ordered by stake
+func (r *validatorRepository) GetLatestCommittee(ctx context.Context, network string) (*models.Committee, error) {
+	query := `
+		SELECT height, total_validators, active_validators, total_power, committee_power, members, recorded_at
+		FROM committee_snapshots
+		WHERE network = $1
+		ORDER BY recorded_at DESC
+		LIMIT 1
+	`
+
+	committee := &models.Committee{}
+	var members pq.Int64Array
+	err := r.db.QueryRowContext(ctx, query, network).Scan(
+		&committee.Height, &committee.TotalValidators, &committee.ActiveValidators,
+		&committee.TotalPower, &committee.CommitteePower, &members, &committee.RecordedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query latest committee: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+validatorColumns+validatorJoins+`
+		WHERE v.network = $1 AND v.number = ANY($2)
+		ORDER BY v.stake DESC, v.number
+	`, network, members)
+	if err != nil {
+		return nil, fmt.Errorf("query committee members: %w", err)
+	}
+	defer rows.Close()
+
+	committee.Members = []*models.Validator{}
+	for rows.Next() {
+		v, err := scanValidator(rows)
+		if err != nil {
+			return nil, err
+		}
+		committee.Members = append(committee.Members, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return committee, nil
+}
+
+// ListValidators returns a page of the validators of a network ordered by
+// stake and the total number of matching validators
+func (r *validatorRepository) ListValidators(ctx context.Context, network string, committeeOnly bool, limit, offset int) ([]*models.Validator, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM validators WHERE network = $1 AND (in_committee OR NOT $2)`, network, committeeOnly,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count validators: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+validatorColumns+validatorJoins+`
+		WHERE v.network = $1 AND (v.in_committee OR NOT $2)
+		ORDER BY v.stake DESC, v.number
+		LIMIT $3 OFFSET $4
+	`, network, committeeOnly, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query validators: %w", err)
+	}
+	defer rows.Close()
+
+	var validators []*models.Validator
+	for rows.Next() {
+		v, err := scanValidator(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		validators = append(validators, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return validators, total, nil
+}
+
+// GetValidator returns a validator of a network by address
+func (r *validatorRepository) GetValidator(ctx context.Context, network, address string) (*models.Validator, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+validatorColumns+validatorJoins+` WHERE v.network = $1 AND v.address = $2`, network, address)
+
+	v, err := scanValidator(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+// GetValidatorHistory returns the daily history of a validator since a time
+func (r *validatorRepository) GetValidatorHistory(ctx context.Context, validatorID int, since time.Time) ([]*models.ValidatorDay, error) {
+	query := `
+		SELECT date, stake, availability_score, in_committee
+		FROM validator_history
+		WHERE validator_id = $1 AND date >= $2::date
+		ORDER BY date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, validatorID, since.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("query validator history: %w", err)
+	}
+	defer rows.Close()
+
+	var days []*models.ValidatorDay
+	for rows.Next() {
+		d := &models.ValidatorDay{}
+		var date time.Time
+		if err := rows.Scan(&date, &d.Stake, &d.AvailabilityScore, &d.InCommittee); err != nil {
+			return nil, fmt.Errorf("scan validator history: %w", err)
+		}
+		d.Date = date.Format("2006-01-02")
+		days = append(days, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetLastBlockHeight returns the height of the latest synced block of a
+// network, or 0 when none has been synced
+func (r *validatorRepository) GetLastBlockHeight(ctx context.Context, network string) (uint32, error) {
+	var height uint32
+	if err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(height), 0) FROM validator_blocks WHERE network = $1`, network).Scan(&height); err != nil {
+		return 0, fmt.Errorf("query last block height: %w", err)
+	}
+	return height, nil
+}
+
+// RecordBlocks stores synced blocks of a network. Blocks synced before are
+// left as is.
+func (r *validatorRepository) RecordBlocks(ctx context.Context, network string, blocks []*models.ValidatorBlock) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO validator_blocks (height, proposer_address, block_time, cert_round, cert_committers, cert_absentees, network)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (network, height) DO NOTHING
+	`
+	for _, b := range blocks {
+		if _, err := tx.ExecContext(ctx, query,
+			b.Height, b.ProposerAddress, b.BlockTime, b.CertRound,
+			pq.Array(b.CertCommitters), pq.Array(b.CertAbsentees), network,
+		); err != nil {
+			return fmt.Errorf("insert validator block: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit validator blocks: %w", err)
+	}
+	return nil
+}
+
+// GetCertificateCounts counts the certificates of blocks of a network since
+// a time that a validator was a committer in or absent from, and the blocks
+// it proposed
+func (r *validatorRepository) GetCertificateCounts(ctx context.Context, network string, number int32, address string, since time.Time) (int, int, int, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE $1 = ANY(cert_committers)),
+			COUNT(*) FILTER (WHERE $1 = ANY(cert_absentees)),
+			COUNT(*) FILTER (WHERE proposer_address = $2)
+		FROM validator_blocks
+		WHERE block_time >= $3 AND network = $4
+	`
+
+	var certificates, missed, proposed int
+	if err := r.db.QueryRowContext(ctx, query, number, address, since, network).Scan(&certificates, &missed, &proposed); err != nil {
+		return 0, 0, 0, fmt.Errorf("query certificate counts: %w", err)
+	}
+	return certificates, missed, proposed, nil
+}
+
+// RecordValidatorPeers stores the peer each validator address of a network
+// was announced by, keyed by validator address
+func (r *validatorRepository) RecordValidatorPeers(ctx context.Context, network string, peers map[string]string, seenAt time.Time) error {
+	query := `
+		INSERT INTO validator_peers (validator_address, peer_id, last_seen, network)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (network, validator_address) DO UPDATE SET
+			peer_id = EXCLUDED.peer_id,
+			last_seen = EXCLUDED.last_seen
+	`
+
+	for address, peerID := range peers {
+		if _, err := r.db.ExecContext(ctx, query, address, peerID, seenAt, network); err != nil {
+			return fmt.Errorf("record validator peer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanValidator scans a row of validatorColumns
+func scanValidator(row interface{ Scan(dest ...any) error }) (*models.Validator, error) {
+	v := &models.Validator{}
+	var (
+		peerID       sql.NullInt64
+		countryCode  string
+		asn          string
+		reachable    bool
+		peerLastSeen sql.NullTime
+	)
+	err := row.Scan(
+		&v.ID, &v.Address, &v.Number, &v.PublicKey, &v.Stake, &v.AvailabilityScore,
+		&v.LastBondingHeight, &v.LastSortitionHeight, &v.UnbondingHeight,
+		&v.ProtocolVersion, &v.InCommittee, &v.FirstSeen, &v.LastSeen,
+		&v.PeerID, &peerID, &countryCode, &asn, &reachable, &peerLastSeen,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan validator: %w", err)
+	}
+
+	if peerID.Valid {
+		v.Peer = &models.ValidatorPeer{
+			ID:          int(peerID.Int64),
+			PeerID:      v.PeerID,
+			CountryCode: countryCode,
+			ASN:         asn,
+			Reachable:   reachable,
+			LastSeen:    peerLastSeen.Time,
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// VersionRepository defines the interface for node software version data access
+type VersionRepository interface {
+	RecordVersion(ctx context.Context, v *models.NodeVersion) error
+	GetHistory(ctx context.Context, nodeType string, nodeID int) ([]*models.NodeVersion, error)
+	GetCurrentVersions(ctx context.Context, network string, since time.Time) ([]*models.NodeVersion, error)
+}
+
+type versionRepository struct {
+	db *sql.DB
+}
+
+// NewVersionRepository creates a new version repository
+func NewVersionRepository(db *sql.DB) VersionRepository {
+	return &versionRepository{db: db}
+}
+
+// RecordVersion stores the agent a node was seen with. Seeing the same
+// agent again only moves last_seen forward.
+func (r *versionRepository) RecordVersion(ctx context.Context, v *models.NodeVersion) error {
+	query := `
+		INSERT INTO node_versions (node_type, node_id, agent, version, first_seen, last_seen)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $5)
+		ON CONFLICT (node_type, node_id, agent)
+		DO UPDATE SET last_seen = GREATEST(node_versions.last_seen, EXCLUDED.last_seen)
+		RETURNING id, first_seen, last_seen
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		v.NodeType, v.NodeID, v.Agent, v.Version, v.LastSeen,
+	).Scan(&v.ID, &v.FirstSeen, &v.LastSeen)
+	if err != nil {
+		return fmt.Errorf("record node version: %w", err)
+	}
+
+	return nil
+}
+
+func (r *versionRepository) GetHistory(ctx context.Context, nodeType string, nodeID int) ([]*models.NodeVersion, error) {
+	query := `
+		SELECT id, node_type, node_id, '', '', agent, COALESCE(version, ''), first_seen, last_seen
+		FROM node_versions
+		WHERE node_type = $1 AND node_id = $2
+		ORDER BY first_seen DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nodeType, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("query version history: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanVersions(rows)
+}
+
+// GetCurrentVersions returns the latest agent of every node of a network
+// seen since the given time, with the name and address of the node it
+// belongs to
+func (r *versionRepository) GetCurrentVersions(ctx context.Context, network string, since time.Time) ([]*models.NodeVersion, error) {
+	query := `
+		SELECT DISTINCT ON (v.node_type, v.node_id)
+			v.id, v.node_type, v.node_id,
+			COALESCE(b.name, g.name, j.name, ''),
+			COALESCE(b.address, g.address, j.address, p.address, ''),
+			v.agent, COALESCE(v.version, ''), v.first_seen, v.last_seen
+		FROM node_versions v
+		LEFT JOIN bootstrap_nodes b ON v.node_type = 'bootstrap' AND b.id = v.node_id
+		LEFT JOIN grpc_servers g ON v.node_type = 'grpc' AND g.id = v.node_id
+		LEFT JOIN jsonrpc_servers j ON v.node_type = 'jsonrpc' AND j.id = v.node_id
+		LEFT JOIN reachable_peers p ON v.node_type = 'peer' AND p.id = v.node_id
+		WHERE v.last_seen >= $1 AND COALESCE(b.network, g.network, j.network, p.network) = $2
+		ORDER BY v.node_type, v.node_id, v.last_seen DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since, network)
+	if err != nil {
+		return nil, fmt.Errorf("query current versions: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanVersions(rows)
+}
+
+// Helper function to scan multiple versions
+func (r *versionRepository) scanVersions(rows *sql.Rows) ([]*models.NodeVersion, error) {
+	var versions []*models.NodeVersion
+
+	for rows.Next() {
+		v := &models.NodeVersion{}
+		err := rows.Scan(
+			&v.ID, &v.NodeType, &v.NodeID, &v.Name, &v.Address,
+			&v.Agent, &v.Version, &v.FirstSeen, &v.LastSeen,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan node version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return versions, nil
+}
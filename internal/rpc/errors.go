@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Implementation-defined server error codes
+const (
+	CodeServerError        = -32000
+	CodeNotFound           = -32001
+	CodeUnauthorized       = -32002
+	CodeForbidden          = -32003
+	CodeConflict           = -32004
+	CodeRateLimited        = -32005
+	CodeServiceUnavailable = -32006
+)
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// NewError creates a JSON-RPC error
+func NewError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorData carries the application error details of a failed call
+type ErrorData struct {
+	Code     models.ErrorCode       `json:"code"`
+	Details  string                 `json:"details,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ToError maps a method error to a JSON-RPC error. Application errors get
+// a code matching their class; anything else is reported as an internal
+// error without its details, which the registry logs instead.
+func ToError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) {
+		return NewError(CodeInternalError, "Internal error")
+	}
+
+	rpcErr = &Error{
+		Code:    appErrorCode(appErr.Code),
+		Message: appErr.Message,
+		Data: &ErrorData{
+			Code:     appErr.Code,
+			Details:  appErr.Details,
+			Metadata: appErr.Metadata,
+		},
+	}
+	return rpcErr
+}
+
+func appErrorCode(code models.ErrorCode) int {
+	switch code {
+	case models.ErrCodeBadRequest, models.ErrCodeValidation, models.ErrCodeNodeInvalidAddress:
+		return CodeInvalidParams
+	case models.ErrCodeNotFound:
+		return CodeNotFound
+	case models.ErrCodeUnauthorized:
+		return CodeUnauthorized
+	case models.ErrCodeForbidden:
+		return CodeForbidden
+	case models.ErrCodeConflict:
+		return CodeConflict
+	case models.ErrCodeRateLimitExceeded:
+		return CodeRateLimited
+	case models.ErrCodeServiceUnavailable:
+		return CodeServiceUnavailable
+	case models.ErrCodeInternal, models.ErrCodeDatabaseConnection,
+		models.ErrCodeDatabaseQuery, models.ErrCodeDatabaseTransaction:
+		return CodeInternalError
+	}
+	return CodeServerError
+}
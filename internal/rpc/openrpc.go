@@ -0,0 +1,256 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OpenRPCVersion is the OpenRPC specification version rpc.discover follows
+const OpenRPCVersion = "1.2.6"
+
+// schemaRefPrefix is where named types are referenced from in a document
+const schemaRefPrefix = "#/components/schemas/"
+
+// OpenRPCDocument is the service description returned by rpc.discover
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Servers    []OpenRPCServer   `json:"servers,omitempty"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo describes the API as a whole
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCServer is an endpoint the methods are served from
+type OpenRPCServer struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// OpenRPCMethod describes a single method
+type OpenRPCMethod struct {
+	Name           string              `json:"name"`
+	Summary        string              `json:"summary,omitempty"`
+	ParamStructure string              `json:"paramStructure"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         ContentDescriptor   `json:"result"`
+}
+
+// OpenRPCComponents holds the schemas of named Go types
+type OpenRPCComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// ContentDescriptor describes a method param or result
+type ContentDescriptor struct {
+	Name     string  `json:"name"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema generated from Go types
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// IsEmpty reports whether the schema accepts anything, which means the Go
+// type behind it could not be described
+func (s *Schema) IsEmpty() bool {
+	return s == nil || (s.Ref == "" && s.Type == "")
+}
+
+// discover implements rpc.discover
+func (r *Registry) discover(ctx context.Context, params struct{}) (*OpenRPCDocument, error) {
+	return r.OpenRPC(), nil
+}
+
+// OpenRPC builds an OpenRPC document from the registered methods. Named
+// struct types are emitted once under components and referenced by $ref.
+func (r *Registry) OpenRPC() *OpenRPCDocument {
+	gen := newSchemaGenerator()
+	doc := &OpenRPCDocument{
+		OpenRPC: OpenRPCVersion,
+		Info:    OpenRPCInfo{Title: r.title, Version: r.version},
+		Methods: []OpenRPCMethod{},
+	}
+
+	for _, method := range r.Methods() {
+		doc.Methods = append(doc.Methods, OpenRPCMethod{
+			Name:           method.Name,
+			Summary:        method.Summary,
+			ParamStructure: "by-name",
+			Params:         gen.params(method.ParamsType),
+			Result:         ContentDescriptor{Name: "result", Schema: gen.schema(method.ResultType)},
+		})
+	}
+
+	doc.Components.Schemas = gen.components
+	return doc
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaGenerator derives JSON schemas from Go types, following the same
+// field naming rules as encoding/json
+type schemaGenerator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// params lists the top-level fields of a params struct. Fields tagged
+// rpc:"required" are marked as required params.
+func (g *schemaGenerator) params(t reflect.Type) []ContentDescriptor {
+	params := []ContentDescriptor{}
+	for _, field := range jsonFields(indirect(t)) {
+		params = append(params, ContentDescriptor{
+			Name:     field.name,
+			Required: field.required,
+			Schema:   g.schema(field.typ),
+		})
+	}
+	return params
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	t = indirect(t)
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return &Schema{Ref: schemaRefPrefix + g.component(t)}
+	}
+
+	return &Schema{}
+}
+
+// component registers a named struct type and returns its component name
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := g.components[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+
+	// Register before recursing so self-referencing types terminate
+	g.names[t] = name
+	g.components[name] = &Schema{}
+	*g.components[name] = *g.object(t)
+	return name
+}
+
+// object describes a struct inline. Fields without omitempty are always
+// present in encoded output and are listed as required.
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range jsonFields(t) {
+		schema.Properties[field.name] = g.schema(field.typ)
+		if !field.omitEmpty {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	return schema
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+	required  bool
+}
+
+// jsonFields lists the fields encoding/json would emit for a struct,
+// flattening embedded structs, sorted by name
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(indirect(field.Type))...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{
+			name:      name,
+			typ:       field.Type,
+			omitEmpty: strings.Contains(opts, "omitempty"),
+			required:  field.Tag.Get("rpc") == "required",
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+	return fields
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
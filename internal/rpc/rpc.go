@@ -0,0 +1,307 @@
+// Package rpc implements a JSON-RPC 2.0 dispatcher backed by a registry of
+// typed method handlers.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Version is the only JSON-RPC protocol version accepted
+const Version = "2.0"
+
+// Batch limits
+const (
+	MaxBatchSize        = 50
+	maxBatchConcurrency = 10
+)
+
+// Request is a single JSON-RPC request object. ID is kept raw so that a
+// missing id (a notification) can be told apart from an explicit null.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// IsNotification reports whether the request expects no response
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response is a single JSON-RPC response object
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Error   *Error          `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// MarshalJSON always includes result on success, even when it is null or
+// empty, and omits it on error as the specification requires
+func (r *Response) MarshalJSON() ([]byte, error) {
+	if r.Error != nil {
+		return json.Marshal(struct {
+			JSONRPC string          `json:"jsonrpc"`
+			Error   *Error          `json:"error"`
+			ID      json.RawMessage `json:"id"`
+		}{r.JSONRPC, r.Error, r.ID})
+	}
+	return json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Result  interface{}     `json:"result"`
+		ID      json.RawMessage `json:"id"`
+	}{r.JSONRPC, r.Result, r.ID})
+}
+
+// Validator is implemented by params that check their own fields. A
+// validation error is reported to the caller as invalid params.
+type Validator interface {
+	Validate() error
+}
+
+// Method is a registered JSON-RPC method
+type Method struct {
+	Name       string
+	Summary    string
+	ParamsType reflect.Type
+	ResultType reflect.Type
+	call       func(ctx context.Context, params json.RawMessage) (interface{}, error)
+}
+
+// Registry maps method names to typed handlers and dispatches requests to them
+type Registry struct {
+	title   string
+	version string
+	mu      sync.RWMutex
+	methods map[string]*Method
+	logger  *logrus.Logger
+}
+
+// NewRegistry creates a method registry. Title and version describe the API
+// in the rpc.discover document.
+func NewRegistry(title, version string, logger *logrus.Logger) *Registry {
+	r := &Registry{
+		title:   title,
+		version: version,
+		methods: make(map[string]*Method),
+		logger:  logger,
+	}
+	Register(r, "rpc.discover", "Returns the OpenRPC description of this API", r.discover)
+	return r
+}
+
+// Register adds a typed method handler to the registry. Params are decoded
+// from the request into P before fn is called. Registering a name twice
+// replaces the earlier handler.
+func Register[P, R any](r *Registry, name, summary string, fn func(ctx context.Context, params P) (R, error)) {
+	method := &Method{
+		Name:       name,
+		Summary:    summary,
+		ParamsType: reflect.TypeOf((*P)(nil)).Elem(),
+		ResultType: reflect.TypeOf((*R)(nil)).Elem(),
+		call: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var params P
+			if err := decodeParams(raw, &params); err != nil {
+				return nil, err
+			}
+			if v, ok := any(&params).(Validator); ok {
+				if err := v.Validate(); err != nil {
+					return nil, NewError(CodeInvalidParams, err.Error())
+				}
+			}
+			return fn(ctx, params)
+		},
+	}
+
+	r.mu.Lock()
+	r.methods[name] = method
+	r.mu.Unlock()
+}
+
+// Methods returns every registered method sorted by name
+func (r *Registry) Methods() []*Method {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methods := make([]*Method, 0, len(r.methods))
+	for _, method := range r.methods {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+func (r *Registry) lookup(name string) (*Method, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	method, ok := r.methods[name]
+	return method, ok
+}
+
+// Handle processes a raw request body, single or batch, and returns the
+// value to encode as the HTTP response. A nil result means every request
+// was a notification and nothing should be written back.
+func (r *Registry) Handle(ctx context.Context, body []byte) interface{} {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		return r.handleBatch(ctx, body)
+	}
+
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return errorResponse(nil, NewError(CodeParseError, "Parse error"))
+	}
+
+	response := r.call(ctx, &req)
+	if response == nil {
+		return nil
+	}
+	return response
+}
+
+func (r *Registry) handleBatch(ctx context.Context, body []byte) interface{} {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return errorResponse(nil, NewError(CodeParseError, "Parse error"))
+	}
+	if len(batch) == 0 {
+		return errorResponse(nil, NewError(CodeInvalidRequest, "Invalid Request: empty batch"))
+	}
+	if len(batch) > MaxBatchSize {
+		return errorResponse(nil, NewError(CodeInvalidRequest, fmt.Sprintf("Invalid Request: batch exceeds %d requests", MaxBatchSize)))
+	}
+
+	responses := make([]*Response, len(batch))
+	semaphore := make(chan struct{}, maxBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, raw := range batch {
+		wg.Add(1)
+		go func(i int, raw json.RawMessage) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			var req Request
+			if err := json.Unmarshal(raw, &req); err != nil {
+				responses[i] = errorResponse(nil, NewError(CodeInvalidRequest, "Invalid Request"))
+				return
+			}
+			responses[i] = r.call(ctx, &req)
+		}(i, raw)
+	}
+
+	wg.Wait()
+
+	// Notifications produce no entry in the batch response
+	results := make([]*Response, 0, len(responses))
+	for _, response := range responses {
+		if response != nil {
+			results = append(results, response)
+		}
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	return results
+}
+
+// call validates and executes a single request
+func (r *Registry) call(ctx context.Context, req *Request) *Response {
+	if req.JSONRPC != Version || req.Method == "" {
+		return errorResponse(req.ID, NewError(CodeInvalidRequest, "Invalid Request"))
+	}
+
+	method, ok := r.lookup(req.Method)
+	if !ok {
+		r.logger.WithField("method", req.Method).Warn("Method not found")
+		if req.IsNotification() {
+			return nil
+		}
+		return errorResponse(req.ID, NewError(CodeMethodNotFound, "Method not found"))
+	}
+
+	result, err := r.invoke(ctx, method, req.Params)
+	if err != nil {
+		rpcErr := ToError(err)
+		entry := r.logger.WithError(err).WithFields(logrus.Fields{
+			"method": req.Method,
+			"code":   rpcErr.Code,
+		})
+		if rpcErr.Code == CodeInternalError || rpcErr.Code == CodeServerError {
+			entry.Error("Failed to process JSON-RPC request")
+		} else {
+			entry.Debug("JSON-RPC request rejected")
+		}
+
+		if req.IsNotification() {
+			return nil
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+
+	if req.IsNotification() {
+		return nil
+	}
+	return &Response{JSONRPC: Version, Result: result, ID: req.ID}
+}
+
+// invoke runs a method and turns a handler panic into an internal error
+func (r *Registry) invoke(ctx context.Context, method *Method, params json.RawMessage) (result interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.logger.WithFields(logrus.Fields{
+				"method": method.Name,
+				"panic":  recovered,
+			}).Error("Panic in JSON-RPC method")
+			err = NewError(CodeInternalError, "Internal error")
+		}
+	}()
+	return method.call(ctx, params)
+}
+
+// decodeParams accepts by-name params as an object, or a single object
+// wrapped in a by-position array. Missing or null params decode to the
+// zero value.
+func decodeParams(raw json.RawMessage, out interface{}) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+
+	if raw[0] == '[' {
+		var positional []json.RawMessage
+		if err := json.Unmarshal(raw, &positional); err != nil {
+			return NewError(CodeInvalidParams, "Invalid params: "+err.Error())
+		}
+		switch len(positional) {
+		case 0:
+			return nil
+		case 1:
+			raw = positional[0]
+		default:
+			return NewError(CodeInvalidParams, "Invalid params: expected a single params object")
+		}
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return NewError(CodeInvalidParams, "Invalid params: "+err.Error())
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: Version, Error: err, ID: id}
+}
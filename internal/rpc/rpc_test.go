@@ -0,0 +1,215 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+type echoParams struct {
+	Name string `json:"name"`
+}
+
+func (p *echoParams) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return nil
+}
+
+func newTestRegistry() (*Registry, *int32) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var calls int32
+	r := NewRegistry("test", "0.0.0", logger)
+	Register(r, "echo", "Echo the name", func(ctx context.Context, p echoParams) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "hello " + p.Name, nil
+	})
+	Register(r, "missing", "Always not found", func(ctx context.Context, p struct{}) (*struct{}, error) {
+		return nil, fmt.Errorf("lookup: %w", models.NewNotFoundError("node not found"))
+	})
+	Register(r, "count", "Return zero", func(ctx context.Context, p struct{}) (int, error) {
+		return 0, nil
+	})
+	Register(r, "broken", "Always fails", func(ctx context.Context, p struct{}) (*struct{}, error) {
+		return nil, errors.New("boom")
+	})
+	return r, &calls
+}
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(out)
+}
+
+func TestRegistry_Handle(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		expect string
+	}{
+		{
+			name:   "Success",
+			body:   `{"jsonrpc":"2.0","method":"echo","params":{"name":"pactus"},"id":1}`,
+			expect: `{"jsonrpc":"2.0","result":"hello pactus","id":1}`,
+		},
+		{
+			name:   "Positional params",
+			body:   `{"jsonrpc":"2.0","method":"echo","params":[{"name":"pactus"}],"id":"a"}`,
+			expect: `{"jsonrpc":"2.0","result":"hello pactus","id":"a"}`,
+		},
+		{
+			name:   "Zero result",
+			body:   `{"jsonrpc":"2.0","method":"count","id":1}`,
+			expect: `{"jsonrpc":"2.0","result":0,"id":1}`,
+		},
+		{
+			name:   "Parse error",
+			body:   `{"jsonrpc":`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`,
+		},
+		{
+			name:   "Wrong version",
+			body:   `{"jsonrpc":"1.0","method":"echo","id":1}`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":1}`,
+		},
+		{
+			name:   "Method not found",
+			body:   `{"jsonrpc":"2.0","method":"nope","id":1}`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":1}`,
+		},
+		{
+			name:   "Params type mismatch",
+			body:   `{"jsonrpc":"2.0","method":"echo","params":{"name":5},"id":1}`,
+			expect: `-32602`,
+		},
+		{
+			name:   "Params validation",
+			body:   `{"jsonrpc":"2.0","method":"echo","params":{},"id":1}`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"name is required"},"id":1}`,
+		},
+		{
+			name:   "Application error",
+			body:   `{"jsonrpc":"2.0","method":"missing","id":null}`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32001,"message":"node not found","data":{"code":"NOT_FOUND"}},"id":null}`,
+		},
+		{
+			name:   "Plain error",
+			body:   `{"jsonrpc":"2.0","method":"broken","id":2}`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":2}`,
+		},
+		{
+			name:   "Empty batch",
+			body:   `[]`,
+			expect: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request: empty batch"},"id":null}`,
+		},
+	}
+
+	r, _ := newTestRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encode(t, r.Handle(context.Background(), []byte(tt.body)))
+			if !strings.Contains(got, tt.expect) {
+				t.Errorf("Expected %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestRegistry_Notifications(t *testing.T) {
+	r, calls := newTestRegistry()
+
+	if got := r.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"echo","params":{"name":"x"}}`)); got != nil {
+		t.Errorf("Expected no response for a notification, got %s", encode(t, got))
+	}
+	if atomic.LoadInt32(calls) != 1 {
+		t.Errorf("Expected the notification to be executed once, got %d", *calls)
+	}
+
+	batch := `[{"jsonrpc":"2.0","method":"echo","params":{"name":"x"}},{"jsonrpc":"2.0","method":"broken"}]`
+	if got := r.Handle(context.Background(), []byte(batch)); got != nil {
+		t.Errorf("Expected no response for a batch of notifications, got %s", encode(t, got))
+	}
+}
+
+func TestRegistry_Batch(t *testing.T) {
+	r, _ := newTestRegistry()
+
+	var requests []string
+	for i := 0; i < 20; i++ {
+		requests = append(requests, fmt.Sprintf(`{"jsonrpc":"2.0","method":"echo","params":{"name":"n%d"},"id":%d}`, i, i))
+	}
+	requests = append(requests, `{"jsonrpc":"2.0","method":"echo","params":{"name":"note"}}`, `1`)
+
+	got := r.Handle(context.Background(), []byte("["+strings.Join(requests, ",")+"]"))
+	responses, ok := got.([]*Response)
+	if !ok {
+		t.Fatalf("Expected batch response, got %T", got)
+	}
+	if len(responses) != 21 {
+		t.Fatalf("Expected 21 responses, got %d", len(responses))
+	}
+	for i := 0; i < 20; i++ {
+		if string(responses[i].ID) != fmt.Sprint(i) || responses[i].Result != fmt.Sprintf("hello n%d", i) {
+			t.Errorf("Response %d out of order: %s", i, encode(t, responses[i]))
+		}
+	}
+	if responses[20].Error == nil || responses[20].Error.Code != CodeInvalidRequest {
+		t.Errorf("Expected invalid request for a non-object entry, got %s", encode(t, responses[20]))
+	}
+
+	requests = make([]string, MaxBatchSize+1)
+	for i := range requests {
+		requests[i] = `{"jsonrpc":"2.0","method":"echo","params":{"name":"x"},"id":1}`
+	}
+	got = r.Handle(context.Background(), []byte("["+strings.Join(requests, ",")+"]"))
+	if response, ok := got.(*Response); !ok || response.Error == nil || response.Error.Code != CodeInvalidRequest {
+		t.Errorf("Expected oversized batch to be rejected, got %s", encode(t, got))
+	}
+}
+
+func TestRegistry_Discover(t *testing.T) {
+	r, _ := newTestRegistry()
+
+	got := encode(t, r.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`)))
+
+	var response struct {
+		Result OpenRPCDocument `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(got), &response); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	doc := response.Result
+	if doc.OpenRPC != OpenRPCVersion || doc.Info.Title != "test" {
+		t.Errorf("Unexpected document header: %+v", doc)
+	}
+
+	for _, method := range doc.Methods {
+		if method.Name != "echo" {
+			continue
+		}
+		if len(method.Params) != 1 || method.Params[0].Name != "name" || method.Params[0].Schema.Type != "string" {
+			t.Errorf("Unexpected echo params: %s", encode(t, method.Params))
+		}
+		if method.Result.Schema.Type != "string" {
+			t.Errorf("Unexpected echo result: %s", encode(t, method.Result))
+		}
+		return
+	}
+	t.Error("echo missing from rpc.discover")
+}
@@ -11,10 +11,21 @@ import (
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
 )
 
+// LeaderChecker reports whether this instance should run scheduled jobs
+type LeaderChecker interface {
+	IsLeader() bool
+	// LeaderContext is cancelled once this instance loses leadership
+	LeaderContext() context.Context
+}
+
 type CronScheduler struct {
 	cron           *cron.Cron
 	monitor        *services.BootstrapMonitor
 	grpcMonitor    *services.GRPCMonitor
+	validators     *services.ValidatorService
+	chain          *services.ChainMonitor
+	retention      *services.RetentionService
+	leader         LeaderChecker
 	logger         *logrus.Logger
 	jobTimeout     time.Duration
 	activeJobs     sync.WaitGroup
@@ -25,6 +36,10 @@ type CronScheduler struct {
 func NewCronScheduler(
 	monitor *services.BootstrapMonitor,
 	grpcMonitor *services.GRPCMonitor,
+	validators *services.ValidatorService,
+	chain *services.ChainMonitor,
+	retention *services.RetentionService,
+	leader LeaderChecker,
 	logger *logrus.Logger,
 ) *CronScheduler {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -33,6 +48,10 @@ func NewCronScheduler(
 		cron:           cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
 		monitor:        monitor,
 		grpcMonitor:    grpcMonitor,
+		validators:     validators,
+		chain:          chain,
+		retention:      retention,
+		leader:         leader,
 		logger:         logger,
 		jobTimeout:     30 * time.Minute, // Configurable timeout for jobs
 		shutdownCtx:    ctx,
@@ -51,7 +70,8 @@ func (s *CronScheduler) Start() {
 
 	// Schedule gRPC server sync every 6 hours
 	_, err = s.cron.AddFunc("30 */6 * * *", s.createJobWrapper("gRPC Sync", func(ctx context.Context) error {
-		return s.grpcMonitor.SyncGRPCServers(ctx)
+		_, err := s.grpcMonitor.SyncGRPCServers(ctx, false)
+		return err
 	}))
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to schedule gRPC sync")
@@ -67,12 +87,53 @@ func (s *CronScheduler) Start() {
 
 	// Schedule bootstrap node sync every 6 hours
 	_, err = s.cron.AddFunc("0 */6 * * *", s.createJobWrapper("Bootstrap Sync", func(ctx context.Context) error {
-		return s.monitor.SyncBootstrapNodes(ctx)
+		_, err := s.monitor.SyncBootstrapNodes(ctx, false)
+		return err
 	}))
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to schedule bootstrap sync")
 	}
 
+	// Validator jobs need a trusted gRPC server
+	if s.validators != nil && s.validators.Enabled() {
+		// Schedule committee and block proposer syncs every 10 minutes
+		_, err = s.cron.AddFunc("*/10 * * * *", s.createJobWrapper("Committee Sync", func(ctx context.Context) error {
+			return s.validators.SyncCommittee(ctx)
+		}))
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to schedule committee sync")
+		}
+
+		// Schedule a refresh of every validator hourly
+		_, err = s.cron.AddFunc("45 * * * *", s.createJobWrapper("Validator Refresh", func(ctx context.Context) error {
+			return s.validators.RefreshValidators(ctx)
+		}))
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to schedule validator refresh")
+		}
+	}
+
+	// Chain monitoring needs trusted servers
+	if s.chain != nil && s.chain.Enabled() {
+		// Schedule chain polls every minute so stalls are caught quickly
+		_, err = s.cron.AddFunc("* * * * *", s.createJobWrapper("Chain Poll", func(ctx context.Context) error {
+			return s.chain.Poll(ctx)
+		}))
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to schedule chain polls")
+		}
+	}
+
+	// Schedule the cleanup of aged-out monitoring data daily at 4 AM UTC
+	if s.retention != nil {
+		_, err = s.cron.AddFunc("0 4 * * *", s.createJobWrapper("Data Cleanup", func(ctx context.Context) error {
+			return s.retention.Cleanup(ctx)
+		}))
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to schedule data cleanup")
+		}
+	}
+
 	s.cron.Start()
 	s.logger.Info("Cron scheduler started successfully")
 }
@@ -80,11 +141,17 @@ func (s *CronScheduler) Start() {
 // createJobWrapper wraps a job with context, timeout, logging, and panic recovery
 func (s *CronScheduler) createJobWrapper(jobName string, jobFunc func(context.Context) error) func() {
 	return func() {
+		// Only the elected leader runs scheduled jobs when replicas share a database
+		if !isLeader(s.leader) {
+			s.logger.WithField("job", jobName).Debug("Skipping scheduled job, not the leader")
+			return
+		}
+
 		s.activeJobs.Add(1)
 		defer s.activeJobs.Done()
 
 		// Create context with timeout
-		ctx, cancel := context.WithTimeout(s.shutdownCtx, s.jobTimeout)
+		ctx, cancel := jobContext(s.shutdownCtx, s.leader, s.jobTimeout)
 		defer cancel()
 
 		// Track job execution time
@@ -129,10 +196,36 @@ func (s *CronScheduler) createJobWrapper(jobName string, jobFunc func(context.Co
 				"job":     jobName,
 				"timeout": s.jobTimeout.String(),
 			}).Warn("Job timed out")
+		} else if ctx.Err() != nil && !isLeader(s.leader) {
+			s.logger.WithField("job", jobName).Warn("Job cancelled, leadership lost")
 		}
 	}
 }
 
+// isLeader treats a missing leader checker as a single-instance deployment
+func isLeader(leader LeaderChecker) bool {
+	if leader == nil {
+		return true
+	}
+	return leader.IsLeader()
+}
+
+// jobContext derives the context of a job run from the scheduler's shutdown
+// context. It is cancelled on timeout and when leadership is lost, so a
+// former leader stops its running jobs while another replica takes over.
+func jobContext(parent context.Context, leader LeaderChecker, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	if leader == nil {
+		return ctx, cancel
+	}
+
+	stop := context.AfterFunc(leader.LeaderContext(), cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 func (s *CronScheduler) Stop() {
 	s.logger.Info("Stopping cron scheduler...")
 
@@ -175,5 +268,6 @@ func (s *CronScheduler) GetSchedulerStatus() map[string]interface{} {
 		"running":   len(entries) > 0,
 		"job_count": len(entries),
 		"jobs":      jobs,
+		"is_leader": isLeader(s.leader),
 	}
 }
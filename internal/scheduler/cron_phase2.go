@@ -11,14 +11,15 @@ import (
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/services"
 )
 
-// CronSchedulerPhase2 extends CronScheduler with Phase 2 functionality
+// CronSchedulerPhase2 extends CronScheduler with Phase 2 functionality. It
+// runs next to CronScheduler, which keeps the bootstrap and gRPC jobs.
 type CronSchedulerPhase2 struct {
 	cron              *cron.Cron
-	bootstrapMonitor  *services.BootstrapMonitor
-	grpcMonitor       *services.GRPCMonitor
 	jsonrpcMonitor    *services.JSONRPCMonitorService
 	networkStats      *services.NetworkStatsService
+	topology          *services.TopologyService
 	geoService        *services.GeoLocationService
+	leader            LeaderChecker
 	logger            *logrus.Logger
 	jobTimeout        time.Duration
 	activeJobs        sync.WaitGroup
@@ -28,22 +29,22 @@ type CronSchedulerPhase2 struct {
 
 // NewCronSchedulerPhase2 creates a new Phase 2 scheduler
 func NewCronSchedulerPhase2(
-	bootstrapMonitor *services.BootstrapMonitor,
-	grpcMonitor *services.GRPCMonitor,
 	jsonrpcMonitor *services.JSONRPCMonitorService,
 	networkStats *services.NetworkStatsService,
+	topology *services.TopologyService,
 	geoService *services.GeoLocationService,
+	leader LeaderChecker,
 	logger *logrus.Logger,
 ) *CronSchedulerPhase2 {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &CronSchedulerPhase2{
 		cron:             cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
-		bootstrapMonitor: bootstrapMonitor,
-		grpcMonitor:      grpcMonitor,
 		jsonrpcMonitor:   jsonrpcMonitor,
 		networkStats:     networkStats,
+		topology:         topology,
 		geoService:       geoService,
+		leader:           leader,
 		logger:           logger,
 		jobTimeout:       30 * time.Minute,
 		shutdownCtx:      ctx,
@@ -52,44 +53,10 @@ func NewCronSchedulerPhase2(
 }
 
 func (s *CronSchedulerPhase2) Start() {
-	// ============ PHASE 1 JOBS ============
-
-	// Schedule daily gRPC server checks at 2 AM UTC
-	_, err := s.cron.AddFunc("0 2 * * *", s.createJobWrapper("gRPC Health Check", func(ctx context.Context) error {
-		return s.grpcMonitor.CheckAllServers(ctx)
-	}))
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to schedule gRPC server checks")
-	}
-
-	// Schedule gRPC server sync every 6 hours
-	_, err = s.cron.AddFunc("30 */6 * * *", s.createJobWrapper("gRPC Sync", func(ctx context.Context) error {
-		return s.grpcMonitor.SyncGRPCServers(ctx)
-	}))
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to schedule gRPC sync")
-	}
-
-	// Schedule daily bootstrap node checks at 1 AM UTC
-	_, err = s.cron.AddFunc("0 1 * * *", s.createJobWrapper("Bootstrap Health Check", func(ctx context.Context) error {
-		return s.bootstrapMonitor.CheckAllNodes(ctx)
-	}))
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to schedule bootstrap node checks")
-	}
-
-	// Schedule bootstrap node sync every 6 hours
-	_, err = s.cron.AddFunc("0 */6 * * *", s.createJobWrapper("Bootstrap Sync", func(ctx context.Context) error {
-		return s.bootstrapMonitor.SyncBootstrapNodes(ctx)
-	}))
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to schedule bootstrap sync")
-	}
-
 	// ============ PHASE 2 JOBS ============
 
 	// Schedule daily JSON-RPC server checks at 3 AM UTC
-	_, err = s.cron.AddFunc("0 3 * * *", s.createJobWrapper("JSON-RPC Health Check", func(ctx context.Context) error {
+	_, err := s.cron.AddFunc("0 3 * * *", s.createJobWrapper("JSON-RPC Health Check", func(ctx context.Context) error {
 		return s.jsonrpcMonitor.CheckAllServers(ctx)
 	}))
 	if err != nil {
@@ -112,6 +79,15 @@ func (s *CronSchedulerPhase2) Start() {
 		s.logger.WithError(err).Error("Failed to schedule network snapshots")
 	}
 
+	// Schedule bootstrap centrality of the peer graph every 6 hours, which
+	// getTopology serves without computing it
+	_, err = s.cron.AddFunc("30 */6 * * *", s.createJobWrapper("Topology Centrality", func(ctx context.Context) error {
+		return s.topology.RefreshCentrality(ctx)
+	}))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to schedule topology centrality")
+	}
+
 	s.cron.Start()
 	s.logger.Info("Phase 2 Cron scheduler started successfully")
 
@@ -129,11 +105,17 @@ func (s *CronSchedulerPhase2) Start() {
 // createJobWrapper wraps a job with context, timeout, logging, and panic recovery
 func (s *CronSchedulerPhase2) createJobWrapper(jobName string, jobFunc func(context.Context) error) func() {
 	return func() {
+		// Only the elected leader runs scheduled jobs when replicas share a database
+		if !isLeader(s.leader) {
+			s.logger.WithField("job", jobName).Debug("Skipping scheduled job, not the leader")
+			return
+		}
+
 		s.activeJobs.Add(1)
 		defer s.activeJobs.Done()
 
 		// Create context with timeout
-		ctx, cancel := context.WithTimeout(s.shutdownCtx, s.jobTimeout)
+		ctx, cancel := jobContext(s.shutdownCtx, s.leader, s.jobTimeout)
 		defer cancel()
 
 		// Track job execution time
@@ -178,6 +160,8 @@ func (s *CronSchedulerPhase2) createJobWrapper(jobName string, jobFunc func(cont
 				"job":     jobName,
 				"timeout": s.jobTimeout.String(),
 			}).Warn("Job timed out")
+		} else if ctx.Err() != nil && !isLeader(s.leader) {
+			s.logger.WithField("job", jobName).Warn("Job cancelled, leadership lost")
 		}
 	}
 }
@@ -224,5 +208,6 @@ func (s *CronSchedulerPhase2) GetSchedulerStatus() map[string]interface{} {
 		"running":   len(entries) > 0,
 		"job_count": len(entries),
 		"jobs":      jobs,
+		"is_leader": isLeader(s.leader),
 	}
 }
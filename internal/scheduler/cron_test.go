@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -11,7 +12,7 @@ func TestNewCronScheduler(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	scheduler := NewCronScheduler(nil, nil, logger)
+	scheduler := NewCronScheduler(nil, nil, nil, nil, nil, nil, logger)
 
 	if scheduler == nil {
 		t.Fatal("Expected non-nil scheduler")
@@ -30,7 +31,7 @@ func TestCronScheduler_GetSchedulerStatus(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	scheduler := NewCronScheduler(nil, nil, logger)
+	scheduler := NewCronScheduler(nil, nil, nil, nil, nil, nil, logger)
 	status := scheduler.GetSchedulerStatus()
 
 	if status == nil {
@@ -49,3 +50,82 @@ func TestCronScheduler_GetSchedulerStatus(t *testing.T) {
 		t.Error("Expected 'jobs' key in status")
 	}
 }
+
+type stubLeader struct {
+	leader bool
+	ctx    context.Context
+}
+
+func (l *stubLeader) IsLeader() bool {
+	return l.leader
+}
+
+func TestCronScheduler_JobsRunOnlyOnLeader(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	leader := &stubLeader{leader: false, ctx: context.Background()}
+	scheduler := NewCronScheduler(nil, nil, nil, nil, nil, leader, logger)
+
+	runs := 0
+	job := scheduler.createJobWrapper("test job", func(ctx context.Context) error {
+		runs++
+		return nil
+	})
+
+	job()
+	if runs != 0 {
+		t.Errorf("Expected job to be skipped on follower, ran %d times", runs)
+	}
+
+	leader.leader = true
+	job()
+	if runs != 1 {
+		t.Errorf("Expected job to run once on leader, ran %d times", runs)
+	}
+
+	if status := scheduler.GetSchedulerStatus(); status["is_leader"] != true {
+		t.Errorf("Expected is_leader to be true, got %v", status["is_leader"])
+	}
+}
+
+func (l *stubLeader) LeaderContext() context.Context {
+	return l.ctx
+}
+
+func TestCronScheduler_JobsCancelledOnLeadershipLoss(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	leaderCtx, resign := context.WithCancel(context.Background())
+	leader := &stubLeader{leader: true, ctx: leaderCtx}
+	scheduler := NewCronScheduler(nil, nil, nil, nil, nil, leader, logger)
+
+	started := make(chan struct{})
+	var jobErr error
+	job := scheduler.createJobWrapper("test job", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		jobErr = ctx.Err()
+		return jobErr
+	})
+
+	done := make(chan struct{})
+	go func() {
+		job()
+		close(done)
+	}()
+
+	<-started
+	leader.leader = false
+	resign()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job to stop when leadership is lost")
+	}
+	if jobErr != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", jobErr)
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// DefaultAddressWindowDays is how recently an address must have been seen
+// or checked to count in getAddressFamilyStats
+const DefaultAddressWindowDays = 7
+
+// MaxAddressWindowDays caps how far back getAddressFamilyStats looks
+const MaxAddressWindowDays = 90
+
+// AddressService stores the addresses nodes advertise and the per-family
+// and per-transport results of probing them
+type AddressService struct {
+	addressRepo repositories.AddressRepository
+	logger      *logrus.Logger
+}
+
+// NewAddressService creates a new address service
+func NewAddressService(addressRepo repositories.AddressRepository, logger *logrus.Logger) *AddressService {
+	return &AddressService{
+		addressRepo: addressRepo,
+		logger:      logger,
+	}
+}
+
+// RecordPeerAddresses stores the advertised addresses of a crawled peer.
+// The peer must have been stored first so its ID is set. Addresses that do
+// not parse are skipped.
+func (s *AddressService) RecordPeerAddresses(ctx context.Context, peer *models.ReachablePeer) error {
+	if peer.ID == 0 || len(peer.Addresses) == 0 {
+		return nil
+	}
+	return s.addressRepo.RecordPeerAddresses(ctx, peerAddresses(peer))
+}
+
+// RecordEndpointChecks stores the latest endpoint checks of a node
+func (s *AddressService) RecordEndpointChecks(ctx context.Context, nodeType string, nodeID int, checks []*models.EndpointCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	return s.addressRepo.RecordEndpointChecks(ctx, nodeType, nodeID, checks)
+}
+
+// GetEndpointChecks returns the latest endpoint checks of every node of a
+// type, keyed by node ID. Failures are logged and yield no checks, so node
+// listings still load.
+func (s *AddressService) GetEndpointChecks(ctx context.Context, nodeType string) map[int][]*models.EndpointCheck {
+	checks, err := s.addressRepo.GetEndpointChecks(ctx, nodeType)
+	if err != nil {
+		s.logger.WithError(err).WithField("node_type", nodeType).Warn("Failed to load endpoint checks")
+		return nil
+	}
+	return checks
+}
+
+// GetFamilyStats returns the reachability of the checked endpoints and the
+// addresses crawled peers advertise on a network per address family and
+// transport
+func (s *AddressService) GetFamilyStats(ctx context.Context, network string, days int) (*models.AddressFamilyStats, error) {
+	if days <= 0 {
+		days = DefaultAddressWindowDays
+	}
+	if days > MaxAddressWindowDays {
+		days = MaxAddressWindowDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	checks, err := s.addressRepo.GetFamilyReachability(ctx, network, since)
+	if err != nil {
+		return nil, err
+	}
+	peers, err := s.addressRepo.GetPeerAddressFamilies(ctx, network, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.AddressFamilyStats{
+		Network: network,
+		Checks:  []*models.FamilyReachability{},
+		Peers:   []*models.AddressFamilyCount{},
+		Since:   since,
+	}
+	for _, family := range checks {
+		family.Share = percentOf(family.Reachable, family.Checked)
+		stats.Checks = append(stats.Checks, family)
+	}
+	stats.Peers = append(stats.Peers, peers...)
+
+	return stats, nil
+}
+
+// peerAddresses parses the advertised addresses of a peer, skipping
+// duplicates and addresses that do not parse
+func peerAddresses(peer *models.ReachablePeer) []*models.PeerAddress {
+	seen := make(map[string]bool)
+	var addresses []*models.PeerAddress
+	for _, addr := range peer.Addresses {
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+
+		endpoint, err := parseMultiaddr(addr)
+		if err != nil {
+			continue
+		}
+		addresses = append(addresses, &models.PeerAddress{
+			PeerID:    peer.ID,
+			Multiaddr: addr,
+			Family:    endpoint.Family,
+			Transport: endpoint.Transport,
+			LastSeen:  peer.LastSeen,
+		})
+	}
+	return addresses
+}
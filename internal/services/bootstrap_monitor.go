@@ -6,18 +6,23 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
-	"github.com/pactus-project/pactus/config"
 	"github.com/sirupsen/logrus"
 )
 
 type BootstrapMonitor struct {
-	bootstrapRepo    repositories.BootstrapRepository
-	statusRepo       repositories.StatusRepository
-	nodeChecker      *NodeChecker
-	bootstrapService *BootstrapService
-	logger           *logrus.Logger
+	bootstrapRepo   repositories.BootstrapRepository
+	statusRepo      repositories.StatusRepository
+	nodeChecker     *NodeChecker
+	reconciler      *nodeReconciler
+	latencyService  *LatencyService
+	addressService  *AddressService
+	maintenanceRepo repositories.MaintenanceRepository
+	networkService  *NetworkService
+	eventBus        *events.Bus
+	logger          *logrus.Logger
 }
 
 func NewBootstrapMonitor(
@@ -25,23 +30,51 @@ func NewBootstrapMonitor(
 	statusRepo repositories.StatusRepository,
 	nodeChecker *NodeChecker,
 	logger *logrus.Logger,
-	bootstrapService *BootstrapService,
+	source NodeSource,
+	syncRunRepo repositories.SyncRunRepository,
+	latencyService *LatencyService,
+	addressService *AddressService,
+	maintenanceRepo repositories.MaintenanceRepository,
+	networkService *NetworkService,
+	eventBus *events.Bus,
 ) *BootstrapMonitor {
 	return &BootstrapMonitor{
-		bootstrapRepo:    bootstrapRepo,
-		statusRepo:       statusRepo,
-		nodeChecker:      nodeChecker,
-		bootstrapService: bootstrapService,
-		logger:           logger,
+		bootstrapRepo: bootstrapRepo,
+		statusRepo:    statusRepo,
+		nodeChecker:   nodeChecker,
+		reconciler: &nodeReconciler{
+			nodeType: models.NodeTypeBootstrap,
+			source:   source,
+			store:    &bootstrapStore{repo: bootstrapRepo},
+			addresses: func(network *models.Network) []string {
+				return network.BootstrapAddresses
+			},
+			name:           bootstrapNodeName,
+			networkService: networkService,
+			syncRunRepo:    syncRunRepo,
+			eventBus:       eventBus,
+			logger:         logger,
+		},
+		latencyService:  latencyService,
+		addressService:  addressService,
+		maintenanceRepo: maintenanceRepo,
+		networkService:  networkService,
+		eventBus:        eventBus,
+		logger:          logger,
 	}
 }
 
-// CheckAllNodes performs health checks on all active nodes
+// CheckAllNodes performs health checks on all active nodes. Nodes in a
+// maintenance window are recorded with the maintenance color.
 func (bm *BootstrapMonitor) CheckAllNodes(ctx context.Context) error {
 	nodes, err := bm.bootstrapRepo.GetActiveNodes(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get active nodes: %w", err)
 	}
+	windows, err := maintenanceWindows(ctx, bm.maintenanceRepo, models.NodeTypeBootstrap)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
 
 	today := time.Now().Truncate(24 * time.Hour)
 
@@ -60,7 +93,7 @@ func (bm *BootstrapMonitor) CheckAllNodes(ctx context.Context) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			if err := bm.checkSingleNode(ctx, n, today); err != nil {
+			if err := bm.checkSingleNode(ctx, n, today, windows[n.ID]); err != nil {
 				bm.logger.WithError(err).WithField("node_id", n.ID).Error("Failed to check node")
 				errChan <- err
 			}
@@ -77,9 +110,10 @@ func (bm *BootstrapMonitor) CheckAllNodes(ctx context.Context) error {
 		errors = append(errors, err)
 	}
 
-	// Update overall scores after checking all nodes
-	if err := bm.bootstrapRepo.UpdateAllScores(ctx); err != nil {
-		bm.logger.WithError(err).Error("Failed to update overall scores")
+	if bm.latencyService != nil {
+		if err := bm.latencyService.Rollup(ctx, models.NodeTypeBootstrap, today); err != nil {
+			bm.logger.WithError(err).Error("Failed to roll up latency")
+		}
 	}
 
 	if len(errors) > 0 {
@@ -89,8 +123,9 @@ func (bm *BootstrapMonitor) CheckAllNodes(ctx context.Context) error {
 	return nil
 }
 
-// checkSingleNode checks a single node's health
-func (bm *BootstrapMonitor) checkSingleNode(ctx context.Context, node *models.BootstrapNode, date time.Time) error {
+// checkSingleNode checks a single node's health. window is the node's open
+// maintenance window, if any.
+func (bm *BootstrapMonitor) checkSingleNode(ctx context.Context, node *models.BootstrapNode, date time.Time, window *models.MaintenanceWindow) error {
 	// Check if we already have a record for today
 	exists, err := bm.statusRepo.HasStatusForDate(ctx, node.ID, date)
 	if err != nil {
@@ -108,23 +143,56 @@ func (bm *BootstrapMonitor) checkSingleNode(ctx context.Context, node *models.Bo
 	// Check the node
 	result := bm.nodeChecker.CheckNode(ctx, node.Address)
 
-	// Determine color based on success
-	color := 0 // red/gray for failure
-	if result.Success {
-		color = 1 // green for success
+	if bm.latencyService != nil {
+		if err := bm.latencyService.Record(ctx, models.NodeTypeBootstrap, node.ID, result.Samples); err != nil {
+			bm.logger.WithError(err).WithField("node_id", node.ID).Error("Failed to record latency")
+		}
 	}
 
+	// Probe TCP and QUIC on every IPv4 and IPv6 address of the node
+	if bm.addressService != nil {
+		checks := bm.nodeChecker.CheckEndpoints(ctx, node.Address)
+		if err := bm.addressService.RecordEndpointChecks(ctx, models.NodeTypeBootstrap, node.ID, checks); err != nil {
+			bm.logger.WithError(err).WithField("node_id", node.ID).Error("Failed to record endpoint checks")
+		}
+	}
+
+	// Determine color based on success: red/gray for failure, green for
+	// success, or the maintenance color during a maintenance window
+	color := statusColor(result.Success, window)
+
 	// Save the result
 	status := &models.DailyStatus{
-		NodeID:   node.ID,
-		Date:     date,
-		Color:    color,
-		Attempts: result.Attempts,
-		Success:  result.Success,
-		ErrorMsg: result.ErrorMsg,
+		NodeID:         node.ID,
+		Date:           date,
+		Color:          color,
+		Attempts:       result.Attempts,
+		Success:        result.Success,
+		ResponseTimeMs: result.ResponseTimeMs,
+		ErrorMsg:       result.ErrorMsg,
+		ErrorClass:     result.ErrorClass,
 	}
 
-	return bm.statusRepo.CreateStatus(ctx, status)
+	var previousColor *int
+	if bm.eventBus != nil {
+		previous, err := bm.statusRepo.GetStatusByNodeAndDate(ctx, node.ID, date.AddDate(0, 0, -1))
+		if err == nil && previous != nil {
+			previousColor = &previous.Color
+		}
+	}
+
+	if err := bm.statusRepo.CreateStatus(ctx, status); err != nil {
+		return err
+	}
+
+	publishCheck(ctx, bm.eventBus, models.NodeTypeBootstrap, node.ID, events.CheckResult{
+		Date:           date.Format("2006-01-02"),
+		Color:          color,
+		Success:        result.Success,
+		ResponseTimeMs: result.ResponseTimeMs,
+		ErrorClass:     string(result.ErrorClass),
+	}, previousColor)
+	return nil
 }
 
 // GetBootstrapNodesWithStatus retrieves all active nodes with their recent status history
@@ -133,151 +201,84 @@ func (bm *BootstrapMonitor) GetBootstrapNodesWithStatus(ctx context.Context) ([]
 	if err != nil {
 		return nil, err
 	}
-
-	var response []*models.BootstrapNodeResponse
-
-	for _, node := range nodes {
-		statuses, err := bm.statusRepo.GetRecentStatusesByNode(ctx, node.ID, 30) // Last 30 days
-		if err != nil {
-			bm.logger.WithError(err).WithField("node_id", node.ID).Error("Failed to get statuses")
-			continue
-		}
-
-		nodeResponse := &models.BootstrapNodeResponse{
-			Name:         node.Name,
-			Email:        node.Email,
-			Website:      node.Website,
-			Address:      node.Address,
-			Status:       statuses,
-			OverallScore: node.OverallScore,
-			Country:      node.Country,
-			City:         node.City,
-			Latitude:     node.Latitude,
-			Longitude:    node.Longitude,
-		}
-
-		response = append(response, nodeResponse)
-	}
-
-	return response, nil
+	return bm.withStatus(ctx, nodes)
 }
 
-// SyncBootstrapNodes synchronizes nodes from the Pactus GitHub repository
-func (bm *BootstrapMonitor) SyncBootstrapNodes(ctx context.Context) error {
-	bm.logger.Info("Starting bootstrap node sync from GitHub")
-
-	githubNodes, err := config.GetBootstrapNodes()
+// ListBootstrapNodes returns one page of active nodes with their recent
+// status history. The filter must have been validated.
+func (bm *BootstrapMonitor) ListBootstrapNodes(ctx context.Context, filter models.NodeFilter) (*models.BootstrapNodePage, error) {
+	nodes, err := bm.bootstrapRepo.ListNodes(ctx, filter)
 	if err != nil {
-		return fmt.Errorf("failed to load bootstrap nodes from GitHub: %w", err)
+		return nil, err
 	}
 
-	var nodes []*BootstrapNode
-	for _, node := range githubNodes {
-		nodes = append(nodes, &BootstrapNode{
-			Name:    node.Name,
-			Email:   node.Email,
-			Website: node.Website,
-			Address: node.Address,
-		})
+	page := &models.BootstrapNodePage{}
+	if len(nodes) > filter.Limit {
+		nodes = nodes[:filter.Limit]
+		last := nodes[len(nodes)-1]
+		page.NextCursor = filter.NextCursor(last.ID, last.Name, last.Country, last.OverallScore)
 	}
 
-	currentNodes, err := bm.bootstrapRepo.GetAllNodes(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get current nodes: %w", err)
+	if page.Items, err = bm.withStatus(ctx, nodes); err != nil {
+		return nil, err
 	}
+	return page, nil
+}
 
-	// Create maps for efficient lookup
-	currentNodesMap := make(map[string]*models.BootstrapNode)
-	for _, node := range currentNodes {
-		currentNodesMap[node.Address] = node
+// withStatus builds node responses, loading the 30-day status history of
+// all nodes in a single query
+func (bm *BootstrapMonitor) withStatus(ctx context.Context, nodes []*models.BootstrapNode) ([]*models.BootstrapNodeResponse, error) {
+	ids := make([]int, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
 	}
 
-	githubNodesMap := make(map[string]*BootstrapNode)
-	for _, node := range nodes {
-		githubNodesMap[node.Address] = node
+	statuses, err := bm.statusRepo.GetRecentStatusesByNodes(ctx, ids, 30) // Last 30 days
+	if err != nil {
+		return nil, err
 	}
 
-	// Process changes
-	stats := &SyncStats{}
-
-	// Add new nodes and update existing ones
-	for _, githubNode := range nodes {
-		if existingNode, exists := currentNodesMap[githubNode.Address]; exists {
-			// Update existing node if needed
-			if bm.shouldUpdateNode(existingNode, githubNode) {
-				updatedNode := &models.BootstrapNode{
-					Name:    githubNode.Name,
-					Email:   githubNode.Email,
-					Website: githubNode.Website,
-					Address: githubNode.Address,
-				}
-				if err := bm.bootstrapRepo.UpdateNode(ctx, updatedNode); err != nil {
-					bm.logger.WithError(err).WithField("address", githubNode.Address).Error("Failed to update node")
-					stats.Errors++
-					continue
-				}
-				stats.Updated++
-			}
-		} else {
-			// Add new node
-			newNode := &models.BootstrapNode{
-				Name:     githubNode.Name,
-				Email:    githubNode.Email,
-				Website:  githubNode.Website,
-				Address:  githubNode.Address,
-				IsActive: true,
-			}
-			if err := bm.bootstrapRepo.CreateNode(ctx, newNode); err != nil {
-				bm.logger.WithError(err).WithField("address", githubNode.Address).Error("Failed to add node")
-				stats.Errors++
-				continue
-			}
-			stats.Added++
-		}
+	var latency map[int]*models.LatencySummary
+	if bm.latencyService != nil {
+		latency = bm.latencyService.GetLatestSummaries(ctx, models.NodeTypeBootstrap, ids)
 	}
 
-	// Deactivate nodes that are no longer in the file
-	var nodesToDeactivate []string
-	for _, node := range currentNodes {
-		if _, exists := githubNodesMap[node.Address]; !exists && node.IsActive {
-			nodesToDeactivate = append(nodesToDeactivate, node.Address)
-		}
+	var endpoints map[int][]*models.EndpointCheck
+	if bm.addressService != nil {
+		endpoints = bm.addressService.GetEndpointChecks(ctx, models.NodeTypeBootstrap)
 	}
 
-	if len(nodesToDeactivate) > 0 {
-		if err := bm.bootstrapRepo.DeactivateNodes(ctx, nodesToDeactivate); err != nil {
-			bm.logger.WithError(err).Error("Failed to deactivate removed nodes")
-		} else {
-			stats.Deactivated = len(nodesToDeactivate)
-		}
+	response := make([]*models.BootstrapNodeResponse, 0, len(nodes))
+	for _, node := range nodes {
+		response = append(response, &models.BootstrapNodeResponse{
+			ID:           node.ID,
+			Name:         node.Name,
+			Email:        node.Email,
+			Website:      node.Website,
+			Address:      node.Address,
+			Network:      node.Network,
+			Status:       statuses[node.ID],
+			OverallScore: node.OverallScore,
+			Country:      node.Country,
+			City:         node.City,
+			Latitude:     node.Latitude,
+			Longitude:    node.Longitude,
+			Latency:      latency[node.ID],
+			Endpoints:    endpoints[node.ID],
+		})
 	}
 
-	bm.logger.WithFields(logrus.Fields{
-		"added":       stats.Added,
-		"updated":     stats.Updated,
-		"deactivated": stats.Deactivated,
-		"errors":      stats.Errors,
-	}).Info("Completed bootstrap node sync from GitHub")
+	return response, nil
+}
 
-	return nil
+// SyncBootstrapNodes reconciles the bootstrap nodes of every active
+// network with the node source and the network's configured addresses,
+// and stores the report of the run. A dry run only reports the changes.
+func (bm *BootstrapMonitor) SyncBootstrapNodes(ctx context.Context, dryRun bool) (*models.SyncRun, error) {
+	return bm.reconciler.Sync(ctx, dryRun)
 }
 
 // GetBootstrapNodeCount returns the count of active bootstrap nodes
 func (bm *BootstrapMonitor) GetBootstrapNodeCount(ctx context.Context) (int, error) {
 	return bm.bootstrapRepo.GetNodeCount(ctx, true)
 }
-
-// Helper types and functions
-
-type SyncStats struct {
-	Added       int
-	Updated     int
-	Deactivated int
-	Errors      int
-}
-
-func (bm *BootstrapMonitor) shouldUpdateNode(existing *models.BootstrapNode, github *BootstrapNode) bool {
-	return existing.Name != github.Name ||
-		existing.Email != github.Email ||
-		existing.Website != github.Website
-}
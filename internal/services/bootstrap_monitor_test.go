@@ -1,30 +1,136 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
 )
 
-func TestSyncStats(t *testing.T) {
-	stats := &SyncStats{
-		Added:       5,
-		Updated:     3,
-		Deactivated: 2,
-		Errors:      0,
+// staticNodeSource lists fixed nodes per network and fails for networks
+// without a list
+type staticNodeSource struct {
+	lists map[string][]*SourceNode
+}
+
+func (s *staticNodeSource) Name() string {
+	return "static"
+}
+
+func (s *staticNodeSource) Nodes(ctx context.Context, network *models.Network) ([]*SourceNode, error) {
+	nodes, ok := s.lists[network.Name]
+	if !ok {
+		return nil, fmt.Errorf("no list for %s", network.Name)
 	}
+	return nodes, nil
+}
+
+// memoryBootstrapRepository keeps bootstrap nodes in memory
+type memoryBootstrapRepository struct {
+	repositories.BootstrapRepository
+	nodes []*models.BootstrapNode
+}
+
+func (r *memoryBootstrapRepository) GetAllNodes(ctx context.Context) ([]*models.BootstrapNode, error) {
+	return r.nodes, nil
+}
+
+func (r *memoryBootstrapRepository) CreateNode(ctx context.Context, node *models.BootstrapNode) error {
+	node.ID = len(r.nodes) + 1
+	r.nodes = append(r.nodes, node)
+	return nil
+}
 
-	if stats.Added != 5 {
-		t.Errorf("Expected 5 added, got %d", stats.Added)
+func (r *memoryBootstrapRepository) UpdateNode(ctx context.Context, node *models.BootstrapNode) error {
+	for _, n := range r.nodes {
+		if n.Address == node.Address {
+			n.Name, n.Email, n.Website = node.Name, node.Email, node.Website
+		}
 	}
+	return nil
+}
 
-	if stats.Updated != 3 {
-		t.Errorf("Expected 3 updated, got %d", stats.Updated)
+func (r *memoryBootstrapRepository) DeactivateNodes(ctx context.Context, addresses []string) error {
+	for _, n := range r.nodes {
+		for _, address := range addresses {
+			if n.Address == address {
+				n.IsActive = false
+			}
+		}
 	}
+	return nil
+}
 
-	if stats.Deactivated != 2 {
-		t.Errorf("Expected 2 deactivated, got %d", stats.Deactivated)
+func (r *memoryBootstrapRepository) ReactivateNodes(ctx context.Context, addresses []string) error {
+	for _, n := range r.nodes {
+		for _, address := range addresses {
+			if n.Address == address {
+				n.IsActive = true
+			}
+		}
+	}
+	return nil
+}
+
+// memorySyncRunRepository keeps sync reports in memory
+type memorySyncRunRepository struct {
+	repositories.SyncRunRepository
+	runs []*models.SyncRun
+}
+
+func (r *memorySyncRunRepository) CreateRun(ctx context.Context, run *models.SyncRun) error {
+	run.ID = len(r.runs) + 1
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func TestBootstrapMonitor_SyncBootstrapNodes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	bootstrapRepo := &memoryBootstrapRepository{nodes: []*models.BootstrapNode{
+		{ID: 1, Name: "old name", Address: "/dns/a.example.org/tcp/21888", Network: "mainnet", IsActive: true},
+		{ID: 2, Name: "b", Address: "/dns/b.example.org/tcp/21888", Network: "mainnet", IsActive: true},
+		{ID: 3, Name: "t", Address: "/dns/t.example.org/tcp/21888", Network: "testnet", IsActive: true},
+	}}
+	source := &staticNodeSource{lists: map[string][]*SourceNode{
+		"mainnet": {
+			{Name: "a", Address: "/dns/a.example.org/tcp/21888"},
+			{Address: "/dns/c.example.org/tcp/21888"},
+		},
+	}}
+	syncRunRepo := &memorySyncRunRepository{}
+	monitor := NewBootstrapMonitor(bootstrapRepo, nil, nil, logger, source, syncRunRepo, nil, nil, nil, nil, nil)
+
+	run, err := monitor.SyncBootstrapNodes(context.Background(), false)
+	if err != nil {
+		t.Fatalf("SyncBootstrapNodes: %v", err)
+	}
+
+	if len(run.Added) != 1 || run.Added[0].Name != "c.example.org" || run.Added[0].Network != "mainnet" {
+		t.Errorf("Expected c to be added under its host name, got %+v", run.Added)
+	}
+	if len(run.Updated) != 1 || run.Updated[0].Address != "/dns/a.example.org/tcp/21888" || fmt.Sprint(run.Updated[0].Fields) != "[name]" {
+		t.Errorf("Expected the name of a to be updated, got %+v", run.Updated)
+	}
+	if len(run.Deactivated) != 1 || run.Deactivated[0].Address != "/dns/b.example.org/tcp/21888" {
+		t.Errorf("Expected b to be deactivated, got %+v", run.Deactivated)
+	}
+
+	// The testnet list failed, so its nodes are left alone
+	if run.Errors != 1 || !strings.Contains(run.Error, "testnet") {
+		t.Errorf("Expected the testnet failure to be reported, got %d errors: %q", run.Errors, run.Error)
+	}
+	if !bootstrapRepo.nodes[2].IsActive {
+		t.Error("Expected the testnet node to stay active")
 	}
 
-	if stats.Errors != 0 {
-		t.Errorf("Expected 0 errors, got %d", stats.Errors)
+	if len(syncRunRepo.runs) != 1 || syncRunRepo.runs[0].ID != 1 || run.Source != "static" || run.FinishedAt.IsZero() {
+		t.Errorf("Expected the run to be stored, got %+v", syncRunRepo.runs)
 	}
 }
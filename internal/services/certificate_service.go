@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/metrics"
+)
+
+// CertificateService stores endpoint certificates and raises alerts for bad ones
+type CertificateService struct {
+	certRepo repositories.CertificateRepository
+	metrics  *metrics.Metrics
+	eventBus *events.Bus
+	logger   *logrus.Logger
+}
+
+// NewCertificateService creates a new certificate service
+func NewCertificateService(certRepo repositories.CertificateRepository, metrics *metrics.Metrics, eventBus *events.Bus, logger *logrus.Logger) *CertificateService {
+	return &CertificateService{
+		certRepo: certRepo,
+		metrics:  metrics,
+		eventBus: eventBus,
+		logger:   logger,
+	}
+}
+
+// Record saves the certificate seen on a node and alerts when it has problems
+func (s *CertificateService) Record(ctx context.Context, nodeType string, nodeID int, cert *models.CertificateInfo) error {
+	cert.NodeType = nodeType
+	cert.NodeID = nodeID
+
+	s.metrics.UpdateCertificate(nodeType, cert.Address, cert.DaysRemaining, map[string]bool{
+		"expired":       cert.IsExpired,
+		"self_signed":   cert.IsSelfSigned,
+		"expiring_soon": cert.ExpiresSoon,
+		"untrusted":     !cert.IsTrusted,
+	})
+
+	if cert.HasProblem() {
+		s.logger.WithFields(logrus.Fields{
+			"alert":          "tls_certificate",
+			"node_type":      nodeType,
+			"node_id":        nodeID,
+			"address":        cert.Address,
+			"issuer":         cert.Issuer,
+			"not_after":      cert.NotAfter,
+			"days_remaining": cert.DaysRemaining,
+			"expired":        cert.IsExpired,
+			"self_signed":    cert.IsSelfSigned,
+			"expiring_soon":  cert.ExpiresSoon,
+			"verify_error":   cert.VerifyError,
+		}).Warn("TLS certificate needs attention")
+	}
+
+	if err := s.certRepo.UpsertCertificate(ctx, cert); err != nil {
+		return fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	// Expiry is alerted on every check inside the warning window, so the
+	// alert repeats daily until the certificate is renewed
+	if cert.IsExpired || cert.ExpiresSoon {
+		s.eventBus.Publish(ctx, events.Event{
+			Type:     events.CertificateExpiring,
+			NodeType: nodeType,
+			NodeID:   nodeID,
+			Data: events.Certificate{
+				Address:       cert.Address,
+				Issuer:        cert.Issuer,
+				NotAfter:      cert.NotAfter,
+				DaysRemaining: cert.DaysRemaining,
+				Expired:       cert.IsExpired,
+			},
+		})
+	}
+
+	return nil
+}
+
+// GetCertificates returns the latest certificate of every TLS endpoint
+func (s *CertificateService) GetCertificates(ctx context.Context) ([]*models.CertificateInfo, error) {
+	return s.certRepo.GetAllCertificates(ctx)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/metrics"
+)
+
+// memoryCertificateRepository keeps certificates in memory
+type memoryCertificateRepository struct {
+	certs []*models.CertificateInfo
+}
+
+func (r *memoryCertificateRepository) UpsertCertificate(ctx context.Context, cert *models.CertificateInfo) error {
+	r.certs = append(r.certs, cert)
+	return nil
+}
+
+func (r *memoryCertificateRepository) GetCertificate(ctx context.Context, nodeType string, nodeID int) (*models.CertificateInfo, error) {
+	return nil, nil
+}
+
+func (r *memoryCertificateRepository) GetAllCertificates(ctx context.Context) ([]*models.CertificateInfo, error) {
+	return r.certs, nil
+}
+
+func (r *memoryCertificateRepository) DeleteCertificate(ctx context.Context, nodeType string, nodeID int) error {
+	return nil
+}
+
+func TestCertificateService_RecordPublishesExpiry(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	bus := events.NewBus(logger)
+	defer bus.Close()
+	sub, err := bus.Subscribe(events.Filter{Types: []events.Type{events.CertificateExpiring}})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	svc := NewCertificateService(&memoryCertificateRepository{}, metrics.NewMetrics(), bus, logger)
+	notAfter := time.Now().Add(5 * 24 * time.Hour)
+
+	healthy := &models.CertificateInfo{Address: "ok.example.org:443", IsTrusted: true, DaysRemaining: 60}
+	expiring := &models.CertificateInfo{Address: "soon.example.org:443", IsTrusted: true, ExpiresSoon: true, DaysRemaining: 5, NotAfter: notAfter}
+	for id, cert := range []*models.CertificateInfo{healthy, expiring} {
+		if err := svc.Record(context.Background(), models.NodeTypeJSONRPC, id+1, cert); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	select {
+	case event := <-sub.C:
+		cert, ok := event.Data.(events.Certificate)
+		if !ok || event.NodeID != 2 || cert.Address != "soon.example.org:443" || cert.DaysRemaining != 5 || cert.Expired {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a certificate.expiring event")
+	}
+
+	select {
+	case event := <-sub.C:
+		t.Errorf("Expected a single event, got %+v", event)
+	default:
+	}
+}
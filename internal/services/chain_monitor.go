@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// MaxChainBlocksPerPoll caps the blocks recorded by one poll, so a monitor
+// that was down catches up on the newest blocks first
+const MaxChainBlocksPerPoll = 500
+
+// DefaultChainStatsHours is the window of getChainStats by default
+const DefaultChainStatsHours = 24
+
+// MaxChainStatsHours caps the window of getChainStats
+const MaxChainStatsHours = 30 * 24
+
+// ChainMonitor polls block headers from trusted servers of a network,
+// records block times, proposers and gaps, and raises stalls and forks on
+// the event bus
+type ChainMonitor struct {
+	chainRepo      repositories.ChainRepository
+	sources        []chainSource
+	network        string
+	stallThreshold time.Duration
+	eventBus       *events.Bus
+	logger         *logrus.Logger
+}
+
+// sourceHead is what a trusted server reported in one poll. Tip is nil
+// when the server did not answer.
+type sourceHead struct {
+	source chainSource
+	status *models.ChainSource
+	tip    *models.ChainBlock
+}
+
+// NewChainMonitor creates a new chain monitor reading the given network
+// from its gRPC and JSON-RPC servers, preferred in that order
+func NewChainMonitor(
+	chainRepo repositories.ChainRepository,
+	grpcChecker *GRPCChecker,
+	jsonrpcMonitor *JSONRPCMonitorService,
+	grpcAddresses []string,
+	jsonrpcAddresses []string,
+	network string,
+	stallThreshold time.Duration,
+	eventBus *events.Bus,
+	logger *logrus.Logger,
+) *ChainMonitor {
+	m := &ChainMonitor{
+		chainRepo:      chainRepo,
+		network:        models.NetworkOrDefault(network),
+		stallThreshold: stallThreshold,
+		eventBus:       eventBus,
+		logger:         logger,
+	}
+	for _, address := range grpcAddresses {
+		m.sources = append(m.sources, newGRPCChainSource(grpcChecker, address))
+	}
+	for _, address := range jsonrpcAddresses {
+		m.sources = append(m.sources, newJSONRPCChainSource(jsonrpcMonitor, address))
+	}
+	return m
+}
+
+// Enabled reports whether any trusted server is configured
+func (m *ChainMonitor) Enabled() bool {
+	return len(m.sources) > 0
+}
+
+// Close closes the connections to the trusted servers
+func (m *ChainMonitor) Close() {
+	for _, source := range m.sources {
+		if closer, ok := source.(interface{ Close() error }); ok {
+			closer.Close()
+		}
+	}
+}
+
+// Poll reads the tip of every trusted server, records the blocks since the
+// last poll from the highest one and opens or closes stall and fork
+// incidents
+func (m *ChainMonitor) Poll(ctx context.Context) error {
+	if !m.Enabled() {
+		return nil
+	}
+	now := time.Now()
+
+	heads := m.pollHeads(ctx, now)
+	statuses := make([]*models.ChainSource, 0, len(heads))
+	var live []*sourceHead
+	for _, head := range heads {
+		statuses = append(statuses, head.status)
+		if head.tip != nil {
+			live = append(live, head)
+		}
+	}
+	if err := m.chainRepo.RecordSources(ctx, m.network, statuses); err != nil {
+		return err
+	}
+	if len(live) == 0 {
+		return fmt.Errorf("no trusted server answered")
+	}
+
+	last, err := m.chainRepo.GetLastBlock(ctx, m.network)
+	if err != nil {
+		return err
+	}
+	recordErr := m.recordBlocks(ctx, highestHead(live), last)
+
+	if err := m.checkStall(ctx, latestTip(live), now); err != nil {
+		return err
+	}
+	if err := m.checkFork(ctx, live, now); err != nil {
+		return err
+	}
+	return recordErr
+}
+
+// pollHeads reads the tip block of every trusted server
+func (m *ChainMonitor) pollHeads(ctx context.Context, now time.Time) []*sourceHead {
+	heads := make([]*sourceHead, 0, len(m.sources))
+	for _, source := range m.sources {
+		head := &sourceHead{
+			source: source,
+			status: &models.ChainSource{Address: source.Address(), Kind: source.Kind(), CheckedAt: now},
+		}
+		heads = append(heads, head)
+
+		height, err := source.Tip(ctx)
+		if err == nil {
+			head.tip, err = source.Block(ctx, height)
+		}
+		if err != nil {
+			head.status.Error = err.Error()
+			m.logger.WithError(err).WithField("source", source.Address()).Warn("Failed to read chain tip")
+			continue
+		}
+		head.status.Height = head.tip.Height
+		head.status.Hash = head.tip.Hash
+	}
+	return heads
+}
+
+// recordBlocks records the blocks after last up to the tip of head.
+// Blocks fetched before a failure are still recorded.
+func (m *ChainMonitor) recordBlocks(ctx context.Context, head *sourceHead, last *models.ChainBlock) error {
+	var lastHeight uint32
+	if last != nil {
+		lastHeight = last.Height
+	}
+
+	from, to := blockRange(lastHeight, head.tip.Height, MaxChainBlocksPerPoll)
+	var blocks []*models.ChainBlock
+	var fetchErr error
+	for height := from; height <= to; height++ {
+		if height == head.tip.Height {
+			blocks = append(blocks, head.tip)
+			continue
+		}
+		block, err := head.source.Block(ctx, height)
+		if err != nil {
+			fetchErr = err
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return fetchErr
+	}
+
+	withGaps(last, blocks)
+	if err := m.chainRepo.RecordBlocks(ctx, m.network, blocks); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// checkStall opens a stall incident when the newest block any server has
+// is older than the stall threshold, and closes it once a new block arrives
+func (m *ChainMonitor) checkStall(ctx context.Context, latest *models.ChainBlock, now time.Time) error {
+	open, err := m.chainRepo.GetOpenIncident(ctx, m.network, models.ChainIncidentStall)
+	if err != nil {
+		return err
+	}
+
+	stalledFor := now.Sub(latest.BlockTime)
+	switch {
+	case stalledFor > m.stallThreshold && open == nil:
+		blockTime := latest.BlockTime
+		incident := &models.ChainIncident{
+			Kind:          models.ChainIncidentStall,
+			Height:        latest.Height,
+			LastBlockTime: &blockTime,
+			StartedAt:     now,
+		}
+		if err := m.chainRepo.OpenIncident(ctx, m.network, incident); err != nil {
+			return err
+		}
+		m.publish(ctx, events.ChainStalled, events.ChainIncident{
+			Kind:          models.ChainIncidentStall,
+			Height:        latest.Height,
+			LastBlockTime: &blockTime,
+			Seconds:       int(stalledFor.Seconds()),
+		})
+
+	case stalledFor <= m.stallThreshold && open != nil:
+		if err := m.chainRepo.CloseIncident(ctx, open.ID, now); err != nil {
+			return err
+		}
+		blockTime := latest.BlockTime
+		data := events.ChainIncident{
+			Kind:          models.ChainIncidentStall,
+			Height:        latest.Height,
+			LastBlockTime: &blockTime,
+		}
+		if open.LastBlockTime != nil {
+			data.Seconds = int(latest.BlockTime.Sub(*open.LastBlockTime).Seconds())
+		}
+		m.publish(ctx, events.ChainRecovered, data)
+	}
+	return nil
+}
+
+// checkFork compares the hashes the servers report at the highest height
+// they all reached. It needs at least two answering servers.
+func (m *ChainMonitor) checkFork(ctx context.Context, live []*sourceHead, now time.Time) error {
+	if len(live) < 2 {
+		return nil
+	}
+
+	common := live[0].tip.Height
+	for _, head := range live[1:] {
+		if head.tip.Height < common {
+			common = head.tip.Height
+		}
+	}
+
+	hashes := make(map[string]string, len(live))
+	for _, head := range live {
+		if head.tip.Height == common {
+			hashes[head.source.Address()] = head.tip.Hash
+			continue
+		}
+		block, err := head.source.Block(ctx, common)
+		if err != nil {
+			m.logger.WithError(err).WithField("source", head.source.Address()).Warn("Failed to read block for fork check")
+			continue
+		}
+		hashes[head.source.Address()] = block.Hash
+	}
+	if len(hashes) < 2 {
+		return nil
+	}
+
+	open, err := m.chainRepo.GetOpenIncident(ctx, m.network, models.ChainIncidentFork)
+	if err != nil {
+		return err
+	}
+
+	forked := distinctHashes(hashes) > 1
+	switch {
+	case forked && open == nil:
+		incident := &models.ChainIncident{
+			Kind:      models.ChainIncidentFork,
+			Height:    common,
+			Hashes:    hashes,
+			StartedAt: now,
+		}
+		if err := m.chainRepo.OpenIncident(ctx, m.network, incident); err != nil {
+			return err
+		}
+		m.publish(ctx, events.ChainForked, events.ChainIncident{
+			Kind:   models.ChainIncidentFork,
+			Height: common,
+			Hashes: hashes,
+		})
+
+	case !forked && open != nil:
+		if err := m.chainRepo.CloseIncident(ctx, open.ID, now); err != nil {
+			return err
+		}
+		m.publish(ctx, events.ChainForkResolved, events.ChainIncident{
+			Kind:   models.ChainIncidentFork,
+			Height: common,
+		})
+	}
+	return nil
+}
+
+func (m *ChainMonitor) publish(ctx context.Context, eventType events.Type, data events.ChainIncident) {
+	data.Network = m.network
+	m.eventBus.Publish(ctx, events.Event{Type: eventType, Data: data})
+}
+
+// GetChainStats returns the chain tip of a network, its trusted servers,
+// block statistics over the last hours and the incidents since then
+func (m *ChainMonitor) GetChainStats(ctx context.Context, network string, hours int) (*models.ChainStats, error) {
+	if hours <= 0 {
+		hours = DefaultChainStatsHours
+	}
+	if hours > MaxChainStatsHours {
+		hours = MaxChainStatsHours
+	}
+	now := time.Now()
+	since := now.Add(-time.Duration(hours) * time.Hour)
+
+	window, err := m.chainRepo.GetBlockStats(ctx, network, since)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := m.chainRepo.GetSources(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	incidents, err := m.chainRepo.GetIncidents(ctx, network, since)
+	if err != nil {
+		return nil, err
+	}
+	last, err := m.chainRepo.GetLastBlock(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ChainStats{
+		Network:   network,
+		Since:     since,
+		Window:    *window,
+		Sources:   []*models.ChainSource{},
+		Incidents: []*models.ChainIncident{},
+	}
+	if last != nil {
+		stats.Height = last.Height
+		stats.Hash = last.Hash
+		stats.LastBlockTime = &last.BlockTime
+		stats.SecondsSinceLastBlock = int(now.Sub(last.BlockTime).Seconds())
+	}
+	setSourceLag(sources)
+	stats.Sources = append(stats.Sources, sources...)
+	for _, incident := range incidents {
+		if incident.EndedAt == nil {
+			stats.Stalled = stats.Stalled || incident.Kind == models.ChainIncidentStall
+			stats.Forked = stats.Forked || incident.Kind == models.ChainIncidentFork
+		}
+		stats.Incidents = append(stats.Incidents, incident)
+	}
+
+	return stats, nil
+}
+
+// highestHead returns the head with the highest tip, the first configured
+// one on a tie
+func highestHead(heads []*sourceHead) *sourceHead {
+	highest := heads[0]
+	for _, head := range heads[1:] {
+		if head.tip.Height > highest.tip.Height {
+			highest = head
+		}
+	}
+	return highest
+}
+
+// latestTip returns the newest tip block of the heads
+func latestTip(heads []*sourceHead) *models.ChainBlock {
+	latest := heads[0].tip
+	for _, head := range heads[1:] {
+		if head.tip.BlockTime.After(latest.BlockTime) {
+			latest = head.tip
+		}
+	}
+	return latest
+}
+
+// withGaps sets the gap of each block that directly follows the previous
+// one. prev is the block recorded before blocks, if any.
+func withGaps(prev *models.ChainBlock, blocks []*models.ChainBlock) {
+	for _, block := range blocks {
+		if prev != nil && prev.Height+1 == block.Height {
+			gap := int(block.BlockTime.Sub(prev.BlockTime).Seconds())
+			block.GapSeconds = &gap
+		}
+		prev = block
+	}
+}
+
+// distinctHashes counts the different hashes reported
+func distinctHashes(hashes map[string]string) int {
+	seen := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		seen[hash] = true
+	}
+	return len(seen)
+}
+
+// setSourceLag sets how many blocks each answering server is behind the
+// highest one
+func setSourceLag(sources []*models.ChainSource) {
+	var highest uint32
+	for _, source := range sources {
+		if source.Error == "" && source.Height > highest {
+			highest = source.Height
+		}
+	}
+	for _, source := range sources {
+		if source.Error == "" {
+			source.Lag = highest - source.Height
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// fakeChainSource serves blocks ten seconds apart ending at tip, which was
+// produced tipAge ago. Blocks from forkAt on get a different hash.
+type fakeChainSource struct {
+	address string
+	tip     uint32
+	tipAge  time.Duration
+	forkAt  uint32
+	down    bool
+}
+
+func (s *fakeChainSource) Address() string { return s.address }
+
+func (s *fakeChainSource) Kind() string { return models.NodeTypeGRPC }
+
+func (s *fakeChainSource) Tip(ctx context.Context) (uint32, error) {
+	if s.down {
+		return 0, fmt.Errorf("connection refused")
+	}
+	return s.tip, nil
+}
+
+func (s *fakeChainSource) Block(ctx context.Context, height uint32) (*models.ChainBlock, error) {
+	if height > s.tip {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	hash := fmt.Sprintf("hash-%d", height)
+	if s.forkAt > 0 && height >= s.forkAt {
+		hash += "-fork"
+	}
+	tipTime := time.Now().Add(-s.tipAge).Truncate(time.Second)
+	return &models.ChainBlock{
+		Height:    height,
+		Hash:      hash,
+		BlockTime: tipTime.Add(-time.Duration(s.tip-height) * 10 * time.Second),
+		TxCount:   int(height % 3),
+		Source:    s.address,
+	}, nil
+}
+
+// memoryChainRepository keeps blocks, sources and incidents in memory
+type memoryChainRepository struct {
+	repositories.ChainRepository
+	blocks    []*models.ChainBlock
+	sources   []*models.ChainSource
+	incidents []*models.ChainIncident
+}
+
+func (r *memoryChainRepository) GetLastBlock(ctx context.Context, network string) (*models.ChainBlock, error) {
+	if len(r.blocks) == 0 {
+		return nil, nil
+	}
+	return r.blocks[len(r.blocks)-1], nil
+}
+
+func (r *memoryChainRepository) RecordBlocks(ctx context.Context, network string, blocks []*models.ChainBlock) error {
+	r.blocks = append(r.blocks, blocks...)
+	return nil
+}
+
+func (r *memoryChainRepository) RecordSources(ctx context.Context, network string, sources []*models.ChainSource) error {
+	r.sources = sources
+	return nil
+}
+
+func (r *memoryChainRepository) GetOpenIncident(ctx context.Context, network, kind string) (*models.ChainIncident, error) {
+	for _, incident := range r.incidents {
+		if incident.Kind == kind && incident.EndedAt == nil {
+			return incident, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryChainRepository) OpenIncident(ctx context.Context, network string, incident *models.ChainIncident) error {
+	incident.ID = len(r.incidents) + 1
+	r.incidents = append(r.incidents, incident)
+	return nil
+}
+
+func (r *memoryChainRepository) CloseIncident(ctx context.Context, id int, endedAt time.Time) error {
+	r.incidents[id-1].EndedAt = &endedAt
+	return nil
+}
+
+func TestChainMonitor_Poll(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	bus := events.NewBus(logger)
+	defer bus.Close()
+	var published []events.Type
+	for _, eventType := range []events.Type{events.ChainStalled, events.ChainRecovered, events.ChainForked, events.ChainForkResolved} {
+		events.On(bus, eventType, "test", events.Sync, func(ctx context.Context, event events.Event, data events.ChainIncident) error {
+			if data.Network != "testnet" {
+				t.Errorf("Expected a testnet incident, got %q", data.Network)
+			}
+			published = append(published, event.Type)
+			return nil
+		})
+	}
+
+	primary := &fakeChainSource{address: "primary:50051", tip: 100, tipAge: 5 * time.Second}
+	secondary := &fakeChainSource{address: "secondary:50051", tip: 99, tipAge: 15 * time.Second}
+	chainRepo := &memoryChainRepository{}
+	monitor := NewChainMonitor(chainRepo, nil, nil, nil, nil, "testnet", 2*time.Minute, bus, logger)
+	monitor.sources = []chainSource{secondary, primary}
+	ctx := context.Background()
+
+	// The first poll records the newest blocks from the highest server
+	if err := monitor.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(chainRepo.blocks) != 100 || chainRepo.blocks[99].Source != "primary:50051" {
+		t.Fatalf("Expected blocks 1..100 from the primary, got %d", len(chainRepo.blocks))
+	}
+	if chainRepo.blocks[0].GapSeconds != nil || chainRepo.blocks[1].GapSeconds == nil || *chainRepo.blocks[1].GapSeconds != 10 {
+		t.Errorf("Unexpected gaps: first %v, second %v", chainRepo.blocks[0].GapSeconds, chainRepo.blocks[1].GapSeconds)
+	}
+	if len(chainRepo.sources) != 2 || chainRepo.sources[0].Height != 99 || chainRepo.sources[1].Hash != "hash-100" {
+		t.Errorf("Unexpected sources: %+v %+v", chainRepo.sources[0], chainRepo.sources[1])
+	}
+	if len(published) != 0 {
+		t.Errorf("Expected no incidents on a healthy chain, got %v", published)
+	}
+
+	// No block for five minutes is a stall, a new block ends it
+	primary.tip, primary.tipAge = 101, 5*time.Minute
+	secondary.tip, secondary.tipAge = 101, 5*time.Minute
+	if err := monitor.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	primary.tip, primary.tipAge = 102, time.Second
+	secondary.down = true
+	if err := monitor.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if chainRepo.sources[0].Error == "" {
+		t.Error("Expected the failing server's error to be recorded")
+	}
+	stall := chainRepo.incidents[0]
+	if stall.Kind != models.ChainIncidentStall || stall.Height != 101 || stall.EndedAt == nil {
+		t.Errorf("Expected a closed stall at 101, got %+v", stall)
+	}
+
+	// Servers disagreeing at the common height is a fork
+	secondary.down = false
+	secondary.tip, secondary.forkAt = 102, 102
+	primary.tip = 103
+	if err := monitor.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	fork := chainRepo.incidents[1]
+	if fork.Kind != models.ChainIncidentFork || fork.Height != 102 || len(fork.Hashes) != 2 || fork.EndedAt != nil {
+		t.Errorf("Expected an open fork at 102, got %+v", fork)
+	}
+	secondary.forkAt = 0
+	if err := monitor.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if fork.EndedAt == nil {
+		t.Error("Expected the fork to be resolved")
+	}
+
+	want := []events.Type{events.ChainStalled, events.ChainRecovered, events.ChainForked, events.ChainForkResolved}
+	if fmt.Sprint(published) != fmt.Sprint(want) {
+		t.Errorf("Expected events %v, got %v", want, published)
+	}
+
+	// With no server answering nothing can be decided
+	primary.down, secondary.down = true, true
+	if err := monitor.Poll(ctx); err == nil {
+		t.Error("Expected an error when no server answers")
+	}
+}
+
+func TestSetSourceLag(t *testing.T) {
+	sources := []*models.ChainSource{
+		{Address: "a", Height: 100},
+		{Address: "b", Height: 97},
+		{Address: "c", Error: "connection refused"},
+	}
+	setSourceLag(sources)
+
+	if sources[0].Lag != 0 || sources[1].Lag != 3 || sources[2].Lag != 0 {
+		t.Errorf("Unexpected lags: %d %d %d", sources[0].Lag, sources[1].Lag, sources[2].Lag)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"google.golang.org/grpc"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// chainSource reads block headers from a trusted server
+type chainSource interface {
+	Address() string
+	Kind() string
+	// Tip returns the height of the server's latest block
+	Tip(ctx context.Context) (uint32, error)
+	// Block returns the header of the block at a height
+	Block(ctx context.Context, height uint32) (*models.ChainBlock, error)
+}
+
+// grpcChainSource reads blocks over gRPC. The connection is opened on
+// first use and kept; gRPC reconnects it when the server comes back.
+type grpcChainSource struct {
+	checker *GRPCChecker
+	address string
+
+	mu   sync.Mutex
+	conn *grpc.ClientConn
+}
+
+func newGRPCChainSource(checker *GRPCChecker, address string) *grpcChainSource {
+	return &grpcChainSource{checker: checker, address: address}
+}
+
+func (s *grpcChainSource) Address() string { return s.address }
+
+func (s *grpcChainSource) Kind() string { return models.NodeTypeGRPC }
+
+func (s *grpcChainSource) client(ctx context.Context) (pactus.BlockchainClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.checker.Connect(ctx, s.address)
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	return pactus.NewBlockchainClient(s.conn), nil
+}
+
+func (s *grpcChainSource) Tip(ctx context.Context) (uint32, error) {
+	chain, err := s.client(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := chain.GetBlockchainInfo(ctx, &pactus.GetBlockchainInfoRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("get blockchain info: %w", err)
+	}
+	return info.LastBlockHeight, nil
+}
+
+func (s *grpcChainSource) Block(ctx context.Context, height uint32) (*models.ChainBlock, error) {
+	chain, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := chain.GetBlock(ctx, &pactus.GetBlockRequest{
+		Height:    height,
+		Verbosity: pactus.BlockVerbosity_BLOCK_VERBOSITY_TRANSACTIONS,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get block %d: %w", height, err)
+	}
+
+	b := &models.ChainBlock{
+		Height:    block.Height,
+		Hash:      block.Hash,
+		BlockTime: time.Unix(int64(block.BlockTime), 0),
+		TxCount:   len(block.Txs),
+		Source:    s.address,
+	}
+	if block.Header != nil {
+		b.ProposerAddress = block.Header.ProposerAddress
+	}
+	return b, nil
+}
+
+// Close closes the connection if one was opened
+func (s *grpcChainSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// jsonrpcChainSource reads blocks over Pactus JSON-RPC
+type jsonrpcChainSource struct {
+	monitor *JSONRPCMonitorService
+	address string
+}
+
+func newJSONRPCChainSource(monitor *JSONRPCMonitorService, address string) *jsonrpcChainSource {
+	return &jsonrpcChainSource{monitor: monitor, address: address}
+}
+
+func (s *jsonrpcChainSource) Address() string { return s.address }
+
+func (s *jsonrpcChainSource) Kind() string { return models.NodeTypeJSONRPC }
+
+func (s *jsonrpcChainSource) Tip(ctx context.Context) (uint32, error) {
+	height, err := s.monitor.callBlockchainInfo(ctx, s.address)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(height), nil
+}
+
+func (s *jsonrpcChainSource) Block(ctx context.Context, height uint32) (*models.ChainBlock, error) {
+	result, err := s.monitor.callMethodWithParams(ctx, s.address, "pactus.blockchain.get_block", map[string]interface{}{
+		"height":    height,
+		"verbosity": int(pactus.BlockVerbosity_BLOCK_VERBOSITY_TRANSACTIONS),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get block %d: %w", height, err)
+	}
+
+	hash, _ := result["hash"].(string)
+	if hash == "" {
+		return nil, fmt.Errorf("get block %d: no block in response", height)
+	}
+	blockTime, _ := result["block_time"].(float64)
+	txs, _ := result["txs"].([]interface{})
+
+	b := &models.ChainBlock{
+		Height:    height,
+		Hash:      hash,
+		BlockTime: time.Unix(int64(blockTime), 0),
+		TxCount:   len(txs),
+		Source:    s.address,
+	}
+	if header, ok := result["header"].(map[string]interface{}); ok {
+		b.ProposerAddress, _ = header["proposer_address"].(string)
+	}
+	return b, nil
+}
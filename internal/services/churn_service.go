@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// PeerSessionGap is how long a peer may go unseen before its session ends.
+// Peers are crawled once a day, so it leaves room for one late crawl.
+const PeerSessionGap = 36 * time.Hour
+
+// DefaultChurnWindowDays is the range getChurnStats covers by default
+const DefaultChurnWindowDays = 30
+
+// MaxChurnWindowDays caps the range of getChurnStats
+const MaxChurnWindowDays = 90
+
+// SnapshotChurnDays is the range of the churn stats stored with each
+// network snapshot
+const SnapshotChurnDays = 7
+
+// sessionBuckets are the session length ranges reported by getChurnStats
+var sessionBuckets = []models.SessionBucket{
+	{Label: "<1d", MinHours: 0, MaxHours: 24},
+	{Label: "1-7d", MinHours: 24, MaxHours: 7 * 24},
+	{Label: "7-30d", MinHours: 7 * 24, MaxHours: 30 * 24},
+	{Label: ">=30d", MinHours: 30 * 24},
+}
+
+// ChurnService tracks the sessions of crawled peers and derives joins,
+// leaves, rejoins, lifespans and session lengths from them
+type ChurnService struct {
+	churnRepo repositories.ChurnRepository
+	logger    *logrus.Logger
+}
+
+// NewChurnService creates a new churn service
+func NewChurnService(churnRepo repositories.ChurnRepository, logger *logrus.Logger) *ChurnService {
+	return &ChurnService{
+		churnRepo: churnRepo,
+		logger:    logger,
+	}
+}
+
+// RecordSightings extends or starts the session of each crawled peer.
+// Peers must have been stored first so their ID is set.
+func (s *ChurnService) RecordSightings(ctx context.Context, peers []*models.ReachablePeer) error {
+	for _, peer := range peers {
+		if peer == nil || peer.ID == 0 || peer.LastSeen.IsZero() {
+			continue
+		}
+		if err := s.churnRepo.RecordSighting(ctx, peer.ID, peer.LastSeen, PeerSessionGap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetChurnStats returns the churn of a network over the days from..to, both
+// inclusive. A zero to is today and a zero from is DefaultChurnWindowDays
+// before to.
+func (s *ChurnService) GetChurnStats(ctx context.Context, network string, from, to time.Time) (*models.ChurnStats, error) {
+	to = startOfDay(to)
+	if to.IsZero() {
+		to = startOfDay(time.Now())
+	}
+	from = startOfDay(from)
+	if from.IsZero() || from.After(to) {
+		from = to.AddDate(0, 0, -(DefaultChurnWindowDays - 1))
+	}
+	if earliest := to.AddDate(0, 0, -(MaxChurnWindowDays - 1)); from.Before(earliest) {
+		from = earliest
+	}
+	end := to.AddDate(0, 0, 1)
+
+	sessions, err := s.churnRepo.GetSessions(ctx, network, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := buildChurnStats(sessions, from, end, time.Now())
+	stats.Network = network
+	return stats, nil
+}
+
+// buildChurnStats aggregates the sessions overlapping [from, end) per UTC
+// day. A session counts as a leave once it has not been extended for
+// PeerSessionGap, so the leaves of the last day or two are provisional.
+func buildChurnStats(sessions []*models.PeerSession, from, end, now time.Time) *models.ChurnStats {
+	stats := &models.ChurnStats{
+		From:           from.Format("2006-01-02"),
+		To:             end.AddDate(0, 0, -1).Format("2006-01-02"),
+		SessionLengths: make([]*models.SessionBucket, len(sessionBuckets)),
+		Days:           []*models.ChurnDay{},
+	}
+	for i := range sessionBuckets {
+		bucket := sessionBuckets[i]
+		stats.SessionLengths[i] = &bucket
+	}
+
+	var dayStarts []time.Time
+	dayIndex := make(map[string]int)
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dayIndex[date] = len(stats.Days)
+		dayStarts = append(dayStarts, day)
+		stats.Days = append(stats.Days, &models.ChurnDay{Date: date})
+	}
+	active := make([]map[int]bool, len(stats.Days))
+	for i := range active {
+		active[i] = make(map[int]bool)
+	}
+
+	lastSeen := make(map[int]time.Time)
+	firstSeen := make(map[int]time.Time)
+	var sessionHours []float64
+	for _, session := range sessions {
+		for i, dayStart := range dayStarts {
+			if session.StartedAt.Before(dayStart.AddDate(0, 0, 1)) && !session.LastSeen.Before(dayStart) {
+				active[i][session.PeerID] = true
+			}
+		}
+
+		if i, ok := dayIndex[session.StartedAt.UTC().Format("2006-01-02")]; ok {
+			if session.First {
+				stats.Days[i].Joins++
+				stats.Joins++
+			} else {
+				stats.Days[i].Rejoins++
+				stats.Rejoins++
+			}
+		}
+
+		if session.LastSeen.Before(now.Add(-PeerSessionGap)) {
+			if i, ok := dayIndex[session.LastSeen.UTC().Format("2006-01-02")]; ok {
+				stats.Days[i].Leaves++
+				stats.Leaves++
+
+				hours := session.LastSeen.Sub(session.StartedAt).Hours()
+				sessionHours = append(sessionHours, hours)
+				for _, bucket := range stats.SessionLengths {
+					if hours >= bucket.MinHours && (bucket.MaxHours == 0 || hours < bucket.MaxHours) {
+						bucket.Sessions++
+						break
+					}
+				}
+			}
+		}
+
+		if session.LastSeen.After(lastSeen[session.PeerID]) {
+			lastSeen[session.PeerID] = session.LastSeen
+		}
+		firstSeen[session.PeerID] = session.FirstSeen
+	}
+
+	for i, peers := range active {
+		stats.Days[i].Active = len(peers)
+	}
+
+	lifespans := make([]float64, 0, len(lastSeen))
+	for peerID, seen := range lastSeen {
+		lifespans = append(lifespans, seen.Sub(firstSeen[peerID]).Hours())
+	}
+	stats.Peers = len(lifespans)
+	stats.MedianLifespanHours = medianHours(lifespans)
+	stats.MedianSessionHours = medianHours(sessionHours)
+
+	return stats
+}
+
+// medianHours returns the median of the values rounded to two decimals, or
+// 0 for none
+func medianHours(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+
+	median := values[n/2]
+	if n%2 == 0 {
+		median = (values[n/2-1] + values[n/2]) / 2
+	}
+	return math.Round(median*100) / 100
+}
+
+// startOfDay truncates t to midnight UTC, keeping the zero time zero
+func startOfDay(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+func TestBuildChurnStats(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := from.AddDate(0, 0, 5)
+	now := end.Add(12 * time.Hour)
+	at := func(day, hour int) time.Time { return from.AddDate(0, 0, day-1).Add(time.Duration(hour) * time.Hour) }
+
+	sessions := []*models.PeerSession{
+		// Peer 1 has been around since February and left on day 2
+		{PeerID: 1, StartedAt: at(-9, 2), LastSeen: at(2, 2), First: true, FirstSeen: at(-9, 2)},
+		// and came back on day 4, still connected
+		{PeerID: 1, StartedAt: at(4, 2), LastSeen: at(5, 2), FirstSeen: at(-9, 2)},
+		// Peer 2 joined on day 1 and left the same day
+		{PeerID: 2, StartedAt: at(1, 2), LastSeen: at(1, 2), First: true, FirstSeen: at(1, 2)},
+		// Peer 3 joined on day 3 and is still connected
+		{PeerID: 3, StartedAt: at(3, 2), LastSeen: at(5, 2), First: true, FirstSeen: at(3, 2)},
+	}
+
+	stats := buildChurnStats(sessions, from, end, now)
+
+	if stats.From != "2026-03-01" || stats.To != "2026-03-05" || len(stats.Days) != 5 {
+		t.Fatalf("Unexpected range %s..%s with %d days", stats.From, stats.To, len(stats.Days))
+	}
+	if stats.Peers != 3 || stats.Joins != 2 || stats.Leaves != 2 || stats.Rejoins != 1 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+
+	want := []models.ChurnDay{
+		{Date: "2026-03-01", Active: 2, Joins: 1, Leaves: 1},
+		{Date: "2026-03-02", Active: 1, Leaves: 1},
+		{Date: "2026-03-03", Active: 1, Joins: 1},
+		{Date: "2026-03-04", Active: 2, Rejoins: 1},
+		{Date: "2026-03-05", Active: 2},
+	}
+	for i, day := range stats.Days {
+		if *day != want[i] {
+			t.Errorf("Day %d: expected %+v, got %+v", i, want[i], *day)
+		}
+	}
+
+	// Lifespans are 14, 0 and 2 days; ended sessions lasted 11 days and 0
+	if stats.MedianLifespanHours != 48 {
+		t.Errorf("Expected median lifespan 48h, got %v", stats.MedianLifespanHours)
+	}
+	if stats.MedianSessionHours != 132 {
+		t.Errorf("Expected median session 132h, got %v", stats.MedianSessionHours)
+	}
+	wantBuckets := []int{1, 0, 1, 0}
+	for i, bucket := range stats.SessionLengths {
+		if bucket.Sessions != wantBuckets[i] {
+			t.Errorf("Bucket %s: expected %d sessions, got %d", bucket.Label, wantBuckets[i], bucket.Sessions)
+		}
+	}
+
+	// A session last seen within the gap has not left yet
+	recent := buildChurnStats(sessions, from, end, at(2, 20))
+	if recent.Days[1].Leaves != 0 || recent.Days[0].Leaves != 1 {
+		t.Errorf("Expected the day 2 leave to be pending, got %+v and %+v", *recent.Days[0], *recent.Days[1])
+	}
+}
+
+func TestBuildChurnStats_Empty(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	stats := buildChurnStats(nil, from, from.AddDate(0, 0, 1), from)
+
+	if len(stats.Days) != 1 || stats.Days[0].Active != 0 || stats.MedianLifespanHours != 0 {
+		t.Errorf("Unexpected stats without sessions: %+v", stats)
+	}
+	if len(stats.SessionLengths) != len(sessionBuckets) {
+		t.Errorf("Expected %d session buckets, got %d", len(sessionBuckets), len(stats.SessionLengths))
+	}
+}
+
+// memoryChurnRepository records sightings in memory
+type memoryChurnRepository struct {
+	repositories.ChurnRepository
+	sightings []int
+}
+
+func (r *memoryChurnRepository) RecordSighting(ctx context.Context, peerID int, seenAt time.Time, gap time.Duration) error {
+	r.sightings = append(r.sightings, peerID)
+	return nil
+}
+
+func TestChurnService_RecordSightings(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	churnRepo := &memoryChurnRepository{}
+	service := NewChurnService(churnRepo, logger)
+
+	seenAt := time.Now()
+	peers := []*models.ReachablePeer{
+		{ID: 1, LastSeen: seenAt},
+		{PeerID: "12D3KooWUnstored", LastSeen: seenAt},
+		{ID: 3},
+		nil,
+		{ID: 5, LastSeen: seenAt},
+	}
+	if err := service.RecordSightings(context.Background(), peers); err != nil {
+		t.Fatalf("RecordSightings: %v", err)
+	}
+
+	if len(churnRepo.sightings) != 2 || churnRepo.sightings[0] != 1 || churnRepo.sightings[1] != 5 {
+		t.Errorf("Expected sightings of peers 1 and 5, got %v", churnRepo.sightings)
+	}
+}
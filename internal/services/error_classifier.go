@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// HTTPStatusError reports an unexpected HTTP status from an endpoint
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// RPCError reports an error returned by a remote procedure call
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// ResponseError reports a response that does not follow the expected protocol
+type ResponseError struct {
+	Reason string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("invalid response: %s", e.Reason)
+}
+
+// AddressError reports a node address that cannot be parsed
+type AddressError struct {
+	Address string
+	Reason  string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("failed to parse address: %s", e.Reason)
+}
+
+// ClassifyError maps a check failure to an error class
+func ClassifyError(err error) models.ErrorClass {
+	if err == nil {
+		return models.ErrorClassNone
+	}
+
+	var addrErr *AddressError
+	if errors.As(err, &addrErr) {
+		return models.ErrorClassInvalidAddress
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return models.ErrorClassTimeout
+		}
+		return models.ErrorClassDNS
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return models.ErrorClassConnectionRefused
+	}
+
+	if isTLSError(err) {
+		return models.ErrorClassTLS
+	}
+
+	var httpErr *HTTPStatusError
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode >= 500 {
+			return models.ErrorClassHTTP5xx
+		}
+		return models.ErrorClassHTTP
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return models.ErrorClassRPC
+	}
+
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return models.ErrorClassRPC
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.ErrorClassTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return models.ErrorClassTimeout
+	}
+
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unavailable && s.Code() != codes.Unknown {
+		if s.Code() == codes.DeadlineExceeded {
+			return models.ErrorClassTimeout
+		}
+		return models.ErrorClassRPC
+	}
+
+	// gRPC and some transports only keep the cause in the message
+	return classifyMessage(err.Error())
+}
+
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &invalidErr) ||
+		errors.As(err, &hostnameErr)
+}
+
+func classifyMessage(msg string) models.ErrorClass {
+	msg = strings.ToLower(msg)
+
+	switch {
+	case strings.Contains(msg, "no such host"), strings.Contains(msg, "server misbehaving"):
+		return models.ErrorClassDNS
+	case strings.Contains(msg, "connection refused"):
+		return models.ErrorClassConnectionRefused
+	case strings.Contains(msg, "tls"), strings.Contains(msg, "x509"), strings.Contains(msg, "certificate"):
+		return models.ErrorClassTLS
+	case strings.Contains(msg, "deadline exceeded"), strings.Contains(msg, "timeout"), strings.Contains(msg, "timed out"):
+		return models.ErrorClassTimeout
+	}
+
+	return models.ErrorClassUnknown
+}
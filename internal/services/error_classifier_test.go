@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect models.ErrorClass
+	}{
+		{name: "Nil", err: nil, expect: models.ErrorClassNone},
+		{name: "Address", err: &AddressError{Address: "x", Reason: "bad"}, expect: models.ErrorClassInvalidAddress},
+		{name: "DNS", err: fmt.Errorf("dial: %w", &net.DNSError{Err: "no such host", Name: "x.invalid"}), expect: models.ErrorClassDNS},
+		{name: "DNS timeout", err: &net.DNSError{Err: "timeout", IsTimeout: true}, expect: models.ErrorClassTimeout},
+		{name: "Connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, expect: models.ErrorClassConnectionRefused},
+		{name: "Deadline", err: fmt.Errorf("failed to connect: %w", context.DeadlineExceeded), expect: models.ErrorClassTimeout},
+		{name: "TLS", err: x509.UnknownAuthorityError{}, expect: models.ErrorClassTLS},
+		{name: "HTTP 5xx", err: &HTTPStatusError{StatusCode: 502}, expect: models.ErrorClassHTTP5xx},
+		{name: "HTTP 4xx", err: &HTTPStatusError{StatusCode: 404}, expect: models.ErrorClassHTTP},
+		{name: "JSON-RPC error", err: &RPCError{Code: -32601, Message: "method not found"}, expect: models.ErrorClassRPC},
+		{name: "gRPC error", err: fmt.Errorf("ping failed: %w", status.Error(codes.Unimplemented, "unknown service")), expect: models.ErrorClassRPC},
+		{name: "gRPC unavailable refused", err: status.Error(codes.Unavailable, "dial tcp 1.2.3.4:50051: connect: connection refused"), expect: models.ErrorClassConnectionRefused},
+		{name: "Unknown", err: errors.New("something odd"), expect: models.ErrorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.expect {
+				t.Errorf("Expected %q, got %q", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestValidateJSONRPCEndpoint_ClassifiesFailures(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/error":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+		case "/html":
+			w.Write([]byte(`<html>Welcome to nginx!</html>`))
+		case "/empty":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"last_block_height":42}}`))
+		}
+	}))
+	defer server.Close()
+
+	svc := NewJSONRPCMonitorService(nil, nil, nil, nil, nil, nil, nil, nil, 5, logger)
+	svc.policy.MaxAttempts = 2
+	svc.policy.InitialDelay = time.Millisecond
+
+	ok := svc.ValidateJSONRPCEndpoint(context.Background(), server.URL+"/ok")
+	if !ok.Success || ok.BlockHeight != 42 || ok.Attempts != 1 {
+		t.Errorf("Expected success with height 42 on first attempt, got %+v", ok)
+	}
+
+	down := svc.ValidateJSONRPCEndpoint(context.Background(), server.URL+"/down")
+	if down.Success || down.ErrorClass != models.ErrorClassHTTP5xx || down.Attempts != 2 {
+		t.Errorf("Expected http_5xx after 2 attempts, got %+v", down)
+	}
+
+	rpcErr := svc.ValidateJSONRPCEndpoint(context.Background(), server.URL+"/error")
+	if rpcErr.Success || rpcErr.ErrorClass != models.ErrorClassRPC {
+		t.Errorf("Expected rpc error class, got %+v", rpcErr)
+	}
+
+	// A 200 answer that is not a JSON-RPC result is a failure
+	for _, path := range []string{"/html", "/empty"} {
+		invalid := svc.ValidateJSONRPCEndpoint(context.Background(), server.URL+path)
+		if invalid.Success || invalid.ErrorClass != models.ErrorClassRPC {
+			t.Errorf("Expected %s to fail with rpc error class, got %+v", path, invalid)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/metrics"
+)
+
+// maxGeoPending caps the nodes waiting for a geo lookup. Nodes arriving
+// while the queue is full are dropped; the geo updates fill in nodes still
+// missing a location.
+const maxGeoPending = 1024
+
+// geoKey identifies a node waiting for geo enrichment
+type geoKey struct {
+	nodeType string
+	nodeID   int
+}
+
+// EventHandlers holds the side effects that react to monitor and
+// registration events: scoring, geo enrichment, metrics and alerting
+type EventHandlers struct {
+	bootstrapRepo repositories.BootstrapRepository
+	grpcRepo      repositories.GRPCRepository
+	jsonrpcRepo   repositories.JSONRPCServerRepository
+	peerRepo      repositories.PeerRepository
+	geoService    *GeoLocationService
+	metrics       *metrics.Metrics
+	logger        *logrus.Logger
+
+	// Nodes waiting for a geo lookup, coalesced by node so that a burst of
+	// events costs one lookup per node
+	geoMu      sync.Mutex
+	geoPending map[geoKey]string
+	geoOrder   []geoKey
+	geoWake    chan struct{}
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NewEventHandlers creates the event handlers. A nil geo service disables
+// geo enrichment.
+func NewEventHandlers(
+	bootstrapRepo repositories.BootstrapRepository,
+	grpcRepo repositories.GRPCRepository,
+	jsonrpcRepo repositories.JSONRPCServerRepository,
+	peerRepo repositories.PeerRepository,
+	geoService *GeoLocationService,
+	metrics *metrics.Metrics,
+	logger *logrus.Logger,
+) *EventHandlers {
+	return &EventHandlers{
+		bootstrapRepo: bootstrapRepo,
+		grpcRepo:      grpcRepo,
+		jsonrpcRepo:   jsonrpcRepo,
+		peerRepo:      peerRepo,
+		geoService:    geoService,
+		metrics:       metrics,
+		logger:        logger,
+		geoPending:    make(map[geoKey]string),
+		geoWake:       make(chan struct{}, 1),
+	}
+}
+
+// Start runs the geo lookup worker, which resolves queued nodes one at a
+// time
+func (h *EventHandlers) Start() {
+	if h.geoService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+
+	go func() {
+		defer close(h.done)
+		h.runGeoLookups(ctx)
+	}()
+}
+
+// Stop ends the geo lookup worker. Nodes still queued are not resolved.
+func (h *EventHandlers) Stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	<-h.done
+}
+
+// Register subscribes the handlers to the bus. Scoring and metrics run
+// synchronously so a node's score is current once its check returns; geo
+// enrichment only queues the node for the lookup worker, and alerts run in
+// the background.
+func (h *EventHandlers) Register(bus *events.Bus) {
+	events.On(bus, events.CheckCompleted, "scoring", events.Sync, h.UpdateScore)
+	events.On(bus, events.CheckCompleted, "check-metrics", events.Sync, h.RecordCheck)
+	events.On(bus, events.SyncFinished, "active-metrics", events.Sync, h.RecordSync)
+	events.On(bus, events.RegistrationApproved, "active-metrics", events.Sync, h.RecordApproval)
+	events.On(bus, events.NodeStatusChanged, "status-alerts", events.Async, h.AlertStatusChange)
+	events.On(bus, events.NodeDeactivated, "deactivation-alerts", events.Async, h.AlertDeactivated)
+	for _, eventType := range []events.Type{events.ChainStalled, events.ChainRecovered, events.ChainForked, events.ChainForkResolved} {
+		events.On(bus, eventType, "chain-alerts", events.Async, h.AlertChain)
+	}
+	events.On(bus, events.CertificateExpiring, "certificate-alerts", events.Async, h.AlertCertificate)
+	if h.geoService != nil {
+		events.On(bus, events.NodeAdded, "geo-enrichment", events.Sync, h.EnrichGeo)
+		events.On(bus, events.NodeUpdated, "geo-enrichment", events.Sync, h.EnrichGeo)
+	}
+}
+
+// UpdateScore recomputes the 30-day score of the checked node
+func (h *EventHandlers) UpdateScore(ctx context.Context, event events.Event, _ events.CheckResult) error {
+	switch event.NodeType {
+	case models.NodeTypeBootstrap:
+		return h.bootstrapRepo.RefreshNodeScore(ctx, event.NodeID)
+	case models.NodeTypeGRPC:
+		return h.grpcRepo.RefreshServerScore(ctx, event.NodeID)
+	case models.NodeTypeJSONRPC:
+		return h.jsonrpcRepo.RefreshServerScore(ctx, event.NodeID)
+	}
+	return fmt.Errorf("unknown node type: %s", event.NodeType)
+}
+
+// RecordCheck counts the check and its response time
+func (h *EventHandlers) RecordCheck(_ context.Context, event events.Event, result events.CheckResult) error {
+	duration := time.Duration(result.ResponseTimeMs) * time.Millisecond
+	h.metrics.RecordNodeCheck(event.NodeType, result.Success, duration)
+	return nil
+}
+
+// RecordSync refreshes the active node count after a sync
+func (h *EventHandlers) RecordSync(ctx context.Context, event events.Event, _ events.SyncResult) error {
+	return h.refreshActiveCount(ctx, event.NodeType)
+}
+
+// RecordApproval refreshes the active node count after a registration adds
+// a node
+func (h *EventHandlers) RecordApproval(ctx context.Context, event events.Event, _ events.Registration) error {
+	return h.refreshActiveCount(ctx, event.NodeType)
+}
+
+func (h *EventHandlers) refreshActiveCount(ctx context.Context, nodeType string) error {
+	var count int
+	var err error
+	switch nodeType {
+	case models.NodeTypeBootstrap:
+		count, err = h.bootstrapRepo.GetNodeCount(ctx, true)
+	case models.NodeTypeGRPC:
+		count, err = h.grpcRepo.GetServerCount(ctx, true)
+	case models.NodeTypeJSONRPC:
+		count, err = h.jsonrpcRepo.GetServerCount(ctx, true)
+	default:
+		return fmt.Errorf("unknown node type: %s", nodeType)
+	}
+	if err != nil {
+		return err
+	}
+
+	h.metrics.UpdateActiveNodesCount(nodeType, count)
+	return nil
+}
+
+// AlertStatusChange logs an alert when a node goes down or recovers
+func (h *EventHandlers) AlertStatusChange(_ context.Context, event events.Event, change events.StatusChange) error {
+	entry := h.logger.WithFields(logrus.Fields{
+		"node_type": event.NodeType,
+		"node_id":   event.NodeID,
+		"date":      change.Date,
+		"from":      change.From,
+		"to":        change.To,
+	})
+
+	switch {
+	case change.From == 1 && change.To == 0:
+		entry.WithField("alert", "node_down").Warn("Node failed its daily check")
+	case change.From == 0 && change.To == 1:
+		entry.WithField("alert", "node_recovered").Info("Node recovered")
+	}
+	return nil
+}
+
+// AlertDeactivated logs a node that was removed from its source list or
+// deregistered by its operator
+func (h *EventHandlers) AlertDeactivated(_ context.Context, event events.Event, node events.Node) error {
+	h.logger.WithFields(logrus.Fields{
+		"alert":     "node_deactivated",
+		"node_type": event.NodeType,
+		"node_id":   event.NodeID,
+		"name":      node.Name,
+		"address":   node.Address,
+	}).Info("Node deactivated")
+	return nil
+}
+
+// AlertChain logs an alert when the chain stalls, forks or recovers
+func (h *EventHandlers) AlertChain(_ context.Context, event events.Event, incident events.ChainIncident) error {
+	entry := h.logger.WithFields(logrus.Fields{
+		"network": incident.Network,
+		"kind":    incident.Kind,
+		"height":  incident.Height,
+	})
+	if incident.Seconds > 0 {
+		entry = entry.WithField("seconds", incident.Seconds)
+	}
+	if len(incident.Hashes) > 0 {
+		entry = entry.WithField("hashes", incident.Hashes)
+	}
+
+	switch event.Type {
+	case events.ChainStalled:
+		entry.WithField("alert", "chain_stalled").Warn("No new block within the stall threshold")
+	case events.ChainRecovered:
+		entry.WithField("alert", "chain_recovered").Info("Chain is producing blocks again")
+	case events.ChainForked:
+		entry.WithField("alert", "chain_forked").Warn("Trusted servers disagree on a block hash")
+	case events.ChainForkResolved:
+		entry.WithField("alert", "chain_fork_resolved").Info("Trusted servers agree on the chain again")
+	}
+	return nil
+}
+
+// AlertCertificate logs an alert when a certificate has expired or is
+// about to
+func (h *EventHandlers) AlertCertificate(_ context.Context, event events.Event, cert events.Certificate) error {
+	entry := h.logger.WithFields(logrus.Fields{
+		"node_type":      event.NodeType,
+		"node_id":        event.NodeID,
+		"address":        cert.Address,
+		"issuer":         cert.Issuer,
+		"not_after":      cert.NotAfter,
+		"days_remaining": cert.DaysRemaining,
+	})
+
+	if cert.Expired {
+		entry.WithField("alert", "certificate_expired").Warn("TLS certificate has expired")
+	} else {
+		entry.WithField("alert", "certificate_expiring").Warn("TLS certificate expires soon")
+	}
+	return nil
+}
+
+// EnrichGeo queues a newly added or updated node or crawled peer for a geo
+// lookup. A node already queued keeps its place and takes the latest
+// address.
+func (h *EventHandlers) EnrichGeo(_ context.Context, event events.Event, node events.Node) error {
+	key := geoKey{nodeType: event.NodeType, nodeID: event.NodeID}
+
+	h.geoMu.Lock()
+	if _, queued := h.geoPending[key]; !queued {
+		if len(h.geoOrder) >= maxGeoPending {
+			h.geoMu.Unlock()
+			h.logger.WithFields(logrus.Fields{
+				"node_type": event.NodeType,
+				"node_id":   event.NodeID,
+			}).Warn("Geo lookup queue is full, dropping node")
+			return nil
+		}
+		h.geoOrder = append(h.geoOrder, key)
+	}
+	h.geoPending[key] = node.Address
+	h.geoMu.Unlock()
+
+	select {
+	case h.geoWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// nextGeoLookup takes the oldest queued node
+func (h *EventHandlers) nextGeoLookup() (geoKey, string, bool) {
+	h.geoMu.Lock()
+	defer h.geoMu.Unlock()
+
+	if len(h.geoOrder) == 0 {
+		return geoKey{}, "", false
+	}
+	key := h.geoOrder[0]
+	h.geoOrder = h.geoOrder[1:]
+	address := h.geoPending[key]
+	delete(h.geoPending, key)
+	return key, address, true
+}
+
+// runGeoLookups resolves queued nodes until ctx is cancelled
+func (h *EventHandlers) runGeoLookups(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.geoWake:
+		}
+
+		for {
+			key, address, ok := h.nextGeoLookup()
+			if !ok {
+				break
+			}
+
+			// The geo service paces lookups under the ip-api.com limit
+			if err := h.resolveGeo(ctx, key, address); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				h.logger.WithError(err).WithFields(logrus.Fields{
+					"node_type": key.nodeType,
+					"node_id":   key.nodeID,
+				}).Error("Geo enrichment failed")
+			}
+		}
+	}
+}
+
+// resolveGeo looks up and stores the location of a node
+func (h *EventHandlers) resolveGeo(ctx context.Context, key geoKey, address string) error {
+	geo, err := h.geoService.LookupAddress(ctx, address)
+	if err != nil {
+		return err
+	}
+	if geo == nil || !geo.IsValid() {
+		h.logger.WithField("address", address).Debug("Geo lookup returned no location")
+		return nil
+	}
+
+	switch key.nodeType {
+	case models.NodeTypeBootstrap:
+		err = h.bootstrapRepo.UpdateNodeGeo(ctx, key.nodeID, geo.Country, geo.CountryCode, geo.City, geo.Latitude, geo.Longitude)
+	case models.NodeTypeGRPC:
+		err = h.grpcRepo.UpdateServerGeo(ctx, key.nodeID, geo.Country, geo.CountryCode, geo.City, geo.Latitude, geo.Longitude)
+	case models.NodeTypeJSONRPC:
+		err = h.jsonrpcRepo.UpdateServerGeo(ctx, key.nodeID, geo)
+	case models.NodeTypePeer:
+		err = h.peerRepo.UpdatePeerGeo(ctx, key.nodeID, geo)
+	default:
+		return fmt.Errorf("unknown node type: %s", key.nodeType)
+	}
+	if err != nil {
+		return err
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"node_type": key.nodeType,
+		"node_id":   key.nodeID,
+		"country":   geo.Country,
+		"city":      geo.City,
+	}).Info("Resolved geo location for node")
+	return nil
+}
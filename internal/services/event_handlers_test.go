@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/metrics"
+)
+
+// scoringGRPCRepository records score refreshes; other methods are not used
+type scoringGRPCRepository struct {
+	repositories.GRPCRepository
+	refreshed []int
+}
+
+func (r *scoringGRPCRepository) RefreshServerScore(ctx context.Context, serverID int) error {
+	r.refreshed = append(r.refreshed, serverID)
+	return nil
+}
+
+func (r *scoringGRPCRepository) GetServerCount(ctx context.Context, activeOnly bool) (int, error) {
+	return len(r.refreshed), nil
+}
+
+func TestEventHandlers_ScoreFollowsChecks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	grpcRepo := &scoringGRPCRepository{}
+	handlers := NewEventHandlers(nil, grpcRepo, nil, nil, nil, metrics.NewMetrics(), logger)
+
+	bus := events.NewBus(logger)
+	defer bus.Close()
+	handlers.Register(bus)
+
+	ctx := context.Background()
+	publishCheck(ctx, bus, models.NodeTypeGRPC, 7, events.CheckResult{Color: 1, Success: true}, nil)
+	publishCheck(ctx, bus, models.NodeTypeGRPC, 9, events.CheckResult{Color: 0}, nil)
+
+	// Scoring is synchronous, so scores are current once publishing returns
+	if len(grpcRepo.refreshed) != 2 || grpcRepo.refreshed[0] != 7 || grpcRepo.refreshed[1] != 9 {
+		t.Errorf("Expected servers 7 and 9 to be rescored, got %v", grpcRepo.refreshed)
+	}
+}
+
+func TestEventHandlers_UpdateScoreRejectsUnknownType(t *testing.T) {
+	handlers := NewEventHandlers(nil, nil, nil, nil, nil, metrics.NewMetrics(), logrus.New())
+
+	err := handlers.UpdateScore(context.Background(), events.Event{NodeType: "peer", NodeID: 1}, events.CheckResult{})
+	if err == nil {
+		t.Error("Expected an error for an unknown node type")
+	}
+}
+
+func TestEventHandlers_RecordSync(t *testing.T) {
+	grpcRepo := &scoringGRPCRepository{refreshed: []int{1, 2}}
+	handlers := NewEventHandlers(nil, grpcRepo, nil, nil, nil, metrics.NewMetrics(), logrus.New())
+
+	event := events.Event{Type: events.SyncFinished, NodeType: models.NodeTypeGRPC}
+	if err := handlers.RecordSync(context.Background(), event, events.SyncResult{Added: 2}); err != nil {
+		t.Errorf("RecordSync: %v", err)
+	}
+}
+
+func TestEventHandlers_GeoEnrichmentCoalescesByNode(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// The lookup worker is not started, so queued nodes stay pending
+	handlers := NewEventHandlers(nil, nil, nil, nil, NewGeoLocationService(logger), metrics.NewMetrics(), logger)
+
+	bus := events.NewBus(logger)
+	defer bus.Close()
+	handlers.Register(bus)
+
+	ctx := context.Background()
+	for _, address := range []string{"1.1.1.1:21888", "2.2.2.2:21888", "3.3.3.3:21888"} {
+		bus.Publish(ctx, events.Event{Type: events.NodeUpdated, NodeType: models.NodeTypeGRPC, NodeID: 5, Data: events.Node{Address: address}})
+	}
+	bus.Publish(ctx, events.Event{Type: events.NodeAdded, NodeType: models.NodeTypeBootstrap, NodeID: 5, Data: events.Node{Address: "4.4.4.4:21888"}})
+
+	key, address, ok := handlers.nextGeoLookup()
+	if !ok || key.nodeType != models.NodeTypeGRPC || key.nodeID != 5 || address != "3.3.3.3:21888" {
+		t.Fatalf("Expected gRPC server 5 at its latest address first, got %v %q", key, address)
+	}
+	key, _, ok = handlers.nextGeoLookup()
+	if !ok || key.nodeType != models.NodeTypeBootstrap {
+		t.Fatalf("Expected bootstrap node 5 next, got %v", key)
+	}
+	if _, _, ok := handlers.nextGeoLookup(); ok {
+		t.Error("Expected no more queued lookups")
+	}
+}
+
+func TestEventHandlers_GeoQueueIsBounded(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	handlers := NewEventHandlers(nil, nil, nil, nil, NewGeoLocationService(logger), metrics.NewMetrics(), logger)
+	ctx := context.Background()
+	for i := 0; i < maxGeoPending+10; i++ {
+		event := events.Event{Type: events.NodeAdded, NodeType: models.NodeTypePeer, NodeID: i}
+		if err := handlers.EnrichGeo(ctx, event, events.Node{Address: "1.1.1.1:21888"}); err != nil {
+			t.Fatalf("EnrichGeo: %v", err)
+		}
+	}
+
+	// Nodes already queued still take their latest address when full
+	event := events.Event{Type: events.NodeUpdated, NodeType: models.NodeTypePeer, NodeID: 0}
+	handlers.EnrichGeo(ctx, event, events.Node{Address: "2.2.2.2:21888"})
+
+	if len(handlers.geoOrder) != maxGeoPending || len(handlers.geoPending) != maxGeoPending {
+		t.Errorf("Expected %d queued nodes, got %d", maxGeoPending, len(handlers.geoOrder))
+	}
+	if _, address, _ := handlers.nextGeoLookup(); address != "2.2.2.2:21888" {
+		t.Errorf("Expected the queued node to take its latest address, got %s", address)
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+)
+
+// publishCheck announces a completed check and, when the color differs from
+// the node's previous daily color, the status change
+func publishCheck(ctx context.Context, bus *events.Bus, nodeType string, nodeID int, result events.CheckResult, previousColor *int) {
+	bus.Publish(ctx, events.Event{
+		Type:     events.CheckCompleted,
+		NodeType: nodeType,
+		NodeID:   nodeID,
+		Data:     result,
+	})
+
+	if previousColor != nil && *previousColor != result.Color {
+		bus.Publish(ctx, events.Event{
+			Type:     events.NodeStatusChanged,
+			NodeType: nodeType,
+			NodeID:   nodeID,
+			Data:     events.StatusChange{Date: result.Date, From: *previousColor, To: result.Color},
+		})
+	}
+}
+
+// publishNode announces a node that was added to, updated in or
+// deactivated in the tracker
+func publishNode(ctx context.Context, bus *events.Bus, eventType events.Type, nodeType string, nodeID int, node events.Node) {
+	bus.Publish(ctx, events.Event{
+		Type:     eventType,
+		NodeType: nodeType,
+		NodeID:   nodeID,
+		Data:     node,
+	})
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// ExportService validates data export requests and streams their rows.
+// Exports take the filters of the matching list APIs; cursors and page
+// sizes do not apply, since an export covers every matching row.
+type ExportService struct {
+	exportRepo repositories.ExportRepository
+	logger     *logrus.Logger
+}
+
+// NewExportService creates a new export service
+func NewExportService(exportRepo repositories.ExportRepository, logger *logrus.Logger) *ExportService {
+	return &ExportService{
+		exportRepo: exportRepo,
+		logger:     logger,
+	}
+}
+
+// ExportNodes streams the active nodes of a type the filter selects
+func (s *ExportService) ExportNodes(ctx context.Context, nodeType string, filter models.NodeFilter, fn func(*models.ExportNode) error) error {
+	if err := validateExportNodes(nodeType, &filter); err != nil {
+		return err
+	}
+	if err := s.exportRepo.ExportNodes(ctx, nodeType, filter, fn); err != nil {
+		return models.NewDatabaseError("failed to export nodes", err)
+	}
+	return nil
+}
+
+// ExportStatuses streams the daily statuses between from and to
+// (inclusive) of the nodes of a type the filter selects
+func (s *ExportService) ExportStatuses(ctx context.Context, nodeType string, filter models.NodeFilter, from, to time.Time, fn func(*models.ExportStatus) error) error {
+	if err := validateExportNodes(nodeType, &filter); err != nil {
+		return err
+	}
+	if err := validateStatusRange(from, to); err != nil {
+		return err
+	}
+	if err := s.exportRepo.ExportStatuses(ctx, nodeType, filter, from, to, fn); err != nil {
+		return models.NewDatabaseError("failed to export statuses", err)
+	}
+	return nil
+}
+
+// ExportSnapshots streams the snapshots of a network, mainnet by default,
+// taken from the day from through the day to. A zero from or to leaves
+// that end open.
+func (s *ExportService) ExportSnapshots(ctx context.Context, network string, from, to time.Time, fn func(*models.NetworkSnapshot) error) error {
+	network = models.NetworkOrDefault(network)
+	if err := models.ValidateNetworkName(network); err != nil {
+		return models.NewValidationError("invalid network", err.Error())
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return models.NewValidationError("invalid date range", "from must not be after to")
+	}
+
+	var before time.Time
+	if !to.IsZero() {
+		before = to.AddDate(0, 0, 1)
+	}
+	if err := s.exportRepo.ExportSnapshots(ctx, network, from, before, fn); err != nil {
+		return models.NewDatabaseError("failed to export snapshots", err)
+	}
+	return nil
+}
+
+// ExportPeers streams the peers the filter selects. Online peers are the
+// reachable ones.
+func (s *ExportService) ExportPeers(ctx context.Context, filter models.NodeFilter, fn func(*models.ReachablePeer) error) error {
+	filter.Cursor, filter.Limit = "", 0
+	if err := filter.Validate(); err != nil {
+		return models.NewValidationError("invalid peer filter", err.Error())
+	}
+	if err := s.exportRepo.ExportPeers(ctx, filter, fn); err != nil {
+		return models.NewDatabaseError("failed to export peers", err)
+	}
+	return nil
+}
+
+// ExportMapNodes streams the map nodes of a network the filter selects
+func (s *ExportService) ExportMapNodes(ctx context.Context, filter models.MapFilter, fn func(*models.MapNode) error) error {
+	filter.Cursor, filter.Limit = "", 0
+	if err := filter.Validate(); err != nil {
+		return models.NewValidationError("invalid map filter", err.Error())
+	}
+	if err := s.exportRepo.ExportMapNodes(ctx, filter, fn); err != nil {
+		return models.NewDatabaseError("failed to export map nodes", err)
+	}
+	return nil
+}
+
+// validateExportNodes checks the node type and filter of a node export
+func validateExportNodes(nodeType string, filter *models.NodeFilter) error {
+	switch nodeType {
+	case models.NodeTypeBootstrap, models.NodeTypeGRPC, models.NodeTypeJSONRPC:
+	default:
+		return invalidNodeType(nodeType)
+	}
+
+	filter.Cursor, filter.Limit = "", 0
+	if err := filter.Validate(); err != nil {
+		return models.NewValidationError("invalid node filter", err.Error())
+	}
+	return nil
+}
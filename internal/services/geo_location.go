@@ -7,7 +7,6 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +15,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// geoLookupInterval keeps geo lookups under the ip-api.com limit of 45
+// requests per minute
+const geoLookupInterval = 1500 * time.Millisecond
+
 // GeoLocationService handles IP geolocation lookups
 type GeoLocationService struct {
 	cache    map[string]*CachedLocation
@@ -24,6 +27,12 @@ type GeoLocationService struct {
 	client   *http.Client
 	logger   *logrus.Logger
 	apiURL   string
+
+	// Lookups that miss the cache share one pace, whichever job, handler
+	// or API call makes them
+	limitMu     sync.Mutex
+	interval    time.Duration
+	nextRequest time.Time
 }
 
 // CachedLocation stores cached geo data with timestamp
@@ -40,8 +49,9 @@ func NewGeoLocationService(logger *logrus.Logger) *GeoLocationService {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		logger: logger,
-		apiURL: "http://ip-api.com/json",
+		logger:   logger,
+		apiURL:   "http://ip-api.com/json",
+		interval: geoLookupInterval,
 	}
 }
 
@@ -57,6 +67,10 @@ func (s *GeoLocationService) GetLocation(ctx context.Context, ip string) (*model
 	}
 	s.cacheMu.RUnlock()
 
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// Fetch from API
 	url := fmt.Sprintf("%s/%s?fields=status,message,country,countryCode,region,regionName,city,zip,lat,lon,timezone,isp,org,as,query", s.apiURL, ip)
 
@@ -97,26 +111,43 @@ func (s *GeoLocationService) GetLocation(ctx context.Context, ip string) (*model
 	return &geo, nil
 }
 
-// BulkGetLocations retrieves geo locations for multiple IPs with rate limiting
+// wait blocks until the next API request is allowed. The slot is taken
+// even when ctx ends first, which only makes the pace more conservative.
+func (s *GeoLocationService) wait(ctx context.Context) error {
+	s.limitMu.Lock()
+	slot := s.nextRequest
+	if now := time.Now(); slot.Before(now) {
+		slot = now
+	}
+	s.nextRequest = slot.Add(s.interval)
+	s.limitMu.Unlock()
+
+	timer := time.NewTimer(time.Until(slot))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// BulkGetLocations retrieves geo locations for multiple IPs, paced by the
+// shared rate limit
 func (s *GeoLocationService) BulkGetLocations(ctx context.Context, ips []string) (map[string]*models.GeoLocation, error) {
 	results := make(map[string]*models.GeoLocation)
 
-	// Rate limit: 45 requests per minute for free tier
-	ticker := time.NewTicker(1500 * time.Millisecond)
-	defer ticker.Stop()
-
 	for _, ip := range ips {
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		case <-ticker.C:
-			geo, err := s.GetLocation(ctx, ip)
-			if err != nil {
-				s.logger.WithError(err).WithField("ip", ip).Warn("Failed to get geo location")
-				continue
+		geo, err := s.GetLocation(ctx, ip)
+		if err != nil {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
 			}
-			results[ip] = geo
+			s.logger.WithError(err).WithField("ip", ip).Warn("Failed to get geo location")
+			continue
 		}
+		results[ip] = geo
 	}
 
 	return results, nil
@@ -124,24 +155,17 @@ func (s *GeoLocationService) BulkGetLocations(ctx context.Context, ips []string)
 
 // ExtractIPFromAddress extracts IP address from various address formats
 func (s *GeoLocationService) ExtractIPFromAddress(address string) string {
-	// Handle multiaddr format: /ip4/192.168.1.1/tcp/21888/p2p/...
-	if strings.HasPrefix(address, "/ip4/") {
-		parts := strings.Split(address, "/")
-		if len(parts) >= 3 {
-			ip := parts[2]
-			if net.ParseIP(ip) != nil {
-				return ip
-			}
+	// Handle multiaddr format: /ip4/192.168.1.1/tcp/21888/p2p/..., /ip6/...,
+	// or /dns, /dns4 and /dns6 names, which resolve in their own family
+	if strings.HasPrefix(address, "/") {
+		endpoint, err := parseMultiaddr(address)
+		if err != nil {
+			return ""
 		}
-	}
-
-	// Handle DNS multiaddr: /dns/example.com/tcp/21888/p2p/...
-	if strings.HasPrefix(address, "/dns/") || strings.HasPrefix(address, "/dns4/") {
-		parts := strings.Split(address, "/")
-		if len(parts) >= 3 {
-			host := parts[2]
-			return s.resolveHost(host)
+		if net.ParseIP(endpoint.Host) != nil {
+			return endpoint.Host
 		}
+		return s.resolveHost(endpoint.Host, endpoint.Family)
 	}
 
 	// Handle URL format: https://rpc.example.com
@@ -152,7 +176,7 @@ func (s *GeoLocationService) ExtractIPFromAddress(address string) string {
 			if ip := net.ParseIP(host); ip != nil {
 				return host
 			}
-			return s.resolveHost(host)
+			return s.resolveHost(host, models.AddressFamilyDNS)
 		}
 	}
 
@@ -163,24 +187,17 @@ func (s *GeoLocationService) ExtractIPFromAddress(address string) string {
 			if ip := net.ParseIP(host); ip != nil {
 				return host
 			}
-			return s.resolveHost(host)
-		}
-	}
-
-	// Try to extract IP directly using regex
-	ipv4Regex := regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
-	if match := ipv4Regex.FindString(address); match != "" {
-		if net.ParseIP(match) != nil {
-			return match
+			return s.resolveHost(host, models.AddressFamilyDNS)
 		}
 	}
 
 	return ""
 }
 
-// resolveHost resolves a hostname to IP address
-func (s *GeoLocationService) resolveHost(host string) string {
-	ips, err := net.LookupIP(host)
+// resolveHost resolves a hostname to an IP address of the given family,
+// preferring IPv4 when the name may resolve to either
+func (s *GeoLocationService) resolveHost(host, family string) string {
+	ips, err := net.DefaultResolver.LookupIP(context.Background(), lookupNetwork(family), host)
 	if err != nil || len(ips) == 0 {
 		s.logger.WithError(err).WithField("host", host).Debug("Failed to resolve hostname")
 		return ""
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestGeoLocationService_PacesLookups(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var mu sync.Mutex
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		mu.Unlock()
+		w.Write([]byte(`{"status":"success","country":"Germany","countryCode":"DE","lat":52.5,"lon":13.4}`))
+	}))
+	defer server.Close()
+
+	service := NewGeoLocationService(logger)
+	service.apiURL = server.URL
+	service.interval = 50 * time.Millisecond
+
+	// Concurrent callers share the pace, and cached lookups skip it
+	var wg sync.WaitGroup
+	for _, ip := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		wg.Add(1)
+		go func(ip string) {
+			defer wg.Done()
+			if _, err := service.GetLocation(context.Background(), ip); err != nil {
+				t.Errorf("GetLocation(%s): %v", ip, err)
+			}
+		}(ip)
+	}
+	wg.Wait()
+	if _, err := service.GetLocation(context.Background(), "1.1.1.1"); err != nil {
+		t.Fatalf("Cached GetLocation: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 API requests, got %d", len(requests))
+	}
+	if spread := requests[2].Sub(requests[0]); spread < 90*time.Millisecond {
+		t.Errorf("Expected requests at least 100ms apart in total, got %s", spread)
+	}
+}
+
+func TestGeoLocationService_WaitHonoursContext(t *testing.T) {
+	service := NewGeoLocationService(logrus.New())
+	service.interval = time.Hour
+	service.nextRequest = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := service.wait(ctx); err != context.Canceled {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+}
@@ -2,27 +2,32 @@ package services
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/retry"
 )
 
 type GRPCChecker struct {
-	timeout    time.Duration
-	maxRetries int
-	logger     *logrus.Logger
+	timeout time.Duration
+	policy  retry.Policy
+	logger  *logrus.Logger
 }
 
 func NewGRPCChecker(timeout time.Duration, maxRetries int, logger *logrus.Logger) *GRPCChecker {
 	return &GRPCChecker{
-		timeout:    timeout,
-		maxRetries: maxRetries,
-		logger:     logger,
+		timeout: timeout,
+		policy:  retry.DefaultPolicy(maxRetries),
+		logger:  logger,
 	}
 }
 
@@ -30,70 +35,222 @@ type GRPCCheckResult struct {
 	Success        bool
 	Attempts       int
 	ErrorMsg       string
+	ErrorClass     models.ErrorClass
 	ResponseTimeMs int
+	TLS            bool
+	Certificate    *models.CertificateInfo
+	Samples        []models.CheckAttempt
+	// Node is the node behind the server as a network peer and Peers are
+	// the peers it is connected to. Both are only set on success; Node is
+	// nil when the server does not serve GetNodeInfo.
+	Node  *models.ReachablePeer
+	Peers []*models.ReachablePeer
 }
 
 // CheckGRPCServer checks if a gRPC server is healthy using Ping API
 func (gc *GRPCChecker) CheckGRPCServer(ctx context.Context, address string) *GRPCCheckResult {
 	result := &GRPCCheckResult{}
 
-	for attempt := 1; attempt <= gc.maxRetries; attempt++ {
-		result.Attempts = attempt
+	// Servers that complete a TLS handshake are probed over TLS
+	if cert, err := InspectTLS(ctx, address, gc.timeout); err == nil {
+		result.TLS = true
+		result.Certificate = cert
+	}
 
+	var network *pactus.GetNetworkInfoResponse
+	attempts, err := retry.Do(ctx, gc.policy, func(ctx context.Context, attempt int) error {
 		start := time.Now()
-		success, err := gc.attemptGRPCPing(ctx, address)
-		duration := time.Since(start)
-
-		if success {
-			result.Success = true
-			result.ResponseTimeMs = int(duration.Milliseconds())
-			gc.logger.WithFields(logrus.Fields{
-				"address":  address,
-				"attempts": attempt,
-				"latency":  duration,
-			}).Info("gRPC server ping successful")
-			return result
+		info, err := gc.attemptGRPCPing(ctx, address, result.TLS)
+		sample := newCheckAttempt(attempt, start, err)
+		result.Samples = append(result.Samples, sample)
+		if err != nil {
+			return err
 		}
+		result.ResponseTimeMs = sample.LatencyMs
+		network = info
+		return nil
+	})
+	result.Attempts = attempts
 
-		result.ErrorMsg = err.Error()
-
-		if attempt < gc.maxRetries {
-			time.Sleep(time.Second * 2) // Wait between retries
+	if err == nil {
+		result.Success = true
+		seenAt := time.Now()
+		result.Peers = connectedPeers(network, seenAt)
+		if info, err := gc.nodeInfo(ctx, address, result.TLS); err == nil {
+			result.Node = &models.ReachablePeer{
+				PeerID:    info.PeerId,
+				Addresses: publicAddrs(info.LocalAddrs),
+				UserAgent: info.Agent,
+				LastSeen:  seenAt,
+			}
+			if len(result.Node.Addresses) > 0 {
+				result.Node.Address = result.Node.Addresses[0]
+			}
+		} else {
+			gc.logger.WithError(err).WithField("address", address).Debug("Failed to get node info")
 		}
+		gc.logger.WithFields(logrus.Fields{
+			"address":  address,
+			"attempts": attempts,
+			"tls":      result.TLS,
+			"latency":  time.Duration(result.ResponseTimeMs) * time.Millisecond,
+		}).Info("gRPC server ping successful")
+		return result
 	}
 
+	result.ErrorMsg = err.Error()
+	result.ErrorClass = ClassifyError(err)
+
 	gc.logger.WithFields(logrus.Fields{
-		"address":  address,
-		"attempts": result.Attempts,
-		"error":    result.ErrorMsg,
+		"address":     address,
+		"attempts":    result.Attempts,
+		"error":       result.ErrorMsg,
+		"error_class": result.ErrorClass,
 	}).Warn("gRPC server ping failed")
 
 	return result
 }
 
 // attemptGRPCPing attempts to connect and call Ping API
-func (gc *GRPCChecker) attemptGRPCPing(ctx context.Context, address string) (bool, error) {
+func (gc *GRPCChecker) attemptGRPCPing(ctx context.Context, address string, useTLS bool) (*pactus.GetNetworkInfoResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, gc.timeout)
+	defer cancel()
+
+	conn, err := gc.dial(ctx, address, useTLS)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Create Network client and call GetNetworkInfo (this acts as a ping)
+	client := pactus.NewNetworkClient(conn)
+	info, err := client.GetNetworkInfo(ctx, &pactus.GetNetworkInfoRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("ping failed: %w", err)
+	}
+
+	return info, nil
+}
+
+// nodeInfo returns what the node behind a gRPC server reports about itself
+func (gc *GRPCChecker) nodeInfo(ctx context.Context, address string, useTLS bool) (*pactus.GetNodeInfoResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, gc.timeout)
 	defer cancel()
 
-	// Create gRPC connection
+	conn, err := gc.dial(ctx, address, useTLS)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	info, err := pactus.NewNetworkClient(conn).GetNodeInfo(ctx, &pactus.GetNodeInfoRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("get node info: %w", err)
+	}
+	return info, nil
+}
+
+// publicAddrs returns the multiaddrs a node listens on that other peers
+// can reach, skipping loopback, private and unspecified IPs
+func publicAddrs(addrs []string) []string {
+	var public []string
+	for _, addr := range addrs {
+		if isPublicMultiaddr(addr) {
+			public = append(public, addr)
+		}
+	}
+	return public
+}
+
+// connectedPeers turns the peers a node reports into crawled peers seen at
+// the given time. The address of an outbound connection is one the peer
+// listens on, so it is kept as an advertised address; inbound connections
+// come from ephemeral ports.
+func connectedPeers(info *pactus.GetNetworkInfoResponse, seenAt time.Time) []*models.ReachablePeer {
+	var peers []*models.ReachablePeer
+	for _, p := range info.GetConnectedPeers() {
+		if p.PeerId == "" {
+			continue
+		}
+		peer := &models.ReachablePeer{
+			PeerID:    p.PeerId,
+			Address:   p.Address,
+			UserAgent: p.Agent,
+			LastSeen:  seenAt,
+		}
+		if p.Direction == pactus.Direction_DIRECTION_OUTBOUND && isPublicMultiaddr(p.Address) {
+			peer.Addresses = []string{p.Address}
+		}
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
+// dial opens a blocking connection to a gRPC server
+func (gc *GRPCChecker) dial(ctx context.Context, address string, useTLS bool) (*grpc.ClientConn, error) {
+	// Certificate trust is reported separately by the TLS inspector, so an
+	// untrusted certificate does not make the server look unreachable
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+	}
+
 	conn, err := grpc.DialContext(
 		ctx,
 		address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
 	)
 	if err != nil {
-		return false, fmt.Errorf("failed to connect: %w", err)
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return conn, nil
+}
+
+// Connect opens a connection to a gRPC server, using TLS when the server
+// offers it. The caller must close the connection.
+func (gc *GRPCChecker) Connect(ctx context.Context, address string) (*grpc.ClientConn, error) {
+	_, tlsErr := InspectTLS(ctx, address, gc.timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, gc.timeout)
+	defer cancel()
+
+	return gc.dial(ctx, address, tlsErr == nil)
+}
+
+// NodeIdentity is what a gRPC server reports about the node behind it
+type NodeIdentity struct {
+	PeerID     string
+	Validators []string
+}
+
+// GetNodeIdentity returns the peer ID of the node behind a gRPC server and
+// the validator addresses it runs consensus for
+func (gc *GRPCChecker) GetNodeIdentity(ctx context.Context, address string) (*NodeIdentity, error) {
+	_, tlsErr := InspectTLS(ctx, address, gc.timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, gc.timeout)
+	defer cancel()
+
+	conn, err := gc.dial(ctx, address, tlsErr == nil)
+	if err != nil {
+		return nil, err
 	}
 	defer conn.Close()
 
-	// Create Network client and call GetNetworkInfo (this acts as a ping)
-	client := pactus.NewNetworkClient(conn)
-	_, err = client.GetNetworkInfo(ctx, &pactus.GetNetworkInfoRequest{})
+	info, err := pactus.NewNetworkClient(conn).GetNodeInfo(ctx, &pactus.GetNodeInfoRequest{})
 	if err != nil {
-		return false, fmt.Errorf("ping failed: %w", err)
+		return nil, fmt.Errorf("get node info: %w", err)
+	}
+	identity := &NodeIdentity{PeerID: info.PeerId}
+
+	consensus, err := pactus.NewBlockchainClient(conn).GetConsensusInfo(ctx, &pactus.GetConsensusInfoRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("get consensus info: %w", err)
+	}
+	for _, instance := range consensus.Instances {
+		identity.Validators = append(identity.Validators, instance.Address)
 	}
 
-	return true, nil
+	return identity, nil
 }
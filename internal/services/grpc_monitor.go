@@ -7,17 +7,26 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
-	"github.com/pactus-project/pactus/wallet"
 )
 
 type GRPCMonitor struct {
-	grpcRepo          repositories.GRPCRepository
-	grpcStatusRepo    repositories.GRPCStatusRepository
-	grpcChecker       *GRPCChecker
-	grpcServerService *GRPCServerService
-	logger            *logrus.Logger
+	grpcRepo        repositories.GRPCRepository
+	grpcStatusRepo  repositories.GRPCStatusRepository
+	grpcChecker     *GRPCChecker
+	reconciler      *nodeReconciler
+	certService     *CertificateService
+	latencyService  *LatencyService
+	versionService  *VersionService
+	topologyService *TopologyService
+	churnService    *ChurnService
+	addressService  *AddressService
+	maintenanceRepo repositories.MaintenanceRepository
+	networkService  *NetworkService
+	eventBus        *events.Bus
+	logger          *logrus.Logger
 }
 
 func NewGRPCMonitor(
@@ -25,43 +34,82 @@ func NewGRPCMonitor(
 	grpcStatusRepo repositories.GRPCStatusRepository,
 	grpcChecker *GRPCChecker,
 	logger *logrus.Logger,
-	grpcServerService *GRPCServerService,
+	source NodeSource,
+	syncRunRepo repositories.SyncRunRepository,
+	certService *CertificateService,
+	latencyService *LatencyService,
+	versionService *VersionService,
+	topologyService *TopologyService,
+	churnService *ChurnService,
+	addressService *AddressService,
+	maintenanceRepo repositories.MaintenanceRepository,
+	networkService *NetworkService,
+	eventBus *events.Bus,
 ) *GRPCMonitor {
-	return &GRPCMonitor{
-		grpcRepo:          grpcRepo,
-		grpcStatusRepo:    grpcStatusRepo,
-		grpcChecker:       grpcChecker,
-		grpcServerService: grpcServerService,
-		logger:            logger,
+	gm := &GRPCMonitor{
+		grpcRepo:        grpcRepo,
+		grpcStatusRepo:  grpcStatusRepo,
+		grpcChecker:     grpcChecker,
+		certService:     certService,
+		latencyService:  latencyService,
+		versionService:  versionService,
+		topologyService: topologyService,
+		churnService:    churnService,
+		addressService:  addressService,
+		maintenanceRepo: maintenanceRepo,
+		networkService:  networkService,
+		eventBus:        eventBus,
+		logger:          logger,
 	}
+	gm.reconciler = &nodeReconciler{
+		nodeType: models.NodeTypeGRPC,
+		source:   source,
+		store:    &grpcStore{repo: grpcRepo},
+		addresses: func(network *models.Network) []string {
+			return network.GRPCServers
+		},
+		name:           gm.extractServerName,
+		networkService: networkService,
+		syncRunRepo:    syncRunRepo,
+		eventBus:       eventBus,
+		logger:         logger,
+	}
+	return gm
 }
 
-// CheckAllServers checks all active gRPC servers
+// CheckAllServers checks all active gRPC servers. Servers in a maintenance
+// window are recorded with the maintenance color.
 func (gm *GRPCMonitor) CheckAllServers(ctx context.Context) error {
 	servers, err := gm.grpcRepo.GetActiveServers(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get active servers: %w", err)
 	}
+	windows, err := maintenanceWindows(ctx, gm.maintenanceRepo, models.NodeTypeGRPC)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
 
 	today := time.Now().Truncate(24 * time.Hour)
 
 	for _, server := range servers {
-		if err := gm.checkSingleServer(ctx, server, today); err != nil {
+		if err := gm.checkSingleServer(ctx, server, today, windows[server.ID]); err != nil {
 			gm.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to check server")
 			continue
 		}
 	}
 
-	// Update overall scores
-	if err := gm.grpcRepo.UpdateAllScores(ctx); err != nil {
-		gm.logger.WithError(err).Error("Failed to update overall scores")
+	if gm.latencyService != nil {
+		if err := gm.latencyService.Rollup(ctx, models.NodeTypeGRPC, today); err != nil {
+			gm.logger.WithError(err).Error("Failed to roll up latency")
+		}
 	}
 
 	return nil
 }
 
-// checkSingleServer checks a single server's health
-func (gm *GRPCMonitor) checkSingleServer(ctx context.Context, server *models.GRPCServer, date time.Time) error {
+// checkSingleServer checks a single server's health. window is the
+// server's open maintenance window, if any.
+func (gm *GRPCMonitor) checkSingleServer(ctx context.Context, server *models.GRPCServer, date time.Time, window *models.MaintenanceWindow) error {
 	// Check if already recorded for today
 	exists, err := gm.grpcStatusRepo.HasStatusForDate(ctx, server.ID, date)
 	if err != nil {
@@ -79,12 +127,25 @@ func (gm *GRPCMonitor) checkSingleServer(ctx context.Context, server *models.GRP
 	// Check the server
 	result := gm.grpcChecker.CheckGRPCServer(ctx, server.Address)
 
-	// Color: 1 = green (success), 0 = grey (failure)
-	color := 0
+	if result.Certificate != nil && gm.certService != nil {
+		if err := gm.certService.Record(ctx, models.NodeTypeGRPC, server.ID, result.Certificate); err != nil {
+			gm.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to record certificate")
+		}
+	}
+
+	if gm.latencyService != nil {
+		if err := gm.latencyService.Record(ctx, models.NodeTypeGRPC, server.ID, result.Samples); err != nil {
+			gm.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to record latency")
+		}
+	}
+
 	if result.Success {
-		color = 1
+		gm.recordCrawl(ctx, server, result)
 	}
 
+	// Color: 1 = green (success), 0 = grey (failure), 3 = maintenance
+	color := statusColor(result.Success, window)
+
 	// Save the result
 	status := &models.GRPCDailyStatus{
 		ServerID:       server.ID,
@@ -93,115 +154,163 @@ func (gm *GRPCMonitor) checkSingleServer(ctx context.Context, server *models.GRP
 		Attempts:       result.Attempts,
 		Success:        result.Success,
 		ErrorMsg:       result.ErrorMsg,
+		ErrorClass:     result.ErrorClass,
 		ResponseTimeMs: result.ResponseTimeMs,
 	}
 
-	return gm.grpcStatusRepo.CreateStatus(ctx, status)
-}
+	var previousColor *int
+	if gm.eventBus != nil {
+		previous, err := gm.grpcStatusRepo.GetStatusByServerAndDate(ctx, server.ID, date.AddDate(0, 0, -1))
+		if err == nil && previous != nil {
+			previousColor = &previous.Color
+		}
+	}
 
-// GetGRPCServersWithStatus returns all servers with their 30-day status
-func (gm *GRPCMonitor) GetGRPCServersWithStatus(ctx context.Context) ([]*models.GRPCServerResponse, error) {
-	servers, err := gm.grpcRepo.GetActiveServers(ctx)
-	if err != nil {
-		return nil, err
+	if err := gm.grpcStatusRepo.CreateStatus(ctx, status); err != nil {
+		return err
 	}
 
-	var response []*models.GRPCServerResponse
+	publishCheck(ctx, gm.eventBus, models.NodeTypeGRPC, server.ID, events.CheckResult{
+		Date:           date.Format("2006-01-02"),
+		Color:          color,
+		Success:        result.Success,
+		ResponseTimeMs: result.ResponseTimeMs,
+		ErrorClass:     string(result.ErrorClass),
+	}, previousColor)
+	return nil
+}
 
-	for _, server := range servers {
-		statuses, err := gm.grpcStatusRepo.GetRecentStatusesByServer(ctx, server.ID, 30)
-		if err != nil {
-			gm.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to get statuses")
-			continue
+// recordCrawl stores the node behind a server, the peers it is connected
+// to, their sessions and addresses, and the software versions they run.
+// Crawled peers belong to the network of the server.
+func (gm *GRPCMonitor) recordCrawl(ctx context.Context, server *models.GRPCServer, result *GRPCCheckResult) {
+	serverID := server.ID
+	peers := result.Peers
+	if result.Node != nil {
+		peers = append(peers, result.Node)
+	}
+	for _, peer := range peers {
+		peer.Network = models.NetworkOrDefault(server.Network)
+	}
+
+	if gm.topologyService != nil {
+		if err := gm.topologyService.RecordCrawl(ctx, result.Node, result.Peers); err != nil {
+			gm.logger.WithError(err).WithField("server_id", serverID).Error("Failed to record crawled peers")
+		} else {
+			gm.recordPeerDetails(ctx, serverID, peers)
 		}
+	}
 
-		serverResponse := &models.GRPCServerResponse{
-			Name:         server.Name,
-			Address:      server.Address,
-			Network:      server.Network,
-			Email:        server.Email,
-			Website:      server.Website,
-			Status:       statuses,
-			OverallScore: server.OverallScore,
+	if gm.versionService == nil {
+		return
+	}
+	if result.Node != nil {
+		if err := gm.versionService.Record(ctx, models.NodeTypeGRPC, serverID, result.Node.UserAgent, result.Node.LastSeen); err != nil {
+			gm.logger.WithError(err).WithField("server_id", serverID).Error("Failed to record version")
 		}
+	}
+	if err := gm.versionService.RecordPeers(ctx, peers); err != nil {
+		gm.logger.WithError(err).WithField("server_id", serverID).Error("Failed to record peer versions")
+	}
+}
 
-		response = append(response, serverResponse)
+// recordPeerDetails stores the sessions and advertised addresses of
+// crawled peers, which must have been stored first
+func (gm *GRPCMonitor) recordPeerDetails(ctx context.Context, serverID int, peers []*models.ReachablePeer) {
+	if gm.churnService != nil {
+		if err := gm.churnService.RecordSightings(ctx, peers); err != nil {
+			gm.logger.WithError(err).WithField("server_id", serverID).Error("Failed to record peer sessions")
+		}
 	}
 
-	return response, nil
+	if gm.addressService == nil {
+		return
+	}
+	for _, peer := range peers {
+		if err := gm.addressService.RecordPeerAddresses(ctx, peer); err != nil {
+			gm.logger.WithError(err).WithField("server_id", serverID).Error("Failed to record peer addresses")
+			return
+		}
+	}
 }
 
-func (gm *GRPCMonitor) SyncGRPCServers(ctx context.Context) error {
-	gm.logger.Info("Starting gRPC server sync from Pactus")
-
-	mainnetServers, err := wallet.GetServerList("mainnet")
+// GetGRPCServersWithStatus returns all servers with their 30-day status
+func (gm *GRPCMonitor) GetGRPCServersWithStatus(ctx context.Context) ([]*models.GRPCServerResponse, error) {
+	servers, err := gm.grpcRepo.GetActiveServers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load gRPC servers from GitHub: %w", err)
+		return nil, err
 	}
+	return gm.withStatus(ctx, servers)
+}
 
-	testnetServers, err := wallet.GetServerList("testnet")
+// ListGRPCServers returns one page of active servers with their 30-day
+// status. The filter must have been validated.
+func (gm *GRPCMonitor) ListGRPCServers(ctx context.Context, filter models.NodeFilter) (*models.GRPCServerPage, error) {
+	servers, err := gm.grpcRepo.ListServers(ctx, filter)
 	if err != nil {
-		return fmt.Errorf("failed to load gRPC servers from GitHub: %w", err)
+		return nil, err
 	}
 
-	// Sync mainnet servers
-	if err := gm.syncNetworkServers(ctx, "mainnet", mainnetServers); err != nil {
-		return fmt.Errorf("failed to sync mainnet: %w", err)
+	page := &models.GRPCServerPage{}
+	if len(servers) > filter.Limit {
+		servers = servers[:filter.Limit]
+		last := servers[len(servers)-1]
+		page.NextCursor = filter.NextCursor(last.ID, last.Name, last.Country, last.OverallScore)
 	}
 
-	// Sync testnet servers
-	if err := gm.syncNetworkServers(ctx, "testnet", testnetServers); err != nil {
-		return fmt.Errorf("failed to sync testnet: %w", err)
+	if page.Items, err = gm.withStatus(ctx, servers); err != nil {
+		return nil, err
 	}
-
-	gm.logger.Info("Completed gRPC server sync")
-	return nil
+	return page, nil
 }
 
-// syncNetworkServers syncs servers for a specific network
-func (gm *GRPCMonitor) syncNetworkServers(ctx context.Context, network string, servers []wallet.ServerInfo) error {
-	for _, server := range servers {
-		// Check if server exists
-		exists, err := gm.grpcRepo.ServerExists(ctx, server.Address)
-		if err != nil {
-			return err
-		}
+// withStatus builds server responses, loading the 30-day status history of
+// all servers in a single query
+func (gm *GRPCMonitor) withStatus(ctx context.Context, servers []*models.GRPCServer) ([]*models.GRPCServerResponse, error) {
+	ids := make([]int, len(servers))
+	for i, server := range servers {
+		ids[i] = server.ID
+	}
 
-		if !exists {
-			// Add new server
-			server := &models.GRPCServer{
-				Name:     server.Name,
-				Address:  server.Address,
-				Network:  network,
-				Email:    server.Email,
-				Website:  server.Website,
-				IsActive: true,
-			}
-
-			if err := gm.grpcRepo.CreateServer(ctx, server); err != nil {
-				gm.logger.WithError(err).WithField("address", server.Address).Error("Failed to add server")
-				continue
-			}
-			gm.logger.WithField("address", server.Address).Info("Added new server")
-		} else {
-			// Update existing server
-			server := &models.GRPCServer{
-				Name:    server.Name,
-				Address: server.Address,
-				Network: network,
-				Email:   server.Email,
-				Website: server.Website,
-			}
-
-			if err := gm.grpcRepo.UpdateServer(ctx, server); err != nil {
-				gm.logger.WithError(err).WithField("address", server.Address).Error("Failed to update server")
-				continue
-			}
-			gm.logger.WithField("address", server.Address).Info("Updated existing server")
-		}
+	statuses, err := gm.grpcStatusRepo.GetRecentStatusesByServers(ctx, ids, 30)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var latency map[int]*models.LatencySummary
+	if gm.latencyService != nil {
+		latency = gm.latencyService.GetLatestSummaries(ctx, models.NodeTypeGRPC, ids)
+	}
+
+	response := make([]*models.GRPCServerResponse, 0, len(servers))
+	for _, server := range servers {
+		response = append(response, &models.GRPCServerResponse{
+			ID:           server.ID,
+			Name:         server.Name,
+			Address:      server.Address,
+			Network:      server.Network,
+			Email:        server.Email,
+			Website:      server.Website,
+			Status:       statuses[server.ID],
+			OverallScore: server.OverallScore,
+			IsVerified:   server.IsVerified,
+			Country:      server.Country,
+			City:         server.City,
+			Latitude:     server.Latitude,
+			Longitude:    server.Longitude,
+			Latency:      latency[server.ID],
+		})
+	}
+
+	return response, nil
+}
+
+// SyncGRPCServers reconciles the curated gRPC servers of every active
+// network with the node source and the network's configured servers, and
+// stores the report of the run. Registered servers are left alone. A dry
+// run only reports the changes.
+func (gm *GRPCMonitor) SyncGRPCServers(ctx context.Context, dryRun bool) (*models.SyncRun, error) {
+	return gm.reconciler.Sync(ctx, dryRun)
 }
 
 // extractServerName extracts a display name from the address
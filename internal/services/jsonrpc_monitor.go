@@ -6,22 +6,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/retry"
 	"github.com/sirupsen/logrus"
 )
 
 // JSONRPCMonitorService handles JSON-RPC server monitoring
 type JSONRPCMonitorService struct {
-	serverRepo repositories.JSONRPCServerRepository
-	statusRepo repositories.JSONRPCStatusRepository
-	geoService *GeoLocationService
-	logger     *logrus.Logger
-	httpClient *http.Client
+	serverRepo      repositories.JSONRPCServerRepository
+	statusRepo      repositories.JSONRPCStatusRepository
+	geoService      *GeoLocationService
+	certService     *CertificateService
+	latencyService  *LatencyService
+	versionService  *VersionService
+	maintenanceRepo repositories.MaintenanceRepository
+	eventBus        *events.Bus
+	logger          *logrus.Logger
+	httpClient      *http.Client
+	policy          retry.Policy
 }
 
 // NewJSONRPCMonitorService creates a new JSON-RPC monitor service
@@ -29,25 +39,42 @@ func NewJSONRPCMonitorService(
 	serverRepo repositories.JSONRPCServerRepository,
 	statusRepo repositories.JSONRPCStatusRepository,
 	geoService *GeoLocationService,
+	certService *CertificateService,
+	latencyService *LatencyService,
+	versionService *VersionService,
+	maintenanceRepo repositories.MaintenanceRepository,
+	eventBus *events.Bus,
+	maxRetries int,
 	logger *logrus.Logger,
 ) *JSONRPCMonitorService {
 	return &JSONRPCMonitorService{
-		serverRepo: serverRepo,
-		statusRepo: statusRepo,
-		geoService: geoService,
-		logger:     logger,
+		serverRepo:      serverRepo,
+		statusRepo:      statusRepo,
+		geoService:      geoService,
+		certService:     certService,
+		latencyService:  latencyService,
+		versionService:  versionService,
+		maintenanceRepo: maintenanceRepo,
+		eventBus:        eventBus,
+		logger:          logger,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		policy: retry.DefaultPolicy(maxRetries),
 	}
 }
 
-// CheckAllServers performs health check on all active JSON-RPC servers
+// CheckAllServers performs health check on all active JSON-RPC servers.
+// Servers in a maintenance window are recorded with the maintenance color.
 func (s *JSONRPCMonitorService) CheckAllServers(ctx context.Context) error {
 	servers, err := s.serverRepo.GetActiveServers(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get active servers: %w", err)
 	}
+	windows, err := maintenanceWindows(ctx, s.maintenanceRepo, models.NodeTypeJSONRPC)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
 
 	today := time.Now().Truncate(24 * time.Hour)
 
@@ -62,7 +89,7 @@ func (s *JSONRPCMonitorService) CheckAllServers(ctx context.Context) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			if err := s.checkSingleServer(ctx, srv, today); err != nil {
+			if err := s.checkSingleServer(ctx, srv, today, windows[srv.ID]); err != nil {
 				s.logger.WithError(err).WithField("server_id", srv.ID).Error("Failed to check server")
 			}
 		}(server)
@@ -70,16 +97,18 @@ func (s *JSONRPCMonitorService) CheckAllServers(ctx context.Context) error {
 
 	wg.Wait()
 
-	// Update overall scores
-	if err := s.serverRepo.UpdateAllScores(ctx); err != nil {
-		s.logger.WithError(err).Error("Failed to update scores")
+	if s.latencyService != nil {
+		if err := s.latencyService.Rollup(ctx, models.NodeTypeJSONRPC, today); err != nil {
+			s.logger.WithError(err).Error("Failed to roll up latency")
+		}
 	}
 
 	return nil
 }
 
-// checkSingleServer checks a single server's health
-func (s *JSONRPCMonitorService) checkSingleServer(ctx context.Context, server *models.JSONRPCServer, date time.Time) error {
+// checkSingleServer checks a single server's health. window is the
+// server's open maintenance window, if any.
+func (s *JSONRPCMonitorService) checkSingleServer(ctx context.Context, server *models.JSONRPCServer, date time.Time, window *models.MaintenanceWindow) error {
 	exists, err := s.statusRepo.HasStatusForDate(ctx, server.ID, date)
 	if err != nil {
 		return err
@@ -91,22 +120,56 @@ func (s *JSONRPCMonitorService) checkSingleServer(ctx context.Context, server *m
 	// Perform JSON-RPC health check
 	result := s.ValidateJSONRPCEndpoint(ctx, server.Address)
 
+	if result.Certificate != nil && s.certService != nil {
+		if err := s.certService.Record(ctx, models.NodeTypeJSONRPC, server.ID, result.Certificate); err != nil {
+			s.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to record certificate")
+		}
+	}
+
+	if s.latencyService != nil {
+		if err := s.latencyService.Record(ctx, models.NodeTypeJSONRPC, server.ID, result.Samples); err != nil {
+			s.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to record latency")
+		}
+	}
+
+	if result.Success && s.versionService != nil {
+		if err := s.versionService.Record(ctx, models.NodeTypeJSONRPC, server.ID, result.Agent, time.Now()); err != nil {
+			s.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to record version")
+		}
+	}
+
 	status := &models.JSONRPCDailyStatus{
 		ServerID:         server.ID,
 		Date:             date,
-		Color:            0,
+		Color:            statusColor(result.Success, window),
 		Attempts:         result.Attempts,
 		Success:          result.Success,
 		ResponseTimeMs:   result.ResponseTimeMs,
 		ErrorMsg:         result.ErrorMsg,
+		ErrorClass:       result.ErrorClass,
 		BlockchainHeight: result.BlockHeight,
 	}
 
-	if result.Success {
-		status.Color = 1
+	var previousColor *int
+	if s.eventBus != nil {
+		previous, err := s.statusRepo.GetStatusByServerAndDate(ctx, server.ID, date.AddDate(0, 0, -1))
+		if err == nil && previous != nil {
+			previousColor = &previous.Color
+		}
+	}
+
+	if err := s.statusRepo.CreateStatus(ctx, status); err != nil {
+		return err
 	}
 
-	return s.statusRepo.CreateStatus(ctx, status)
+	publishCheck(ctx, s.eventBus, models.NodeTypeJSONRPC, server.ID, events.CheckResult{
+		Date:           date.Format("2006-01-02"),
+		Color:          status.Color,
+		Success:        result.Success,
+		ResponseTimeMs: result.ResponseTimeMs,
+		ErrorClass:     string(result.ErrorClass),
+	}, previousColor)
+	return nil
 }
 
 // JSONRPCCheckResult holds the result of a JSON-RPC endpoint check
@@ -116,65 +179,144 @@ type JSONRPCCheckResult struct {
 	ResponseTimeMs int
 	BlockHeight    int64
 	ErrorMsg       string
+	ErrorClass     models.ErrorClass
+	Certificate    *models.CertificateInfo
+	Samples        []models.CheckAttempt
+	Agent          string
 }
 
 // ValidateJSONRPCEndpoint checks if a JSON-RPC endpoint is responding correctly
 func (s *JSONRPCMonitorService) ValidateJSONRPCEndpoint(ctx context.Context, address string) *JSONRPCCheckResult {
-	result := &JSONRPCCheckResult{Attempts: 5}
+	result := &JSONRPCCheckResult{}
 
-	for i := 0; i < 5; i++ {
+	attempts, err := retry.Do(ctx, s.policy, func(ctx context.Context, attempt int) error {
 		start := time.Now()
-
-		// Call getBlockchainInfo method (Pactus JSON-RPC)
-		request := map[string]interface{}{
-			"jsonrpc": "2.0",
-			"method":  "pactus.blockchain.get_blockchain_info",
-			"params":  map[string]interface{}{},
-			"id":      1,
-		}
-
-		body, _ := json.Marshal(request)
-		req, err := http.NewRequestWithContext(ctx, "POST", address, bytes.NewReader(body))
-		if err != nil {
-			result.ErrorMsg = err.Error()
-			time.Sleep(time.Second)
-			continue
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := s.httpClient.Do(req)
+		height, err := s.callBlockchainInfo(ctx, address)
+		sample := newCheckAttempt(attempt, start, err)
+		result.Samples = append(result.Samples, sample)
 		if err != nil {
-			result.ErrorMsg = err.Error()
-			time.Sleep(time.Second)
-			continue
+			return err
 		}
+		result.ResponseTimeMs = sample.LatencyMs
+		result.BlockHeight = height
+		return nil
+	})
+	result.Attempts = attempts
 
-		responseBody, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-
-		if resp.StatusCode == 200 {
-			result.Success = true
-			result.ResponseTimeMs = int(time.Since(start).Milliseconds())
-
-			// Parse response for block height
-			var response map[string]interface{}
-			if json.Unmarshal(responseBody, &response) == nil {
-				if r, ok := response["result"].(map[string]interface{}); ok {
-					if height, ok := r["last_block_height"].(float64); ok {
-						result.BlockHeight = int64(height)
-					}
-				}
-			}
-			break
-		}
+	if endpoint, parseErr := url.Parse(address); parseErr == nil && endpoint.Scheme == "https" {
+		result.Certificate = s.inspectCertificate(ctx, endpoint)
+	}
 
-		result.ErrorMsg = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(responseBody))
-		time.Sleep(time.Second)
+	if err != nil {
+		result.ErrorMsg = err.Error()
+		result.ErrorClass = ClassifyError(err)
+		return result
 	}
 
+	result.Success = true
+	if agent, err := s.callNodeInfo(ctx, address); err == nil {
+		result.Agent = agent
+	} else {
+		s.logger.WithError(err).WithField("address", address).Debug("Failed to get node agent")
+	}
 	return result
 }
 
+// inspectCertificate records the certificate of an HTTPS endpoint
+func (s *JSONRPCMonitorService) inspectCertificate(ctx context.Context, endpoint *url.URL) *models.CertificateInfo {
+	host := endpoint.Host
+	if endpoint.Port() == "" {
+		host = net.JoinHostPort(endpoint.Hostname(), "443")
+	}
+
+	cert, err := InspectTLS(ctx, host, s.httpClient.Timeout)
+	if err != nil {
+		s.logger.WithError(err).WithField("address", endpoint.String()).Debug("Failed to inspect certificate")
+		return nil
+	}
+	return cert
+}
+
+// callBlockchainInfo calls getBlockchainInfo (Pactus JSON-RPC) and returns the last block height
+func (s *JSONRPCMonitorService) callBlockchainInfo(ctx context.Context, address string) (int64, error) {
+	result, err := s.callMethod(ctx, address, "pactus.blockchain.get_blockchain_info")
+	if err != nil {
+		return 0, err
+	}
+
+	if height, ok := result["last_block_height"].(float64); ok {
+		return int64(height), nil
+	}
+	return 0, nil
+}
+
+// callNodeInfo calls getNodeInfo (Pactus JSON-RPC) and returns the node's agent string
+func (s *JSONRPCMonitorService) callNodeInfo(ctx context.Context, address string) (string, error) {
+	result, err := s.callMethod(ctx, address, "pactus.network.get_node_info")
+	if err != nil {
+		return "", err
+	}
+
+	agent, _ := result["agent"].(string)
+	return agent, nil
+}
+
+// callMethod posts a parameterless JSON-RPC request and returns its result
+func (s *JSONRPCMonitorService) callMethod(ctx context.Context, address, method string) (map[string]interface{}, error) {
+	return s.callMethodWithParams(ctx, address, method, map[string]interface{}{})
+}
+
+// callMethodWithParams posts a JSON-RPC request and returns its result.
+// A body that is not a JSON-RPC response, or one without a result, is an
+// error, so an endpoint answering 200 with anything else is not healthy.
+func (s *JSONRPCMonitorService) callMethodWithParams(ctx context.Context, address, method string, params map[string]interface{}) (map[string]interface{}, error) {
+	request := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      1,
+	}
+
+	body, _ := json.Marshal(request)
+	req, err := http.NewRequestWithContext(ctx, "POST", address, bytes.NewReader(body))
+	if err != nil {
+		return nil, retry.Permanent(&AddressError{Address: address, Reason: err.Error()})
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(responseBody)}
+	}
+
+	// Parse response for errors and the result
+	var response struct {
+		Result map[string]interface{} `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, &ResponseError{Reason: err.Error()}
+	}
+	if response.Error != nil {
+		return nil, &RPCError{Code: response.Error.Code, Message: response.Error.Message}
+	}
+	if response.Result == nil {
+		return nil, &ResponseError{Reason: "missing result"}
+	}
+
+	return response.Result, nil
+}
+
 // GetServersWithStatus returns all servers with their 30-day status
 func (s *JSONRPCMonitorService) GetServersWithStatus(ctx context.Context, network string) ([]*models.JSONRPCServerResponse, error) {
 	var servers []*models.JSONRPCServer
@@ -190,14 +332,50 @@ func (s *JSONRPCMonitorService) GetServersWithStatus(ctx context.Context, networ
 		return nil, err
 	}
 
-	var response []*models.JSONRPCServerResponse
-	for _, server := range servers {
-		statuses, err := s.statusRepo.GetRecentStatusesByServer(ctx, server.ID, 30)
-		if err != nil {
-			s.logger.WithError(err).WithField("server_id", server.ID).Error("Failed to get statuses")
-			continue
-		}
+	return s.withStatus(ctx, servers)
+}
+
+// ListServers returns one page of active servers with their 30-day status.
+// The filter must have been validated.
+func (s *JSONRPCMonitorService) ListServers(ctx context.Context, filter models.NodeFilter) (*models.JSONRPCServerPage, error) {
+	servers, err := s.serverRepo.ListServers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.JSONRPCServerPage{}
+	if len(servers) > filter.Limit {
+		servers = servers[:filter.Limit]
+		last := servers[len(servers)-1]
+		page.NextCursor = filter.NextCursor(last.ID, last.Name, last.Country, last.OverallScore)
+	}
 
+	if page.Items, err = s.withStatus(ctx, servers); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// withStatus builds server responses, loading the 30-day status history of
+// all servers in a single query
+func (s *JSONRPCMonitorService) withStatus(ctx context.Context, servers []*models.JSONRPCServer) ([]*models.JSONRPCServerResponse, error) {
+	ids := make([]int, len(servers))
+	for i, server := range servers {
+		ids[i] = server.ID
+	}
+
+	statuses, err := s.statusRepo.GetRecentStatusesByServers(ctx, ids, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	var latency map[int]*models.LatencySummary
+	if s.latencyService != nil {
+		latency = s.latencyService.GetLatestSummaries(ctx, models.NodeTypeJSONRPC, ids)
+	}
+
+	response := make([]*models.JSONRPCServerResponse, 0, len(servers))
+	for _, server := range servers {
 		response = append(response, &models.JSONRPCServerResponse{
 			ID:           server.ID,
 			Name:         server.Name,
@@ -209,8 +387,10 @@ func (s *JSONRPCMonitorService) GetServersWithStatus(ctx context.Context, networ
 			City:         server.City,
 			Latitude:     server.Latitude,
 			Longitude:    server.Longitude,
-			Status:       statuses,
+			Status:       statuses[server.ID],
 			OverallScore: server.OverallScore,
+			IsVerified:   server.IsVerified,
+			Latency:      latency[server.ID],
 		})
 	}
 
@@ -229,10 +409,8 @@ func (s *JSONRPCMonitorService) UpdateServerGeoLocations(ctx context.Context) er
 		return err
 	}
 
-	// Use concurrency to speed up updates
-	// Note: basic ip-api.com free tier has 45 req/min rate limit.
-	// We use a small concurrency limit to avoid overwhelming it immediately,
-	// but if many updates are needed, we might still hit limits.
+	// The geo service paces lookups under the ip-api.com limit of 45
+	// requests per minute for every caller, so workers just queue on it
 	const maxConcurrent = 5
 	semaphore := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/rpc"
 	"github.com/sirupsen/logrus"
 )
 
@@ -14,7 +15,11 @@ type JsonRPCService struct {
 	grpcMonitor       *GRPCMonitor
 	bootstrapMonitor  *BootstrapMonitor
 	registrationRepo  repositories.RegistrationRepository
+	syncRunRepo       repositories.SyncRunRepository
 	networkStats      *NetworkStatsService
+	certService       *CertificateService
+	latencyService    *LatencyService
+	versionService    *VersionService
 	logger            *logrus.Logger
 }
 
@@ -22,55 +27,72 @@ func NewJsonRPCService(
 	grpcMonitor *GRPCMonitor,
 	bootstrapMonitor *BootstrapMonitor,
 	registrationRepo repositories.RegistrationRepository,
+	syncRunRepo repositories.SyncRunRepository,
 	networkStats *NetworkStatsService,
+	certService *CertificateService,
+	latencyService *LatencyService,
+	versionService *VersionService,
 	logger *logrus.Logger,
 ) *JsonRPCService {
 	return &JsonRPCService{
 		grpcMonitor:      grpcMonitor,
 		bootstrapMonitor: bootstrapMonitor,
 		registrationRepo: registrationRepo,
+		syncRunRepo:      syncRunRepo,
 		networkStats:     networkStats,
+		certService:      certService,
+		latencyService:   latencyService,
+		versionService:   versionService,
 		logger:           logger,
 	}
 }
 
+// RegisterMethods registers the JSON-RPC methods served by this service
+func (s *JsonRPCService) RegisterMethods(r *rpc.Registry) {
+	rpc.Register(r, "getNodes", "List a page of gRPC nodes with their 30-day status", s.GetNodes)
+	rpc.Register(r, "getBootstrapNodes", "List a page of bootstrap nodes with their 30-day status", s.GetBootstrapNodes)
+	rpc.Register(r, "getNodeCount", "Count active gRPC nodes", s.GetNodeCount)
+	rpc.Register(r, "getBootstrapNodeCount", "Count active bootstrap nodes", s.GetBootstrapNodeCount)
+	rpc.Register(r, "getSyncRuns", "List the reports of recent node syncs", s.GetSyncRuns)
+	rpc.Register(r, "getHealth", "Report service health", s.GetHealth)
+	rpc.Register(r, "getNetworkStats", "Get aggregate network statistics", s.GetNetworkStats)
+	rpc.Register(r, "getMapNodes", "List a page of nodes with coordinates for the map", s.GetMapNodes)
+	rpc.Register(r, "getCertificates", "List TLS certificates of monitored endpoints", s.GetCertificates)
+	rpc.Register(r, "getLatencyHistory", "Get daily latency percentiles of a node", s.GetLatencyHistory)
+	rpc.Register(r, "getVersionDistribution", "Get the software versions run by nodes and the servers on outdated versions", s.GetVersionDistribution)
+	rpc.Register(r, "getVersionHistory", "Get the software versions a node has run", s.GetVersionHistory)
+	rpc.Register(r, "registerNode", "Submit a node for listing", s.RegisterNode)
+}
+
+// RegisterAdminMethods registers the methods that run checks and syncs on
+// demand. Every call does the work of a scheduled job, so they are only
+// served behind operator authentication.
+func (s *JsonRPCService) RegisterAdminMethods(r *rpc.Registry) {
+	rpc.Register(r, "checkAllNodes", "Run a health check on every gRPC node", s.CheckAllNodes)
+	rpc.Register(r, "checkAllBootstrapNodes", "Run a health check on every bootstrap node", s.CheckAllBootstrapNodes)
+	rpc.Register(r, "syncNodes", "Sync gRPC nodes from their node source and report the changes", s.SyncNodes)
+	rpc.Register(r, "syncBootstrapNodes", "Sync bootstrap nodes from their node source and report the changes", s.SyncBootstrapNodes)
+	rpc.Register(r, "updateGeoLocations", "Refresh geographic data of nodes", s.UpdateGeoLocations)
+}
+
 // ========== NODE METHODS ==========
 
-// GetNodes returns all gRPC nodes with their status
-func (s *JsonRPCService) GetNodes(ctx context.Context, params struct{}) ([]*models.JsonRPCNodeResponse, error) {
-	servers, err := s.grpcMonitor.GetGRPCServersWithStatus(ctx)
+// GetNodes returns one page of gRPC nodes with their status
+func (s *JsonRPCService) GetNodes(ctx context.Context, params models.NodeFilter) (*models.GRPCServerPage, error) {
+	page, err := s.grpcMonitor.ListGRPCServers(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nodes: %w", err)
 	}
-
-	var response []*models.JsonRPCNodeResponse
-	for _, server := range servers {
-		jsonRPCNode := &models.JsonRPCNodeResponse{
-			Name:         server.Name,
-			Address:      server.Address,
-			Network:      server.Network,
-			Email:        server.Email,
-			Website:      server.Website,
-			Status:       server.Status,
-			OverallScore: server.OverallScore,
-			Country:      server.Country,
-			City:         server.City,
-			Latitude:     server.Latitude,
-			Longitude:    server.Longitude,
-		}
-		response = append(response, jsonRPCNode)
-	}
-
-	return response, nil
+	return page, nil
 }
 
-// GetBootstrapNodes returns all bootstrap nodes with their status
-func (s *JsonRPCService) GetBootstrapNodes(ctx context.Context, params struct{}) ([]*models.BootstrapNodeResponse, error) {
-	nodes, err := s.bootstrapMonitor.GetBootstrapNodesWithStatus(ctx)
+// GetBootstrapNodes returns one page of bootstrap nodes with their status
+func (s *JsonRPCService) GetBootstrapNodes(ctx context.Context, params models.NodeFilter) (*models.BootstrapNodePage, error) {
+	page, err := s.bootstrapMonitor.ListBootstrapNodes(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bootstrap nodes: %w", err)
 	}
-	return nodes, nil
+	return page, nil
 }
 
 // CheckAllNodes triggers a health check for all gRPC nodes
@@ -125,42 +147,70 @@ func (s *JsonRPCService) GetBootstrapNodeCount(ctx context.Context, params struc
 	}, nil
 }
 
-// SyncNodes triggers a sync of all gRPC nodes from source
-func (s *JsonRPCService) SyncNodes(ctx context.Context, params struct{}) (*models.SyncResponse, error) {
-	err := s.grpcMonitor.SyncGRPCServers(ctx)
+// SyncParams asks for a dry run, which reports the changes of a sync
+// without applying them
+type SyncParams struct {
+	DryRun bool `json:"dryRun"`
+}
+
+// SyncNodes triggers a sync of all gRPC nodes from source and returns
+// its report
+func (s *JsonRPCService) SyncNodes(ctx context.Context, params SyncParams) (*models.SyncRun, error) {
+	run, err := s.grpcMonitor.SyncGRPCServers(ctx, params.DryRun)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sync nodes: %w", err)
 	}
+	return run, nil
+}
 
-	count, err := s.grpcMonitor.GetGRPCServerCount(ctx)
+// SyncBootstrapNodes triggers a sync of all bootstrap nodes from source and
+// returns its report
+func (s *JsonRPCService) SyncBootstrapNodes(ctx context.Context, params SyncParams) (*models.SyncRun, error) {
+	run, err := s.bootstrapMonitor.SyncBootstrapNodes(ctx, params.DryRun)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to get server count")
+		return nil, fmt.Errorf("failed to sync bootstrap nodes: %w", err)
 	}
+	return run, nil
+}
 
-	return &models.SyncResponse{
-		Message:      "nodes synced successfully",
-		TotalServers: count,
-		Timestamp:    time.Now().UTC(),
-	}, nil
+// GetSyncRunsParams filters the sync reports by node type
+type GetSyncRunsParams struct {
+	NodeType string `json:"nodeType"`
+	Limit    int    `json:"limit"`
 }
 
-// SyncBootstrapNodes triggers a sync of all bootstrap nodes from source
-func (s *JsonRPCService) SyncBootstrapNodes(ctx context.Context, params struct{}) (*models.SyncResponse, error) {
-	err := s.bootstrapMonitor.SyncBootstrapNodes(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sync bootstrap nodes: %w", err)
+// Validate checks the node type and limit
+func (p *GetSyncRunsParams) Validate() error {
+	switch p.NodeType {
+	case "", models.NodeTypeBootstrap, models.NodeTypeGRPC:
+	default:
+		return fmt.Errorf("nodeType must be %q or %q", models.NodeTypeBootstrap, models.NodeTypeGRPC)
+	}
+	if p.Limit < 0 || p.Limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
 	}
+	return nil
+}
 
-	count, err := s.bootstrapMonitor.GetBootstrapNodeCount(ctx)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to get bootstrap node count")
+// GetSyncRuns returns the latest sync reports, newest first
+func (s *JsonRPCService) GetSyncRuns(ctx context.Context, params GetSyncRunsParams) ([]*models.SyncRun, error) {
+	if s.syncRunRepo == nil {
+		return nil, models.NewServiceUnavailableError("sync run repository not available")
 	}
 
-	return &models.SyncResponse{
-		Message:      "bootstrap nodes synced successfully",
-		TotalServers: count,
-		Timestamp:    time.Now().UTC(),
-	}, nil
+	limit := params.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	runs, err := s.syncRunRepo.ListRuns(ctx, params.NodeType, limit)
+	if err != nil {
+		return nil, models.NewDatabaseError("failed to get sync runs", err)
+	}
+	if runs == nil {
+		runs = []*models.SyncRun{}
+	}
+	return runs, nil
 }
 
 // GetHealth returns the health status of the service
@@ -174,26 +224,36 @@ func (s *JsonRPCService) GetHealth(ctx context.Context, params struct{}) (*model
 
 // ========== PHASE 2 METHODS ==========
 
-// GetNetworkStats returns network statistics
-func (s *JsonRPCService) GetNetworkStats(ctx context.Context, params struct{}) (*models.NetworkStats, error) {
+// NetworkParams selects the network of an API, mainnet by default
+type NetworkParams struct {
+	Network string `json:"network"`
+}
+
+// Validate fills in the default network and checks its name
+func (p *NetworkParams) Validate() error {
+	return defaultNetwork(&p.Network)
+}
+
+// GetNetworkStats returns statistics of a network
+func (s *JsonRPCService) GetNetworkStats(ctx context.Context, params NetworkParams) (*models.NetworkStats, error) {
 	if s.networkStats == nil {
-		return nil, fmt.Errorf("network stats service not available")
+		return nil, models.NewServiceUnavailableError("network stats service not available")
 	}
-	return s.networkStats.GetNetworkStats(ctx)
+	return s.networkStats.GetNetworkStats(ctx, params.Network)
 }
 
-// GetMapNodes returns all nodes formatted for map display
-func (s *JsonRPCService) GetMapNodes(ctx context.Context, params struct{}) ([]models.MapNode, error) {
+// GetMapNodes returns one page of nodes formatted for map display
+func (s *JsonRPCService) GetMapNodes(ctx context.Context, params models.MapFilter) (*models.MapNodePage, error) {
 	if s.networkStats == nil {
-		return nil, fmt.Errorf("network stats service not available")
+		return nil, models.NewServiceUnavailableError("network stats service not available")
 	}
-	return s.networkStats.GetMapNodes(ctx)
+	return s.networkStats.ListMapNodes(ctx, params)
 }
 
 // UpdateGeoLocations updates geographic data for all servers
 func (s *JsonRPCService) UpdateGeoLocations(ctx context.Context, params struct{}) (*models.StatusResponse, error) {
 	if s.networkStats == nil {
-		return nil, fmt.Errorf("network stats service not available")
+		return nil, models.NewServiceUnavailableError("network stats service not available")
 	}
 
 	if err := s.networkStats.UpdateAllGeoLocations(ctx); err != nil {
@@ -206,6 +266,153 @@ func (s *JsonRPCService) UpdateGeoLocations(ctx context.Context, params struct{}
 	}, nil
 }
 
+// GetCertificatesParams filters the certificates returned by GetCertificates
+type GetCertificatesParams struct {
+	NodeType     string `json:"nodeType"`
+	ProblemsOnly bool   `json:"problemsOnly"`
+}
+
+// Validate checks the certificate filter
+func (p *GetCertificatesParams) Validate() error {
+	switch p.NodeType {
+	case "", models.NodeTypeGRPC, models.NodeTypeJSONRPC:
+		return nil
+	}
+	return fmt.Errorf("nodeType must be %q or %q", models.NodeTypeGRPC, models.NodeTypeJSONRPC)
+}
+
+// GetCertificates returns TLS certificate details for gRPC and JSON-RPC endpoints
+func (s *JsonRPCService) GetCertificates(ctx context.Context, params GetCertificatesParams) ([]*models.CertificateInfo, error) {
+	if s.certService == nil {
+		return nil, models.NewServiceUnavailableError("certificate service not available")
+	}
+
+	certs, err := s.certService.GetCertificates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+
+	filtered := make([]*models.CertificateInfo, 0, len(certs))
+	for _, cert := range certs {
+		if params.NodeType != "" && cert.NodeType != params.NodeType {
+			continue
+		}
+		if params.ProblemsOnly && !cert.HasProblem() {
+			continue
+		}
+		filtered = append(filtered, cert)
+	}
+
+	return filtered, nil
+}
+
+// GetLatencyHistoryParams selects the node whose latency history is returned
+type GetLatencyHistoryParams struct {
+	NodeType string `json:"nodeType" rpc:"required"`
+	NodeID   int    `json:"nodeId" rpc:"required"`
+	Days     int    `json:"days"`
+}
+
+// Validate checks that a node was selected
+func (p *GetLatencyHistoryParams) Validate() error {
+	if !isProbeNodeType(p.NodeType) {
+		return fmt.Errorf("nodeType must be one of bootstrap, grpc or jsonrpc")
+	}
+	if p.NodeID <= 0 {
+		return fmt.Errorf("nodeId is required")
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+	return nil
+}
+
+// GetLatencyHistory returns daily p50/p95/p99 response times for a node
+func (s *JsonRPCService) GetLatencyHistory(ctx context.Context, params GetLatencyHistoryParams) (*models.LatencyHistoryResponse, error) {
+	if s.latencyService == nil {
+		return nil, models.NewServiceUnavailableError("latency service not available")
+	}
+
+	history, err := s.latencyService.GetLatencyHistory(ctx, params.NodeType, params.NodeID, params.Days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latency history: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetVersionDistributionParams limits the nodes counted in the distribution
+type GetVersionDistributionParams struct {
+	Network  string `json:"network"`
+	NodeType string `json:"nodeType"`
+	Days     int    `json:"days"`
+}
+
+// Validate checks the network, node type and window
+func (p *GetVersionDistributionParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	if p.NodeType != "" && !isVersionNodeType(p.NodeType) {
+		return fmt.Errorf("nodeType must be one of bootstrap, grpc, jsonrpc or peer")
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+	return nil
+}
+
+// GetVersionDistribution returns how many nodes run each software version,
+// the share on the latest version and the public servers behind it
+func (s *JsonRPCService) GetVersionDistribution(ctx context.Context, params GetVersionDistributionParams) (*models.VersionDistribution, error) {
+	if s.versionService == nil {
+		return nil, models.NewServiceUnavailableError("version service not available")
+	}
+
+	distribution, err := s.versionService.GetDistribution(ctx, params.Network, params.NodeType, params.Days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version distribution: %w", err)
+	}
+
+	return distribution, nil
+}
+
+// GetVersionHistoryParams selects the node whose version history is returned
+type GetVersionHistoryParams struct {
+	NodeType string `json:"nodeType" rpc:"required"`
+	NodeID   int    `json:"nodeId" rpc:"required"`
+}
+
+// Validate checks that a node was selected
+func (p *GetVersionHistoryParams) Validate() error {
+	if !isVersionNodeType(p.NodeType) {
+		return fmt.Errorf("nodeType must be one of bootstrap, grpc, jsonrpc or peer")
+	}
+	if p.NodeID <= 0 {
+		return fmt.Errorf("nodeId is required")
+	}
+	return nil
+}
+
+// GetVersionHistory returns the agents a node has run, newest first
+func (s *JsonRPCService) GetVersionHistory(ctx context.Context, params GetVersionHistoryParams) (*models.VersionHistoryResponse, error) {
+	if s.versionService == nil {
+		return nil, models.NewServiceUnavailableError("version service not available")
+	}
+
+	history, err := s.versionService.GetHistory(ctx, params.NodeType, params.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version history: %w", err)
+	}
+
+	return history, nil
+}
+
+// isVersionNodeType reports whether versions are tracked for a node type
+func isVersionNodeType(nodeType string) bool {
+	return nodeType == models.NodeTypePeer || isProbeNodeType(nodeType)
+}
+
 func getNodeStatus(score float64) string {
 	if score >= 50 {
 		return "online"
@@ -216,7 +423,7 @@ func getNodeStatus(score float64) string {
 // RegisterNode handles public node registration
 func (s *JsonRPCService) RegisterNode(ctx context.Context, params RegisterNodeParams) (*models.RegistrationResponse, error) {
 	if s.registrationRepo == nil {
-		return nil, fmt.Errorf("registration not available")
+		return nil, models.NewServiceUnavailableError("registration not available")
 	}
 
 	// Create registration record
@@ -234,7 +441,7 @@ func (s *JsonRPCService) RegisterNode(ctx context.Context, params RegisterNodePa
 	// Check for duplicates
 	exists, _ := s.registrationRepo.ExistsByAddress(ctx, params.Address)
 	if exists {
-		return nil, fmt.Errorf("a registration for this address already exists")
+		return nil, models.NewConflictError("a registration for this address already exists")
 	}
 
 	// Save registration
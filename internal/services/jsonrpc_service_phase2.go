@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/rpc"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,6 +17,13 @@ type JsonRPCServicePhase2 struct {
 	jsonrpcMonitor     *JSONRPCMonitorService
 	networkStats       *NetworkStatsService
 	registrationService *RegistrationService
+	maintenanceService  *MaintenanceService
+	topologyService     *TopologyService
+	churnService        *ChurnService
+	addressService      *AddressService
+	validatorService    *ValidatorService
+	chainMonitor        *ChainMonitor
+	networkService      *NetworkService
 	logger             *logrus.Logger
 }
 
@@ -25,6 +33,13 @@ func NewJsonRPCServicePhase2(
 	jsonrpcMonitor *JSONRPCMonitorService,
 	networkStats *NetworkStatsService,
 	registrationService *RegistrationService,
+	maintenanceService *MaintenanceService,
+	topologyService *TopologyService,
+	churnService *ChurnService,
+	addressService *AddressService,
+	validatorService *ValidatorService,
+	chainMonitor *ChainMonitor,
+	networkService *NetworkService,
 	logger *logrus.Logger,
 ) *JsonRPCServicePhase2 {
 	return &JsonRPCServicePhase2{
@@ -32,19 +47,67 @@ func NewJsonRPCServicePhase2(
 		jsonrpcMonitor:     jsonrpcMonitor,
 		networkStats:       networkStats,
 		registrationService: registrationService,
+		maintenanceService:  maintenanceService,
+		topologyService:     topologyService,
+		churnService:        churnService,
+		addressService:      addressService,
+		validatorService:    validatorService,
+		chainMonitor:        chainMonitor,
+		networkService:      networkService,
 		logger:             logger,
 	}
 }
 
+// RegisterMethods registers the Phase 1 methods and the Phase 2 methods,
+// which replace the Phase 1 versions where both exist
+func (s *JsonRPCServicePhase2) RegisterMethods(r *rpc.Registry) {
+	s.JsonRPCService.RegisterMethods(r)
+
+	rpc.Register(r, "getJSONRPCNodes", "List a page of JSON-RPC nodes with their 30-day status", s.GetJSONRPCNodes)
+	rpc.Register(r, "getJSONRPCNodeCount", "Count active JSON-RPC nodes", s.GetJSONRPCNodeCount)
+	rpc.Register(r, "getNetworks", "List the networks the tracker follows", s.GetNetworks)
+	rpc.Register(r, "getNetworkStats", "Get aggregate network statistics", s.GetNetworkStats)
+	rpc.Register(r, "getMapNodes", "List a page of nodes with coordinates for the map", s.GetMapNodes)
+	rpc.Register(r, "getSnapshots", "List recent network snapshots", s.GetSnapshots)
+	rpc.Register(r, "getTopology", "Get the graph of crawled peer connections with degree, component and bootstrap centrality statistics", s.GetTopology)
+	rpc.Register(r, "getChurnStats", "Get daily peer joins, leaves and rejoins with lifespan and session length statistics", s.GetChurnStats)
+	rpc.Register(r, "getAddressFamilyStats", "Get endpoint reachability and advertised peer addresses per address family and transport", s.GetAddressFamilyStats)
+	rpc.Register(r, "getValidators", "List a page of validators by stake with their committee membership and peer", s.GetValidators)
+	rpc.Register(r, "getCommittee", "Get the current validator committee and its power", s.GetCommittee)
+	rpc.Register(r, "getValidatorUptime", "Get the certificates a validator signed and missed, its proposals and daily history", s.GetValidatorUptime)
+	rpc.Register(r, "getChainStats", "Get the chain tip, block time and gap statistics, trusted server heights and stall and fork incidents", s.GetChainStats)
+	rpc.Register(r, "registerNode", "Submit a node for listing after a reachability check", s.RegisterNode)
+	rpc.Register(r, "getRegistrationStatus", "Get the review status of a registration", s.GetRegistrationStatus)
+	rpc.Register(r, "verifyRegistration", "Prove ownership of a registered endpoint", s.VerifyRegistration)
+}
+
+// RegisterAdminMethods registers the Phase 1 check and sync methods, the
+// JSON-RPC check and the registration review and maintenance methods. They
+// run scheduled work or change what the tracker lists and scores, so they
+// must only be served behind operator authentication.
+func (s *JsonRPCServicePhase2) RegisterAdminMethods(r *rpc.Registry) {
+	s.JsonRPCService.RegisterAdminMethods(r)
+
+	rpc.Register(r, "checkAllJSONRPCNodes", "Run a health check on every JSON-RPC node", s.CheckAllJSONRPCNodes)
+	rpc.Register(r, "updateGeoLocations", "Refresh geographic data of JSON-RPC nodes", s.UpdateGeoLocations)
+	rpc.Register(r, "getPendingRegistrations", "List registrations awaiting review", s.GetPendingRegistrations)
+	rpc.Register(r, "approveRegistration", "Approve a pending registration", s.ApproveRegistration)
+	rpc.Register(r, "rejectRegistration", "Reject a pending registration", s.RejectRegistration)
+	rpc.Register(r, "scheduleMaintenance", "Declare a maintenance window for a node", s.ScheduleMaintenance)
+	rpc.Register(r, "cancelMaintenance", "Cancel or end a maintenance window", s.CancelMaintenance)
+	rpc.Register(r, "getMaintenanceWindows", "List the open and upcoming maintenance windows of a node", s.GetMaintenanceWindows)
+	rpc.Register(r, "saveNetwork", "Add a network or change its bootstrap addresses, gRPC servers and status", s.SaveNetwork)
+}
+
 // ========== JSON-RPC NODES (Phase 2) ==========
 
-// GetJSONRPCNodes returns all JSON-RPC nodes with their status
-func (s *JsonRPCServicePhase2) GetJSONRPCNodes(ctx context.Context, params struct{ Network string }) ([]*models.JSONRPCServerResponse, error) {
-	servers, err := s.jsonrpcMonitor.GetServersWithStatus(ctx, params.Network)
+// GetJSONRPCNodes returns one page of JSON-RPC nodes with their status
+func (s *JsonRPCServicePhase2) GetJSONRPCNodes(ctx context.Context, params models.NodeFilter) (*models.JSONRPCServerPage, error) {
+	page, err := s.jsonrpcMonitor.ListServers(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get JSON-RPC nodes: %w", err)
 	}
-	return servers, nil
+	return page, nil
 }
 
 // CheckAllJSONRPCNodes triggers a health check for all JSON-RPC nodes
@@ -86,54 +149,394 @@ func (s *JsonRPCServicePhase2) UpdateGeoLocations(ctx context.Context, params st
 	}, nil
 }
 
+// ========== NETWORKS (Phase 2) ==========
+
+// GetNetworksParams selects the networks returned
+type GetNetworksParams struct {
+	ActiveOnly bool `json:"activeOnly"`
+}
+
+// GetNetworks returns the networks the tracker follows
+func (s *JsonRPCServicePhase2) GetNetworks(ctx context.Context, params GetNetworksParams) ([]*models.Network, error) {
+	if s.networkService == nil {
+		return nil, models.NewServiceUnavailableError("network service not available")
+	}
+
+	networks, err := s.networkService.ListNetworks(ctx, params.ActiveOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get networks: %w", err)
+	}
+	return networks, nil
+}
+
+// SaveNetworkParams describes a network to add or change. IsActive
+// defaults to true.
+type SaveNetworkParams struct {
+	Name               string   `json:"name" rpc:"required"`
+	Kind               string   `json:"kind" rpc:"required"`
+	Description        string   `json:"description"`
+	BootstrapAddresses []string `json:"bootstrapAddresses"`
+	GRPCServers        []string `json:"grpcServers"`
+	IsActive           *bool    `json:"isActive"`
+}
+
+// Validate checks that a network was named
+func (p *SaveNetworkParams) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.Kind == "" {
+		return fmt.Errorf("kind is required")
+	}
+	return nil
+}
+
+// SaveNetwork adds a network or replaces the settings of an existing one
+func (s *JsonRPCServicePhase2) SaveNetwork(ctx context.Context, params SaveNetworkParams) (*models.Network, error) {
+	if s.networkService == nil {
+		return nil, models.NewServiceUnavailableError("network service not available")
+	}
+
+	network := &models.Network{
+		Name:               params.Name,
+		Kind:               params.Kind,
+		Description:        params.Description,
+		BootstrapAddresses: params.BootstrapAddresses,
+		GRPCServers:        params.GRPCServers,
+		IsActive:           params.IsActive == nil || *params.IsActive,
+	}
+	if network.BootstrapAddresses == nil {
+		network.BootstrapAddresses = []string{}
+	}
+	if network.GRPCServers == nil {
+		network.GRPCServers = []string{}
+	}
+
+	if err := s.networkService.SaveNetwork(ctx, network); err != nil {
+		return nil, fmt.Errorf("failed to save network: %w", err)
+	}
+	return network, nil
+}
+
 // ========== NETWORK STATS (Phase 2) ==========
 
-// GetNetworkStats returns network statistics
-func (s *JsonRPCServicePhase2) GetNetworkStats(ctx context.Context, params struct{}) (*models.NetworkStats, error) {
-	stats, err := s.networkStats.GetNetworkStats(ctx)
+// GetNetworkStats returns statistics of a network
+func (s *JsonRPCServicePhase2) GetNetworkStats(ctx context.Context, params NetworkParams) (*models.NetworkStats, error) {
+	stats, err := s.networkStats.GetNetworkStats(ctx, params.Network)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network stats: %w", err)
 	}
 	return stats, nil
 }
 
-// GetMapNodes returns all nodes formatted for map display
-func (s *JsonRPCServicePhase2) GetMapNodes(ctx context.Context, params struct{}) ([]models.MapNode, error) {
-	nodes, err := s.networkStats.GetMapNodes(ctx)
+// GetMapNodes returns one page of nodes formatted for map display
+func (s *JsonRPCServicePhase2) GetMapNodes(ctx context.Context, params models.MapFilter) (*models.MapNodePage, error) {
+	nodes, err := s.networkStats.ListMapNodes(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get map nodes: %w", err)
 	}
 	return nodes, nil
 }
 
-// GetSnapshots returns recent network snapshots
-func (s *JsonRPCServicePhase2) GetSnapshots(ctx context.Context, params struct{ Limit int }) ([]*models.NetworkSnapshot, error) {
+// GetSnapshotsParams selects the network and limits the number of
+// snapshots returned
+type GetSnapshotsParams struct {
+	Network string `json:"network"`
+	Limit   int    `json:"limit"`
+}
+
+// Validate fills in the default network and checks its name
+func (p *GetSnapshotsParams) Validate() error {
+	return defaultNetwork(&p.Network)
+}
+
+// GetSnapshots returns recent snapshots of a network
+func (s *JsonRPCServicePhase2) GetSnapshots(ctx context.Context, params GetSnapshotsParams) ([]*models.NetworkSnapshot, error) {
 	limit := params.Limit
 	if limit <= 0 {
 		limit = 10
 	}
 	
-	snapshots, err := s.networkStats.GetSnapshots(ctx, limit)
+	snapshots, err := s.networkStats.GetSnapshots(ctx, params.Network, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get snapshots: %w", err)
 	}
 	return snapshots, nil
 }
 
+// GetTopologyParams selects the network of the peer graph and limits it to
+// a location and time window
+type GetTopologyParams struct {
+	Network     string `json:"network"`
+	CountryCode string `json:"countryCode"`
+	ASN         string `json:"asn"`
+	Days        int    `json:"days"`
+}
+
+// Validate checks the network, location filters and window
+func (p *GetTopologyParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	if p.CountryCode != "" && len(p.CountryCode) != 2 {
+		return fmt.Errorf("countryCode must be a two-letter country code")
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+	return nil
+}
+
+// GetTopology returns the graph of peer connections seen by the crawler
+func (s *JsonRPCServicePhase2) GetTopology(ctx context.Context, params GetTopologyParams) (*models.Topology, error) {
+	if s.topologyService == nil {
+		return nil, models.NewServiceUnavailableError("topology service not available")
+	}
+
+	topology, err := s.topologyService.GetTopology(ctx, params.Network, params.CountryCode, params.ASN, params.Days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topology: %w", err)
+	}
+	return topology, nil
+}
+
+// GetChurnStatsParams selects the network and days of the churn stats,
+// both inclusive
+type GetChurnStatsParams struct {
+	Network string `json:"network"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+
+	from, to time.Time
+}
+
+// Validate checks the network, parses the dates and checks the range
+func (p *GetChurnStatsParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	var err error
+	if p.From != "" {
+		if p.from, err = time.Parse("2006-01-02", p.From); err != nil {
+			return fmt.Errorf("from must be a date in YYYY-MM-DD format")
+		}
+	}
+	if p.To != "" {
+		if p.to, err = time.Parse("2006-01-02", p.To); err != nil {
+			return fmt.Errorf("to must be a date in YYYY-MM-DD format")
+		}
+	}
+	if !p.from.IsZero() && !p.to.IsZero() {
+		if p.from.After(p.to) {
+			return fmt.Errorf("from must not be after to")
+		}
+		if p.to.Sub(p.from) >= MaxChurnWindowDays*24*time.Hour {
+			return fmt.Errorf("range must not exceed %d days", MaxChurnWindowDays)
+		}
+	}
+	return nil
+}
+
+// GetChurnStats returns the churn of crawled peers over a range of days
+func (s *JsonRPCServicePhase2) GetChurnStats(ctx context.Context, params GetChurnStatsParams) (*models.ChurnStats, error) {
+	if s.churnService == nil {
+		return nil, models.NewServiceUnavailableError("churn service not available")
+	}
+
+	stats, err := s.churnService.GetChurnStats(ctx, params.Network, params.from, params.to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get churn stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetAddressFamilyStatsParams sets the network and window of
+// getAddressFamilyStats
+type GetAddressFamilyStatsParams struct {
+	Network string `json:"network"`
+	Days    int    `json:"days"`
+}
+
+// Validate checks the network and window
+func (p *GetAddressFamilyStatsParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+	return nil
+}
+
+// GetAddressFamilyStats returns IPv4, IPv6, TCP and QUIC reachability of
+// checked endpoints and the address families crawled peers advertise
+func (s *JsonRPCServicePhase2) GetAddressFamilyStats(ctx context.Context, params GetAddressFamilyStatsParams) (*models.AddressFamilyStats, error) {
+	if s.addressService == nil {
+		return nil, models.NewServiceUnavailableError("address service not available")
+	}
+
+	stats, err := s.addressService.GetFamilyStats(ctx, params.Network, params.Days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address family stats: %w", err)
+	}
+	return stats, nil
+}
+
+// ========== VALIDATORS (Phase 2) ==========
+
+// GetValidatorsParams selects a page of the validators of a network
+type GetValidatorsParams struct {
+	Network   string `json:"network"`
+	Committee bool   `json:"committee"`
+	Limit     int    `json:"limit"`
+	Offset    int    `json:"offset"`
+}
+
+// Validate checks the network and page bounds
+func (p *GetValidatorsParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	if p.Limit < 0 || p.Limit > MaxValidatorPageSize {
+		return fmt.Errorf("limit must be between 0 and %d", MaxValidatorPageSize)
+	}
+	if p.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	return nil
+}
+
+// GetValidators returns a page of validators, optionally only the committee
+func (s *JsonRPCServicePhase2) GetValidators(ctx context.Context, params GetValidatorsParams) (*models.ValidatorList, error) {
+	if s.validatorService == nil {
+		return nil, models.NewServiceUnavailableError("validator service not available")
+	}
+
+	validators, err := s.validatorService.GetValidators(ctx, params.Network, params.Committee, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators: %w", err)
+	}
+	return validators, nil
+}
+
+// GetCommittee returns the committee of a network at the latest sync
+func (s *JsonRPCServicePhase2) GetCommittee(ctx context.Context, params NetworkParams) (*models.Committee, error) {
+	if s.validatorService == nil {
+		return nil, models.NewServiceUnavailableError("validator service not available")
+	}
+
+	committee, err := s.validatorService.GetCommittee(ctx, params.Network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get committee: %w", err)
+	}
+	return committee, nil
+}
+
+// GetValidatorUptimeParams selects a validator and the window of its uptime
+type GetValidatorUptimeParams struct {
+	Network string `json:"network"`
+	Address string `json:"address" rpc:"required"`
+	Days    int    `json:"days"`
+}
+
+// Validate checks the network, that a validator was selected and the window
+func (p *GetValidatorUptimeParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	if p.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+	return nil
+}
+
+// GetValidatorUptime returns the signing uptime and history of a validator
+func (s *JsonRPCServicePhase2) GetValidatorUptime(ctx context.Context, params GetValidatorUptimeParams) (*models.ValidatorUptime, error) {
+	if s.validatorService == nil {
+		return nil, models.NewServiceUnavailableError("validator service not available")
+	}
+
+	uptime, err := s.validatorService.GetValidatorUptime(ctx, params.Network, params.Address, params.Days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator uptime: %w", err)
+	}
+	return uptime, nil
+}
+
+// ========== CHAIN (Phase 2) ==========
+
+// GetChainStatsParams sets the network and window of getChainStats
+type GetChainStatsParams struct {
+	Network string `json:"network"`
+	Hours   int    `json:"hours"`
+}
+
+// Validate checks the network and window
+func (p *GetChainStatsParams) Validate() error {
+	if err := defaultNetwork(&p.Network); err != nil {
+		return err
+	}
+	if p.Hours < 0 {
+		return fmt.Errorf("hours must not be negative")
+	}
+	return nil
+}
+
+// GetChainStats returns the health of the chain as seen by the trusted servers
+func (s *JsonRPCServicePhase2) GetChainStats(ctx context.Context, params GetChainStatsParams) (*models.ChainStats, error) {
+	if s.chainMonitor == nil {
+		return nil, models.NewServiceUnavailableError("chain monitor not available")
+	}
+
+	stats, err := s.chainMonitor.GetChainStats(ctx, params.Network, params.Hours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain stats: %w", err)
+	}
+	return stats, nil
+}
+
 // ========== REGISTRATION (Phase 2) ==========
 
 // RegisterNodeParams contains registration request parameters
 type RegisterNodeParams struct {
-	NodeType string `json:"nodeType"`
-	Name     string `json:"name"`
-	Address  string `json:"address"`
+	NodeType string `json:"nodeType" rpc:"required"`
+	Name     string `json:"name" rpc:"required"`
+	Address  string `json:"address" rpc:"required"`
 	Network  string `json:"network"`
 	Email    string `json:"email"`
 	Website  string `json:"website"`
 }
 
-// RegisterNode handles public node registration
+// Validate checks the required registration fields
+func (p *RegisterNodeParams) Validate() error {
+	if p.NodeType != models.NodeTypeGRPC && p.NodeType != models.NodeTypeJSONRPC {
+		return fmt.Errorf("nodeType must be %q or %q", models.NodeTypeGRPC, models.NodeTypeJSONRPC)
+	}
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	return defaultNetwork(&p.Network)
+}
+
+// RegisterNode handles public node registration. Nodes can only be
+// registered on an active network.
 func (s *JsonRPCServicePhase2) RegisterNode(ctx context.Context, params RegisterNodeParams) (*models.RegistrationResponse, error) {
+	if s.networkService != nil {
+		network, err := s.networkService.GetNetwork(ctx, params.Network)
+		if err != nil {
+			return nil, err
+		}
+		if !network.IsActive {
+			return nil, models.NewValidationError("invalid network", fmt.Sprintf("network %s is not active", params.Network))
+		}
+	}
+
 	req := &models.RegistrationRequest{
 		NodeType: params.NodeType,
 		Name:     params.Name,
@@ -151,18 +554,73 @@ func (s *JsonRPCServicePhase2) RegisterNode(ctx context.Context, params Register
 	return response, nil
 }
 
+// GetRegistrationStatusParams selects a registration
+type GetRegistrationStatusParams struct {
+	ID int `json:"id" rpc:"required"`
+}
+
+// Validate checks that a registration was selected
+func (p *GetRegistrationStatusParams) Validate() error {
+	if p.ID <= 0 {
+		return fmt.Errorf("id is required")
+	}
+	return nil
+}
+
 // GetRegistrationStatus returns the status of a registration
-func (s *JsonRPCServicePhase2) GetRegistrationStatus(ctx context.Context, params struct{ ID int }) (*models.NodeRegistration, error) {
+func (s *JsonRPCServicePhase2) GetRegistrationStatus(ctx context.Context, params GetRegistrationStatusParams) (*models.NodeRegistration, error) {
 	registration, err := s.registrationService.GetRegistrationByID(ctx, params.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get registration: %w", err)
 	}
 	if registration == nil {
-		return nil, fmt.Errorf("registration not found: %d", params.ID)
+		return nil, models.NewNotFoundError(fmt.Sprintf("registration not found: %d", params.ID))
 	}
 	return registration, nil
 }
 
+// VerifyRegistrationParams carries an ownership proof. PublicKey and
+// Signature are only used by the key based methods.
+type VerifyRegistrationParams struct {
+	ID        int    `json:"id" rpc:"required"`
+	Method    string `json:"method" rpc:"required"`
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature"`
+}
+
+// Validate checks the proof fields required by the method
+func (p *VerifyRegistrationParams) Validate() error {
+	if p.ID <= 0 {
+		return fmt.Errorf("id is required")
+	}
+	switch p.Method {
+	case models.VerificationHTTP, models.VerificationDNS:
+	case models.VerificationPeerKey:
+		if p.Signature == "" {
+			return fmt.Errorf("signature is required")
+		}
+	case models.VerificationValidatorKey:
+		if p.PublicKey == "" || p.Signature == "" {
+			return fmt.Errorf("publicKey and signature are required")
+		}
+	default:
+		return fmt.Errorf("method must be one of http, dns, peer_key or validator_key")
+	}
+	return nil
+}
+
+// VerifyRegistration checks the ownership proof of a pending registration
+func (s *JsonRPCServicePhase2) VerifyRegistration(ctx context.Context, params VerifyRegistrationParams) (*models.VerificationResult, error) {
+	result, err := s.registrationService.VerifyRegistration(ctx, params.ID, params.Method, OwnershipProof{
+		PublicKey: params.PublicKey,
+		Signature: params.Signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify registration: %w", err)
+	}
+	return result, nil
+}
+
 // GetPendingRegistrations returns all pending registrations (admin only)
 func (s *JsonRPCServicePhase2) GetPendingRegistrations(ctx context.Context, params struct{}) ([]*models.NodeRegistration, error) {
 	registrations, err := s.registrationService.GetPendingRegistrations(ctx)
@@ -174,10 +632,18 @@ func (s *JsonRPCServicePhase2) GetPendingRegistrations(ctx context.Context, para
 
 // ApproveRegistrationParams contains approval parameters
 type ApproveRegistrationParams struct {
-	ID         int    `json:"id"`
+	ID         int    `json:"id" rpc:"required"`
 	ReviewedBy string `json:"reviewedBy"`
 }
 
+// Validate checks that a registration was selected
+func (p *ApproveRegistrationParams) Validate() error {
+	if p.ID <= 0 {
+		return fmt.Errorf("id is required")
+	}
+	return nil
+}
+
 // ApproveRegistration approves a pending registration (admin only)
 func (s *JsonRPCServicePhase2) ApproveRegistration(ctx context.Context, params ApproveRegistrationParams) (*models.StatusResponse, error) {
 	err := s.registrationService.ApproveRegistration(ctx, params.ID, params.ReviewedBy)
@@ -193,11 +659,19 @@ func (s *JsonRPCServicePhase2) ApproveRegistration(ctx context.Context, params A
 
 // RejectRegistrationParams contains rejection parameters
 type RejectRegistrationParams struct {
-	ID         int    `json:"id"`
+	ID         int    `json:"id" rpc:"required"`
 	Reason     string `json:"reason"`
 	ReviewedBy string `json:"reviewedBy"`
 }
 
+// Validate checks that a registration was selected
+func (p *RejectRegistrationParams) Validate() error {
+	if p.ID <= 0 {
+		return fmt.Errorf("id is required")
+	}
+	return nil
+}
+
 // RejectRegistration rejects a pending registration (admin only)
 func (s *JsonRPCServicePhase2) RejectRegistration(ctx context.Context, params RejectRegistrationParams) (*models.StatusResponse, error) {
 	err := s.registrationService.RejectRegistration(ctx, params.ID, params.Reason, params.ReviewedBy)
@@ -211,6 +685,98 @@ func (s *JsonRPCServicePhase2) RejectRegistration(ctx context.Context, params Re
 	}, nil
 }
 
+// ========== MAINTENANCE ==========
+
+// ScheduleMaintenanceParams declares a maintenance window. The window
+// starts now when startsAt is omitted.
+type ScheduleMaintenanceParams struct {
+	NodeType  string    `json:"nodeType" rpc:"required"`
+	NodeID    int       `json:"nodeId" rpc:"required"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt" rpc:"required"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"createdBy"`
+}
+
+// Validate checks that a node and an end were given
+func (p *ScheduleMaintenanceParams) Validate() error {
+	if p.NodeID <= 0 {
+		return fmt.Errorf("nodeId is required")
+	}
+	if p.EndsAt.IsZero() {
+		return fmt.Errorf("endsAt is required")
+	}
+	return nil
+}
+
+// ScheduleMaintenance declares a maintenance window for any node (admin only)
+func (s *JsonRPCServicePhase2) ScheduleMaintenance(ctx context.Context, params ScheduleMaintenanceParams) (*models.MaintenanceWindow, error) {
+	createdBy := params.CreatedBy
+	if createdBy == "" {
+		createdBy = "admin"
+	}
+
+	window := &models.MaintenanceWindow{
+		NodeType:  params.NodeType,
+		NodeID:    params.NodeID,
+		StartsAt:  params.StartsAt,
+		EndsAt:    params.EndsAt,
+		Reason:    params.Reason,
+		CreatedBy: createdBy,
+	}
+	if err := s.maintenanceService.Schedule(ctx, window); err != nil {
+		return nil, fmt.Errorf("failed to schedule maintenance: %w", err)
+	}
+	return window, nil
+}
+
+// CancelMaintenanceParams selects a maintenance window
+type CancelMaintenanceParams struct {
+	ID int `json:"id" rpc:"required"`
+}
+
+// Validate checks that a window was selected
+func (p *CancelMaintenanceParams) Validate() error {
+	if p.ID <= 0 {
+		return fmt.Errorf("id is required")
+	}
+	return nil
+}
+
+// CancelMaintenance removes an upcoming window or ends an open one (admin
+// only)
+func (s *JsonRPCServicePhase2) CancelMaintenance(ctx context.Context, params CancelMaintenanceParams) (*models.MaintenanceWindow, error) {
+	window, err := s.maintenanceService.Cancel(ctx, params.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel maintenance: %w", err)
+	}
+	return window, nil
+}
+
+// GetMaintenanceWindowsParams selects a node
+type GetMaintenanceWindowsParams struct {
+	NodeType string `json:"nodeType" rpc:"required"`
+	NodeID   int    `json:"nodeId" rpc:"required"`
+}
+
+// Validate checks that a node was selected
+func (p *GetMaintenanceWindowsParams) Validate() error {
+	if p.NodeID <= 0 {
+		return fmt.Errorf("nodeId is required")
+	}
+	return nil
+}
+
+// GetMaintenanceWindows lists the open and upcoming windows of a node
+// (admin only)
+func (s *JsonRPCServicePhase2) GetMaintenanceWindows(ctx context.Context, params GetMaintenanceWindowsParams) ([]*models.MaintenanceWindow, error) {
+	windows, err := s.maintenanceService.ListForNode(ctx, params.NodeType, params.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
 // ParseParams parses JSON-RPC params into the target struct
 func ParseParams[T any](rawParams json.RawMessage) (T, error) {
 	var params T
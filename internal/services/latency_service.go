@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// MaxLatencyHistoryDays caps how far back getLatencyHistory looks
+const MaxLatencyHistoryDays = 90
+
+// LatencyService records per-attempt latency and serves daily percentiles
+type LatencyService struct {
+	latencyRepo repositories.LatencyRepository
+	logger      *logrus.Logger
+}
+
+// NewLatencyService creates a new latency service
+func NewLatencyService(latencyRepo repositories.LatencyRepository, logger *logrus.Logger) *LatencyService {
+	return &LatencyService{
+		latencyRepo: latencyRepo,
+		logger:      logger,
+	}
+}
+
+// Record stores the attempts made while checking a node
+func (s *LatencyService) Record(ctx context.Context, nodeType string, nodeID int, samples []models.CheckAttempt) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	for i := range samples {
+		samples[i].NodeType = nodeType
+		samples[i].NodeID = nodeID
+	}
+
+	if err := s.latencyRepo.CreateAttempts(ctx, samples); err != nil {
+		return fmt.Errorf("failed to record attempts: %w", err)
+	}
+
+	return nil
+}
+
+// Rollup recomputes the daily percentiles of every node of a type
+func (s *LatencyService) Rollup(ctx context.Context, nodeType string, date time.Time) error {
+	if err := s.latencyRepo.RollupDay(ctx, nodeType, date); err != nil {
+		return fmt.Errorf("failed to roll up latency: %w", err)
+	}
+	return nil
+}
+
+// GetLatencyHistory returns the daily percentiles of a node for the last days
+func (s *LatencyService) GetLatencyHistory(ctx context.Context, nodeType string, nodeID int, days int) (*models.LatencyHistoryResponse, error) {
+	if !isProbeNodeType(nodeType) {
+		return nil, fmt.Errorf("invalid node type: %s", nodeType)
+	}
+	if days <= 0 {
+		days = 30
+	}
+	if days > MaxLatencyHistoryDays {
+		days = MaxLatencyHistoryDays
+	}
+
+	history, err := s.latencyRepo.GetLatencyHistory(ctx, nodeType, nodeID, days)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []*models.LatencyStats{}
+	}
+
+	return &models.LatencyHistoryResponse{
+		NodeType: nodeType,
+		NodeID:   nodeID,
+		Days:     days,
+		History:  history,
+	}, nil
+}
+
+// GetLatestSummaries returns the most recent percentiles of the given nodes
+// of a type
+func (s *LatencyService) GetLatestSummaries(ctx context.Context, nodeType string, nodeIDs []int) map[int]*models.LatencySummary {
+	summaries, err := s.latencyRepo.GetLatestByNodes(ctx, nodeType, nodeIDs)
+	if err != nil {
+		s.logger.WithError(err).WithField("node_type", nodeType).Warn("Failed to load latency summaries")
+		return nil
+	}
+	return summaries
+}
+
+// newCheckAttempt describes one checker attempt that started at start
+func newCheckAttempt(attempt int, start time.Time, err error) models.CheckAttempt {
+	sample := models.CheckAttempt{
+		Attempt:   attempt,
+		Success:   err == nil,
+		LatencyMs: int(time.Since(start).Milliseconds()),
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		sample.ErrorClass = ClassifyError(err)
+	}
+	return sample
+}
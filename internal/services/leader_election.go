@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// LeaderElector coordinates scheduled work between tracker replicas.
+// Leadership is held through a session-level Postgres advisory lock on a
+// dedicated connection, so it is released automatically if the process dies
+// or the connection drops. When disabled, the instance is always the leader.
+type LeaderElector struct {
+	db         *sql.DB
+	enabled    bool
+	lockID     int64
+	instanceID string
+	interval   time.Duration
+	logger     *logrus.Logger
+
+	mu          sync.RWMutex
+	conn        *sql.Conn
+	isLeader    bool
+	leaderSince time.Time
+	lastCheck   time.Time
+	lastError   string
+
+	// leaderCtx is cancelled when leadership is lost
+	leaderCtx    context.Context
+	leaderCancel context.CancelFunc
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewLeaderElector creates a new leader elector
+func NewLeaderElector(
+	db *sql.DB,
+	enabled bool,
+	lockID int64,
+	instanceID string,
+	interval time.Duration,
+	logger *logrus.Logger,
+) *LeaderElector {
+	le := &LeaderElector{
+		db:         db,
+		enabled:    enabled,
+		isLeader:   !enabled,
+		lockID:     lockID,
+		instanceID: instanceID,
+		interval:   interval,
+		logger:     logger,
+	}
+	if !enabled {
+		le.leaderCtx = context.Background()
+	}
+	return le
+}
+
+// Start begins campaigning for leadership in the background
+func (le *LeaderElector) Start() {
+	if !le.enabled {
+		le.leaderSince = time.Now().UTC()
+		le.logger.WithField("instance_id", le.instanceID).Info("Leader election disabled, running as sole instance")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	le.cancel = cancel
+	le.done = make(chan struct{})
+
+	go func() {
+		defer close(le.done)
+
+		ticker := time.NewTicker(le.interval)
+		defer ticker.Stop()
+
+		le.campaign(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				le.campaign(ctx)
+			}
+		}
+	}()
+
+	le.logger.WithFields(logrus.Fields{
+		"instance_id": le.instanceID,
+		"lock_id":     le.lockID,
+	}).Info("Leader election started")
+}
+
+// Stop stops campaigning and releases leadership so another replica can take over
+func (le *LeaderElector) Stop() {
+	if le.cancel == nil {
+		return
+	}
+	le.cancel()
+	<-le.done
+
+	// The campaign loop has ended, so nothing else uses the connection
+	le.mu.Lock()
+	conn := le.conn
+	le.conn = nil
+	wasLeader := le.isLeader
+	le.resignLocked()
+	le.mu.Unlock()
+
+	if conn != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, le.lockID); err != nil {
+			le.logger.WithError(err).Warn("Failed to release leader lock")
+		}
+		conn.Close()
+	}
+
+	if wasLeader {
+		le.logger.WithField("instance_id", le.instanceID).Info("Resigned leadership")
+	}
+}
+
+// IsLeader reports whether this instance currently holds leadership
+func (le *LeaderElector) IsLeader() bool {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+	return le.isLeader
+}
+
+// LeaderContext returns a context that is cancelled once this instance loses
+// leadership. On a follower it is already cancelled.
+func (le *LeaderElector) LeaderContext() context.Context {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+
+	if le.leaderCtx == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx
+	}
+	return le.leaderCtx
+}
+
+// Status returns the leader state of this instance
+func (le *LeaderElector) Status() *models.LeaderStatus {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+
+	status := &models.LeaderStatus{
+		Enabled:    le.enabled,
+		InstanceID: le.instanceID,
+		IsLeader:   le.isLeader,
+		LastError:  le.lastError,
+	}
+	if le.isLeader {
+		since := le.leaderSince
+		status.LeaderSince = &since
+	}
+	if !le.lastCheck.IsZero() {
+		checked := le.lastCheck
+		status.LastCheckedAt = &checked
+	}
+
+	return status
+}
+
+// campaign verifies held leadership or tries to acquire it. Only the
+// campaign loop changes the connection, so the database calls run without
+// holding the mutex and the outcome is applied under it.
+func (le *LeaderElector) campaign(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, le.interval)
+	defer cancel()
+
+	le.mu.RLock()
+	conn := le.conn
+	le.mu.RUnlock()
+
+	if conn != nil {
+		// The lock lives as long as the session does
+		err := conn.PingContext(checkCtx)
+		if err == nil {
+			le.mu.Lock()
+			le.lastCheck = time.Now().UTC()
+			le.lastError = ""
+			le.mu.Unlock()
+			return
+		}
+
+		le.logger.WithError(err).WithField("instance_id", le.instanceID).Warn("Lost leader connection")
+		conn.Close()
+
+		le.mu.Lock()
+		le.conn = nil
+		le.lastError = err.Error()
+		le.setLeaderLocked(false)
+		le.mu.Unlock()
+	}
+
+	conn, err := le.tryAcquire(checkCtx)
+
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	le.lastCheck = time.Now().UTC()
+	if err != nil {
+		le.lastError = err.Error()
+		le.logger.WithError(err).Warn("Leader election attempt failed")
+		return
+	}
+
+	le.lastError = ""
+	le.conn = conn
+	le.setLeaderLocked(conn != nil)
+}
+
+// tryAcquire attempts to take the advisory lock on a dedicated connection.
+// It returns the connection holding the lock, or nil if another instance
+// holds it.
+func (le *LeaderElector) tryAcquire(ctx context.Context) (*sql.Conn, error) {
+	conn, err := le.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, le.lockID).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("try advisory lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+
+	return conn, nil
+}
+
+func (le *LeaderElector) setLeaderLocked(leader bool) {
+	if leader == le.isLeader {
+		return
+	}
+
+	le.isLeader = leader
+	if leader {
+		le.leaderSince = time.Now().UTC()
+		le.leaderCtx, le.leaderCancel = context.WithCancel(context.Background())
+		le.logger.WithField("instance_id", le.instanceID).Info("Acquired leadership")
+	} else {
+		le.resignLocked()
+		le.logger.WithField("instance_id", le.instanceID).Warn("Lost leadership")
+	}
+}
+
+// resignLocked drops leadership and cancels the leader context
+func (le *LeaderElector) resignLocked() {
+	le.isLeader = false
+	le.leaderSince = time.Time{}
+	if le.leaderCancel != nil {
+		le.leaderCancel()
+	}
+	le.leaderCtx, le.leaderCancel = nil, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// MaintenanceService schedules maintenance windows. Checks made during a
+// window are recorded with models.StatusColorMaintenance and do not count
+// towards the node's score.
+type MaintenanceService struct {
+	repo          repositories.MaintenanceRepository
+	bootstrapRepo repositories.BootstrapRepository
+	grpcRepo      repositories.GRPCRepository
+	jsonrpcRepo   repositories.JSONRPCServerRepository
+	logger        *logrus.Logger
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(
+	repo repositories.MaintenanceRepository,
+	bootstrapRepo repositories.BootstrapRepository,
+	grpcRepo repositories.GRPCRepository,
+	jsonrpcRepo repositories.JSONRPCServerRepository,
+	logger *logrus.Logger,
+) *MaintenanceService {
+	return &MaintenanceService{
+		repo:          repo,
+		bootstrapRepo: bootstrapRepo,
+		grpcRepo:      grpcRepo,
+		jsonrpcRepo:   jsonrpcRepo,
+		logger:        logger,
+	}
+}
+
+// Schedule stores a maintenance window for an active node. A window without
+// a start, or with a start in the past, starts now. Windows of a node must
+// not overlap, and together may not cover more than MaxMaintenanceDuration of
+// any models.MaintenancePeriod.
+func (s *MaintenanceService) Schedule(ctx context.Context, window *models.MaintenanceWindow) error {
+	now := time.Now().UTC()
+	if window.StartsAt.IsZero() || window.StartsAt.Before(now) {
+		window.StartsAt = now
+	}
+	window.StartsAt = window.StartsAt.UTC()
+	window.EndsAt = window.EndsAt.UTC()
+	window.Reason = strings.TrimSpace(window.Reason)
+
+	if !window.EndsAt.After(window.StartsAt) {
+		return models.NewValidationError("invalid maintenance window", "end must be in the future and after the start")
+	}
+	if window.EndsAt.Sub(window.StartsAt) > models.MaxMaintenanceDuration {
+		return models.NewValidationError("invalid maintenance window",
+			fmt.Sprintf("a window may last at most %d days", int(models.MaxMaintenanceDuration.Hours()/24)))
+	}
+
+	if err := s.checkNode(ctx, window.NodeType, window.NodeID); err != nil {
+		return err
+	}
+
+	overlaps, err := s.repo.HasOverlap(ctx, window.NodeType, window.NodeID, window.StartsAt, window.EndsAt)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return models.NewConflictError("the window overlaps another maintenance window of the node")
+	}
+
+	windows, err := s.repo.ListForNode(ctx, window.NodeType, window.NodeID, window.StartsAt.Add(-models.MaintenancePeriod))
+	if err != nil {
+		return err
+	}
+	if busiestPeriod(append(windows, window), window) > models.MaxMaintenanceDuration {
+		return models.NewConflictError(fmt.Sprintf("a node may be in maintenance for at most %d days within %d days",
+			int(models.MaxMaintenanceDuration.Hours()/24), int(models.MaintenancePeriod.Hours()/24)))
+	}
+
+	if err := s.repo.Create(ctx, window); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"node_type":  window.NodeType,
+		"node_id":    window.NodeID,
+		"starts_at":  window.StartsAt,
+		"ends_at":    window.EndsAt,
+		"created_by": window.CreatedBy,
+	}).Info("Scheduled maintenance window")
+	return nil
+}
+
+// Cancel removes a window that has not started yet, or ends an open one
+// now. The returned window reflects the change.
+func (s *MaintenanceService) Cancel(ctx context.Context, id int) (*models.MaintenanceWindow, error) {
+	window, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, models.NewNotFoundError(fmt.Sprintf("maintenance window %d not found", id))
+	}
+	if err := s.cancel(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// CancelForNode cancels a window like Cancel, provided it belongs to the
+// given node
+func (s *MaintenanceService) CancelForNode(ctx context.Context, nodeType string, nodeID, id int) (*models.MaintenanceWindow, error) {
+	window, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if window == nil || window.NodeType != nodeType || window.NodeID != nodeID {
+		return nil, models.NewNotFoundError(fmt.Sprintf("maintenance window %d not found", id))
+	}
+	if err := s.cancel(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// EndActive ends the node's open window
+func (s *MaintenanceService) EndActive(ctx context.Context, nodeType string, nodeID int) error {
+	window, err := s.Active(ctx, nodeType, nodeID)
+	if err != nil {
+		return err
+	}
+	if window == nil {
+		return models.NewConflictError("the node is not in maintenance")
+	}
+	return s.cancel(ctx, window)
+}
+
+func (s *MaintenanceService) cancel(ctx context.Context, window *models.MaintenanceWindow) error {
+	now := time.Now().UTC()
+	switch {
+	case !window.EndsAt.After(now):
+		return models.NewConflictError(fmt.Sprintf("maintenance window %d has already ended", window.ID))
+	case window.StartsAt.After(now):
+		if err := s.repo.Delete(ctx, window.ID); err != nil {
+			return err
+		}
+	default:
+		if err := s.repo.End(ctx, window.ID, now); err != nil {
+			return err
+		}
+		window.EndsAt = now
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"node_type": window.NodeType,
+		"node_id":   window.NodeID,
+		"window_id": window.ID,
+	}).Info("Cancelled maintenance window")
+	return nil
+}
+
+// Active returns the node's open window, or nil
+func (s *MaintenanceService) Active(ctx context.Context, nodeType string, nodeID int) (*models.MaintenanceWindow, error) {
+	return s.repo.GetActive(ctx, nodeType, nodeID, time.Now().UTC())
+}
+
+// ListForNode returns the node's open and upcoming windows
+func (s *MaintenanceService) ListForNode(ctx context.Context, nodeType string, nodeID int) ([]*models.MaintenanceWindow, error) {
+	windows, err := s.repo.ListForNode(ctx, nodeType, nodeID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if windows == nil {
+		windows = []*models.MaintenanceWindow{}
+	}
+	return windows, nil
+}
+
+// checkNode returns a not found error unless the node exists and is active
+func (s *MaintenanceService) checkNode(ctx context.Context, nodeType string, nodeID int) error {
+	active := false
+	switch nodeType {
+	case models.NodeTypeBootstrap:
+		node, err := s.bootstrapRepo.GetNodeByID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		active = node != nil && node.IsActive
+	case models.NodeTypeGRPC:
+		server, err := s.grpcRepo.GetServerByID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		active = server != nil && server.IsActive
+	case models.NodeTypeJSONRPC:
+		server, err := s.jsonrpcRepo.GetServerByID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		active = server != nil && server.IsActive
+	default:
+		return models.NewValidationError("invalid node type", nodeType)
+	}
+
+	if !active {
+		return models.NewNotFoundError(fmt.Sprintf("%s node %d not found", nodeType, nodeID))
+	}
+	return nil
+}
+
+// busiestPeriod returns the most maintenance time any
+// models.MaintenancePeriod touching the given window holds. The total only
+// changes slope where a period boundary meets a window boundary, so those
+// period starts are the only ones to try.
+func busiestPeriod(windows []*models.MaintenanceWindow, window *models.MaintenanceWindow) time.Duration {
+	var starts []time.Time
+	for _, w := range windows {
+		starts = append(starts, w.StartsAt, w.EndsAt,
+			w.StartsAt.Add(-models.MaintenancePeriod), w.EndsAt.Add(-models.MaintenancePeriod))
+	}
+
+	var busiest time.Duration
+	for _, from := range starts {
+		to := from.Add(models.MaintenancePeriod)
+		if from.After(window.EndsAt) || to.Before(window.StartsAt) {
+			continue
+		}
+		var total time.Duration
+		for _, w := range windows {
+			start, end := w.StartsAt, w.EndsAt
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		if total > busiest {
+			busiest = total
+		}
+	}
+	return busiest
+}
+
+// maintenanceWindows returns the open maintenance windows of a node type
+// keyed by node ID. A nil repository has no windows.
+func maintenanceWindows(ctx context.Context, repo repositories.MaintenanceRepository, nodeType string) (map[int]*models.MaintenanceWindow, error) {
+	if repo == nil {
+		return nil, nil
+	}
+	return repo.GetActiveByType(ctx, nodeType, time.Now().UTC())
+}
+
+// statusColor is the daily color of a check, taking maintenance into
+// account
+func statusColor(success bool, window *models.MaintenanceWindow) int {
+	switch {
+	case window != nil:
+		return models.StatusColorMaintenance
+	case success:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func TestStatusColor(t *testing.T) {
+	window := &models.MaintenanceWindow{}
+	tests := []struct {
+		name    string
+		success bool
+		window  *models.MaintenanceWindow
+		want    int
+	}{
+		{"success", true, nil, 1},
+		{"failure", false, nil, 0},
+		{"failure in maintenance", false, window, models.StatusColorMaintenance},
+		{"success in maintenance", true, window, models.StatusColorMaintenance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusColor(tt.success, tt.window); got != tt.want {
+				t.Errorf("Expected color %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func newTestMaintenanceService() (*MaintenanceService, *memoryMaintenanceRepository) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	repo := &memoryMaintenanceRepository{}
+	grpcRepo := &operatorGRPCRepository{servers: map[int]*models.GRPCServer{
+		1: {ID: 1, Address: "node1.example.org:50051", IsActive: true},
+		2: {ID: 2, Address: "node2.example.org:50051"},
+	}}
+	return NewMaintenanceService(repo, nil, grpcRepo, &operatorJSONRPCRepository{}, logger), repo
+}
+
+func TestMaintenanceService_Schedule(t *testing.T) {
+	service, _ := newTestMaintenanceService()
+	ctx := context.Background()
+	now := time.Now()
+
+	// Occupies the first hour from now
+	first := &models.MaintenanceWindow{NodeType: models.NodeTypeGRPC, NodeID: 1, EndsAt: now.Add(time.Hour)}
+	if err := service.Schedule(ctx, first); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if first.StartsAt.Before(now) {
+		t.Errorf("Expected a window without start to start now, got %s", first.StartsAt)
+	}
+
+	tests := []struct {
+		name     string
+		nodeType string
+		nodeID   int
+		startsAt time.Time
+		endsAt   time.Time
+		code     models.ErrorCode
+	}{
+		{"upcoming", models.NodeTypeGRPC, 1, now.Add(2 * time.Hour), now.Add(3 * time.Hour), ""},
+		{"ends before it starts", models.NodeTypeGRPC, 1, now.Add(5 * time.Hour), now.Add(4 * time.Hour), models.ErrCodeValidation},
+		{"ended", models.NodeTypeGRPC, 1, now.Add(-2 * time.Hour), now.Add(-time.Hour), models.ErrCodeValidation},
+		{"too long", models.NodeTypeGRPC, 1, now.Add(4 * time.Hour), now.Add(4*time.Hour + models.MaxMaintenanceDuration + time.Minute), models.ErrCodeValidation},
+		{"overlapping", models.NodeTypeGRPC, 1, now.Add(30 * time.Minute), now.Add(90 * time.Minute), models.ErrCodeConflict},
+		{"inactive node", models.NodeTypeGRPC, 2, time.Time{}, now.Add(time.Hour), models.ErrCodeNotFound},
+		{"unknown node", models.NodeTypeGRPC, 9, time.Time{}, now.Add(time.Hour), models.ErrCodeNotFound},
+		{"unknown node type", "peer", 1, time.Time{}, now.Add(time.Hour), models.ErrCodeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.Schedule(ctx, &models.MaintenanceWindow{
+				NodeType: tt.nodeType,
+				NodeID:   tt.nodeID,
+				StartsAt: tt.startsAt,
+				EndsAt:   tt.endsAt,
+			})
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("Expected the window to be scheduled, got %v", err)
+				}
+				return
+			}
+			assertErrorCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestMaintenanceService_ScheduleCapsMaintenancePerPeriod(t *testing.T) {
+	service, _ := newTestMaintenanceService()
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second).Add(time.Hour)
+	day := 24 * time.Hour
+
+	// Each step is scheduled after the previous ones; six days are taken
+	// before the first step
+	if err := service.Schedule(ctx, &models.MaintenanceWindow{NodeType: models.NodeTypeGRPC, NodeID: 1, StartsAt: now, EndsAt: now.Add(6 * day)}); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		startsAt time.Duration
+		endsAt   time.Duration
+		code     models.ErrorCode
+	}{
+		{"adjacent window", 6 * day, 12 * day, models.ErrCodeConflict},
+		{"adjacent window within the cap", 6 * day, 7 * day, ""},
+		{"later in the same period", 20 * day, 22 * day, models.ErrCodeConflict},
+		{"once the period has passed", 30 * day, 36 * day, ""},
+		{"chained onto the next period", 36 * day, 37 * day, ""},
+		{"chained past the cap", 37 * day, 38 * day, models.ErrCodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.Schedule(ctx, &models.MaintenanceWindow{
+				NodeType: models.NodeTypeGRPC,
+				NodeID:   1,
+				StartsAt: now.Add(tt.startsAt),
+				EndsAt:   now.Add(tt.endsAt),
+			})
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("Expected the window to be scheduled, got %v", err)
+				}
+				return
+			}
+			assertErrorCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestMaintenanceService_Cancel(t *testing.T) {
+	service, repo := newTestMaintenanceService()
+	ctx := context.Background()
+	now := time.Now()
+
+	open := &models.MaintenanceWindow{NodeType: models.NodeTypeGRPC, NodeID: 1, EndsAt: now.Add(time.Hour)}
+	upcoming := &models.MaintenanceWindow{NodeType: models.NodeTypeGRPC, NodeID: 1, StartsAt: now.Add(2 * time.Hour), EndsAt: now.Add(3 * time.Hour)}
+	for _, window := range []*models.MaintenanceWindow{open, upcoming} {
+		if err := service.Schedule(ctx, window); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+
+	if _, err := service.CancelForNode(ctx, models.NodeTypeJSONRPC, 1, upcoming.ID); err == nil {
+		t.Error("Expected a window of another node to be refused")
+	}
+	if _, err := service.Cancel(ctx, upcoming.ID); err != nil {
+		t.Fatalf("Cancel upcoming: %v", err)
+	}
+	if window, _ := repo.GetByID(ctx, upcoming.ID); window != nil {
+		t.Error("Expected the upcoming window to be deleted")
+	}
+
+	if err := service.EndActive(ctx, models.NodeTypeGRPC, 1); err != nil {
+		t.Fatalf("EndActive: %v", err)
+	}
+	if open.EndsAt.After(time.Now()) {
+		t.Errorf("Expected the open window to end now, got %s", open.EndsAt)
+	}
+
+	_, err := service.Cancel(ctx, open.ID)
+	assertErrorCode(t, err, models.ErrCodeConflict)
+	err = service.EndActive(ctx, models.NodeTypeGRPC, 1)
+	assertErrorCode(t, err, models.ErrCodeConflict)
+	_, err = service.Cancel(ctx, 99)
+	assertErrorCode(t, err, models.ErrCodeNotFound)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"net"
+	"strings"
+
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// parseMultiaddr parses a node multiaddr such as
+// "/dns4/bootstrap.example.org/tcp/21888/p2p/12D3KooW...". The address and
+// transport parts are validated; protocols after the transport, such as
+// /ws or /webtransport, are ignored. The /p2p/ part is kept as text, since
+// checks do not need the peer ID to reach a node.
+func parseMultiaddr(address string) (*models.Endpoint, error) {
+	endpoint := &models.Endpoint{Multiaddr: address}
+
+	transportPart := address
+	if before, peerID, ok := strings.Cut(address, "/p2p/"); ok {
+		transportPart = before
+		endpoint.PeerID, _, _ = strings.Cut(peerID, "/")
+	}
+	if transportPart == "" {
+		return nil, &AddressError{Address: address, Reason: "invalid address format"}
+	}
+
+	addr, err := ma.NewMultiaddr(transportPart)
+	if err != nil {
+		return nil, &AddressError{Address: address, Reason: err.Error()}
+	}
+
+	for i, c := range addr {
+		switch c.Code() {
+		case ma.P_IP4, ma.P_IP6, ma.P_DNS, ma.P_DNS4, ma.P_DNS6:
+			if endpoint.Host == "" {
+				endpoint.Host = c.Value()
+				endpoint.Family = multiaddrFamily(c.Code())
+			}
+		case ma.P_TCP:
+			if endpoint.Transport == "" {
+				endpoint.Transport = models.TransportTCP
+				endpoint.Port = c.Value()
+			}
+		case ma.P_UDP:
+			if endpoint.Transport != "" {
+				continue
+			}
+			if i+1 >= len(addr) || addr[i+1].Code() != ma.P_QUIC_V1 {
+				return nil, &AddressError{Address: address, Reason: "only QUIC is supported over UDP"}
+			}
+			endpoint.Transport = models.TransportQUIC
+			endpoint.Port = c.Value()
+		}
+	}
+
+	if endpoint.Host == "" || endpoint.Transport == "" {
+		return nil, &AddressError{Address: address, Reason: "could not extract host and port from address"}
+	}
+
+	return endpoint, nil
+}
+
+// multiaddrFamily returns the address family of an address protocol
+func multiaddrFamily(code int) string {
+	switch code {
+	case ma.P_IP4, ma.P_DNS4:
+		return models.AddressFamilyIPv4
+	case ma.P_IP6, ma.P_DNS6:
+		return models.AddressFamilyIPv6
+	default:
+		return models.AddressFamilyDNS
+	}
+}
+
+// lookupNetwork returns the network to resolve a host of the given family
+// in, as used by net.Resolver.LookupIP
+func lookupNetwork(family string) string {
+	switch family {
+	case models.AddressFamilyIPv4:
+		return "ip4"
+	case models.AddressFamilyIPv6:
+		return "ip6"
+	default:
+		return "ip"
+	}
+}
+
+// resolvedEndpoint returns the multiaddr of a transport on a resolved IP,
+// such as "/ip6/2a01:4f9::1/udp/21888/quic-v1"
+func resolvedEndpoint(ip net.IP, transport, port string) *models.Endpoint {
+	endpoint := &models.Endpoint{
+		Family:    models.AddressFamilyIPv6,
+		Transport: transport,
+		Host:      ip.String(),
+		Port:      port,
+	}
+	prefix := "/ip6/"
+	if ip.To4() != nil {
+		endpoint.Family = models.AddressFamilyIPv4
+		prefix = "/ip4/"
+	}
+
+	suffix := "/tcp/" + port
+	if transport == models.TransportQUIC {
+		suffix = "/udp/" + port + "/quic-v1"
+	}
+	endpoint.Multiaddr = prefix + endpoint.Host + suffix
+	return endpoint
+}
+
+// isPublicIP reports whether other peers can reach an IP, which excludes
+// loopback, private, unspecified and link-local addresses
+func isPublicIP(ip net.IP) bool {
+	return ip != nil && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast()
+}
+
+// isPublicMultiaddr reports whether a multiaddr parses and is either a DNS
+// name or a public IP
+func isPublicMultiaddr(address string) bool {
+	endpoint, err := parseMultiaddr(address)
+	if err != nil {
+		return false
+	}
+	if ip := net.ParseIP(endpoint.Host); ip != nil {
+		return isPublicIP(ip)
+	}
+	return true
+}
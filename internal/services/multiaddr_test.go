@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func TestParseMultiaddr(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		expect      models.Endpoint
+		expectError bool
+	}{
+		{
+			name:    "Valid DNS address",
+			address: "/dns/bootstrap1.pactus.org/tcp/21888/p2p/12D3KooWPxG5TnY",
+			expect:  models.Endpoint{Family: models.AddressFamilyDNS, Transport: models.TransportTCP, Host: "bootstrap1.pactus.org", Port: "21888", PeerID: "12D3KooWPxG5TnY"},
+		},
+		{
+			name:    "Valid IP4 address",
+			address: "/ip4/65.108.211.187/tcp/21888/p2p/12D3KooWPxG5TnY",
+			expect:  models.Endpoint{Family: models.AddressFamilyIPv4, Transport: models.TransportTCP, Host: "65.108.211.187", Port: "21888", PeerID: "12D3KooWPxG5TnY"},
+		},
+		{
+			name:    "IP6 QUIC address",
+			address: "/ip6/2a01:4f9:c012:1::1/udp/21888/quic-v1",
+			expect:  models.Endpoint{Family: models.AddressFamilyIPv6, Transport: models.TransportQUIC, Host: "2a01:4f9:c012:1::1", Port: "21888"},
+		},
+		{
+			name:    "DNS4 address",
+			address: "/dns4/bootstrap1.pactus.org/tcp/21888",
+			expect:  models.Endpoint{Family: models.AddressFamilyIPv4, Transport: models.TransportTCP, Host: "bootstrap1.pactus.org", Port: "21888"},
+		},
+		{
+			name:    "DNS6 address with trailing protocols",
+			address: "/dns6/bootstrap1.pactus.org/udp/21888/quic-v1/webtransport/p2p/12D3KooWPxG5TnY",
+			expect:  models.Endpoint{Family: models.AddressFamilyIPv6, Transport: models.TransportQUIC, Host: "bootstrap1.pactus.org", Port: "21888", PeerID: "12D3KooWPxG5TnY"},
+		},
+		{
+			name:        "UDP without QUIC",
+			address:     "/ip4/65.108.211.187/udp/21888",
+			expectError: true,
+		},
+		{
+			name:        "Port out of range",
+			address:     "/ip4/127.0.0.1/tcp/99999",
+			expectError: true,
+		},
+		{
+			name:        "Missing transport",
+			address:     "/ip4/65.108.211.187/p2p/12D3KooWPxG5TnY",
+			expectError: true,
+		},
+		{
+			name:        "Invalid address format",
+			address:     "invalid-address",
+			expectError: true,
+		},
+		{
+			name:        "Empty address",
+			address:     "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := parseMultiaddr(tt.address)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %+v", endpoint)
+				} else if ClassifyError(err) != models.ErrorClassInvalidAddress {
+					t.Errorf("Expected an invalid address error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.expect.Multiaddr = tt.address
+			if *endpoint != tt.expect {
+				t.Errorf("Expected %+v, got %+v", tt.expect, *endpoint)
+			}
+		})
+	}
+}
+
+func TestIsPublicMultiaddr(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"/ip4/65.108.211.187/tcp/21888", true},
+		{"/ip6/2a01:4f9:c012:1::1/udp/21888/quic-v1", true},
+		{"/dns/bootstrap1.pactus.org/tcp/21888", true},
+		{"/ip4/127.0.0.1/tcp/21888", false},
+		{"/ip4/192.168.1.10/tcp/21888", false},
+		{"/ip6/::/tcp/21888", false},
+		{"/ip6/fe80::1/tcp/21888", false},
+		{"not-a-multiaddr", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicMultiaddr(tt.address); got != tt.want {
+			t.Errorf("isPublicMultiaddr(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestNodeChecker_CheckEndpoints(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	nc := NewNodeChecker(200*time.Millisecond, 1, logger)
+	var lookups []string
+	nc.lookupIP = func(ctx context.Context, network, host string) ([]net.IP, error) {
+		lookups = append(lookups, network+" "+host)
+		return []net.IP{net.ParseIP("127.0.0.1")}, nil
+	}
+
+	checks := nc.CheckEndpoints(context.Background(), "/dns4/node.example.org/tcp/"+port+"/p2p/12D3KooWTest")
+
+	if len(lookups) != 1 || lookups[0] != "ip4 node.example.org" {
+		t.Errorf("Expected one IPv4 lookup, got %v", lookups)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("Expected a TCP and a QUIC check, got %d", len(checks))
+	}
+	tcp, quic := checks[0], checks[1]
+	if tcp.Multiaddr != "/ip4/127.0.0.1/tcp/"+port || tcp.Family != models.AddressFamilyIPv4 || !tcp.Reachable {
+		t.Errorf("Unexpected TCP check: %+v", tcp)
+	}
+	if quic.Multiaddr != "/ip4/127.0.0.1/udp/"+port+"/quic-v1" || quic.Transport != models.TransportQUIC || quic.Reachable {
+		t.Errorf("Expected an unreachable QUIC check, got %+v", quic)
+	}
+
+	if checks := nc.CheckEndpoints(context.Background(), "invalid-address"); checks != nil {
+		t.Errorf("Expected no checks for an invalid address, got %d", len(checks))
+	}
+}
+
+func TestPeerAddresses(t *testing.T) {
+	seenAt := time.Now()
+	peer := &models.ReachablePeer{
+		ID:       7,
+		LastSeen: seenAt,
+		Addresses: []string{
+			"/ip4/65.108.211.187/tcp/21888",
+			"/ip4/65.108.211.187/udp/21888/quic-v1",
+			"/ip4/65.108.211.187/tcp/21888",
+			"/ip6/2a01:4f9:c012:1::1/tcp/21888",
+			"garbage",
+		},
+	}
+
+	addresses := peerAddresses(peer)
+
+	want := []models.PeerAddress{
+		{PeerID: 7, Multiaddr: "/ip4/65.108.211.187/tcp/21888", Family: models.AddressFamilyIPv4, Transport: models.TransportTCP, LastSeen: seenAt},
+		{PeerID: 7, Multiaddr: "/ip4/65.108.211.187/udp/21888/quic-v1", Family: models.AddressFamilyIPv4, Transport: models.TransportQUIC, LastSeen: seenAt},
+		{PeerID: 7, Multiaddr: "/ip6/2a01:4f9:c012:1::1/tcp/21888", Family: models.AddressFamilyIPv6, Transport: models.TransportTCP, LastSeen: seenAt},
+	}
+	if len(addresses) != len(want) {
+		t.Fatalf("Expected %d addresses, got %d", len(want), len(addresses))
+	}
+	for i := range want {
+		if *addresses[i] != want[i] {
+			t.Errorf("Address %d: expected %+v, got %+v", i, want[i], *addresses[i])
+		}
+	}
+}
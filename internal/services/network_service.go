@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// NetworkService manages the networks the tracker follows and resolves the
+// bootstrap nodes and gRPC servers each of them syncs
+type NetworkService struct {
+	networkRepo repositories.NetworkRepository
+	logger      *logrus.Logger
+}
+
+// NewNetworkService creates a new network service
+func NewNetworkService(networkRepo repositories.NetworkRepository, logger *logrus.Logger) *NetworkService {
+	return &NetworkService{
+		networkRepo: networkRepo,
+		logger:      logger,
+	}
+}
+
+// ListNetworks returns the networks by name, optionally only active ones
+func (s *NetworkService) ListNetworks(ctx context.Context, activeOnly bool) ([]*models.Network, error) {
+	networks, err := s.networkRepo.ListNetworks(ctx, activeOnly)
+	if err != nil {
+		return nil, models.NewDatabaseError("failed to list networks", err)
+	}
+	if networks == nil {
+		networks = []*models.Network{}
+	}
+	return networks, nil
+}
+
+// GetNetwork returns a network by name
+func (s *NetworkService) GetNetwork(ctx context.Context, name string) (*models.Network, error) {
+	network, err := s.networkRepo.GetNetwork(ctx, name)
+	if err != nil {
+		return nil, models.NewDatabaseError("failed to get network", err)
+	}
+	if network == nil {
+		return nil, models.NewNotFoundError(fmt.Sprintf("network not found: %s", name))
+	}
+	return network, nil
+}
+
+// SaveNetwork validates and stores a network. The mainnet and testnet
+// kinds are reserved for the networks of the same name, since they sync
+// the lists shipped with Pactus.
+func (s *NetworkService) SaveNetwork(ctx context.Context, network *models.Network) error {
+	if err := validateNetwork(network); err != nil {
+		return models.NewValidationError("invalid network", err.Error())
+	}
+	if err := s.networkRepo.SaveNetwork(ctx, network); err != nil {
+		return models.NewDatabaseError("failed to save network", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"network":   network.Name,
+		"kind":      network.Kind,
+		"bootstrap": len(network.BootstrapAddresses),
+		"grpc":      len(network.GRPCServers),
+		"active":    network.IsActive,
+	}).Info("Saved network")
+	return nil
+}
+
+// activeNetworks returns the networks to sync and snapshot. Without a
+// network service only mainnet and testnet are followed.
+func activeNetworks(ctx context.Context, s *NetworkService) ([]*models.Network, error) {
+	if s == nil {
+		return []*models.Network{
+			{Name: models.NetworkKindMainnet, Kind: models.NetworkKindMainnet, IsActive: true},
+			{Name: models.NetworkKindTestnet, Kind: models.NetworkKindTestnet, IsActive: true},
+		}, nil
+	}
+	return s.ListNetworks(ctx, true)
+}
+
+// defaultNetwork fills in DefaultNetwork for the empty network of API
+// params and checks the name
+func defaultNetwork(network *string) error {
+	*network = models.NetworkOrDefault(*network)
+	return models.ValidateNetworkName(*network)
+}
+
+// validateNetwork checks the name, kind and addresses of a network
+func validateNetwork(network *models.Network) error {
+	if err := models.ValidateNetworkName(network.Name); err != nil {
+		return err
+	}
+	if err := models.ValidateNetworkKind(network.Kind); err != nil {
+		return err
+	}
+	if (network.Kind == models.NetworkKindMainnet || network.Kind == models.NetworkKindTestnet) && network.Kind != network.Name {
+		return fmt.Errorf("kind %s is reserved for the %s network", network.Kind, network.Kind)
+	}
+	for _, address := range network.BootstrapAddresses {
+		if _, err := parseMultiaddr(address); err != nil {
+			return err
+		}
+	}
+	for _, address := range network.GRPCServers {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return fmt.Errorf("gRPC server %q must be host:port", address)
+		}
+	}
+	return nil
+}
+
+// networkNodes returns the nodes a network syncs: those its source lists
+// followed by the addresses configured on the network, without duplicates.
+// Nodes without a name are named by name.
+func networkNodes(ctx context.Context, source NodeSource, network *models.Network, addresses []string, name func(string) string) ([]*SourceNode, error) {
+	listed, err := source.Nodes(ctx, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s nodes from %s: %w", network.Name, source.Name(), err)
+	}
+	for _, address := range addresses {
+		listed = append(listed, &SourceNode{Address: address})
+	}
+
+	nodes := make([]*SourceNode, 0, len(listed))
+	seen := make(map[string]bool, len(listed))
+	for _, node := range listed {
+		if seen[node.Address] {
+			continue
+		}
+		seen[node.Address] = true
+		if node.Name == "" {
+			node.Name = name(node.Address)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// bootstrapNodeName names a bootstrap node after the host of its address
+func bootstrapNodeName(address string) string {
+	endpoint, err := parseMultiaddr(address)
+	if err != nil {
+		return address
+	}
+	return endpoint.Host
+}
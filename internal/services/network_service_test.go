@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func TestValidateNetwork(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     models.Network
+		expectError bool
+	}{
+		{
+			name:    "Mainnet",
+			network: models.Network{Name: "mainnet", Kind: models.NetworkKindMainnet},
+		},
+		{
+			name: "Custom network with addresses",
+			network: models.Network{
+				Name:               "devnet-1",
+				Kind:               models.NetworkKindCustom,
+				BootstrapAddresses: []string{"/ip4/10.0.0.1/tcp/21888/p2p/12D3KooWPxG5TnY"},
+				GRPCServers:        []string{"10.0.0.1:50051"},
+			},
+		},
+		{
+			name:        "Uppercase name",
+			network:     models.Network{Name: "DevNet", Kind: models.NetworkKindCustom},
+			expectError: true,
+		},
+		{
+			name:        "Unknown kind",
+			network:     models.Network{Name: "devnet", Kind: "staging"},
+			expectError: true,
+		},
+		{
+			name:        "Reserved kind",
+			network:     models.Network{Name: "devnet", Kind: models.NetworkKindTestnet},
+			expectError: true,
+		},
+		{
+			name:        "Invalid bootstrap address",
+			network:     models.Network{Name: "devnet", Kind: models.NetworkKindCustom, BootstrapAddresses: []string{"10.0.0.1:21888"}},
+			expectError: true,
+		},
+		{
+			name:        "gRPC server without port",
+			network:     models.Network{Name: "devnet", Kind: models.NetworkKindCustom, GRPCServers: []string{"10.0.0.1"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNetwork(&tt.network)
+			if tt.expectError && err == nil {
+				t.Error("Expected an error")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNetworkNodes(t *testing.T) {
+	ctx := context.Background()
+	bootstrapSource := &pactusSource{nodeType: models.NodeTypeBootstrap}
+
+	testnet := &models.Network{Name: "testnet", Kind: models.NetworkKindTestnet}
+	shipped, err := networkNodes(ctx, bootstrapSource, testnet, nil, bootstrapNodeName)
+	if err != nil {
+		t.Fatalf("networkNodes: %v", err)
+	}
+	if len(shipped) == 0 {
+		t.Fatal("Expected the testnet bootstrap nodes shipped with Pactus")
+	}
+	if shipped[0].Name == "" {
+		t.Error("Expected shipped nodes without a name to be named after their host")
+	}
+
+	// Configured addresses follow the listed ones, without duplicates
+	extra := "/dns/node.example.org/tcp/21888/p2p/12D3KooWPxG5TnY"
+	nodes, err := networkNodes(ctx, bootstrapSource, testnet, []string{shipped[0].Address, extra, extra}, bootstrapNodeName)
+	if err != nil {
+		t.Fatalf("networkNodes: %v", err)
+	}
+	if len(nodes) != len(shipped)+1 {
+		t.Fatalf("Expected %d nodes, got %d", len(shipped)+1, len(nodes))
+	}
+	last := nodes[len(nodes)-1]
+	if last.Address != extra || last.Name != "node.example.org" {
+		t.Errorf("Expected the configured node named after its host, got %+v", last)
+	}
+
+	// Custom networks have no shipped list
+	custom := &models.Network{Name: "devnet", Kind: models.NetworkKindCustom}
+	nodes, err = networkNodes(ctx, bootstrapSource, custom, []string{extra}, bootstrapNodeName)
+	if err != nil {
+		t.Fatalf("networkNodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Address != extra {
+		t.Errorf("Expected only the configured node, got %d nodes", len(nodes))
+	}
+
+	grpcSource := &pactusSource{nodeType: models.NodeTypeGRPC}
+	servers, err := networkNodes(ctx, grpcSource, &models.Network{Name: "mainnet", Kind: models.NetworkKindMainnet}, nil, bootstrapNodeName)
+	if err != nil {
+		t.Fatalf("networkNodes: %v", err)
+	}
+	if len(servers) == 0 {
+		t.Error("Expected the mainnet servers shipped with Pactus")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
 	"github.com/sirupsen/logrus"
@@ -16,7 +17,11 @@ type NetworkStatsService struct {
 	jsonrpcRepo  repositories.JSONRPCServerRepository
 	bootstrapRepo repositories.BootstrapRepository
 	snapshotRepo repositories.SnapshotRepository
+	mapRepo      repositories.MapRepository
 	geoService   *GeoLocationService
+	churnService *ChurnService
+	networkService *NetworkService
+	eventBus     *events.Bus
 	logger       *logrus.Logger
 }
 
@@ -27,7 +32,11 @@ func NewNetworkStatsService(
 	jsonrpcRepo repositories.JSONRPCServerRepository,
 	bootstrapRepo repositories.BootstrapRepository,
 	snapshotRepo repositories.SnapshotRepository,
+	mapRepo repositories.MapRepository,
 	geoService *GeoLocationService,
+	churnService *ChurnService,
+	networkService *NetworkService,
+	eventBus *events.Bus,
 	logger *logrus.Logger,
 ) *NetworkStatsService {
 	return &NetworkStatsService{
@@ -36,29 +45,28 @@ func NewNetworkStatsService(
 		jsonrpcRepo:  jsonrpcRepo,
 		bootstrapRepo: bootstrapRepo,
 		snapshotRepo: snapshotRepo,
+		mapRepo:      mapRepo,
 		geoService:   geoService,
+		churnService: churnService,
+		networkService: networkService,
+		eventBus:     eventBus,
 		logger:       logger,
 	}
 }
 
-// GetNetworkStats returns current network statistics
-func (s *NetworkStatsService) GetNetworkStats(ctx context.Context) (*models.NetworkStats, error) {
+// GetNetworkStats returns current statistics of a network
+func (s *NetworkStatsService) GetNetworkStats(ctx context.Context, network string) (*models.NetworkStats, error) {
 	// Get peer counts
-	reachablePeers, _ := s.peerRepo.CountReachable(ctx)
-	avgUptime, _ := s.peerRepo.GetAvgUptime(ctx)
-
-	// Get server counts
-	grpcCount, _ := s.grpcRepo.GetServerCount(ctx, true)
-	jsonrpcCount, _ := s.jsonrpcRepo.GetServerCount(ctx, true)
-	bootstrapCount, _ := s.bootstrapRepo.GetActiveCount(ctx)
-
-	totalNodes := reachablePeers + grpcCount + jsonrpcCount + bootstrapCount
+	reachablePeers, _ := s.peerRepo.CountReachable(ctx, network)
+	avgUptime, _ := s.peerRepo.GetAvgUptime(ctx, network)
 
 	// Calculate stats from all sources
 	countryMap := make(map[string]int)
-	
+	var grpcCount, jsonrpcCount, bootstrapCount int
+
 	// Process gRPC servers
-	if grpcServers, err := s.grpcRepo.GetActiveServers(ctx); err == nil {
+	if grpcServers, err := s.grpcRepo.GetServersByNetwork(ctx, network); err == nil {
+		grpcCount = len(grpcServers)
 		for _, server := range grpcServers {
 			if server.Country != "" {
 				countryMap[server.Country]++
@@ -68,8 +76,15 @@ func (s *NetworkStatsService) GetNetworkStats(ctx context.Context) (*models.Netw
 		s.logger.WithError(err).Warn("Failed to get gRPC servers for stats")
 	}
 
+	if jsonrpcServers, err := s.jsonrpcRepo.GetServersByNetwork(ctx, network); err == nil {
+		jsonrpcCount = len(jsonrpcServers)
+	} else {
+		s.logger.WithError(err).Warn("Failed to get JSON-RPC servers for stats")
+	}
+
 	// Process bootstrap nodes
-	if bootstrapNodes, err := s.bootstrapRepo.GetActiveNodes(ctx); err == nil {
+	if bootstrapNodes, err := s.bootstrapRepo.GetNodesByNetwork(ctx, network); err == nil {
+		bootstrapCount = len(bootstrapNodes)
 		for _, node := range bootstrapNodes {
 			if node.Country != "" {
 				countryMap[node.Country]++
@@ -79,9 +94,11 @@ func (s *NetworkStatsService) GetNetworkStats(ctx context.Context) (*models.Netw
 		s.logger.WithError(err).Warn("Failed to get bootstrap nodes for stats")
 	}
 
+	totalNodes := reachablePeers + grpcCount + jsonrpcCount + bootstrapCount
+
 	// Process peers (if any)
 	// We use repository aggregation for peers to avoid loading all into memory if many
-	if peerCountries, err := s.peerRepo.CountCountries(ctx); err == nil && peerCountries > 0 {
+	if peerCountries, err := s.peerRepo.CountCountries(ctx, network); err == nil && peerCountries > 0 {
 		// For detailed breakdown we would need to query group by, skipping for now
 		// or we could assume peerRepo.GetTopCountries includes the counts we need
 	}
@@ -100,6 +117,7 @@ func (s *NetworkStatsService) GetNetworkStats(ctx context.Context) (*models.Netw
 	// TODO: Implement sorting if list grows large
 
 	return &models.NetworkStats{
+		Network:        network,
 		TotalNodes:     totalNodes,
 		ReachableNodes: reachablePeers,
 		CountriesCount: len(countryMap),
@@ -111,13 +129,13 @@ func (s *NetworkStatsService) GetNetworkStats(ctx context.Context) (*models.Netw
 	}, nil
 }
 
-// GetMapNodes returns all nodes formatted for map display
-func (s *NetworkStatsService) GetMapNodes(ctx context.Context) ([]models.MapNode, error) {
+// GetMapNodes returns all nodes of a network formatted for map display
+func (s *NetworkStatsService) GetMapNodes(ctx context.Context, network string) ([]models.MapNode, error) {
 	mapNodes := make([]models.MapNode, 0)
 
 
 	// Get gRPC servers
-	grpcServers, err := s.grpcRepo.GetActiveServers(ctx)
+	grpcServers, err := s.grpcRepo.GetServersByNetwork(ctx, network)
 	if err == nil {
 		for _, server := range grpcServers {
 			if server.Latitude != 0 || server.Longitude != 0 {
@@ -139,7 +157,7 @@ func (s *NetworkStatsService) GetMapNodes(ctx context.Context) ([]models.MapNode
 	}
 
 	// Get JSON-RPC servers
-	jsonrpcServers, err := s.jsonrpcRepo.GetActiveServers(ctx)
+	jsonrpcServers, err := s.jsonrpcRepo.GetServersByNetwork(ctx, network)
 	if err == nil {
 		for _, server := range jsonrpcServers {
 			if server.Latitude != 0 || server.Longitude != 0 {
@@ -161,7 +179,7 @@ func (s *NetworkStatsService) GetMapNodes(ctx context.Context) ([]models.MapNode
 	}
 
 	// Get bootstrap nodes
-	bootstrapNodes, err := s.bootstrapRepo.GetActiveNodes(ctx)
+	bootstrapNodes, err := s.bootstrapRepo.GetNodesByNetwork(ctx, network)
 	if err == nil {
 		for _, node := range bootstrapNodes {
 			if node.Latitude != 0 || node.Longitude != 0 {
@@ -183,7 +201,7 @@ func (s *NetworkStatsService) GetMapNodes(ctx context.Context) ([]models.MapNode
 	}
 
 	// Get reachable peers
-	peers, err := s.peerRepo.GetReachablePeers(ctx)
+	peers, err := s.peerRepo.GetReachablePeers(ctx, network)
 	if err == nil {
 		for _, peer := range peers {
 			if peer.Latitude != 0 || peer.Longitude != 0 {
@@ -207,15 +225,52 @@ func (s *NetworkStatsService) GetMapNodes(ctx context.Context) ([]models.MapNode
 	return mapNodes, nil
 }
 
-// CreateSnapshot creates a new network snapshot
+// ListMapNodes returns one page of map nodes. The filter must have been
+// validated.
+func (s *NetworkStatsService) ListMapNodes(ctx context.Context, filter models.MapFilter) (*models.MapNodePage, error) {
+	nodes, err := s.mapRepo.ListMapNodes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.MapNodePage{Items: make([]models.MapNode, 0, len(nodes))}
+	if len(nodes) > filter.Limit {
+		nodes = nodes[:filter.Limit]
+		last := nodes[len(nodes)-1]
+		page.NextCursor = models.PageCursor{Sort: "type", Order: "asc", Value: last.Type, ID: last.ID}.Encode()
+	}
+	page.Items = append(page.Items, nodes...)
+
+	return page, nil
+}
+
+// CreateSnapshot creates a new snapshot of every active network
 func (s *NetworkStatsService) CreateSnapshot(ctx context.Context) error {
-	stats, err := s.GetNetworkStats(ctx)
+	networks, err := activeNetworks(ctx, s.networkService)
 	if err != nil {
 		return err
 	}
 
+	for _, network := range networks {
+		if err := s.createNetworkSnapshot(ctx, network.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createNetworkSnapshot creates a snapshot of one network. The churn of the
+// last SnapshotChurnDays days is stored with it when churn is tracked.
+func (s *NetworkStatsService) createNetworkSnapshot(ctx context.Context, network string) error {
+	stats, err := s.GetNetworkStats(ctx, network)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
 	snapshot := &models.NetworkSnapshot{
-		Timestamp:      time.Now(),
+		Network:        network,
+		Timestamp:      now,
 		TotalNodes:     stats.TotalNodes,
 		ReachableNodes: stats.ReachableNodes,
 		CountriesCount: stats.CountriesCount,
@@ -224,15 +279,29 @@ func (s *NetworkStatsService) CreateSnapshot(ctx context.Context) error {
 		BootstrapNodes: stats.BootstrapNodes,
 	}
 
-	return s.snapshotRepo.CreateSnapshot(ctx, snapshot)
+	if s.churnService != nil {
+		churn, err := s.churnService.GetChurnStats(ctx, network, now.AddDate(0, 0, -(SnapshotChurnDays-1)), now)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to compute churn for snapshot")
+		} else {
+			snapshot.SnapshotData = &models.SnapshotData{Churn: churn}
+		}
+	}
+
+	if err := s.snapshotRepo.CreateSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+
+	s.eventBus.Publish(ctx, events.Event{Type: events.SnapshotCreated, Data: snapshot})
+	return nil
 }
 
-// GetSnapshots returns recent network snapshots
-func (s *NetworkStatsService) GetSnapshots(ctx context.Context, limit int) ([]*models.NetworkSnapshot, error) {
+// GetSnapshots returns recent snapshots of a network
+func (s *NetworkStatsService) GetSnapshots(ctx context.Context, network string, limit int) ([]*models.NetworkSnapshot, error) {
 	if limit <= 0 {
 		limit = 10
 	}
-	return s.snapshotRepo.GetSnapshots(ctx, limit)
+	return s.snapshotRepo.GetSnapshots(ctx, network, limit)
 }
 
 // UpdateAllGeoLocations updates geo data for all nodes without geo data
@@ -266,8 +335,6 @@ func (s *NetworkStatsService) UpdateAllGeoLocations(ctx context.Context) error {
 							"lon":     geo.Longitude,
 						}).Info("Updated geo for gRPC server")
 					}
-					// Rate limit: 45 requests per minute
-					time.Sleep(1500 * time.Millisecond)
 				} else {
 					s.logger.WithField("address", server.Address).Warn("Geo lookup returned no success status")
 				}
@@ -299,8 +366,6 @@ func (s *NetworkStatsService) UpdateAllGeoLocations(ctx context.Context) error {
 							"lon":     geo.Longitude,
 						}).Info("Updated geo for bootstrap node")
 					}
-					// Rate limit: 45 requests per minute
-					time.Sleep(1500 * time.Millisecond)
 				} else {
 					s.logger.WithField("address", node.Address).Warn("Geo lookup returned no success status")
 				}
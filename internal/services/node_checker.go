@@ -2,119 +2,203 @@ package services
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
-	"strings"
 	"time"
 
+	"github.com/quic-go/quic-go"
 	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/pkg/retry"
 )
 
 type NodeChecker struct {
-	timeout    time.Duration
-	maxRetries int
-	logger     *logrus.Logger
+	timeout  time.Duration
+	policy   retry.Policy
+	lookupIP func(ctx context.Context, network, host string) ([]net.IP, error)
+	logger   *logrus.Logger
 }
 
 func NewNodeChecker(timeout time.Duration, maxRetries int, logger *logrus.Logger) *NodeChecker {
 	return &NodeChecker{
-		timeout:    timeout,
-		maxRetries: maxRetries,
-		logger:     logger,
+		timeout:  timeout,
+		policy:   retry.DefaultPolicy(maxRetries),
+		lookupIP: net.DefaultResolver.LookupIP,
+		logger:   logger,
 	}
 }
 
 type CheckResult struct {
-	Success  bool
-	Attempts int
-	ErrorMsg string
-	Duration time.Duration
+	Success        bool
+	Attempts       int
+	ErrorMsg       string
+	ErrorClass     models.ErrorClass
+	Duration       time.Duration
+	ResponseTimeMs int
+	Samples        []models.CheckAttempt
 }
 
 func (nc *NodeChecker) CheckNode(ctx context.Context, address string) *CheckResult {
 	result := &CheckResult{}
 
-	host, port, err := nc.parseAddress(address)
+	endpoint, err := parseMultiaddr(address)
 	if err != nil {
-		result.ErrorMsg = fmt.Sprintf("failed to parse address: %v", err)
+		result.ErrorMsg = err.Error()
+		result.ErrorClass = ClassifyError(err)
 		return result
 	}
 
 	start := time.Now()
 
-	for attempt := 1; attempt <= nc.maxRetries; attempt++ {
-		result.Attempts = attempt
-
-		if nc.attemptConnection(ctx, host, port) {
-			result.Success = true
-			result.Duration = time.Since(start)
-			nc.logger.WithFields(logrus.Fields{
-				"address":  address,
-				"attempts": attempt,
-				"duration": result.Duration,
-			}).Info("Node connection successful")
-			return result
+	attempts, err := retry.Do(ctx, nc.policy, func(ctx context.Context, attempt int) error {
+		attemptStart := time.Now()
+		err := nc.probe(ctx, endpoint)
+		sample := newCheckAttempt(attempt, attemptStart, err)
+		result.Samples = append(result.Samples, sample)
+		if err == nil {
+			result.ResponseTimeMs = sample.LatencyMs
 		}
+		return err
+	})
+	result.Attempts = attempts
+	result.Duration = time.Since(start)
 
-		if attempt < nc.maxRetries {
-			time.Sleep(time.Second * 2) // Wait between retries
-		}
+	if err == nil {
+		result.Success = true
+		nc.logger.WithFields(logrus.Fields{
+			"address":  address,
+			"attempts": attempts,
+			"duration": result.Duration,
+		}).Info("Node connection successful")
+		return result
 	}
 
-	result.Duration = time.Since(start)
-	result.ErrorMsg = fmt.Sprintf("failed to connect after %d attempts", nc.maxRetries)
+	result.ErrorClass = ClassifyError(err)
+	result.ErrorMsg = fmt.Sprintf("failed to connect after %d attempts: %v", attempts, err)
 
 	nc.logger.WithFields(logrus.Fields{
-		"address":  address,
-		"attempts": result.Attempts,
-		"duration": result.Duration,
+		"address":     address,
+		"attempts":    result.Attempts,
+		"duration":    result.Duration,
+		"error_class": result.ErrorClass,
 	}).Warn("Node connection failed")
 
 	return result
 }
 
-func (nc *NodeChecker) parseAddress(address string) (string, string, error) {
-	// Parse different address formats:
-	// /dns/bootstrap1.pactus.org/tcp/21888/p2p/...
-	// /ip4/65.108.211.187/tcp/21888/p2p/...
-
-	parts := strings.Split(address, "/")
-	if len(parts) < 5 {
-		return "", "", fmt.Errorf("invalid address format")
+// CheckEndpoints resolves a node address to its IPv4 and IPv6 addresses
+// and probes TCP and QUIC once on each. Pactus nodes listen for both on the
+// same port, so both are probed whichever one the address names.
+func (nc *NodeChecker) CheckEndpoints(ctx context.Context, address string) []*models.EndpointCheck {
+	endpoint, err := parseMultiaddr(address)
+	if err != nil {
+		return nil
 	}
 
-	var host, port string
+	ips, err := nc.resolve(ctx, endpoint)
+	if err != nil {
+		nc.logger.WithError(err).WithField("address", address).Debug("Failed to resolve node address")
+		return nil
+	}
 
-	for i := 0; i < len(parts)-1; i++ {
-		switch parts[i] {
-		case "dns", "ip4", "ip6":
-			if i+1 < len(parts) {
-				host = parts[i+1]
+	var checks []*models.EndpointCheck
+	for _, ip := range ips {
+		for _, transport := range []string{models.TransportTCP, models.TransportQUIC} {
+			resolved := resolvedEndpoint(ip, transport, endpoint.Port)
+			start := time.Now()
+			err := nc.probe(ctx, resolved)
+			sample := newCheckAttempt(1, start, err)
+
+			check := &models.EndpointCheck{
+				Multiaddr:  resolved.Multiaddr,
+				Family:     resolved.Family,
+				Transport:  resolved.Transport,
+				Reachable:  sample.Success,
+				ErrorClass: sample.ErrorClass,
+				CheckedAt:  sample.CheckedAt,
 			}
-		case "tcp":
-			if i+1 < len(parts) {
-				port = parts[i+1]
+			if sample.Success {
+				check.ResponseTimeMs = sample.LatencyMs
 			}
+			checks = append(checks, check)
 		}
 	}
 
-	if host == "" || port == "" {
-		return "", "", fmt.Errorf("could not extract host and port from address")
+	return checks
+}
+
+// resolve returns the IPs of an endpoint, looking up DNS names in the
+// family the address asks for
+func (nc *NodeChecker) resolve(ctx context.Context, endpoint *models.Endpoint) ([]net.IP, error) {
+	if ip := net.ParseIP(endpoint.Host); ip != nil {
+		return []net.IP{ip}, nil
 	}
 
-	return host, port, nil
+	ctx, cancel := context.WithTimeout(ctx, nc.timeout)
+	defer cancel()
+
+	return nc.lookupIP(ctx, lookupNetwork(endpoint.Family), endpoint.Host)
 }
 
-func (nc *NodeChecker) attemptConnection(ctx context.Context, host, port string) bool {
+// probe tries to reach an endpoint over its transport once
+func (nc *NodeChecker) probe(ctx context.Context, endpoint *models.Endpoint) error {
+	if endpoint.Transport == models.TransportQUIC {
+		return nc.attemptQUIC(ctx, endpoint.Host, endpoint.Port)
+	}
+	return nc.attemptConnection(ctx, endpoint.Host, endpoint.Port)
+}
+
+func (nc *NodeChecker) attemptConnection(ctx context.Context, host, port string) error {
 	ctx, cancel := context.WithTimeout(ctx, nc.timeout)
 	defer cancel()
 
 	dialer := &net.Dialer{}
 	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
 	if err != nil {
-		return false
+		return err
 	}
 	defer conn.Close()
 
-	return true
+	return nil
+}
+
+// attemptQUIC starts a QUIC handshake with the libp2p ALPN. libp2p asks the
+// client for a certificate it does not have, so a handshake the node
+// rejects still shows the node is reachable over QUIC.
+func (nc *NodeChecker) attemptQUIC(ctx context.Context, host, port string) error {
+	ctx, cancel := context.WithTimeout(ctx, nc.timeout)
+	defer cancel()
+
+	tlsConf := &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"libp2p"},
+	}
+	conn, err := quic.DialAddr(ctx, net.JoinHostPort(host, port), tlsConf, &quic.Config{HandshakeIdleTimeout: nc.timeout})
+	if err != nil {
+		if quicAnswered(err) {
+			return nil
+		}
+		return err
+	}
+
+	return conn.CloseWithError(0, "")
+}
+
+// quicAnswered reports whether a failed QUIC dial got an answer from the
+// remote end
+func quicAnswered(err error) bool {
+	var transportErr *quic.TransportError
+	if errors.As(err, &transportErr) {
+		return transportErr.Remote
+	}
+	var appErr *quic.ApplicationError
+	if errors.As(err, &appErr) {
+		return appErr.Remote
+	}
+	var versionErr *quic.VersionNegotiationError
+	var resetErr *quic.StatelessResetError
+	return errors.As(err, &versionErr) || errors.As(err, &resetErr)
 }
@@ -2,76 +2,14 @@ package services
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
-)
-
-func TestNodeChecker_ParseAddress(t *testing.T) {
-	logger := logrus.New()
-	logger.SetLevel(logrus.ErrorLevel) // Reduce noise in tests
-	nc := NewNodeChecker(5*time.Second, 3, logger)
-
-	tests := []struct {
-		name        string
-		address     string
-		expectHost  string
-		expectPort  string
-		expectError bool
-	}{
-		{
-			name:        "Valid DNS address",
-			address:     "/dns/bootstrap1.pactus.org/tcp/21888/p2p/12D3KooWPxG5TnY",
-			expectHost:  "bootstrap1.pactus.org",
-			expectPort:  "21888",
-			expectError: false,
-		},
-		{
-			name:        "Valid IP4 address",
-			address:     "/ip4/65.108.211.187/tcp/21888/p2p/12D3KooWPxG5TnY",
-			expectHost:  "65.108.211.187",
-			expectPort:  "21888",
-			expectError: false,
-		},
-		{
-			name:        "Invalid address format",
-			address:     "invalid-address",
-			expectError: true,
-		},
-		{
-			name:        "Empty address",
-			address:     "",
-			expectError: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			host, port, err := nc.parseAddress(tt.address)
-
-			if tt.expectError {
-				if err == nil {
-					t.Errorf("Expected error but got none")
-				}
-				return
-			}
 
-			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
-				return
-			}
-
-			if host != tt.expectHost {
-				t.Errorf("Expected host %s, got %s", tt.expectHost, host)
-			}
-
-			if port != tt.expectPort {
-				t.Errorf("Expected port %s, got %s", tt.expectPort, port)
-			}
-		})
-	}
-}
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
 
 func TestNodeChecker_CheckNode(t *testing.T) {
 	logger := logrus.New()
@@ -94,7 +32,7 @@ func TestNodeChecker_CheckNode(t *testing.T) {
 
 	t.Run("Valid address format", func(t *testing.T) {
 		// Use a valid address format (will likely fail to connect but should parse correctly)
-		result := nc.CheckNode(ctx, "/ip4/127.0.0.1/tcp/99999/p2p/test")
+		result := nc.CheckNode(ctx, "/ip4/127.0.0.1/tcp/9/p2p/test")
 
 		// We expect this to fail to connect, but the address should parse
 		if result.Attempts == 0 {
@@ -102,3 +40,35 @@ func TestNodeChecker_CheckNode(t *testing.T) {
 		}
 	})
 }
+
+func TestNodeChecker_RecordsAttemptSamples(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	nc := NewNodeChecker(time.Second, 2, logger)
+	nc.policy.InitialDelay = time.Millisecond
+
+	result := nc.CheckNode(context.Background(), "/ip4/"+host+"/tcp/"+port)
+
+	if result.Success {
+		t.Fatal("Expected closed port to fail")
+	}
+	if len(result.Samples) != result.Attempts {
+		t.Fatalf("Expected %d samples, got %d", result.Attempts, len(result.Samples))
+	}
+	for i, sample := range result.Samples {
+		if sample.Attempt != i+1 {
+			t.Errorf("Sample %d: expected attempt %d, got %d", i, i+1, sample.Attempt)
+		}
+		if sample.Success || sample.ErrorClass != models.ErrorClassConnectionRefused {
+			t.Errorf("Sample %d: expected refused failure, got %+v", i, sample)
+		}
+	}
+}
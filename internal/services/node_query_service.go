@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// MaxStatusRangeDays caps the date range of a status history query
+const MaxStatusRangeDays = 366
+
+// NodeQueryService answers read-only queries about nodes of any type for
+// the REST API
+type NodeQueryService struct {
+	bootstrapMonitor  *BootstrapMonitor
+	grpcMonitor       *GRPCMonitor
+	jsonrpcMonitor    *JSONRPCMonitorService
+	bootstrapRepo     repositories.BootstrapRepository
+	statusRepo        repositories.StatusRepository
+	grpcRepo          repositories.GRPCRepository
+	grpcStatusRepo    repositories.GRPCStatusRepository
+	jsonrpcRepo       repositories.JSONRPCServerRepository
+	jsonrpcStatusRepo repositories.JSONRPCStatusRepository
+	logger            *logrus.Logger
+}
+
+// NewNodeQueryService creates a new node query service
+func NewNodeQueryService(
+	bootstrapMonitor *BootstrapMonitor,
+	grpcMonitor *GRPCMonitor,
+	jsonrpcMonitor *JSONRPCMonitorService,
+	bootstrapRepo repositories.BootstrapRepository,
+	statusRepo repositories.StatusRepository,
+	grpcRepo repositories.GRPCRepository,
+	grpcStatusRepo repositories.GRPCStatusRepository,
+	jsonrpcRepo repositories.JSONRPCServerRepository,
+	jsonrpcStatusRepo repositories.JSONRPCStatusRepository,
+	logger *logrus.Logger,
+) *NodeQueryService {
+	return &NodeQueryService{
+		bootstrapMonitor:  bootstrapMonitor,
+		grpcMonitor:       grpcMonitor,
+		jsonrpcMonitor:    jsonrpcMonitor,
+		bootstrapRepo:     bootstrapRepo,
+		statusRepo:        statusRepo,
+		grpcRepo:          grpcRepo,
+		grpcStatusRepo:    grpcStatusRepo,
+		jsonrpcRepo:       jsonrpcRepo,
+		jsonrpcStatusRepo: jsonrpcStatusRepo,
+		logger:            logger,
+	}
+}
+
+// ListNodes returns one page of active nodes of a type with their 30-day
+// status. An empty network lists the nodes of every network.
+func (s *NodeQueryService) ListNodes(ctx context.Context, nodeType string, filter models.NodeFilter) (interface{}, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, models.NewValidationError("invalid node filter", err.Error())
+	}
+
+	switch nodeType {
+	case models.NodeTypeBootstrap:
+		page, err := s.bootstrapMonitor.ListBootstrapNodes(ctx, filter)
+		if err != nil {
+			return nil, models.NewDatabaseError("failed to list bootstrap nodes", err)
+		}
+		return page, nil
+	case models.NodeTypeGRPC:
+		page, err := s.grpcMonitor.ListGRPCServers(ctx, filter)
+		if err != nil {
+			return nil, models.NewDatabaseError("failed to list gRPC servers", err)
+		}
+		return page, nil
+	case models.NodeTypeJSONRPC:
+		page, err := s.jsonrpcMonitor.ListServers(ctx, filter)
+		if err != nil {
+			return nil, models.NewDatabaseError("failed to list JSON-RPC servers", err)
+		}
+		return page, nil
+	}
+	return nil, invalidNodeType(nodeType)
+}
+
+// GetNode returns a single node and when it was last updated
+func (s *NodeQueryService) GetNode(ctx context.Context, nodeType string, id int) (interface{}, time.Time, error) {
+	var (
+		node      interface{}
+		updatedAt time.Time
+		err       error
+	)
+
+	switch nodeType {
+	case models.NodeTypeBootstrap:
+		var n *models.BootstrapNode
+		if n, err = s.bootstrapRepo.GetNodeByID(ctx, id); err == nil && n != nil {
+			node, updatedAt = n, n.UpdatedAt
+		}
+	case models.NodeTypeGRPC:
+		var n *models.GRPCServer
+		if n, err = s.grpcRepo.GetServerByID(ctx, id); err == nil && n != nil {
+			node, updatedAt = n, n.UpdatedAt
+		}
+	case models.NodeTypeJSONRPC:
+		var n *models.JSONRPCServer
+		if n, err = s.jsonrpcRepo.GetServerByID(ctx, id); err == nil && n != nil {
+			node, updatedAt = n, n.UpdatedAt
+		}
+	default:
+		return nil, time.Time{}, invalidNodeType(nodeType)
+	}
+
+	if err != nil {
+		return nil, time.Time{}, models.NewDatabaseError("failed to get node", err)
+	}
+	if node == nil {
+		return nil, time.Time{}, models.NewNotFoundError(fmt.Sprintf("%s node %d not found", nodeType, id))
+	}
+	return node, updatedAt, nil
+}
+
+// GetNodeStatuses returns the daily statuses of a node between from and to
+// (inclusive) and the time the newest of them was recorded
+func (s *NodeQueryService) GetNodeStatuses(ctx context.Context, nodeType string, id int, from, to time.Time) (interface{}, time.Time, error) {
+	if err := validateStatusRange(from, to); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if _, _, err := s.GetNode(ctx, nodeType, id); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var lastModified time.Time
+	track := func(t time.Time) {
+		if t.After(lastModified) {
+			lastModified = t
+		}
+	}
+
+	switch nodeType {
+	case models.NodeTypeBootstrap:
+		statuses, err := s.statusRepo.GetStatusesByNodeAndDateRange(ctx, id, from, to)
+		if err != nil {
+			return nil, time.Time{}, models.NewDatabaseError("failed to get statuses", err)
+		}
+		for _, status := range statuses {
+			track(status.CreatedAt)
+		}
+		return nonNil(statuses), lastModified, nil
+	case models.NodeTypeGRPC:
+		statuses, err := s.grpcStatusRepo.GetStatusesByServerAndDateRange(ctx, id, from, to)
+		if err != nil {
+			return nil, time.Time{}, models.NewDatabaseError("failed to get statuses", err)
+		}
+		for _, status := range statuses {
+			track(status.CreatedAt)
+		}
+		return nonNil(statuses), lastModified, nil
+	default:
+		statuses, err := s.jsonrpcStatusRepo.GetStatusesByServerAndDateRange(ctx, id, from, to)
+		if err != nil {
+			return nil, time.Time{}, models.NewDatabaseError("failed to get statuses", err)
+		}
+		for _, status := range statuses {
+			track(status.CreatedAt)
+		}
+		return nonNil(statuses), lastModified, nil
+	}
+}
+
+// validateStatusRange checks a status history date range
+func validateStatusRange(from, to time.Time) error {
+	if to.Before(from) {
+		return models.NewValidationError("invalid date range", "from must not be after to")
+	}
+	if to.Sub(from) > MaxStatusRangeDays*24*time.Hour {
+		return models.NewValidationError("invalid date range", fmt.Sprintf("range must not exceed %d days", MaxStatusRangeDays))
+	}
+	return nil
+}
+
+func invalidNodeType(nodeType string) *models.AppError {
+	return models.NewValidationError("invalid node type", fmt.Sprintf("%q is not one of bootstrap, grpc or jsonrpc", nodeType))
+}
+
+// nonNil makes empty lists encode as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
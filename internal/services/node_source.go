@@ -0,0 +1,258 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pactus-project/pactus/config"
+	"github.com/pactus-project/pactus/wallet"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// maxSourceSize caps the node lists read from files and URLs
+const maxSourceSize = 4 << 20
+
+// SourceNode is a bootstrap node or gRPC server as listed by a node source
+type SourceNode struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Website string `json:"website"`
+	Address string `json:"address"`
+}
+
+// NodeSource lists the bootstrap nodes or gRPC servers of a network
+type NodeSource interface {
+	// Name identifies the source in sync reports
+	Name() string
+	// Nodes returns the nodes the source lists for a network
+	Nodes(ctx context.Context, network *models.Network) ([]*SourceNode, error)
+}
+
+// NewNodeSource creates the source of a node type from its spec: "pactus"
+// (or empty) for the lists embedded in the Pactus module, an http(s) URL,
+// or the path of a local file, optionally prefixed with "file:"
+func NewNodeSource(spec, nodeType string, timeout time.Duration, logger *logrus.Logger) (NodeSource, error) {
+	if nodeType != models.NodeTypeBootstrap && nodeType != models.NodeTypeGRPC {
+		return nil, fmt.Errorf("node sources list bootstrap or grpc nodes, not %s", nodeType)
+	}
+
+	switch {
+	case spec == "" || spec == "pactus":
+		return &pactusSource{nodeType: nodeType}, nil
+	case strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://"):
+		return &urlSource{
+			url:    spec,
+			client: &http.Client{Timeout: timeout},
+			logger: logger,
+		}, nil
+	default:
+		path := strings.TrimPrefix(spec, "file:")
+		if path == "" {
+			return nil, fmt.Errorf("node source %q has no file path", spec)
+		}
+		return &fileSource{path: path}, nil
+	}
+}
+
+// pactusSource lists the bootstrap nodes and gRPC servers shipped with the
+// Pactus module, which needs no network access. Only mainnet and testnet
+// have such lists.
+type pactusSource struct {
+	nodeType string
+}
+
+func (s *pactusSource) Name() string {
+	return "pactus"
+}
+
+func (s *pactusSource) Nodes(ctx context.Context, network *models.Network) ([]*SourceNode, error) {
+	if network.Kind != models.NetworkKindMainnet && network.Kind != models.NetworkKindTestnet {
+		return nil, nil
+	}
+
+	var nodes []*SourceNode
+	if s.nodeType == models.NodeTypeGRPC {
+		servers, err := wallet.GetServerList(network.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s gRPC servers: %w", network.Kind, err)
+		}
+		for _, server := range servers {
+			nodes = append(nodes, &SourceNode{
+				Name:    server.Name,
+				Email:   server.Email,
+				Website: server.Website,
+				Address: server.Address,
+			})
+		}
+		return nodes, nil
+	}
+
+	if network.Kind == models.NetworkKindTestnet {
+		for _, address := range config.DefaultConfigTestnet().Network.DefaultBootstrapAddrStrings {
+			nodes = append(nodes, &SourceNode{Address: address})
+		}
+		return nodes, nil
+	}
+
+	infos, err := config.GetBootstrapNodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mainnet bootstrap nodes: %w", err)
+	}
+	for _, info := range infos {
+		nodes = append(nodes, &SourceNode{
+			Name:    info.Name,
+			Email:   info.Email,
+			Website: info.Website,
+			Address: info.Address,
+		})
+	}
+	return nodes, nil
+}
+
+// fileSource reads node lists from a local JSON file on every sync
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Name() string {
+	return "file:" + s.path
+}
+
+func (s *fileSource) Nodes(ctx context.Context, network *models.Network) ([]*SourceNode, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return parseSourceNodes(data, network.Name)
+}
+
+// urlSource fetches node lists from a URL. The last list fetched is kept
+// with its ETag: an unchanged list is not downloaded again, and while the
+// URL is unreachable the last list is used.
+type urlSource struct {
+	url    string
+	client *http.Client
+	logger *logrus.Logger
+
+	mu   sync.Mutex
+	etag string
+	body []byte
+}
+
+func (s *urlSource) Name() string {
+	return s.url
+}
+
+func (s *urlSource) Nodes(ctx context.Context, network *models.Network) ([]*SourceNode, error) {
+	body, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseSourceNodes(body, network.Name)
+}
+
+// fetch returns the current list, asking the server whether the cached
+// one is still current
+func (s *urlSource) fetch(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if s.etag != "" && s.body != nil {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return s.cached(err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		if s.body == nil {
+			return nil, fmt.Errorf("fetch %s: not modified without a cached list", s.url)
+		}
+		return s.body, nil
+	case http.StatusOK:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxSourceSize))
+		if err != nil {
+			return s.cached(fmt.Errorf("read response: %w", err))
+		}
+		if _, err := decodeSourceLists(body); err != nil {
+			return s.cached(err)
+		}
+		s.etag = resp.Header.Get("ETag")
+		s.body = body
+		return body, nil
+	default:
+		return s.cached(fmt.Errorf("HTTP %d", resp.StatusCode))
+	}
+}
+
+// cached returns the last list fetched after a failed fetch
+func (s *urlSource) cached(err error) ([]byte, error) {
+	if s.body == nil {
+		return nil, fmt.Errorf("fetch %s: %w", s.url, err)
+	}
+	s.logger.WithError(err).WithField("url", s.url).Warn("Node source unreachable, using the last list fetched")
+	return s.body, nil
+}
+
+// parseSourceNodes returns the nodes a file or URL lists for a network.
+// The JSON is either an object mapping network names to node lists, or a
+// single list, which is the mainnet list. A list holds addresses or node
+// objects.
+func parseSourceNodes(data []byte, network string) ([]*SourceNode, error) {
+	lists, err := decodeSourceLists(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*SourceNode
+	for i, entry := range lists[network] {
+		node := &SourceNode{}
+		if err := json.Unmarshal(entry, &node.Address); err != nil {
+			if err := json.Unmarshal(entry, node); err != nil {
+				return nil, fmt.Errorf("%s node %d is neither an address nor a node: %w", network, i, err)
+			}
+		}
+		if node.Address == "" {
+			return nil, fmt.Errorf("%s node %d has empty address", network, i)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// decodeSourceLists splits a node list document into the raw entries of
+// each network
+func decodeSourceLists(data []byte) (map[string][]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		return map[string][]json.RawMessage{models.DefaultNetwork: list}, nil
+	}
+
+	var lists map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &lists); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return lists, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+func TestParseSourceNodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		network     string
+		expect      []string
+		expectError bool
+	}{
+		{
+			name:    "Single list is mainnet",
+			data:    `[{"name": "a", "address": "/dns/a.example.org/tcp/21888"}]`,
+			network: "mainnet",
+			expect:  []string{"/dns/a.example.org/tcp/21888"},
+		},
+		{
+			name:    "Single list has no testnet nodes",
+			data:    `[{"name": "a", "address": "/dns/a.example.org/tcp/21888"}]`,
+			network: "testnet",
+		},
+		{
+			name:    "Lists per network with addresses and nodes",
+			data:    `{"mainnet": ["m1:50051"], "devnet": ["d1:50051", {"name": "d2", "address": "d2:50051"}]}`,
+			network: "devnet",
+			expect:  []string{"d1:50051", "d2:50051"},
+		},
+		{
+			name:        "Node without address",
+			data:        `{"mainnet": [{"name": "a"}]}`,
+			network:     "mainnet",
+			expectError: true,
+		},
+		{
+			name:        "Invalid JSON",
+			data:        `{"mainnet": `,
+			network:     "mainnet",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parseSourceNodes([]byte(tt.data), tt.network)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(nodes) != len(tt.expect) {
+				t.Fatalf("Expected %d nodes, got %d", len(tt.expect), len(nodes))
+			}
+			for i, address := range tt.expect {
+				if nodes[i].Address != address {
+					t.Errorf("Expected node %d at %s, got %s", i, address, nodes[i].Address)
+				}
+			}
+		})
+	}
+}
+
+func TestNewNodeSource(t *testing.T) {
+	logger := logrus.New()
+
+	tests := []struct {
+		spec   string
+		expect string
+	}{
+		{spec: "", expect: "pactus"},
+		{spec: "pactus", expect: "pactus"},
+		{spec: "https://example.org/servers.json", expect: "https://example.org/servers.json"},
+		{spec: "file:./servers.json", expect: "file:./servers.json"},
+		{spec: "./servers.json", expect: "file:./servers.json"},
+	}
+	for _, tt := range tests {
+		source, err := NewNodeSource(tt.spec, models.NodeTypeGRPC, time.Second, logger)
+		if err != nil {
+			t.Fatalf("NewNodeSource(%q): %v", tt.spec, err)
+		}
+		if source.Name() != tt.expect {
+			t.Errorf("Expected %q for %q, got %q", tt.expect, tt.spec, source.Name())
+		}
+	}
+
+	if _, err := NewNodeSource("pactus", models.NodeTypeJSONRPC, time.Second, logger); err == nil {
+		t.Error("Expected an error for JSON-RPC nodes")
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bootstrap.json")
+	if err := os.WriteFile(path, []byte(`{"testnet": ["/dns/t.example.org/tcp/21888"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &fileSource{path: path}
+	nodes, err := source.Nodes(context.Background(), &models.Network{Name: "testnet", Kind: models.NetworkKindTestnet})
+	if err != nil {
+		t.Fatalf("Nodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Address != "/dns/t.example.org/tcp/21888" {
+		t.Errorf("Expected the testnet node, got %+v", nodes)
+	}
+}
+
+func TestURLSource(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	var requests, downloads int
+	down := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`["a.example.org:50051", "b.example.org:50051"]`))
+	}))
+	defer server.Close()
+
+	source, err := NewNodeSource(server.URL, models.NodeTypeGRPC, time.Second, logger)
+	if err != nil {
+		t.Fatalf("NewNodeSource: %v", err)
+	}
+	mainnet := &models.Network{Name: "mainnet", Kind: models.NetworkKindMainnet}
+	ctx := context.Background()
+
+	// The second fetch revalidates the cached list instead of downloading it
+	for i := 0; i < 2; i++ {
+		nodes, err := source.Nodes(ctx, mainnet)
+		if err != nil {
+			t.Fatalf("Nodes: %v", err)
+		}
+		if len(nodes) != 2 {
+			t.Fatalf("Expected 2 nodes, got %d", len(nodes))
+		}
+	}
+	if requests != 2 || downloads != 1 {
+		t.Errorf("Expected 2 requests and 1 download, got %d and %d", requests, downloads)
+	}
+
+	// While the URL fails the last list is used
+	down = true
+	nodes, err := source.Nodes(ctx, mainnet)
+	if err != nil || len(nodes) != 2 {
+		t.Errorf("Expected the cached list, got %d nodes: %v", len(nodes), err)
+	}
+
+	// Without a cached list the failure is returned
+	fresh, _ := NewNodeSource(server.URL, models.NodeTypeGRPC, time.Second, logger)
+	if _, err := fresh.Nodes(ctx, mainnet); err == nil {
+		t.Error("Expected an error without a cached list")
+	}
+}
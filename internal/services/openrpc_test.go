@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/rpc"
+)
+
+// TestOpenRPC_SchemaCoverage fails when a registered method cannot be fully
+// described, e.g. it lacks a summary or uses interface{} in its params or
+// result. Give new methods concrete param and result types.
+func TestOpenRPC_SchemaCoverage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewJsonRPCServicePhase2(
+		NewJsonRPCService(nil, nil, nil, nil, nil, nil, nil, nil, logger),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger,
+	)
+	registry := rpc.NewRegistry("test", "0.0.0", logger)
+	service.RegisterMethods(registry)
+	service.RegisterAdminMethods(registry)
+
+	doc := registry.OpenRPC()
+	if len(doc.Methods) != len(registry.Methods()) {
+		t.Fatalf("Expected %d methods, got %d", len(registry.Methods()), len(doc.Methods))
+	}
+
+	for _, method := range doc.Methods {
+		if method.Summary == "" {
+			t.Errorf("%s: missing summary", method.Name)
+		}
+		for _, param := range method.Params {
+			checkSchema(t, doc, method.Name+" param "+param.Name, param.Schema)
+		}
+		checkSchema(t, doc, method.Name+" result", method.Result.Schema)
+	}
+
+	for name, schema := range doc.Components.Schemas {
+		checkSchema(t, doc, "component "+name, schema)
+	}
+}
+
+func TestOpenRPC_RequiredParams(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewJsonRPCServicePhase2(
+		NewJsonRPCService(nil, nil, nil, nil, nil, nil, nil, nil, logger),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger,
+	)
+	registry := rpc.NewRegistry("test", "0.0.0", logger)
+	service.RegisterMethods(registry)
+
+	for _, method := range registry.OpenRPC().Methods {
+		if method.Name != "registerNode" {
+			continue
+		}
+
+		var required []string
+		for _, param := range method.Params {
+			if param.Required {
+				required = append(required, param.Name)
+			}
+		}
+		if got := strings.Join(required, ","); got != "address,name,nodeType" {
+			t.Errorf("Expected address,name,nodeType to be required, got %s", got)
+		}
+		return
+	}
+	t.Error("registerNode not registered")
+}
+
+func checkSchema(t *testing.T, doc *rpc.OpenRPCDocument, where string, schema *rpc.Schema) {
+	t.Helper()
+
+	if schema.IsEmpty() {
+		t.Errorf("%s: no schema", where)
+		return
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("%s: dangling reference %s", where, schema.Ref)
+		}
+	}
+	if schema.Items != nil {
+		checkSchema(t, doc, where+"[]", schema.Items)
+	}
+	if schema.AdditionalProperties != nil {
+		checkSchema(t, doc, where+"{}", schema.AdditionalProperties)
+	}
+	for name, property := range schema.Properties {
+		checkSchema(t, doc, fmt.Sprintf("%s.%s", where, name), property)
+	}
+}
@@ -0,0 +1,671 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/mail"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// operatorMethodKey marks signing challenges; sessions record the key
+// method that answered them
+const operatorMethodKey = "key"
+
+// auditLogLimit caps the entries returned for a node
+const auditLogLimit = 100
+
+// operatorMail is the data the operator login template is rendered with
+type operatorMail struct {
+	Email     string
+	LoginURL  string
+	ExpiresIn string
+}
+
+// OperatorService lets operators of registered gRPC and JSON-RPC servers
+// manage their nodes. Operators log in with a link emailed to the address
+// the node was registered with, or by signing a challenge with the node's
+// peer or validator key.
+type OperatorService struct {
+	operatorRepo  repositories.OperatorRepository
+	auditRepo     repositories.AuditRepository
+	maintenance   *MaintenanceService
+	grpcRepo      repositories.GRPCRepository
+	jsonrpcRepo   repositories.JSONRPCServerRepository
+	registrations *RegistrationService
+	verifier      *OwnershipVerifier
+	mailer        *mail.Mailer
+	publicURL     string
+	eventBus      *events.Bus
+	logger        *logrus.Logger
+}
+
+// NewOperatorService creates a new operator service. A nil mailer disables
+// email login.
+func NewOperatorService(
+	operatorRepo repositories.OperatorRepository,
+	auditRepo repositories.AuditRepository,
+	maintenance *MaintenanceService,
+	grpcRepo repositories.GRPCRepository,
+	jsonrpcRepo repositories.JSONRPCServerRepository,
+	registrations *RegistrationService,
+	verifier *OwnershipVerifier,
+	mailer *mail.Mailer,
+	publicURL string,
+	eventBus *events.Bus,
+	logger *logrus.Logger,
+) *OperatorService {
+	return &OperatorService{
+		operatorRepo:  operatorRepo,
+		auditRepo:     auditRepo,
+		maintenance:   maintenance,
+		grpcRepo:      grpcRepo,
+		jsonrpcRepo:   jsonrpcRepo,
+		registrations: registrations,
+		verifier:      verifier,
+		mailer:        mailer,
+		publicURL:     strings.TrimRight(publicURL, "/"),
+		eventBus:      eventBus,
+		logger:        logger,
+	}
+}
+
+// ========== LOGIN ==========
+
+// RequestLoginLink emails a login link when active nodes are registered
+// with email. It succeeds either way so that it cannot be used to find out
+// which addresses operate nodes.
+func (s *OperatorService) RequestLoginLink(ctx context.Context, email string) error {
+	if s.mailer == nil {
+		return models.NewServiceUnavailableError("email login is not configured")
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	nodes, err := s.nodesByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		s.logger.Debug("Operator login requested for an address without nodes")
+		return nil
+	}
+
+	token, err := s.issueToken(ctx, &models.OperatorToken{
+		Kind:   models.OperatorTokenLogin,
+		Email:  email,
+		Method: models.OperatorMethodEmail,
+	}, models.OperatorLinkTTL)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, email, mail.TemplateOperatorLogin, operatorMail{
+		Email:     email,
+		LoginURL:  s.publicURL + "/api/v1/operator/login/email?token=" + url.QueryEscape(token),
+		ExpiresIn: fmt.Sprintf("%d minutes", int(models.OperatorLinkTTL.Minutes())),
+	})
+	if errors.Is(err, mail.ErrRateLimited) {
+		return models.NewRateLimitError("too many login emails, try again later")
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to send operator login email")
+		return models.NewServiceUnavailableError("login email could not be sent")
+	}
+
+	return nil
+}
+
+// LoginWithLink exchanges an emailed login token for a session
+func (s *OperatorService) LoginWithLink(ctx context.Context, token string) (*models.OperatorSession, error) {
+	login, err := s.operatorRepo.ConsumeToken(ctx, models.OperatorTokenLogin, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if login == nil {
+		return nil, models.NewUnauthorizedError("login link is invalid or has expired")
+	}
+
+	return s.issueSession(ctx, &models.OperatorToken{
+		Email:  login.Email,
+		Method: models.OperatorMethodEmail,
+	})
+}
+
+// Challenge returns a message the operator of a gRPC server signs with its
+// peer or validator key to log in
+func (s *OperatorService) Challenge(ctx context.Context, nodeType string, nodeID int) (*models.OperatorChallenge, error) {
+	if nodeType != models.NodeTypeGRPC {
+		return nil, models.NewValidationError("key login is only available for gRPC nodes", nodeType)
+	}
+	node, err := s.loadNode(ctx, nodeType, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.issueToken(ctx, &models.OperatorToken{
+		Kind:     models.OperatorTokenChallenge,
+		NodeType: nodeType,
+		NodeID:   nodeID,
+		Method:   operatorMethodKey,
+	}, models.OperatorChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OperatorChallenge{
+		Challenge:   challenge,
+		SignMessage: models.VerificationMessage(node.Address, challenge),
+		Methods:     []string{models.VerificationPeerKey, models.VerificationValidatorKey},
+		ExpiresAt:   time.Now().Add(models.OperatorChallengeTTL).UTC(),
+	}, nil
+}
+
+// LoginWithKey checks a signed challenge and opens a session for the node
+// it was issued for
+func (s *OperatorService) LoginWithKey(ctx context.Context, req *models.OperatorKeyLoginRequest) (*models.OperatorSession, error) {
+	challenge, err := s.operatorRepo.ConsumeToken(ctx, models.OperatorTokenChallenge, hashToken(req.Challenge))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, models.NewUnauthorizedError("challenge is invalid or has expired")
+	}
+
+	node, err := s.loadNode(ctx, challenge.NodeType, challenge.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The challenge takes the place of a registration's verification token
+	subject := &models.NodeRegistration{
+		NodeType:          node.NodeType,
+		Address:           node.Address,
+		VerificationToken: req.Challenge,
+	}
+	proof := OwnershipProof{PublicKey: req.PublicKey, Signature: req.Signature}
+	if err := s.verifier.Verify(ctx, subject, req.Method, proof); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"node_type": node.NodeType,
+			"node_id":   node.ID,
+			"method":    req.Method,
+		}).Info("Operator key login failed")
+		return nil, models.NewUnauthorizedError("signature verification failed").WithDetails(err.Error())
+	}
+
+	return s.issueSession(ctx, &models.OperatorToken{
+		NodeType: node.NodeType,
+		NodeID:   node.ID,
+		Method:   req.Method,
+	})
+}
+
+// Authenticate returns the session a bearer token belongs to
+func (s *OperatorService) Authenticate(ctx context.Context, token string) (*models.OperatorToken, error) {
+	session, err := s.operatorRepo.GetSession(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, models.NewUnauthorizedError("session is invalid or has expired")
+	}
+	return session, nil
+}
+
+// Logout ends a session
+func (s *OperatorService) Logout(ctx context.Context, token string) error {
+	return s.operatorRepo.RevokeToken(ctx, hashToken(token))
+}
+
+// issueSession stores a session token and clears out expired tokens
+func (s *OperatorService) issueSession(ctx context.Context, session *models.OperatorToken) (*models.OperatorSession, error) {
+	session.Kind = models.OperatorTokenSession
+	token, err := s.issueToken(ctx, session, models.OperatorSessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.operatorRepo.DeleteExpired(ctx); err != nil {
+		s.logger.WithError(err).Warn("Failed to delete expired operator tokens")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"actor":  session.Actor(),
+		"method": session.Method,
+	}).Info("Operator logged in")
+
+	return &models.OperatorSession{
+		Token:     token,
+		Method:    session.Method,
+		Email:     session.Email,
+		NodeType:  session.NodeType,
+		NodeID:    session.NodeID,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// issueToken stores the hash of a new random token and returns the token
+func (s *OperatorService) issueToken(ctx context.Context, record *models.OperatorToken, ttl time.Duration) (string, error) {
+	token, err := NewVerificationToken()
+	if err != nil {
+		return "", err
+	}
+	record.TokenHash = hashToken(token)
+	record.ExpiresAt = time.Now().Add(ttl).UTC()
+
+	if err := s.operatorRepo.CreateToken(ctx, record); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// hashToken returns the stored form of an operator token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ========== NODE MANAGEMENT ==========
+
+// ListNodes returns the nodes a session may manage
+func (s *OperatorService) ListNodes(ctx context.Context, session *models.OperatorToken) ([]*models.OperatorNode, error) {
+	var nodes []*models.OperatorNode
+	if session.Email != "" {
+		var err error
+		if nodes, err = s.nodesByEmail(ctx, session.Email); err != nil {
+			return nil, err
+		}
+	} else {
+		node, err := s.loadNode(ctx, session.NodeType, session.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		nodes = []*models.OperatorNode{node}
+	}
+
+	for _, node := range nodes {
+		if err := s.attachMaintenance(ctx, node); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// GetNode returns one of the session's nodes
+func (s *OperatorService) GetNode(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int) (*models.OperatorNode, error) {
+	node, err := s.authorizedNode(ctx, session, nodeType, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachMaintenance(ctx, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// UpdateNode changes a node's name, address, email or website. The node
+// must still pass the registration reachability check afterwards. Moving a
+// node to a new address clears its ownership verification.
+func (s *OperatorService) UpdateNode(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int, req *models.NodeUpdateRequest) (*models.OperatorNode, error) {
+	node, err := s.authorizedNode(ctx, session, nodeType, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *node
+	changes := make(map[string]models.FieldChange)
+	apply := func(field string, value *string, target *string) {
+		if value == nil {
+			return
+		}
+		v := strings.TrimSpace(*value)
+		if v != *target {
+			changes[field] = models.FieldChange{From: *target, To: v}
+			*target = v
+		}
+	}
+	apply("name", req.Name, &updated.Name)
+	apply("address", req.Address, &updated.Address)
+	apply("email", req.Email, &updated.Email)
+	apply("website", req.Website, &updated.Website)
+
+	if len(changes) == 0 {
+		return node, nil
+	}
+
+	_, moved := changes["address"]
+	if moved {
+		exists, err := s.registrations.checkDuplicate(ctx, nodeType, updated.Address)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, models.NewConflictError(fmt.Sprintf("a node with address %s is already registered", updated.Address))
+		}
+		updated.IsVerified = false
+	}
+
+	reachable, err := s.registrations.validateNode(ctx, nodeType, updated.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate node: %w", err)
+	}
+	if !reachable {
+		return nil, models.NewNodeNotReachableError(updated.Address, nil)
+	}
+
+	if err := s.saveNode(ctx, &updated); err != nil {
+		return nil, err
+	}
+
+	action := models.AuditNodeUpdated
+	if moved {
+		action = models.AuditNodeMoved
+	}
+	s.audit(ctx, session, &updated, action, changes)
+
+	publishNode(ctx, s.eventBus, events.NodeUpdated, nodeType, nodeID, events.Node{
+		Name:    updated.Name,
+		Address: updated.Address,
+		Network: updated.Network,
+	})
+
+	if err := s.attachMaintenance(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// PauseMonitoring schedules a maintenance window from req.StartsAt, or now,
+// until req.Until. Only operators who proved ownership of the node may
+// schedule maintenance: key sessions, or email sessions for verified nodes.
+func (s *OperatorService) PauseMonitoring(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int, req *models.PauseMonitoringRequest) (*models.MaintenanceWindow, error) {
+	node, err := s.authorizedNode(ctx, session, nodeType, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Email != "" && !node.IsVerified {
+		return nil, models.NewForbiddenError("verify ownership of the node to schedule maintenance")
+	}
+
+	window := &models.MaintenanceWindow{
+		NodeType:  nodeType,
+		NodeID:    nodeID,
+		EndsAt:    req.Until,
+		Reason:    req.Reason,
+		CreatedBy: session.Actor(),
+	}
+	if req.StartsAt != nil {
+		window.StartsAt = *req.StartsAt
+	}
+	if err := s.maintenance.Schedule(ctx, window); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, session, node, models.AuditMonitoringPaused, map[string]string{
+		"from":   window.StartsAt.Format(time.RFC3339),
+		"until":  window.EndsAt.Format(time.RFC3339),
+		"reason": window.Reason,
+	})
+	return window, nil
+}
+
+// ResumeMonitoring ends the node's open maintenance window
+func (s *OperatorService) ResumeMonitoring(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int) error {
+	node, err := s.authorizedNode(ctx, session, nodeType, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.maintenance.EndActive(ctx, nodeType, nodeID); err != nil {
+		return err
+	}
+
+	s.audit(ctx, session, node, models.AuditMonitoringResumed, nil)
+	return nil
+}
+
+// ListMaintenance returns the open and upcoming maintenance windows of one
+// of the session's nodes
+func (s *OperatorService) ListMaintenance(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int) ([]*models.MaintenanceWindow, error) {
+	if _, err := s.authorizedNode(ctx, session, nodeType, nodeID); err != nil {
+		return nil, err
+	}
+	return s.maintenance.ListForNode(ctx, nodeType, nodeID)
+}
+
+// CancelMaintenance removes an upcoming maintenance window of one of the
+// session's nodes, or ends it if it is open
+func (s *OperatorService) CancelMaintenance(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID, windowID int) error {
+	node, err := s.authorizedNode(ctx, session, nodeType, nodeID)
+	if err != nil {
+		return err
+	}
+
+	window, err := s.maintenance.CancelForNode(ctx, nodeType, nodeID, windowID)
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, session, node, models.AuditMaintenanceCancelled, map[string]string{
+		"from":  window.StartsAt.Format(time.RFC3339),
+		"until": window.EndsAt.Format(time.RFC3339),
+	})
+	return nil
+}
+
+// Deregister removes a node from the tracker. Its history is kept.
+func (s *OperatorService) Deregister(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int) error {
+	node, err := s.authorizedNode(ctx, session, nodeType, nodeID)
+	if err != nil {
+		return err
+	}
+
+	switch nodeType {
+	case models.NodeTypeGRPC:
+		err = s.grpcRepo.DeactivateServer(ctx, node.Address)
+	case models.NodeTypeJSONRPC:
+		err = s.jsonrpcRepo.DeactivateServer(ctx, node.Address)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, session, node, models.AuditNodeDeregistered, nil)
+
+	publishNode(ctx, s.eventBus, events.NodeDeactivated, nodeType, nodeID, events.Node{
+		Name:    node.Name,
+		Address: node.Address,
+		Network: node.Network,
+	})
+	return nil
+}
+
+// GetAuditLog returns the latest changes made to one of the session's nodes
+func (s *OperatorService) GetAuditLog(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int) ([]*models.AuditEntry, error) {
+	if _, err := s.authorizedNode(ctx, session, nodeType, nodeID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditRepo.ListForNode(ctx, nodeType, nodeID, auditLogLimit)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*models.AuditEntry{}
+	}
+	return entries, nil
+}
+
+// authorizedNode loads a node and checks that the session may manage it
+func (s *OperatorService) authorizedNode(ctx context.Context, session *models.OperatorToken, nodeType string, nodeID int) (*models.OperatorNode, error) {
+	node, err := s.loadNode(ctx, nodeType, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	if session.Email != "" {
+		allowed = strings.EqualFold(session.Email, node.Email)
+	} else {
+		allowed = session.NodeType == node.NodeType && session.NodeID == node.ID
+	}
+	if !allowed {
+		return nil, models.NewForbiddenError(fmt.Sprintf("not allowed to manage %s node %d", nodeType, nodeID))
+	}
+	return node, nil
+}
+
+// loadNode returns an active gRPC or JSON-RPC server
+func (s *OperatorService) loadNode(ctx context.Context, nodeType string, nodeID int) (*models.OperatorNode, error) {
+	var node *models.OperatorNode
+
+	switch nodeType {
+	case models.NodeTypeGRPC:
+		server, err := s.grpcRepo.GetServerByID(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		if server != nil {
+			node = grpcOperatorNode(server)
+		}
+	case models.NodeTypeJSONRPC:
+		server, err := s.jsonrpcRepo.GetServerByID(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		if server != nil {
+			node = jsonrpcOperatorNode(server)
+		}
+	default:
+		return nil, models.NewValidationError("invalid node type", "operators manage grpc and jsonrpc nodes")
+	}
+
+	if node == nil || !node.IsActive {
+		return nil, models.NewNotFoundError(fmt.Sprintf("%s node %d not found", nodeType, nodeID))
+	}
+	return node, nil
+}
+
+// nodesByEmail returns the active nodes registered with an email address
+func (s *OperatorService) nodesByEmail(ctx context.Context, email string) ([]*models.OperatorNode, error) {
+	grpcServers, err := s.grpcRepo.GetServersByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	jsonrpcServers, err := s.jsonrpcRepo.GetServersByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*models.OperatorNode, 0, len(grpcServers)+len(jsonrpcServers))
+	for _, server := range grpcServers {
+		nodes = append(nodes, grpcOperatorNode(server))
+	}
+	for _, server := range jsonrpcServers {
+		nodes = append(nodes, jsonrpcOperatorNode(server))
+	}
+	return nodes, nil
+}
+
+// saveNode stores the operator editable fields of a node
+func (s *OperatorService) saveNode(ctx context.Context, node *models.OperatorNode) error {
+	switch node.NodeType {
+	case models.NodeTypeGRPC:
+		return s.grpcRepo.UpdateServerByID(ctx, &models.GRPCServer{
+			ID:         node.ID,
+			Name:       node.Name,
+			Address:    node.Address,
+			Email:      node.Email,
+			Website:    node.Website,
+			IsVerified: node.IsVerified,
+		})
+	case models.NodeTypeJSONRPC:
+		return s.jsonrpcRepo.UpdateServerByID(ctx, &models.JSONRPCServer{
+			ID:         node.ID,
+			Name:       node.Name,
+			Address:    node.Address,
+			Email:      node.Email,
+			Website:    node.Website,
+			IsVerified: node.IsVerified,
+		})
+	}
+	return fmt.Errorf("unknown node type: %s", node.NodeType)
+}
+
+// attachMaintenance sets the node's open maintenance window, if any
+func (s *OperatorService) attachMaintenance(ctx context.Context, node *models.OperatorNode) error {
+	window, err := s.maintenance.Active(ctx, node.NodeType, node.ID)
+	if err != nil {
+		return err
+	}
+	node.Maintenance = window
+	return nil
+}
+
+// audit records a change in the node's audit log. A failed write is
+// logged rather than undoing a change that already took effect.
+func (s *OperatorService) audit(ctx context.Context, session *models.OperatorToken, node *models.OperatorNode, action string, changes interface{}) {
+	entry := &models.AuditEntry{
+		NodeType: node.NodeType,
+		NodeID:   node.ID,
+		Actor:    session.Actor(),
+		Action:   action,
+	}
+	if changes != nil {
+		encoded, err := json.Marshal(changes)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to encode audit changes")
+		}
+		entry.Changes = encoded
+	}
+
+	fields := logrus.Fields{
+		"node_type": node.NodeType,
+		"node_id":   node.ID,
+		"actor":     entry.Actor,
+		"action":    action,
+	}
+	if err := s.auditRepo.Record(ctx, entry); err != nil {
+		s.logger.WithError(err).WithFields(fields).Error("Failed to record audit entry")
+		return
+	}
+	s.logger.WithFields(fields).Info("Operator changed node")
+}
+
+func grpcOperatorNode(server *models.GRPCServer) *models.OperatorNode {
+	return &models.OperatorNode{
+		NodeType:   models.NodeTypeGRPC,
+		ID:         server.ID,
+		Name:       server.Name,
+		Address:    server.Address,
+		Network:    server.Network,
+		Email:      server.Email,
+		Website:    server.Website,
+		IsActive:   server.IsActive,
+		IsVerified: server.IsVerified,
+	}
+}
+
+func jsonrpcOperatorNode(server *models.JSONRPCServer) *models.OperatorNode {
+	return &models.OperatorNode{
+		NodeType:   models.NodeTypeJSONRPC,
+		ID:         server.ID,
+		Name:       server.Name,
+		Address:    server.Address,
+		Network:    server.Network,
+		Email:      server.Email,
+		Website:    server.Website,
+		IsActive:   server.IsActive,
+		IsVerified: server.IsVerified,
+	}
+}
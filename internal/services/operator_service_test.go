@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// memoryOperatorRepository keeps operator tokens in a map keyed by hash
+type memoryOperatorRepository struct {
+	tokens map[string]*models.OperatorToken
+}
+
+func (r *memoryOperatorRepository) CreateToken(ctx context.Context, token *models.OperatorToken) error {
+	stored := *token
+	r.tokens[token.TokenHash] = &stored
+	return nil
+}
+
+func (r *memoryOperatorRepository) ConsumeToken(ctx context.Context, kind, tokenHash string) (*models.OperatorToken, error) {
+	token := r.tokens[tokenHash]
+	if token == nil || token.Kind != kind || token.UsedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return token, nil
+}
+
+func (r *memoryOperatorRepository) GetSession(ctx context.Context, tokenHash string) (*models.OperatorToken, error) {
+	token := r.tokens[tokenHash]
+	if token == nil || token.Kind != models.OperatorTokenSession || token.UsedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return token, nil
+}
+
+func (r *memoryOperatorRepository) RevokeToken(ctx context.Context, tokenHash string) error {
+	delete(r.tokens, tokenHash)
+	return nil
+}
+
+func (r *memoryOperatorRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// memoryMaintenanceRepository keeps maintenance windows in a slice
+type memoryMaintenanceRepository struct {
+	windows []*models.MaintenanceWindow
+	nextID  int
+}
+
+func (r *memoryMaintenanceRepository) Create(ctx context.Context, window *models.MaintenanceWindow) error {
+	r.nextID++
+	window.ID = r.nextID
+	r.windows = append(r.windows, window)
+	return nil
+}
+
+func (r *memoryMaintenanceRepository) GetActive(ctx context.Context, nodeType string, nodeID int, at time.Time) (*models.MaintenanceWindow, error) {
+	for _, window := range r.windows {
+		if window.NodeType == nodeType && window.NodeID == nodeID && !window.StartsAt.After(at) && window.EndsAt.After(at) {
+			return window, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryMaintenanceRepository) GetActiveByType(ctx context.Context, nodeType string, at time.Time) (map[int]*models.MaintenanceWindow, error) {
+	active := make(map[int]*models.MaintenanceWindow)
+	for _, window := range r.windows {
+		if window.NodeType == nodeType && !window.StartsAt.After(at) && window.EndsAt.After(at) {
+			active[window.NodeID] = window
+		}
+	}
+	return active, nil
+}
+
+func (r *memoryMaintenanceRepository) EndActive(ctx context.Context, nodeType string, nodeID int, at time.Time) (int64, error) {
+	window, _ := r.GetActive(ctx, nodeType, nodeID, at)
+	if window == nil {
+		return 0, nil
+	}
+	window.EndsAt = at
+	return 1, nil
+}
+
+func (r *memoryMaintenanceRepository) GetByID(ctx context.Context, id int) (*models.MaintenanceWindow, error) {
+	for _, window := range r.windows {
+		if window.ID == id {
+			return window, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryMaintenanceRepository) ListForNode(ctx context.Context, nodeType string, nodeID int, endsAfter time.Time) ([]*models.MaintenanceWindow, error) {
+	var windows []*models.MaintenanceWindow
+	for _, window := range r.windows {
+		if window.NodeType == nodeType && window.NodeID == nodeID && window.EndsAt.After(endsAfter) {
+			windows = append(windows, window)
+		}
+	}
+	return windows, nil
+}
+
+func (r *memoryMaintenanceRepository) HasOverlap(ctx context.Context, nodeType string, nodeID int, startsAt, endsAt time.Time) (bool, error) {
+	for _, window := range r.windows {
+		if window.NodeType == nodeType && window.NodeID == nodeID && window.StartsAt.Before(endsAt) && window.EndsAt.After(startsAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryMaintenanceRepository) End(ctx context.Context, id int, at time.Time) error {
+	if window, _ := r.GetByID(ctx, id); window != nil {
+		window.EndsAt = at
+	}
+	return nil
+}
+
+func (r *memoryMaintenanceRepository) Delete(ctx context.Context, id int) error {
+	for i, window := range r.windows {
+		if window.ID == id {
+			r.windows = append(r.windows[:i], r.windows[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// memoryAuditRepository records audit entries in a slice
+type memoryAuditRepository struct {
+	entries []*models.AuditEntry
+}
+
+func (r *memoryAuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *memoryAuditRepository) ListForNode(ctx context.Context, nodeType string, nodeID, limit int) ([]*models.AuditEntry, error) {
+	var entries []*models.AuditEntry
+	for _, entry := range r.entries {
+		if entry.NodeType == nodeType && entry.NodeID == nodeID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// operatorGRPCRepository serves gRPC servers from a map; other methods are
+// not used
+type operatorGRPCRepository struct {
+	repositories.GRPCRepository
+	servers map[int]*models.GRPCServer
+}
+
+func (r *operatorGRPCRepository) GetServerByID(ctx context.Context, id int) (*models.GRPCServer, error) {
+	return r.servers[id], nil
+}
+
+func (r *operatorGRPCRepository) GetServersByEmail(ctx context.Context, email string) ([]*models.GRPCServer, error) {
+	var servers []*models.GRPCServer
+	for _, server := range r.servers {
+		if server.IsActive && strings.EqualFold(server.Email, email) {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+func (r *operatorGRPCRepository) DeactivateServer(ctx context.Context, address string) error {
+	for _, server := range r.servers {
+		if server.Address == address {
+			server.IsActive = false
+			server.IsCurated = false
+		}
+	}
+	return nil
+}
+
+// operatorJSONRPCRepository has no servers
+type operatorJSONRPCRepository struct {
+	repositories.JSONRPCServerRepository
+}
+
+func (r *operatorJSONRPCRepository) GetServerByID(ctx context.Context, id int) (*models.JSONRPCServer, error) {
+	return nil, nil
+}
+
+func (r *operatorJSONRPCRepository) GetServersByEmail(ctx context.Context, email string) ([]*models.JSONRPCServer, error) {
+	return nil, nil
+}
+
+type operatorFixture struct {
+	service     *OperatorService
+	grpc        *operatorGRPCRepository
+	maintenance *memoryMaintenanceRepository
+	audit       *memoryAuditRepository
+}
+
+func newOperatorFixture(t *testing.T) (*operatorFixture, func() []string) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	f := &operatorFixture{
+		grpc: &operatorGRPCRepository{servers: map[int]*models.GRPCServer{
+			1: {ID: 1, Name: "node-1", Address: "node1.example.org:50051", Email: "op@example.org", IsActive: true, IsVerified: true},
+			2: {ID: 2, Name: "node-2", Address: "node2.example.org:50051", Email: "other@example.org", IsActive: true},
+			3: {ID: 3, Name: "node-3", Address: "node3.example.org:50051", Email: "op@example.org", IsActive: true},
+		}},
+		maintenance: &memoryMaintenanceRepository{},
+		audit:       &memoryAuditRepository{},
+	}
+	mailer, transport := newTestMailer(t)
+	jsonrpcRepo := &operatorJSONRPCRepository{}
+	f.service = NewOperatorService(
+		&memoryOperatorRepository{tokens: make(map[string]*models.OperatorToken)},
+		f.audit, NewMaintenanceService(f.maintenance, nil, f.grpc, jsonrpcRepo, logger), f.grpc, jsonrpcRepo,
+		nil, nil, mailer, "https://tracker.example.org/", events.NewBus(logger), logger,
+	)
+
+	// loginTokens returns the tokens of the login links sent so far
+	loginTokens := func() []string {
+		var tokens []string
+		for _, message := range transport.Messages() {
+			_, rest, found := strings.Cut(message.Body, "?token=")
+			if !found {
+				t.Fatalf("Expected a login link in %q", message.Body)
+			}
+			token, err := url.QueryUnescape(strings.Fields(rest)[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			tokens = append(tokens, token)
+		}
+		return tokens
+	}
+	return f, loginTokens
+}
+
+func assertErrorCode(t *testing.T, err error, code models.ErrorCode) {
+	t.Helper()
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != code {
+		t.Errorf("Expected %s error, got %v", code, err)
+	}
+}
+
+func TestOperatorService_EmailLogin(t *testing.T) {
+	f, loginTokens := newOperatorFixture(t)
+	ctx := context.Background()
+
+	if err := f.service.RequestLoginLink(ctx, "nobody@example.org"); err != nil {
+		t.Fatalf("RequestLoginLink without nodes: %v", err)
+	}
+	if len(loginTokens()) != 0 {
+		t.Fatal("Expected no mail for an address without nodes")
+	}
+
+	if err := f.service.RequestLoginLink(ctx, " OP@example.org "); err != nil {
+		t.Fatalf("RequestLoginLink: %v", err)
+	}
+	tokens := loginTokens()
+	if len(tokens) != 1 {
+		t.Fatalf("Expected 1 login mail, got %d", len(tokens))
+	}
+
+	session, err := f.service.LoginWithLink(ctx, tokens[0])
+	if err != nil {
+		t.Fatalf("LoginWithLink: %v", err)
+	}
+	if session.Email != "op@example.org" {
+		t.Errorf("Expected session for op@example.org, got %q", session.Email)
+	}
+	if _, err := f.service.LoginWithLink(ctx, tokens[0]); err == nil {
+		t.Error("Expected a used login link to be rejected")
+	}
+
+	authenticated, err := f.service.Authenticate(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	nodes, err := f.service.ListNodes(ctx, authenticated)
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Errorf("Expected nodes 1 and 3, got %+v", nodes)
+	}
+	for _, node := range nodes {
+		if node.Email != "op@example.org" {
+			t.Errorf("Expected only nodes of op@example.org, got %+v", node)
+		}
+	}
+
+	if err := f.service.Logout(ctx, session.Token); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	_, err = f.service.Authenticate(ctx, session.Token)
+	assertErrorCode(t, err, models.ErrCodeUnauthorized)
+}
+
+func TestOperatorService_Authorization(t *testing.T) {
+	f, _ := newOperatorFixture(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		session *models.OperatorToken
+		nodeID  int
+		code    models.ErrorCode
+	}{
+		{"email owner", &models.OperatorToken{Email: "op@example.org"}, 1, ""},
+		{"email owner different case", &models.OperatorToken{Email: "OP@example.org"}, 1, ""},
+		{"email of another node", &models.OperatorToken{Email: "op@example.org"}, 2, models.ErrCodeForbidden},
+		{"key session", &models.OperatorToken{NodeType: models.NodeTypeGRPC, NodeID: 2}, 2, ""},
+		{"key session for another node", &models.OperatorToken{NodeType: models.NodeTypeGRPC, NodeID: 2}, 1, models.ErrCodeForbidden},
+		{"unknown node", &models.OperatorToken{Email: "op@example.org"}, 9, models.ErrCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.GetNode(ctx, tt.session, models.NodeTypeGRPC, tt.nodeID)
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("Expected access, got %v", err)
+				}
+				return
+			}
+			assertErrorCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestOperatorService_PauseMonitoring(t *testing.T) {
+	f, _ := newOperatorFixture(t)
+	ctx := context.Background()
+	session := &models.OperatorToken{Email: "op@example.org", Method: models.OperatorMethodEmail}
+
+	_, err := f.service.PauseMonitoring(ctx, session, models.NodeTypeGRPC, 3, &models.PauseMonitoringRequest{Until: time.Now().Add(time.Hour)})
+	assertErrorCode(t, err, models.ErrCodeForbidden)
+
+	_, err = f.service.PauseMonitoring(ctx, session, models.NodeTypeGRPC, 1, &models.PauseMonitoringRequest{Until: time.Now().Add(-time.Hour)})
+	assertErrorCode(t, err, models.ErrCodeValidation)
+
+	_, err = f.service.PauseMonitoring(ctx, session, models.NodeTypeGRPC, 1, &models.PauseMonitoringRequest{Until: time.Now().Add(models.MaxMaintenanceDuration + time.Hour)})
+	assertErrorCode(t, err, models.ErrCodeValidation)
+
+	window, err := f.service.PauseMonitoring(ctx, session, models.NodeTypeGRPC, 1, &models.PauseMonitoringRequest{Until: time.Now().Add(2 * time.Hour), Reason: "upgrade"})
+	if err != nil {
+		t.Fatalf("PauseMonitoring: %v", err)
+	}
+	if window.CreatedBy != "email:op@example.org" {
+		t.Errorf("Expected window created by email:op@example.org, got %q", window.CreatedBy)
+	}
+
+	_, err = f.service.PauseMonitoring(ctx, session, models.NodeTypeGRPC, 1, &models.PauseMonitoringRequest{Until: time.Now().Add(time.Hour)})
+	assertErrorCode(t, err, models.ErrCodeConflict)
+
+	node, err := f.service.GetNode(ctx, session, models.NodeTypeGRPC, 1)
+	if err != nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	if node.Maintenance == nil || node.Maintenance.Reason != "upgrade" {
+		t.Errorf("Expected the open maintenance window on the node, got %+v", node.Maintenance)
+	}
+
+	if err := f.service.ResumeMonitoring(ctx, session, models.NodeTypeGRPC, 1); err != nil {
+		t.Fatalf("ResumeMonitoring: %v", err)
+	}
+	err = f.service.ResumeMonitoring(ctx, session, models.NodeTypeGRPC, 1)
+	assertErrorCode(t, err, models.ErrCodeConflict)
+
+	entries, err := f.service.GetAuditLog(ctx, session, models.NodeTypeGRPC, 1)
+	if err != nil {
+		t.Fatalf("GetAuditLog: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != models.AuditMonitoringPaused || entries[1].Action != models.AuditMonitoringResumed {
+		t.Errorf("Expected paused and resumed audit entries, got %+v", entries)
+	}
+}
+
+func TestOperatorService_Deregister(t *testing.T) {
+	f, _ := newOperatorFixture(t)
+	ctx := context.Background()
+	session := &models.OperatorToken{NodeType: models.NodeTypeGRPC, NodeID: 1, Method: models.VerificationPeerKey}
+
+	if err := f.service.Deregister(ctx, session, models.NodeTypeGRPC, 1); err != nil {
+		t.Fatalf("Deregister: %v", err)
+	}
+	if f.grpc.servers[1].IsActive {
+		t.Error("Expected node 1 to be deactivated")
+	}
+	if len(f.audit.entries) != 1 || f.audit.entries[0].Action != models.AuditNodeDeregistered {
+		t.Errorf("Expected a deregistration audit entry, got %+v", f.audit.entries)
+	}
+
+	_, err := f.service.GetNode(ctx, session, models.NodeTypeGRPC, 1)
+	assertErrorCode(t, err, models.ErrCodeNotFound)
+}
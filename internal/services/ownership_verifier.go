@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pactus-project/pactus/crypto/bls"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// maxVerificationBody caps how much of a well-known file is read
+const maxVerificationBody = 4096
+
+// errNonPublicAddress is returned when an endpoint resolves to an address
+// the tracker must not fetch from on a registrant's behalf
+var errNonPublicAddress = errors.New("endpoint does not resolve to a public address")
+
+// NodeIdentifier reports the identity of the node behind a gRPC server
+type NodeIdentifier interface {
+	GetNodeIdentity(ctx context.Context, address string) (*NodeIdentity, error)
+}
+
+// OwnershipProof carries the signature for key based verification
+type OwnershipProof struct {
+	PublicKey string
+	Signature string
+}
+
+// OwnershipVerifier checks that a registrant controls the endpoint they
+// registered
+type OwnershipVerifier struct {
+	httpClient *http.Client
+	lookupTXT  func(ctx context.Context, name string) ([]string, error)
+	identifier NodeIdentifier
+	logger     *logrus.Logger
+}
+
+// NewOwnershipVerifier creates a verifier that reads node identities from
+// gRPC servers through identifier
+func NewOwnershipVerifier(identifier NodeIdentifier, timeout time.Duration, logger *logrus.Logger) *OwnershipVerifier {
+	// Check every address the client connects to, after resolution, so a
+	// registrant cannot point the check at the tracker's own network
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicIP(net.ParseIP(host)) {
+				return errNonPublicAddress
+			}
+			return nil
+		},
+	}
+
+	return &OwnershipVerifier{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			// No proxy, since the proxy address is all the dialer would see
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+			// A redirect could point the check at a host the registrant controls
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		lookupTXT:  net.DefaultResolver.LookupTXT,
+		identifier: identifier,
+		logger:     logger,
+	}
+}
+
+// NewVerificationToken returns a random challenge token
+func NewVerificationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate verification token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Challenge describes how a registrant can prove ownership of address
+func (v *OwnershipVerifier) Challenge(nodeType, address, token string) *models.VerificationChallenge {
+	challenge := &models.VerificationChallenge{Token: token}
+
+	switch nodeType {
+	case models.NodeTypeJSONRPC:
+		challenge.Methods = []string{models.VerificationHTTP, models.VerificationDNS}
+		if endpoint, err := url.Parse(address); err == nil && endpoint.Host != "" {
+			challenge.HTTPURL = endpoint.Scheme + "://" + endpoint.Host + models.VerificationPath
+			challenge.DNSName = models.VerificationDNSPrefix + endpoint.Hostname()
+		}
+		challenge.DNSValue = models.VerificationTXTPrefix + token
+	case models.NodeTypeGRPC:
+		challenge.Methods = []string{models.VerificationPeerKey, models.VerificationValidatorKey}
+		challenge.SignMessage = models.VerificationMessage(address, token)
+	}
+
+	return challenge
+}
+
+// Verify checks the registration's challenge with the given method. The
+// returned error explains why verification failed.
+func (v *OwnershipVerifier) Verify(ctx context.Context, registration *models.NodeRegistration, method string, proof OwnershipProof) error {
+	if registration.VerificationToken == "" {
+		return fmt.Errorf("registration has no verification challenge")
+	}
+
+	switch {
+	case registration.NodeType == models.NodeTypeJSONRPC && method == models.VerificationHTTP:
+		return v.verifyHTTP(ctx, registration.Address, registration.VerificationToken)
+	case registration.NodeType == models.NodeTypeJSONRPC && method == models.VerificationDNS:
+		return v.verifyDNS(ctx, registration.Address, registration.VerificationToken)
+	case registration.NodeType == models.NodeTypeGRPC && method == models.VerificationPeerKey:
+		return v.verifyPeerKey(ctx, registration, proof)
+	case registration.NodeType == models.NodeTypeGRPC && method == models.VerificationValidatorKey:
+		return v.verifyValidatorKey(ctx, registration, proof)
+	}
+	return fmt.Errorf("method %s is not available for %s nodes", method, registration.NodeType)
+}
+
+// verifyHTTP expects the token in the well-known file of the endpoint host
+func (v *OwnershipVerifier) verifyHTTP(ctx context.Context, address, token string) error {
+	endpoint, err := url.Parse(address)
+	if err != nil || endpoint.Host == "" {
+		return fmt.Errorf("invalid endpoint address")
+	}
+	target := endpoint.Scheme + "://" + endpoint.Host + models.VerificationPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid verification url: %w", err)
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: status %d", target, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVerificationBody))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", target, err)
+	}
+	if strings.TrimSpace(string(body)) != token {
+		return fmt.Errorf("%s does not contain the verification token", target)
+	}
+	return nil
+}
+
+// verifyDNS expects a TXT record with the token on the endpoint host
+func (v *OwnershipVerifier) verifyDNS(ctx context.Context, address, token string) error {
+	endpoint, err := url.Parse(address)
+	if err != nil || endpoint.Hostname() == "" {
+		return fmt.Errorf("invalid endpoint address")
+	}
+	if net.ParseIP(endpoint.Hostname()) != nil {
+		return fmt.Errorf("dns verification needs a host name, not an IP address")
+	}
+	name := models.VerificationDNSPrefix + endpoint.Hostname()
+
+	records, err := v.lookupTXT(ctx, name)
+	if err != nil {
+		return fmt.Errorf("lookup %s: %w", name, err)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == models.VerificationTXTPrefix+token {
+			return nil
+		}
+	}
+	return fmt.Errorf("no TXT record on %s contains the verification token", name)
+}
+
+// verifyPeerKey checks a signature by the key behind the node's peer ID.
+// Ed25519 peer IDs embed their public key, so none has to be supplied.
+func (v *OwnershipVerifier) verifyPeerKey(ctx context.Context, registration *models.NodeRegistration, proof OwnershipProof) error {
+	signature, err := hex.DecodeString(proof.Signature)
+	if err != nil || len(signature) == 0 {
+		return fmt.Errorf("signature must be hex encoded")
+	}
+
+	identity, err := v.identifier.GetNodeIdentity(ctx, registration.Address)
+	if err != nil {
+		return fmt.Errorf("query node identity: %w", err)
+	}
+	peerID, err := peer.Decode(identity.PeerID)
+	if err != nil {
+		return fmt.Errorf("node reported an invalid peer id: %w", err)
+	}
+	publicKey, err := peerID.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("peer id does not embed its public key: %w", err)
+	}
+
+	message := models.VerificationMessage(registration.Address, registration.VerificationToken)
+	valid, err := publicKey.Verify([]byte(message), signature)
+	if err != nil || !valid {
+		return fmt.Errorf("signature does not match peer %s", identity.PeerID)
+	}
+	return nil
+}
+
+// verifyValidatorKey checks a signature by a validator key the node runs
+// consensus for
+func (v *OwnershipVerifier) verifyValidatorKey(ctx context.Context, registration *models.NodeRegistration, proof OwnershipProof) error {
+	publicKey, err := bls.PublicKeyFromString(proof.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid validator public key: %w", err)
+	}
+	signature, err := bls.SignatureFromString(proof.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	message := models.VerificationMessage(registration.Address, registration.VerificationToken)
+	if err := publicKey.Verify([]byte(message), signature); err != nil {
+		return fmt.Errorf("signature does not match the validator key")
+	}
+
+	identity, err := v.identifier.GetNodeIdentity(ctx, registration.Address)
+	if err != nil {
+		return fmt.Errorf("query node identity: %w", err)
+	}
+	validator := publicKey.ValidatorAddress().String()
+	for _, address := range identity.Validators {
+		if address == validator {
+			return nil
+		}
+	}
+	return fmt.Errorf("node does not run validator %s", validator)
+}
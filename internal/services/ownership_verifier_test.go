@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	libp2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pactus-project/pactus/crypto/bls"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// staticIdentifier reports a fixed node identity
+type staticIdentifier struct {
+	identity *NodeIdentity
+}
+
+func (s *staticIdentifier) GetNodeIdentity(ctx context.Context, address string) (*NodeIdentity, error) {
+	if s.identity == nil {
+		return nil, errors.New("unreachable")
+	}
+	return s.identity, nil
+}
+
+func newTestVerifier(identity *NodeIdentity) *OwnershipVerifier {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return NewOwnershipVerifier(&staticIdentifier{identity: identity}, 5*time.Second, logger)
+}
+
+func TestOwnershipVerifier_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != models.VerificationPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("token-1\n"))
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback, which the default client refuses
+	verifier := newTestVerifier(nil)
+	verifier.httpClient = server.Client()
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"Matching token", "token-1", false},
+		{"Other token", "token-2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registration := &models.NodeRegistration{
+				NodeType:          models.NodeTypeJSONRPC,
+				Address:           server.URL + "/rpc",
+				VerificationToken: tt.token,
+			}
+			err := verifier.Verify(context.Background(), registration, models.VerificationHTTP, OwnershipProof{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOwnershipVerifier_HTTPRejectsNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("token-1"))
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	verifier := newTestVerifier(nil)
+	for _, address := range []string{server.URL, "http://localhost:" + port} {
+		registration := &models.NodeRegistration{
+			NodeType:          models.NodeTypeJSONRPC,
+			Address:           address,
+			VerificationToken: "token-1",
+		}
+		err := verifier.Verify(context.Background(), registration, models.VerificationHTTP, OwnershipProof{})
+		if !errors.Is(err, errNonPublicAddress) {
+			t.Errorf("Expected %s to be refused, got %v", address, err)
+		}
+	}
+}
+
+func TestOwnershipVerifier_DNS(t *testing.T) {
+	verifier := newTestVerifier(nil)
+	verifier.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		if name != "_pactus-tracker.rpc.example.org" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"v=spf1 -all", "pactus-tracker-verification=token-1"}, nil
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		token   string
+		wantErr bool
+	}{
+		{"Matching record", "https://rpc.example.org/", "token-1", false},
+		{"Other token", "https://rpc.example.org/", "token-2", true},
+		{"Other host", "https://node.example.org/", "token-1", true},
+		{"IP address", "http://127.0.0.1:8545/", "token-1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registration := &models.NodeRegistration{
+				NodeType:          models.NodeTypeJSONRPC,
+				Address:           tt.address,
+				VerificationToken: tt.token,
+			}
+			err := verifier.Verify(context.Background(), registration, models.VerificationDNS, OwnershipProof{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOwnershipVerifier_PeerKey(t *testing.T) {
+	privateKey, publicKey, err := libp2pcrypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerID, err := peer.IDFromPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, _ := libp2pcrypto.GenerateEd25519Key(rand.Reader)
+	otherID, _ := peer.IDFromPublicKey(otherKey)
+
+	registration := &models.NodeRegistration{
+		NodeType:          models.NodeTypeGRPC,
+		Address:           "node.example.org:50051",
+		VerificationToken: "token-1",
+	}
+	signature, err := privateKey.Sign([]byte(models.VerificationMessage(registration.Address, "token-1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		identity *NodeIdentity
+		wantErr  bool
+	}{
+		{"Node runs the key", &NodeIdentity{PeerID: peerID.String()}, false},
+		{"Node runs another key", &NodeIdentity{PeerID: otherID.String()}, true},
+		{"Node unreachable", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestVerifier(tt.identity)
+			proof := OwnershipProof{Signature: hex.EncodeToString(signature)}
+			err := verifier.Verify(context.Background(), registration, models.VerificationPeerKey, proof)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOwnershipVerifier_ValidatorKey(t *testing.T) {
+	privateKey, err := bls.KeyGen([]byte("registration-verification-test-seed-0001"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := privateKey.PublicKeyNative()
+	validator := publicKey.ValidatorAddress().String()
+
+	registration := &models.NodeRegistration{
+		NodeType:          models.NodeTypeGRPC,
+		Address:           "node.example.org:50051",
+		VerificationToken: "token-1",
+	}
+	signature := privateKey.SignNative([]byte(models.VerificationMessage(registration.Address, "token-1")))
+	wrongSignature := privateKey.SignNative([]byte("something else"))
+
+	tests := []struct {
+		name      string
+		signature string
+		identity  *NodeIdentity
+		wantErr   bool
+	}{
+		{"Node runs the validator", signature.String(), &NodeIdentity{Validators: []string{validator}}, false},
+		{"Node runs other validators", signature.String(), &NodeIdentity{Validators: []string{"pc1pother"}}, true},
+		{"Wrong message signed", wrongSignature.String(), &NodeIdentity{Validators: []string{validator}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestVerifier(tt.identity)
+			proof := OwnershipProof{PublicKey: publicKey.String(), Signature: tt.signature}
+			err := verifier.Verify(context.Background(), registration, models.VerificationValidatorKey, proof)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOwnershipVerifier_RejectsMethodForNodeType(t *testing.T) {
+	verifier := newTestVerifier(nil)
+	registration := &models.NodeRegistration{
+		NodeType:          models.NodeTypeGRPC,
+		Address:           "node.example.org:50051",
+		VerificationToken: "token-1",
+	}
+
+	if err := verifier.Verify(context.Background(), registration, models.VerificationHTTP, OwnershipProof{}); err == nil {
+		t.Error("Expected http verification to be rejected for gRPC nodes")
+	}
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// Headers used to authenticate probe agent requests
+const (
+	ProbeHeaderAgent     = "X-Probe-Agent"
+	ProbeHeaderTimestamp = "X-Probe-Timestamp"
+	ProbeHeaderNonce     = "X-Probe-Nonce"
+	ProbeHeaderSignature = "X-Probe-Signature"
+)
+
+// ProbeSigningPayload builds the message an agent signs for a request. The
+// nonce is unique per request so a captured request cannot be replayed.
+func ProbeSigningPayload(agentID, timestamp, nonce string, body []byte) []byte {
+	payload := make([]byte, 0, len(agentID)+len(timestamp)+len(nonce)+len(body)+3)
+	payload = append(payload, agentID...)
+	payload = append(payload, '\n')
+	payload = append(payload, timestamp...)
+	payload = append(payload, '\n')
+	payload = append(payload, nonce...)
+	payload = append(payload, '\n')
+	return append(payload, body...)
+}
+
+// newProbeNonce returns a random request nonce
+func newProbeNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
+
+// ParseProbePrivateKey decodes a hex-encoded ed25519 seed
+func ParseProbePrivateKey(seedHex string) (ed25519.PrivateKey, error) {
+	seed, err := hex.DecodeString(strings.TrimSpace(seedHex))
+	if err != nil {
+		return nil, fmt.Errorf("decode private key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("private key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ProbeClient is the transport a probe agent uses to talk to the tracker
+type ProbeClient interface {
+	FetchTargets(ctx context.Context) ([]*models.ProbeTarget, error)
+	SubmitReport(ctx context.Context, report *models.ProbeReport) (*models.ProbeReportResponse, error)
+}
+
+// HTTPProbeClient talks to the tracker probe API with signed requests
+type HTTPProbeClient struct {
+	baseURL    string
+	agentID    string
+	key        ed25519.PrivateKey
+	httpClient *http.Client
+}
+
+// NewHTTPProbeClient creates a new signed HTTP probe client
+func NewHTTPProbeClient(baseURL, agentID string, key ed25519.PrivateKey, timeout time.Duration) *HTTPProbeClient {
+	return &HTTPProbeClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		agentID: agentID,
+		key:     key,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// FetchTargets returns the nodes the tracker wants this agent to check
+func (c *HTTPProbeClient) FetchTargets(ctx context.Context) ([]*models.ProbeTarget, error) {
+	var targets []*models.ProbeTarget
+	if err := c.do(ctx, http.MethodGet, "/api/v1/probes/targets", nil, &targets); err != nil {
+		return nil, fmt.Errorf("fetch targets: %w", err)
+	}
+	return targets, nil
+}
+
+// SubmitReport sends the agent's check results to the tracker
+func (c *HTTPProbeClient) SubmitReport(ctx context.Context, report *models.ProbeReport) (*models.ProbeReportResponse, error) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("marshal report: %w", err)
+	}
+
+	response := &models.ProbeReportResponse{}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/probes/results", body, response); err != nil {
+		return nil, fmt.Errorf("submit report: %w", err)
+	}
+	return response, nil
+}
+
+func (c *HTTPProbeClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	nonce, err := newProbeNonce()
+	if err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := ed25519.Sign(c.key, ProbeSigningPayload(c.agentID, timestamp, nonce, body))
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ProbeHeaderAgent, c.agentID)
+	req.Header.Set(ProbeHeaderTimestamp, timestamp)
+	req.Header.Set(ProbeHeaderNonce, nonce)
+	req.Header.Set(ProbeHeaderSignature, base64.StdEncoding.EncodeToString(signature))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(responseBody))
+	}
+
+	return json.Unmarshal(responseBody, out)
+}
+
+// ProbeAgent runs node checks from a remote vantage point and reports them
+type ProbeAgent struct {
+	id             string
+	region         string
+	client         ProbeClient
+	nodeChecker    *NodeChecker
+	grpcChecker    *GRPCChecker
+	jsonrpcChecker *JSONRPCMonitorService
+	logger         *logrus.Logger
+}
+
+// NewProbeAgent creates a new probe agent
+func NewProbeAgent(
+	id, region string,
+	client ProbeClient,
+	nodeChecker *NodeChecker,
+	grpcChecker *GRPCChecker,
+	jsonrpcChecker *JSONRPCMonitorService,
+	logger *logrus.Logger,
+) *ProbeAgent {
+	return &ProbeAgent{
+		id:             id,
+		region:         region,
+		client:         client,
+		nodeChecker:    nodeChecker,
+		grpcChecker:    grpcChecker,
+		jsonrpcChecker: jsonrpcChecker,
+		logger:         logger,
+	}
+}
+
+// Run probes on every interval until the context is cancelled
+func (a *ProbeAgent) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.RunOnce(ctx); err != nil {
+			a.logger.WithError(err).Error("Probe round failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Finished checks are submitted in batches, at the latest probeFlushInterval
+// after the first one, so their CheckedAt stays within the tracker's clock
+// skew however long the whole round takes
+const (
+	probeBatchSize     = 50
+	probeFlushInterval = time.Minute
+)
+
+// RunOnce fetches targets, checks them all and submits the results in
+// batches as the checks finish
+func (a *ProbeAgent) RunOnce(ctx context.Context) (*models.ProbeReportResponse, error) {
+	targets, err := a.client.FetchTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const maxConcurrent = 10
+	semaphore := make(chan struct{}, maxConcurrent)
+	results := make(chan *models.ProbeResult)
+	var wg sync.WaitGroup
+
+	for _, target := range targets {
+		wg.Add(1)
+		go func(t *models.ProbeTarget) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results <- a.probe(ctx, t)
+		}(target)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	response := &models.ProbeReportResponse{}
+	var batch []*models.ProbeResult
+	var deadline <-chan time.Time
+	var submitErr error
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		report := &models.ProbeReport{AgentID: a.id, Region: a.region, Results: batch}
+		batch, deadline = nil, nil
+
+		submitted, err := a.client.SubmitReport(ctx, report)
+		if err != nil {
+			// Keep checking so later batches still reach the tracker
+			a.logger.WithError(err).WithField("results", len(report.Results)).Error("Failed to submit probe results")
+			if submitErr == nil {
+				submitErr = err
+			}
+			return
+		}
+		response.Accepted += submitted.Accepted
+		response.Rejected += submitted.Rejected
+		response.Timestamp = submitted.Timestamp
+	}
+
+	for open := true; open; {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				open = false
+				break
+			}
+			if len(batch) == 0 {
+				deadline = time.After(probeFlushInterval)
+			}
+			batch = append(batch, result)
+			if len(batch) >= probeBatchSize {
+				flush()
+			}
+		case <-deadline:
+			flush()
+		}
+	}
+	flush()
+
+	if submitErr != nil {
+		return nil, submitErr
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"agent_id": a.id,
+		"region":   a.region,
+		"targets":  len(targets),
+		"accepted": response.Accepted,
+		"rejected": response.Rejected,
+	}).Info("Probe round completed")
+
+	return response, nil
+}
+
+// probe checks a single target with the checker matching its node type
+func (a *ProbeAgent) probe(ctx context.Context, target *models.ProbeTarget) *models.ProbeResult {
+	result := &models.ProbeResult{
+		NodeType: target.NodeType,
+		NodeID:   target.NodeID,
+	}
+
+	switch target.NodeType {
+	case models.NodeTypeBootstrap:
+		check := a.nodeChecker.CheckNode(ctx, target.Address)
+		result.Success = check.Success
+		result.Attempts = check.Attempts
+		result.ErrorMsg = check.ErrorMsg
+		result.ErrorClass = check.ErrorClass
+		result.ResponseTimeMs = check.ResponseTimeMs
+	case models.NodeTypeGRPC:
+		check := a.grpcChecker.CheckGRPCServer(ctx, target.Address)
+		result.Success = check.Success
+		result.Attempts = check.Attempts
+		result.ErrorMsg = check.ErrorMsg
+		result.ErrorClass = check.ErrorClass
+		result.ResponseTimeMs = check.ResponseTimeMs
+	case models.NodeTypeJSONRPC:
+		check := a.jsonrpcChecker.ValidateJSONRPCEndpoint(ctx, target.Address)
+		result.Success = check.Success
+		result.Attempts = check.Attempts
+		result.ErrorMsg = check.ErrorMsg
+		result.ErrorClass = check.ErrorClass
+		result.ResponseTimeMs = check.ResponseTimeMs
+	default:
+		result.ErrorMsg = fmt.Sprintf("unsupported node type: %s", target.NodeType)
+	}
+
+	// Stamped once the check, retries included, has finished
+	result.CheckedAt = time.Now().UTC()
+	return result
+}
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// ProbeAgentIdentity is a registered probe agent and its signing key
+type ProbeAgentIdentity struct {
+	ID        string
+	Region    string
+	PublicKey ed25519.PublicKey
+}
+
+// ParseProbeAgents parses a comma-separated list of "id:region:hexpubkey" entries
+func ParseProbeAgents(spec string) ([]*ProbeAgentIdentity, error) {
+	var agents []*ProbeAgentIdentity
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid probe agent entry %q, expected id:region:pubkey", entry)
+		}
+
+		key, err := hex.DecodeString(parts[2])
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key for probe agent %s", parts[0])
+		}
+
+		agents = append(agents, &ProbeAgentIdentity{
+			ID:        parts[0],
+			Region:    parts[1],
+			PublicKey: ed25519.PublicKey(key),
+		})
+	}
+
+	return agents, nil
+}
+
+// maxProbeNonceLength bounds the nonce of an agent request
+const maxProbeNonceLength = 64
+
+// ProbeService receives results from remote probe agents and derives the
+// daily status of each node from a quorum across regions
+type ProbeService struct {
+	agents            map[string]*ProbeAgentIdentity
+	probeRepo         repositories.ProbeRepository
+	bootstrapRepo     repositories.BootstrapRepository
+	statusRepo        repositories.StatusRepository
+	grpcRepo          repositories.GRPCRepository
+	grpcStatusRepo    repositories.GRPCStatusRepository
+	jsonrpcRepo       repositories.JSONRPCServerRepository
+	jsonrpcStatusRepo repositories.JSONRPCStatusRepository
+	maintenanceRepo   repositories.MaintenanceRepository
+	minRegions        int
+	maxClockSkew      time.Duration
+	logger            *logrus.Logger
+}
+
+// NewProbeService creates a new probe service
+func NewProbeService(
+	agents []*ProbeAgentIdentity,
+	probeRepo repositories.ProbeRepository,
+	bootstrapRepo repositories.BootstrapRepository,
+	statusRepo repositories.StatusRepository,
+	grpcRepo repositories.GRPCRepository,
+	grpcStatusRepo repositories.GRPCStatusRepository,
+	jsonrpcRepo repositories.JSONRPCServerRepository,
+	jsonrpcStatusRepo repositories.JSONRPCStatusRepository,
+	maintenanceRepo repositories.MaintenanceRepository,
+	minRegions int,
+	maxClockSkew time.Duration,
+	logger *logrus.Logger,
+) *ProbeService {
+	registry := make(map[string]*ProbeAgentIdentity, len(agents))
+	for _, agent := range agents {
+		registry[agent.ID] = agent
+	}
+
+	return &ProbeService{
+		agents:            registry,
+		probeRepo:         probeRepo,
+		bootstrapRepo:     bootstrapRepo,
+		statusRepo:        statusRepo,
+		grpcRepo:          grpcRepo,
+		grpcStatusRepo:    grpcStatusRepo,
+		jsonrpcRepo:       jsonrpcRepo,
+		jsonrpcStatusRepo: jsonrpcStatusRepo,
+		maintenanceRepo:   maintenanceRepo,
+		minRegions:        minRegions,
+		maxClockSkew:      maxClockSkew,
+		logger:            logger,
+	}
+}
+
+// Authenticate verifies a signed agent request and returns the agent
+// identity. Each nonce is accepted once per agent, across every replica.
+func (s *ProbeService) Authenticate(ctx context.Context, agentID, timestamp, nonce, signature string, body []byte) (*ProbeAgentIdentity, error) {
+	agent, ok := s.agents[agentID]
+	if !ok {
+		return nil, fmt.Errorf("unknown probe agent: %s", agentID)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	signedAt := time.Unix(unix, 0)
+	skew := time.Since(signedAt)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > s.maxClockSkew {
+		return nil, fmt.Errorf("timestamp outside allowed skew of %s", s.maxClockSkew)
+	}
+
+	if nonce == "" || len(nonce) > maxProbeNonceLength {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	if !ed25519.Verify(agent.PublicKey, ProbeSigningPayload(agentID, timestamp, nonce, body), sig) {
+		return nil, fmt.Errorf("invalid signature for probe agent: %s", agentID)
+	}
+
+	// Only signed requests are remembered, so unknown callers cannot fill
+	// the table. A nonce is kept until its timestamp leaves the allowed
+	// skew, after which the request is refused anyway.
+	fresh, err := s.probeRepo.MarkNonce(ctx, agentID, nonce, signedAt.Add(s.maxClockSkew))
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, fmt.Errorf("replayed request for probe agent: %s", agentID)
+	}
+
+	return agent, nil
+}
+
+// GetTargets returns every active node the agents should check
+func (s *ProbeService) GetTargets(ctx context.Context) ([]*models.ProbeTarget, error) {
+	var targets []*models.ProbeTarget
+
+	nodes, err := s.bootstrapRepo.GetActiveNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get active bootstrap nodes: %w", err)
+	}
+	for _, node := range nodes {
+		targets = append(targets, &models.ProbeTarget{NodeType: models.NodeTypeBootstrap, NodeID: node.ID, Address: node.Address})
+	}
+
+	grpcServers, err := s.grpcRepo.GetActiveServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get active grpc servers: %w", err)
+	}
+	for _, server := range grpcServers {
+		targets = append(targets, &models.ProbeTarget{NodeType: models.NodeTypeGRPC, NodeID: server.ID, Address: server.Address})
+	}
+
+	jsonrpcServers, err := s.jsonrpcRepo.GetActiveServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get active jsonrpc servers: %w", err)
+	}
+	for _, server := range jsonrpcServers {
+		targets = append(targets, &models.ProbeTarget{NodeType: models.NodeTypeJSONRPC, NodeID: server.ID, Address: server.Address})
+	}
+
+	return targets, nil
+}
+
+// SubmitReport stores an agent's results and recomputes the quorum for the affected nodes
+func (s *ProbeService) SubmitReport(ctx context.Context, agent *ProbeAgentIdentity, report *models.ProbeReport) (*models.ProbeReportResponse, error) {
+	response := &models.ProbeReportResponse{Timestamp: time.Now().UTC()}
+
+	type nodeDay struct {
+		nodeType string
+		nodeID   int
+		date     time.Time
+	}
+	touched := make(map[nodeDay]struct{})
+
+	for _, result := range report.Results {
+		if result == nil || !isProbeNodeType(result.NodeType) {
+			response.Rejected++
+			continue
+		}
+		if result.CheckedAt.IsZero() {
+			result.CheckedAt = response.Timestamp
+		}
+		// Checks are only accepted while they are fresh, so old results
+		// cannot be resubmitted to sway a quorum
+		if result.CheckedAt.After(response.Timestamp.Add(s.maxClockSkew)) ||
+			result.CheckedAt.Before(response.Timestamp.Add(-s.maxClockSkew)) {
+			response.Rejected++
+			continue
+		}
+
+		// The registry is authoritative for who reported and from where
+		result.AgentID = agent.ID
+		result.Region = agent.Region
+		result.Date = result.CheckedAt.UTC().Truncate(24 * time.Hour)
+
+		if err := s.probeRepo.CreateResult(ctx, result); err != nil {
+			return nil, err
+		}
+
+		response.Accepted++
+		touched[nodeDay{result.NodeType, result.NodeID, result.Date}] = struct{}{}
+	}
+
+	for key := range touched {
+		if err := s.applyQuorum(ctx, key.nodeType, key.nodeID, key.date); err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"node_type": key.nodeType,
+				"node_id":   key.nodeID,
+			}).Error("Failed to apply probe quorum")
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"agent_id": agent.ID,
+		"region":   agent.Region,
+		"accepted": response.Accepted,
+		"rejected": response.Rejected,
+	}).Info("Probe report received")
+
+	return response, nil
+}
+
+// applyQuorum overwrites a node's daily status once enough regions have
+// reported. A node in a maintenance window at the latest report gets the
+// maintenance color.
+func (s *ProbeService) applyQuorum(ctx context.Context, nodeType string, nodeID int, date time.Time) error {
+	results, err := s.probeRepo.GetResultsByNodeAndDate(ctx, nodeType, nodeID, date)
+	if err != nil {
+		return err
+	}
+
+	quorum := ComputeQuorum(results, s.minRegions)
+	if !quorum.Decided {
+		return nil
+	}
+
+	errorMsg := ""
+	if quorum.Color == 0 {
+		errorMsg = fmt.Sprintf("reachable from %d of %d regions", quorum.RegionsUp, quorum.RegionsTotal)
+	}
+
+	color := quorum.Color
+	if s.maintenanceRepo != nil {
+		var latest time.Time
+		for _, result := range results {
+			if result.CheckedAt.After(latest) {
+				latest = result.CheckedAt
+			}
+		}
+		window, err := s.maintenanceRepo.GetActive(ctx, nodeType, nodeID, latest)
+		if err != nil {
+			return err
+		}
+		if window != nil {
+			color = models.StatusColorMaintenance
+		}
+	}
+
+	switch nodeType {
+	case models.NodeTypeBootstrap:
+		return s.statusRepo.CreateStatus(ctx, &models.DailyStatus{
+			NodeID:     nodeID,
+			Date:       date,
+			Color:      color,
+			Attempts:   quorum.Probes,
+			Success:    quorum.Color == 1,
+			ErrorMsg:   errorMsg,
+			ErrorClass: quorum.ErrorClass,
+		})
+	case models.NodeTypeGRPC:
+		return s.grpcStatusRepo.CreateStatus(ctx, &models.GRPCDailyStatus{
+			ServerID:       nodeID,
+			Date:           date,
+			Color:          color,
+			Attempts:       quorum.Probes,
+			Success:        quorum.Color == 1,
+			ErrorMsg:       errorMsg,
+			ErrorClass:     quorum.ErrorClass,
+			ResponseTimeMs: quorum.ResponseTimeMs,
+		})
+	case models.NodeTypeJSONRPC:
+		return s.jsonrpcStatusRepo.CreateStatus(ctx, &models.JSONRPCDailyStatus{
+			ServerID:       nodeID,
+			Date:           date,
+			Color:          color,
+			Attempts:       quorum.Probes,
+			Success:        quorum.Color == 1,
+			ErrorMsg:       errorMsg,
+			ErrorClass:     quorum.ErrorClass,
+			ResponseTimeMs: quorum.ResponseTimeMs,
+		})
+	}
+
+	return nil
+}
+
+// ComputeQuorum derives a node's color from probe results across regions.
+// A region counts as up when any of its agents reached the node, and the
+// node is green when a strict majority of reporting regions are up. The
+// verdict is only decided once at least minRegions regions have reported.
+func ComputeQuorum(results []*models.ProbeResult, minRegions int) *models.QuorumResult {
+	if minRegions < 1 {
+		minRegions = 1
+	}
+
+	regions := make(map[string]bool)
+	classes := make(map[models.ErrorClass]int)
+	var latencies []int
+
+	for _, result := range results {
+		regions[result.Region] = regions[result.Region] || result.Success
+		if result.Success {
+			latencies = append(latencies, result.ResponseTimeMs)
+		} else if result.ErrorClass != models.ErrorClassNone {
+			classes[result.ErrorClass]++
+		}
+	}
+
+	quorum := &models.QuorumResult{
+		RegionsTotal: len(regions),
+		Probes:       len(results),
+	}
+	for _, up := range regions {
+		if up {
+			quorum.RegionsUp++
+		}
+	}
+
+	quorum.Decided = quorum.RegionsTotal >= minRegions
+	if quorum.RegionsUp*2 > quorum.RegionsTotal {
+		quorum.Color = 1
+	} else {
+		quorum.ErrorClass = dominantErrorClass(classes)
+	}
+
+	if len(latencies) > 0 {
+		sort.Ints(latencies)
+		quorum.ResponseTimeMs = latencies[len(latencies)/2]
+	}
+
+	return quorum
+}
+
+// dominantErrorClass returns the most frequent failure class, preferring the
+// alphabetically first one on ties so the result is stable
+func dominantErrorClass(classes map[models.ErrorClass]int) models.ErrorClass {
+	dominant := models.ErrorClassNone
+	for class, count := range classes {
+		if count > classes[dominant] || (count == classes[dominant] && class < dominant) {
+			dominant = class
+		}
+	}
+	return dominant
+}
+
+func isProbeNodeType(nodeType string) bool {
+	switch nodeType {
+	case models.NodeTypeBootstrap, models.NodeTypeGRPC, models.NodeTypeJSONRPC:
+		return true
+	}
+	return false
+}
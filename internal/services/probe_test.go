@@ -0,0 +1,472 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// memoryProbeRepository keeps probe results and nonces in memory
+type memoryProbeRepository struct {
+	mu      sync.Mutex
+	results []*models.ProbeResult
+	nonces  map[string]time.Time
+}
+
+func (r *memoryProbeRepository) CreateResult(ctx context.Context, result *models.ProbeResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *memoryProbeRepository) GetResultsByNodeAndDate(ctx context.Context, nodeType string, nodeID int, date time.Time) ([]*models.ProbeResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.ProbeResult
+	for _, result := range r.results {
+		if result.NodeType == nodeType && result.NodeID == nodeID && result.Date.Equal(date) {
+			out = append(out, result)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryProbeRepository) DeleteOldResults(ctx context.Context, beforeDate time.Time) error {
+	return nil
+}
+
+func (r *memoryProbeRepository) MarkNonce(ctx context.Context, agentID, nonce string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.nonces == nil {
+		r.nonces = make(map[string]time.Time)
+	}
+	key := agentID + "\n" + nonce
+	if expiry, seen := r.nonces[key]; seen && time.Now().Before(expiry) {
+		return false, nil
+	}
+	r.nonces[key] = expiresAt
+	return true, nil
+}
+
+func (r *memoryProbeRepository) DeleteExpiredNonces(ctx context.Context) error {
+	return nil
+}
+
+// memoryStatusRepository records the daily statuses written by the quorum
+type memoryStatusRepository struct {
+	mu       sync.Mutex
+	statuses map[int]*models.DailyStatus
+}
+
+func (r *memoryStatusRepository) CreateStatus(ctx context.Context, status *models.DailyStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses[status.NodeID] = status
+	return nil
+}
+
+func (r *memoryStatusRepository) GetStatusByNodeAndDate(ctx context.Context, nodeID int, date time.Time) (*models.DailyStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statuses[nodeID], nil
+}
+
+func (r *memoryStatusRepository) GetRecentStatusesByNode(ctx context.Context, nodeID int, days int) ([]models.StatusItem, error) {
+	return nil, nil
+}
+
+func (r *memoryStatusRepository) GetRecentStatusesByNodes(ctx context.Context, nodeIDs []int, days int) (map[int][]models.StatusItem, error) {
+	return map[int][]models.StatusItem{}, nil
+}
+
+func (r *memoryStatusRepository) HasStatusForDate(ctx context.Context, nodeID int, date time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *memoryStatusRepository) GetStatusesByNodeAndDateRange(ctx context.Context, nodeID int, startDate, endDate time.Time) ([]*models.DailyStatus, error) {
+	return nil, nil
+}
+
+func (r *memoryStatusRepository) GetStatusesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.DailyStatus, error) {
+	return nil, nil
+}
+
+func (r *memoryStatusRepository) DeleteOldStatuses(ctx context.Context, beforeDate time.Time) error {
+	return nil
+}
+
+// blockedProbeClient simulates a region that cannot route to some addresses
+type blockedProbeClient struct {
+	ProbeClient
+	blocked map[string]string
+}
+
+func (c *blockedProbeClient) FetchTargets(ctx context.Context) ([]*models.ProbeTarget, error) {
+	targets, err := c.ProbeClient.FetchTargets(ctx)
+	for _, target := range targets {
+		if replacement, ok := c.blocked[target.Address]; ok {
+			target.Address = replacement
+		}
+	}
+	return targets, err
+}
+
+func TestComputeQuorum(t *testing.T) {
+	result := func(region string, success bool, latency int) *models.ProbeResult {
+		return &models.ProbeResult{Region: region, Success: success, ResponseTimeMs: latency}
+	}
+
+	tests := []struct {
+		name          string
+		results       []*models.ProbeResult
+		minRegions    int
+		expectColor   int
+		expectDecided bool
+		expectLatency int
+	}{
+		{
+			name:          "No results",
+			minRegions:    1,
+			expectColor:   0,
+			expectDecided: false,
+		},
+		{
+			name:          "All regions up",
+			results:       []*models.ProbeResult{result("eu", true, 10), result("us", true, 30), result("ap", true, 20)},
+			minRegions:    2,
+			expectColor:   1,
+			expectDecided: true,
+			expectLatency: 20,
+		},
+		{
+			name:          "Single region outage is outvoted",
+			results:       []*models.ProbeResult{result("eu", true, 10), result("us", true, 30), result("ap", false, 0)},
+			minRegions:    2,
+			expectColor:   1,
+			expectDecided: true,
+			expectLatency: 30,
+		},
+		{
+			name:          "Even split is not a majority",
+			results:       []*models.ProbeResult{result("eu", true, 10), result("us", false, 0)},
+			minRegions:    2,
+			expectColor:   0,
+			expectDecided: true,
+			expectLatency: 10,
+		},
+		{
+			name:          "Any agent in a region counts the region as up",
+			results:       []*models.ProbeResult{result("eu", false, 0), result("eu", true, 15)},
+			minRegions:    1,
+			expectColor:   1,
+			expectDecided: true,
+			expectLatency: 15,
+		},
+		{
+			name:          "Too few regions is undecided",
+			results:       []*models.ProbeResult{result("eu", true, 10), result("eu", true, 12)},
+			minRegions:    2,
+			expectColor:   1,
+			expectDecided: false,
+			expectLatency: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quorum := ComputeQuorum(tt.results, tt.minRegions)
+
+			if quorum.Color != tt.expectColor {
+				t.Errorf("Expected color %d, got %d", tt.expectColor, quorum.Color)
+			}
+			if quorum.Decided != tt.expectDecided {
+				t.Errorf("Expected decided %v, got %v", tt.expectDecided, quorum.Decided)
+			}
+			if quorum.ResponseTimeMs != tt.expectLatency {
+				t.Errorf("Expected latency %d, got %d", tt.expectLatency, quorum.ResponseTimeMs)
+			}
+		})
+	}
+}
+
+func TestProbeService_AuthenticateRejectsBadRequests(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+
+	// Two replicas sharing the nonce store
+	agents := []*ProbeAgentIdentity{{ID: "eu-1", Region: "eu", PublicKey: pub}}
+	probeRepo := &memoryProbeRepository{}
+	svc := NewProbeService(agents, probeRepo, nil, nil, nil, nil, nil, nil, nil, 2, time.Minute, logger)
+	replica := NewProbeService(agents, probeRepo, nil, nil, nil, nil, nil, nil, nil, 2, time.Minute, logger)
+
+	body := []byte(`{"results":[]}`)
+	now := fmt.Sprintf("%d", time.Now().Unix())
+	stale := fmt.Sprintf("%d", time.Now().Add(-time.Hour).Unix())
+
+	sign := func(key ed25519.PrivateKey, agentID, ts, nonce string) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(key, ProbeSigningPayload(agentID, ts, nonce, body)))
+	}
+
+	tests := []struct {
+		name      string
+		replica   bool
+		agentID   string
+		timestamp string
+		nonce     string
+		signature string
+		expectErr bool
+	}{
+		{name: "Valid signature", agentID: "eu-1", timestamp: now, nonce: "n1", signature: sign(priv, "eu-1", now, "n1")},
+		{name: "Replayed nonce", agentID: "eu-1", timestamp: now, nonce: "n1", signature: sign(priv, "eu-1", now, "n1"), expectErr: true},
+		{name: "Replayed on another replica", replica: true, agentID: "eu-1", timestamp: now, nonce: "n1", signature: sign(priv, "eu-1", now, "n1"), expectErr: true},
+		{name: "Missing nonce", agentID: "eu-1", timestamp: now, signature: sign(priv, "eu-1", now, ""), expectErr: true},
+		{name: "Signature over other nonce", agentID: "eu-1", timestamp: now, nonce: "n2", signature: sign(priv, "eu-1", now, "n3"), expectErr: true},
+		{name: "Unknown agent", agentID: "us-1", timestamp: now, nonce: "n4", signature: sign(priv, "us-1", now, "n4"), expectErr: true},
+		{name: "Wrong key", agentID: "eu-1", timestamp: now, nonce: "n5", signature: sign(otherPriv, "eu-1", now, "n5"), expectErr: true},
+		{name: "Stale timestamp", agentID: "eu-1", timestamp: stale, nonce: "n6", signature: sign(priv, "eu-1", stale, "n6"), expectErr: true},
+		{name: "Signature over other timestamp", agentID: "eu-1", timestamp: now, nonce: "n7", signature: sign(priv, "eu-1", stale, "n7"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := svc
+			if tt.replica {
+				target = replica
+			}
+			_, err := target.Authenticate(context.Background(), tt.agentID, tt.timestamp, tt.nonce, tt.signature, body)
+			if tt.expectErr && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestProbeService_SubmitReportRejectsStaleChecks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	probeRepo := &memoryProbeRepository{}
+	statusRepo := &memoryStatusRepository{statuses: make(map[int]*models.DailyStatus)}
+	svc := NewProbeService(nil, probeRepo, nil, statusRepo, nil, nil, nil, nil, nil, 2, time.Minute, logger)
+
+	now := time.Now().UTC()
+	report := &models.ProbeReport{Results: []*models.ProbeResult{
+		{NodeType: models.NodeTypeBootstrap, NodeID: 1, Success: true, CheckedAt: now},
+		{NodeType: models.NodeTypeBootstrap, NodeID: 2, Success: true, CheckedAt: now.Add(-time.Hour)},
+		{NodeType: models.NodeTypeBootstrap, NodeID: 3, Success: true, CheckedAt: now.Add(time.Hour)},
+	}}
+
+	response, err := svc.SubmitReport(context.Background(), &ProbeAgentIdentity{ID: "eu-1", Region: "eu"}, report)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Accepted != 1 || response.Rejected != 2 {
+		t.Errorf("Expected 1 accepted and 2 rejected results, got %+v", response)
+	}
+}
+
+// recordingProbeClient serves fixed targets and records submitted reports
+type recordingProbeClient struct {
+	targets []*models.ProbeTarget
+	reports []*models.ProbeReport
+}
+
+func (c *recordingProbeClient) FetchTargets(ctx context.Context) ([]*models.ProbeTarget, error) {
+	return c.targets, nil
+}
+
+func (c *recordingProbeClient) SubmitReport(ctx context.Context, report *models.ProbeReport) (*models.ProbeReportResponse, error) {
+	c.reports = append(c.reports, report)
+	return &models.ProbeReportResponse{Accepted: len(report.Results), Timestamp: time.Now().UTC()}, nil
+}
+
+func TestProbeAgent_SubmitsResultsInBatches(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// Unsupported node types finish without a network call
+	client := &recordingProbeClient{}
+	for i := 0; i < probeBatchSize*2+1; i++ {
+		client.targets = append(client.targets, &models.ProbeTarget{NodeType: "unknown", NodeID: i})
+	}
+
+	agent := NewProbeAgent("eu-1", "eu", client, nil, nil, nil, logger)
+	response, err := agent.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	if len(client.reports) != 3 {
+		t.Fatalf("Expected 3 batches, got %d", len(client.reports))
+	}
+	if response.Accepted != len(client.targets) {
+		t.Errorf("Expected %d accepted results, got %d", len(client.targets), response.Accepted)
+	}
+	for _, report := range client.reports {
+		if report.AgentID != "eu-1" || report.Region != "eu" || len(report.Results) > probeBatchSize {
+			t.Errorf("Unexpected batch: agent %s, region %s, %d results", report.AgentID, report.Region, len(report.Results))
+		}
+	}
+}
+
+func TestProbeAgents_InProcessQuorum(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// A reachable node and one that nobody can reach
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	upAddress := fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/up", listener.Addr().(*net.TCPAddr).Port)
+	downAddress := fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/down", closedPort)
+
+	targets := []*models.ProbeTarget{
+		{NodeType: models.NodeTypeBootstrap, NodeID: 1, Address: upAddress},
+		{NodeType: models.NodeTypeBootstrap, NodeID: 2, Address: downAddress},
+	}
+
+	regions := []string{"eu", "us", "ap"}
+	keys := make(map[string]ed25519.PrivateKey)
+	var identities []*ProbeAgentIdentity
+	for _, region := range regions {
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		id := region + "-1"
+		keys[id] = priv
+		identities = append(identities, &ProbeAgentIdentity{ID: id, Region: region, PublicKey: pub})
+	}
+
+	probeRepo := &memoryProbeRepository{}
+	statusRepo := &memoryStatusRepository{statuses: make(map[int]*models.DailyStatus)}
+	svc := NewProbeService(identities, probeRepo, nil, statusRepo, nil, nil, nil, nil, nil, 2, time.Minute, logger)
+
+	// In-process tracker exposing the probe API
+	tracker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		agent, err := svc.Authenticate(
+			r.Context(),
+			r.Header.Get(ProbeHeaderAgent),
+			r.Header.Get(ProbeHeaderTimestamp),
+			r.Header.Get(ProbeHeaderNonce),
+			r.Header.Get(ProbeHeaderSignature),
+			body,
+		)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/probes/targets":
+			copied := make([]*models.ProbeTarget, len(targets))
+			for i, target := range targets {
+				target := *target
+				copied[i] = &target
+			}
+			json.NewEncoder(w).Encode(copied)
+		case "/api/v1/probes/results":
+			var report models.ProbeReport
+			if err := json.Unmarshal(body, &report); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			response, err := svc.SubmitReport(r.Context(), agent, &report)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(response)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer tracker.Close()
+
+	nodeChecker := NewNodeChecker(time.Second, 1, logger)
+
+	var wg sync.WaitGroup
+	for _, identity := range identities {
+		var client ProbeClient = NewHTTPProbeClient(tracker.URL, identity.ID, keys[identity.ID], 5*time.Second)
+		if identity.Region == "ap" {
+			// This region has a routing problem towards the reachable node
+			client = &blockedProbeClient{ProbeClient: client, blocked: map[string]string{upAddress: downAddress}}
+		}
+
+		agent := NewProbeAgent(identity.ID, identity.Region, client, nodeChecker, nil, nil, logger)
+
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			response, err := agent.RunOnce(context.Background())
+			if err != nil {
+				t.Errorf("Agent in %s failed: %v", region, err)
+				return
+			}
+			if response.Accepted != len(targets) {
+				t.Errorf("Agent in %s: expected %d accepted results, got %d", region, len(targets), response.Accepted)
+			}
+		}(identity.Region)
+	}
+	wg.Wait()
+
+	if len(probeRepo.results) != len(targets)*len(regions) {
+		t.Fatalf("Expected %d stored results, got %d", len(targets)*len(regions), len(probeRepo.results))
+	}
+
+	up := statusRepo.statuses[1]
+	if up == nil || up.Color != 1 {
+		t.Errorf("Expected node 1 to be green by quorum, got %+v", up)
+	}
+
+	down := statusRepo.statuses[2]
+	if down == nil || down.Color != 0 {
+		t.Errorf("Expected node 2 to be red by quorum, got %+v", down)
+	}
+
+	// A forged report from an unregistered agent is refused
+	_, forgedKey, _ := ed25519.GenerateKey(rand.Reader)
+	forged := NewHTTPProbeClient(tracker.URL, "eu-1", forgedKey, 5*time.Second)
+	if _, err := forged.SubmitReport(context.Background(), &models.ProbeReport{}); err == nil {
+		t.Error("Expected forged report to be rejected")
+	}
+}
@@ -0,0 +1,446 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// curatedNode is a stored node as the reconciler sees it. Only curated
+// nodes, the ones syncs created, are updated, deactivated and reactivated.
+type curatedNode struct {
+	ID       int
+	Name     string
+	Email    string
+	Website  string
+	Address  string
+	Network  string
+	IsActive bool
+	Curated  bool
+}
+
+// curatedStore adapts the repository of a curated node type to the
+// reconciler
+type curatedStore interface {
+	List(ctx context.Context) ([]*curatedNode, error)
+	Create(ctx context.Context, network string, node *SourceNode) (int, error)
+	Update(ctx context.Context, network string, node *SourceNode) error
+	Deactivate(ctx context.Context, addresses []string) error
+	Reactivate(ctx context.Context, addresses []string) error
+}
+
+// nodeReconciler syncs a curated node type: it loads the list of every
+// active network from the node source and adds the listed nodes that are
+// missing, updates the ones whose details changed, reactivates the ones
+// that returned and deactivates the ones no longer listed
+type nodeReconciler struct {
+	nodeType       string
+	source         NodeSource
+	store          curatedStore
+	addresses      func(network *models.Network) []string
+	name           func(address string) string
+	networkService *NetworkService
+	syncRunRepo    repositories.SyncRunRepository
+	eventBus       *events.Bus
+	logger         *logrus.Logger
+}
+
+// Sync reconciles every active network with its list and returns the
+// report of the run. A network whose list cannot be loaded is skipped and
+// counted as an error, and so is an empty list while the network still has
+// tracked nodes. A dry run changes nothing and is neither stored nor
+// published.
+func (r *nodeReconciler) Sync(ctx context.Context, dryRun bool) (*models.SyncRun, error) {
+	r.logger.WithFields(logrus.Fields{
+		"node_type": r.nodeType,
+		"source":    r.source.Name(),
+		"dry_run":   dryRun,
+	}).Info("Starting node sync")
+
+	run := &models.SyncRun{
+		NodeType:  r.nodeType,
+		Source:    r.source.Name(),
+		DryRun:    dryRun,
+		StartedAt: time.Now().UTC(),
+	}
+	defer r.finish(ctx, run)
+
+	networks, err := activeNetworks(ctx, r.networkService)
+	if err != nil {
+		err = fmt.Errorf("failed to get networks: %w", err)
+		run.Fail(err)
+		return run, err
+	}
+
+	current, err := r.store.List(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to get current nodes: %w", err)
+		run.Fail(err)
+		return run, err
+	}
+
+	for _, network := range networks {
+		listed, err := networkNodes(ctx, r.source, network, r.addresses(network), r.name)
+		if err != nil {
+			r.logger.WithError(err).WithFields(logrus.Fields{
+				"node_type": r.nodeType,
+				"network":   network.Name,
+			}).Error("Failed to load node list")
+			run.Fail(err)
+			continue
+		}
+
+		// An empty list is more likely a broken source than a network
+		// that lost every node, so it deactivates nothing
+		if len(listed) == 0 {
+			if tracked := activeCurated(current, network.Name); tracked > 0 {
+				r.logger.WithFields(logrus.Fields{
+					"node_type": r.nodeType,
+					"network":   network.Name,
+					"tracked":   tracked,
+				}).Error("Node list is empty, keeping the tracked nodes")
+				run.Fail(fmt.Errorf("%s lists no %s nodes", r.source.Name(), network.Name))
+			}
+			continue
+		}
+		current = r.reconcile(ctx, network.Name, listed, current, run)
+	}
+
+	return run, nil
+}
+
+// reconcile applies the list of a network to the current nodes, records
+// the changes in run and returns the current nodes including the added
+// ones
+func (r *nodeReconciler) reconcile(ctx context.Context, network string, listed []*SourceNode, current []*curatedNode, run *models.SyncRun) []*curatedNode {
+	byAddress := make(map[string]*curatedNode, len(current))
+	for _, node := range current {
+		byAddress[node.Address] = node
+	}
+
+	listedAddresses := make(map[string]bool, len(listed))
+	var reactivate []*curatedNode
+	for _, node := range listed {
+		listedAddresses[node.Address] = true
+
+		existing, exists := byAddress[node.Address]
+		if !exists {
+			if added := r.add(ctx, network, node, run); added != nil {
+				current = append(current, added)
+			}
+			continue
+		}
+
+		if existing.Network != network || !existing.Curated {
+			r.logger.WithFields(logrus.Fields{
+				"node_type": r.nodeType,
+				"address":   node.Address,
+				"network":   network,
+				"current":   existing.Network,
+				"curated":   existing.Curated,
+			}).Warn("Listed node is already tracked on another network or outside syncs")
+			continue
+		}
+
+		if fields := changedFields(existing.Name, existing.Email, existing.Website, node); len(fields) > 0 {
+			if !run.DryRun {
+				if err := r.store.Update(ctx, network, node); err != nil {
+					r.logger.WithError(err).WithField("address", node.Address).Error("Failed to update node")
+					run.Errors++
+					continue
+				}
+			}
+			run.Updated = append(run.Updated, models.SyncChange{
+				Network: network,
+				Address: node.Address,
+				Name:    node.Name,
+				Fields:  fields,
+			})
+		}
+
+		if !existing.IsActive {
+			reactivate = append(reactivate, existing)
+		}
+	}
+
+	var deactivate []*curatedNode
+	for _, node := range current {
+		if node.Network == network && node.Curated && node.IsActive && !listedAddresses[node.Address] {
+			deactivate = append(deactivate, node)
+		}
+	}
+
+	r.setActive(ctx, network, reactivate, true, run)
+	r.setActive(ctx, network, deactivate, false, run)
+	return current
+}
+
+// activeCurated counts the active curated nodes of a network
+func activeCurated(current []*curatedNode, network string) int {
+	count := 0
+	for _, node := range current {
+		if node.Network == network && node.Curated && node.IsActive {
+			count++
+		}
+	}
+	return count
+}
+
+// add stores a listed node that is not tracked yet
+func (r *nodeReconciler) add(ctx context.Context, network string, node *SourceNode, run *models.SyncRun) *curatedNode {
+	added := &curatedNode{
+		Name:     node.Name,
+		Email:    node.Email,
+		Website:  node.Website,
+		Address:  node.Address,
+		Network:  network,
+		IsActive: true,
+		Curated:  true,
+	}
+	if !run.DryRun {
+		id, err := r.store.Create(ctx, network, node)
+		if err != nil {
+			r.logger.WithError(err).WithField("address", node.Address).Error("Failed to add node")
+			run.Errors++
+			return nil
+		}
+		added.ID = id
+	}
+
+	run.Added = append(run.Added, models.SyncChange{
+		Network: network,
+		Address: node.Address,
+		Name:    node.Name,
+	})
+	if added.ID != 0 {
+		publishNode(ctx, r.eventBus, events.NodeAdded, r.nodeType, added.ID, events.Node{
+			Name:    node.Name,
+			Address: node.Address,
+			Network: network,
+		})
+	}
+	return added
+}
+
+// setActive reactivates or deactivates nodes of a network
+func (r *nodeReconciler) setActive(ctx context.Context, network string, nodes []*curatedNode, active bool, run *models.SyncRun) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	addresses := make([]string, len(nodes))
+	for i, node := range nodes {
+		addresses[i] = node.Address
+	}
+
+	if !run.DryRun {
+		store := r.store.Deactivate
+		if active {
+			store = r.store.Reactivate
+		}
+		if err := store(ctx, addresses); err != nil {
+			r.logger.WithError(err).WithFields(logrus.Fields{
+				"network": network,
+				"active":  active,
+			}).Error("Failed to change node activity")
+			run.Errors++
+			return
+		}
+	}
+
+	for _, node := range nodes {
+		change := models.SyncChange{Network: network, Address: node.Address, Name: node.Name}
+		if active {
+			run.Reactivated = append(run.Reactivated, change)
+			continue
+		}
+		run.Deactivated = append(run.Deactivated, change)
+		if !run.DryRun {
+			publishNode(ctx, r.eventBus, events.NodeDeactivated, r.nodeType, node.ID, events.Node{
+				Name:    node.Name,
+				Address: node.Address,
+				Network: network,
+			})
+		}
+	}
+}
+
+// finish stores the report of a sync, logs it and publishes SyncFinished.
+// Dry runs are only logged.
+func (r *nodeReconciler) finish(ctx context.Context, run *models.SyncRun) {
+	run.FinishedAt = time.Now().UTC()
+
+	r.logger.WithFields(logrus.Fields{
+		"node_type":   run.NodeType,
+		"source":      run.Source,
+		"dry_run":     run.DryRun,
+		"added":       len(run.Added),
+		"updated":     len(run.Updated),
+		"deactivated": len(run.Deactivated),
+		"reactivated": len(run.Reactivated),
+		"errors":      run.Errors,
+	}).Info("Completed node sync")
+
+	if run.DryRun {
+		return
+	}
+
+	if r.syncRunRepo != nil {
+		if err := r.syncRunRepo.CreateRun(ctx, run); err != nil {
+			r.logger.WithError(err).WithField("node_type", run.NodeType).Error("Failed to store sync run")
+		}
+	}
+
+	r.eventBus.Publish(ctx, events.Event{
+		Type:     events.SyncFinished,
+		NodeType: run.NodeType,
+		Data: events.SyncResult{
+			Source:      run.Source,
+			Added:       len(run.Added),
+			Updated:     len(run.Updated),
+			Deactivated: len(run.Deactivated),
+			Reactivated: len(run.Reactivated),
+			Errors:      run.Errors,
+		},
+	})
+}
+
+// changedFields returns the fields of a stored node that differ from the
+// listed node
+func changedFields(name, email, website string, listed *SourceNode) []string {
+	var fields []string
+	if name != listed.Name {
+		fields = append(fields, "name")
+	}
+	if email != listed.Email {
+		fields = append(fields, "email")
+	}
+	if website != listed.Website {
+		fields = append(fields, "website")
+	}
+	return fields
+}
+
+// bootstrapStore reconciles bootstrap nodes, all of which are curated
+type bootstrapStore struct {
+	repo repositories.BootstrapRepository
+}
+
+func (s *bootstrapStore) List(ctx context.Context) ([]*curatedNode, error) {
+	nodes, err := s.repo.GetAllNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make([]*curatedNode, len(nodes))
+	for i, node := range nodes {
+		current[i] = &curatedNode{
+			ID:       node.ID,
+			Name:     node.Name,
+			Email:    node.Email,
+			Website:  node.Website,
+			Address:  node.Address,
+			Network:  node.Network,
+			IsActive: node.IsActive,
+			Curated:  true,
+		}
+	}
+	return current, nil
+}
+
+func (s *bootstrapStore) Create(ctx context.Context, network string, node *SourceNode) (int, error) {
+	created := &models.BootstrapNode{
+		Name:     node.Name,
+		Email:    node.Email,
+		Website:  node.Website,
+		Address:  node.Address,
+		Network:  network,
+		IsActive: true,
+	}
+	if err := s.repo.CreateNode(ctx, created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (s *bootstrapStore) Update(ctx context.Context, network string, node *SourceNode) error {
+	return s.repo.UpdateNode(ctx, &models.BootstrapNode{
+		Name:    node.Name,
+		Email:   node.Email,
+		Website: node.Website,
+		Address: node.Address,
+	})
+}
+
+func (s *bootstrapStore) Deactivate(ctx context.Context, addresses []string) error {
+	return s.repo.DeactivateNodes(ctx, addresses)
+}
+
+func (s *bootstrapStore) Reactivate(ctx context.Context, addresses []string) error {
+	return s.repo.ReactivateNodes(ctx, addresses)
+}
+
+// grpcStore reconciles gRPC servers. Registered servers are not curated.
+type grpcStore struct {
+	repo repositories.GRPCRepository
+}
+
+func (s *grpcStore) List(ctx context.Context) ([]*curatedNode, error) {
+	servers, err := s.repo.GetAllServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make([]*curatedNode, len(servers))
+	for i, server := range servers {
+		current[i] = &curatedNode{
+			ID:       server.ID,
+			Name:     server.Name,
+			Email:    server.Email,
+			Website:  server.Website,
+			Address:  server.Address,
+			Network:  server.Network,
+			IsActive: server.IsActive,
+			Curated:  server.IsCurated,
+		}
+	}
+	return current, nil
+}
+
+func (s *grpcStore) Create(ctx context.Context, network string, node *SourceNode) (int, error) {
+	created := &models.GRPCServer{
+		Name:      node.Name,
+		Address:   node.Address,
+		Network:   network,
+		Email:     node.Email,
+		Website:   node.Website,
+		IsActive:  true,
+		IsCurated: true,
+	}
+	if err := s.repo.CreateServer(ctx, created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (s *grpcStore) Update(ctx context.Context, network string, node *SourceNode) error {
+	return s.repo.UpdateServer(ctx, &models.GRPCServer{
+		Name:    node.Name,
+		Address: node.Address,
+		Network: network,
+		Email:   node.Email,
+		Website: node.Website,
+	})
+}
+
+func (s *grpcStore) Deactivate(ctx context.Context, addresses []string) error {
+	return s.repo.DeactivateServers(ctx, addresses)
+}
+
+func (s *grpcStore) Reactivate(ctx context.Context, addresses []string) error {
+	return s.repo.ReactivateServers(ctx, addresses)
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// memoryCuratedStore keeps reconciled nodes in memory
+type memoryCuratedStore struct {
+	nodes []*curatedNode
+}
+
+func (s *memoryCuratedStore) List(ctx context.Context) ([]*curatedNode, error) {
+	current := make([]*curatedNode, len(s.nodes))
+	for i, node := range s.nodes {
+		copied := *node
+		current[i] = &copied
+	}
+	return current, nil
+}
+
+func (s *memoryCuratedStore) Create(ctx context.Context, network string, node *SourceNode) (int, error) {
+	s.nodes = append(s.nodes, &curatedNode{
+		ID:       len(s.nodes) + 1,
+		Name:     node.Name,
+		Email:    node.Email,
+		Website:  node.Website,
+		Address:  node.Address,
+		Network:  network,
+		IsActive: true,
+		Curated:  true,
+	})
+	return len(s.nodes), nil
+}
+
+func (s *memoryCuratedStore) Update(ctx context.Context, network string, node *SourceNode) error {
+	stored := s.find(node.Address)
+	stored.Name, stored.Email, stored.Website = node.Name, node.Email, node.Website
+	return nil
+}
+
+func (s *memoryCuratedStore) Deactivate(ctx context.Context, addresses []string) error {
+	for _, address := range addresses {
+		s.find(address).IsActive = false
+	}
+	return nil
+}
+
+func (s *memoryCuratedStore) Reactivate(ctx context.Context, addresses []string) error {
+	for _, address := range addresses {
+		s.find(address).IsActive = true
+	}
+	return nil
+}
+
+func (s *memoryCuratedStore) find(address string) *curatedNode {
+	for _, node := range s.nodes {
+		if node.Address == address {
+			return node
+		}
+	}
+	return nil
+}
+
+func changeAddresses(changes []models.SyncChange) string {
+	addresses := make([]string, len(changes))
+	for i, change := range changes {
+		addresses[i] = change.Address
+	}
+	return fmt.Sprint(addresses)
+}
+
+func TestNodeReconciler_Sync(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	fixture := func() []*curatedNode {
+		return []*curatedNode{
+			{ID: 1, Name: "a", Address: "a:50051", Network: "mainnet", IsActive: true, Curated: true},
+			{ID: 2, Name: "b", Address: "b:50051", Network: "mainnet", IsActive: true, Curated: true},
+			{ID: 3, Name: "c", Address: "c:50051", Network: "mainnet", IsActive: false, Curated: true},
+			{ID: 4, Name: "registered", Address: "r:50051", Network: "mainnet", IsActive: true, Curated: false},
+			{ID: 5, Name: "t", Address: "t:50051", Network: "testnet", IsActive: true, Curated: true},
+		}
+	}
+	lists := map[string][]*SourceNode{
+		"mainnet": {
+			{Name: "a", Email: "ops@a.example.org", Address: "a:50051"},
+			{Name: "c", Address: "c:50051"},
+			{Name: "d", Address: "d:50051"},
+			{Name: "renamed", Address: "r:50051"},
+			{Name: "t", Address: "t:50051"},
+		},
+		"testnet": {
+			{Name: "t", Address: "t:50051"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		added       string
+		updated     string
+		deactivated string
+		reactivated string
+	}{
+		{
+			name:        "Applies changes",
+			added:       "[d:50051]",
+			updated:     "[a:50051]",
+			deactivated: "[b:50051]",
+			reactivated: "[c:50051]",
+		},
+		{
+			name:        "Dry run",
+			dryRun:      true,
+			added:       "[d:50051]",
+			updated:     "[a:50051]",
+			deactivated: "[b:50051]",
+			reactivated: "[c:50051]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryCuratedStore{nodes: fixture()}
+			syncRunRepo := &memorySyncRunRepository{}
+			reconciler := &nodeReconciler{
+				nodeType:    models.NodeTypeGRPC,
+				source:      &staticNodeSource{lists: lists},
+				store:       store,
+				addresses:   func(*models.Network) []string { return nil },
+				name:        func(address string) string { return address },
+				syncRunRepo: syncRunRepo,
+				logger:      logger,
+			}
+
+			run, err := reconciler.Sync(context.Background(), tt.dryRun)
+			if err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+
+			if got := changeAddresses(run.Added); got != tt.added {
+				t.Errorf("Expected %s added, got %s", tt.added, got)
+			}
+			if got := changeAddresses(run.Updated); got != tt.updated {
+				t.Errorf("Expected %s updated, got %s", tt.updated, got)
+			}
+			if got := changeAddresses(run.Deactivated); got != tt.deactivated {
+				t.Errorf("Expected %s deactivated, got %s", tt.deactivated, got)
+			}
+			if got := changeAddresses(run.Reactivated); got != tt.reactivated {
+				t.Errorf("Expected %s reactivated, got %s", tt.reactivated, got)
+			}
+			if run.Errors != 0 {
+				t.Errorf("Expected no errors, got %d: %s", run.Errors, run.Error)
+			}
+
+			// The registered server and the testnet node listed on mainnet
+			// are left alone
+			if registered := store.find("r:50051"); registered.Name != "registered" || !registered.IsActive {
+				t.Errorf("Expected the registered server to be untouched, got %+v", registered)
+			}
+			if testnet := store.find("t:50051"); testnet.Network != "testnet" || !testnet.IsActive {
+				t.Errorf("Expected the testnet node to be untouched, got %+v", testnet)
+			}
+
+			if tt.dryRun {
+				if len(store.nodes) != 5 || !store.find("b:50051").IsActive || store.find("c:50051").IsActive || store.find("a:50051").Email != "" {
+					t.Error("Expected a dry run to change nothing")
+				}
+				if len(syncRunRepo.runs) != 0 {
+					t.Error("Expected a dry run not to be stored")
+				}
+				return
+			}
+
+			if store.find("d:50051") == nil || store.find("b:50051").IsActive || !store.find("c:50051").IsActive || store.find("a:50051").Email != "ops@a.example.org" {
+				t.Error("Expected the changes to be applied")
+			}
+			if len(syncRunRepo.runs) != 1 {
+				t.Errorf("Expected the run to be stored, got %d runs", len(syncRunRepo.runs))
+			}
+		})
+	}
+}
+
+func TestNodeReconciler_SyncKeepsNodesOnEmptyList(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	store := &memoryCuratedStore{nodes: []*curatedNode{
+		{ID: 1, Name: "a", Address: "a:50051", Network: "mainnet", IsActive: true, Curated: true},
+		{ID: 2, Name: "t", Address: "t:50051", Network: "testnet", IsActive: true, Curated: true},
+	}}
+	reconciler := &nodeReconciler{
+		nodeType: models.NodeTypeGRPC,
+		source: &staticNodeSource{lists: map[string][]*SourceNode{
+			"mainnet": {{Name: "a", Address: "a:50051"}},
+			"testnet": nil,
+		}},
+		store:     store,
+		addresses: func(*models.Network) []string { return nil },
+		name:      func(address string) string { return address },
+		logger:    logger,
+	}
+
+	run, err := reconciler.Sync(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	if len(run.Deactivated) != 0 || !store.find("t:50051").IsActive {
+		t.Errorf("Expected the testnet node to stay active, got %s deactivated", changeAddresses(run.Deactivated))
+	}
+	if run.Errors != 1 {
+		t.Errorf("Expected the empty list to be counted as an error, got %d", run.Errors)
+	}
+}
+
+func TestNodeReconciler_SyncLeavesOperatorManagedServers(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	// Operator edits and deregistrations clear the curated flag of a
+	// listed server
+	tests := []struct {
+		name   string
+		server curatedNode
+		added  string
+	}{
+		{
+			name:   "Edited",
+			server: curatedNode{ID: 1, Name: "mine", Email: "op@example.org", Address: "a:50051", Network: "mainnet", IsActive: true},
+			added:  "[]",
+		},
+		{
+			name:   "Deregistered",
+			server: curatedNode{ID: 1, Name: "a", Address: "a:50051", Network: "mainnet", IsActive: false},
+			added:  "[]",
+		},
+		{
+			name:   "Moved",
+			server: curatedNode{ID: 1, Name: "a", Address: "moved:50051", Network: "mainnet", IsActive: true},
+			added:  "[a:50051]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			store := &memoryCuratedStore{nodes: []*curatedNode{&server}}
+			reconciler := &nodeReconciler{
+				nodeType: models.NodeTypeGRPC,
+				source: &staticNodeSource{lists: map[string][]*SourceNode{
+					"mainnet": {{Name: "a", Address: "a:50051"}},
+					"testnet": nil,
+				}},
+				store:     store,
+				addresses: func(*models.Network) []string { return nil },
+				name:      func(address string) string { return address },
+				logger:    logger,
+			}
+
+			run, err := reconciler.Sync(context.Background(), false)
+			if err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+
+			if server != tt.server {
+				t.Errorf("Expected the server to be left alone, got %+v", server)
+			}
+			if len(run.Updated) != 0 || len(run.Reactivated) != 0 || len(run.Deactivated) != 0 {
+				t.Errorf("Expected no changes, got %s updated, %s reactivated and %s deactivated",
+					changeAddresses(run.Updated), changeAddresses(run.Reactivated), changeAddresses(run.Deactivated))
+			}
+			if got := changeAddresses(run.Added); got != tt.added {
+				t.Errorf("Expected %s added, got %s", tt.added, got)
+			}
+		})
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	listed := &SourceNode{Name: "a", Email: "ops@a.example.org", Website: "https://a.example.org"}
+
+	if fields := changedFields("a", "ops@a.example.org", "https://a.example.org", listed); len(fields) != 0 {
+		t.Errorf("Expected no changes, got %v", fields)
+	}
+	if fields := changedFields("old", "ops@a.example.org", "", listed); fmt.Sprint(fields) != "[name website]" {
+		t.Errorf("Expected name and website to change, got %v", fields)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/mail"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// registrationMail is the data the registration templates are rendered
+// with. Custom templates may use any of its fields.
+type registrationMail struct {
+	NodeType   string
+	Name       string
+	Address    string
+	Network    string
+	ConfirmURL string
+	ExpiresIn  string
+	Reason     string
+}
+
+// RegistrationNotifier emails registrants when their registration is
+// reviewed
+type RegistrationNotifier struct {
+	registrationRepo repositories.RegistrationRepository
+	mailer           *mail.Mailer
+	logger           *logrus.Logger
+}
+
+// NewRegistrationNotifier creates a new registration notifier
+func NewRegistrationNotifier(registrationRepo repositories.RegistrationRepository, mailer *mail.Mailer, logger *logrus.Logger) *RegistrationNotifier {
+	return &RegistrationNotifier{
+		registrationRepo: registrationRepo,
+		mailer:           mailer,
+		logger:           logger,
+	}
+}
+
+// Register subscribes the notifier to review events. Mail is sent off the
+// publishing path so that a slow relay does not hold up reviews.
+func (n *RegistrationNotifier) Register(bus *events.Bus) {
+	events.On(bus, events.RegistrationApproved, "registration-approved-mail", events.Async, n.NotifyReviewed)
+	events.On(bus, events.RegistrationRejected, "registration-rejected-mail", events.Async, n.NotifyReviewed)
+}
+
+// NotifyReviewed emails the outcome of a review to the registrant. The
+// registration is reloaded because events do not carry contact details or
+// the rejection reason.
+func (n *RegistrationNotifier) NotifyReviewed(ctx context.Context, event events.Event, data events.Registration) error {
+	registration, err := n.registrationRepo.GetByID(ctx, data.ID)
+	if err != nil {
+		return err
+	}
+	if registration == nil {
+		return fmt.Errorf("registration %d not found", data.ID)
+	}
+
+	template := mail.TemplateRegistrationApproved
+	if event.Type == events.RegistrationRejected {
+		template = mail.TemplateRegistrationRejected
+	}
+
+	err = n.mailer.Send(ctx, registration.Email, template, registrationMail{
+		NodeType: registration.NodeType,
+		Name:     registration.Name,
+		Address:  registration.Address,
+		Network:  registration.Network,
+		Reason:   registration.RejectionReason,
+	})
+	if err != nil {
+		return fmt.Errorf("notify registration %d: %w", registration.ID, err)
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"registration_id": registration.ID,
+		"status":          data.Status,
+	}).Info("Registrant notified of review")
+	return nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/mail"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// memoryRegistrationRepository keeps registrations in a map; other methods
+// are not used
+type memoryRegistrationRepository struct {
+	repositories.RegistrationRepository
+	registrations map[int]*models.NodeRegistration
+}
+
+func (r *memoryRegistrationRepository) GetByID(ctx context.Context, id int) (*models.NodeRegistration, error) {
+	return r.registrations[id], nil
+}
+
+func (r *memoryRegistrationRepository) Confirm(ctx context.Context, token string, issuedAfter time.Time) (int, error) {
+	for _, registration := range r.registrations {
+		if registration.ConfirmationToken == token && registration.Status == "unconfirmed" && registration.CreatedAt.After(issuedAfter) {
+			registration.Status = "pending"
+			registration.ConfirmationToken = ""
+			return registration.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r *memoryRegistrationRepository) UpdateStatus(ctx context.Context, id int, status, reason, reviewedBy string, reviewedAt *time.Time) error {
+	r.registrations[id].Status = status
+	return nil
+}
+
+func (r *memoryRegistrationRepository) Approve(ctx context.Context, id int, reviewedBy string, reviewedAt time.Time) (bool, error) {
+	if r.registrations[id].Status != "pending" {
+		return false, nil
+	}
+	r.registrations[id].Status = "approved"
+	return true, nil
+}
+
+// createGRPCRepository records created servers, failing when err is set
+type createGRPCRepository struct {
+	repositories.GRPCRepository
+	created []*models.GRPCServer
+	err     error
+}
+
+func (r *createGRPCRepository) CreateServer(ctx context.Context, server *models.GRPCServer) error {
+	if r.err != nil {
+		return r.err
+	}
+	server.ID = len(r.created) + 1
+	r.created = append(r.created, server)
+	return nil
+}
+
+func newTestMailer(t *testing.T) (*mail.Mailer, *mail.MemoryTransport) {
+	templates, err := mail.LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := mail.NewMemoryTransport()
+	return mail.NewMailer(transport, templates, "no-reply@example.org", mail.Limits{}), transport
+}
+
+func TestRegistrationNotifier_NotifyReviewed(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	repo := &memoryRegistrationRepository{registrations: map[int]*models.NodeRegistration{
+		1: {ID: 1, NodeType: models.NodeTypeGRPC, Name: "node-1", Email: "a@example.org", Status: "approved"},
+		2: {ID: 2, NodeType: models.NodeTypeJSONRPC, Name: "node-2", Email: "b@example.org", Status: "rejected", RejectionReason: "endpoint is a proxy"},
+	}}
+	mailer, transport := newTestMailer(t)
+	notifier := NewRegistrationNotifier(repo, mailer, logger)
+
+	ctx := context.Background()
+	if err := notifier.NotifyReviewed(ctx, events.Event{Type: events.RegistrationApproved}, events.Registration{ID: 1}); err != nil {
+		t.Fatalf("NotifyReviewed approved: %v", err)
+	}
+	if err := notifier.NotifyReviewed(ctx, events.Event{Type: events.RegistrationRejected}, events.Registration{ID: 2}); err != nil {
+		t.Fatalf("NotifyReviewed rejected: %v", err)
+	}
+	if err := notifier.NotifyReviewed(ctx, events.Event{Type: events.RegistrationRejected}, events.Registration{ID: 3}); err == nil {
+		t.Error("Expected an error for an unknown registration")
+	}
+
+	messages := transport.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].To != "a@example.org" || !strings.Contains(messages[0].Subject, "approved") {
+		t.Errorf("Expected an approval mail to a@example.org, got %q to %s", messages[0].Subject, messages[0].To)
+	}
+	if messages[1].To != "b@example.org" || !strings.Contains(messages[1].Body, "endpoint is a proxy") {
+		t.Errorf("Expected the rejection reason in the mail to b@example.org, got %q", messages[1].Body)
+	}
+}
+
+func TestRegistrationService_ConfirmRegistration(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	repo := &memoryRegistrationRepository{registrations: map[int]*models.NodeRegistration{
+		1: {ID: 1, Status: "unconfirmed", ConfirmationToken: "fresh", CreatedAt: time.Now().Add(-time.Hour)},
+		2: {ID: 2, Status: "unconfirmed", ConfirmationToken: "stale", CreatedAt: time.Now().Add(-models.RegistrationConfirmationTTL - time.Hour)},
+	}}
+	bus := events.NewBus(logger)
+	defer bus.Close()
+
+	var submitted []int
+	events.On(bus, events.RegistrationSubmitted, "test", events.Sync, func(ctx context.Context, event events.Event, data events.Registration) error {
+		submitted = append(submitted, data.ID)
+		return nil
+	})
+
+	mailer, _ := newTestMailer(t)
+	service := NewRegistrationService(repo, nil, nil, nil, nil, nil, false, mailer, "https://tracker.example.org/", bus, logger)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"Fresh link", "fresh", false},
+		{"Link used twice", "fresh", true},
+		{"Expired link", "stale", true},
+		{"Unknown link", "other", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registration, err := service.ConfirmRegistration(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && registration.Status != "pending" {
+				t.Errorf("Expected status pending, got %s", registration.Status)
+			}
+		})
+	}
+
+	if len(submitted) != 1 || submitted[0] != 1 {
+		t.Errorf("Expected registration 1 to be submitted for review once, got %v", submitted)
+	}
+}
+
+func TestRegistrationService_ApproveRegistration(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	repo := &memoryRegistrationRepository{registrations: map[int]*models.NodeRegistration{
+		1: {ID: 1, NodeType: models.NodeTypeGRPC, Address: "node1.example.org:50051", Status: "pending"},
+		2: {ID: 2, NodeType: models.NodeTypeGRPC, Address: "node2.example.org:50051", Status: "pending"},
+	}}
+	grpcRepo := &createGRPCRepository{}
+	bus := events.NewBus(logger)
+	defer bus.Close()
+
+	mailer, _ := newTestMailer(t)
+	service := NewRegistrationService(repo, grpcRepo, nil, nil, nil, nil, false, mailer, "https://tracker.example.org/", bus, logger)
+	ctx := context.Background()
+
+	if err := service.ApproveRegistration(ctx, 1, "admin"); err != nil {
+		t.Fatalf("ApproveRegistration: %v", err)
+	}
+	assertErrorCode(t, service.ApproveRegistration(ctx, 1, "admin"), models.ErrCodeConflict)
+	if len(grpcRepo.created) != 1 {
+		t.Errorf("Expected the node to be added once, got %d", len(grpcRepo.created))
+	}
+
+	grpcRepo.err = errors.New("connection reset")
+	if err := service.ApproveRegistration(ctx, 2, "admin"); err == nil {
+		t.Fatal("Expected the failed insert to be returned")
+	}
+	if status := repo.registrations[2].Status; status != "pending" {
+		t.Errorf("Expected a registration whose node was not added to stay pending, got %s", status)
+	}
+}
@@ -2,9 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/mail"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
 	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
 	"github.com/sirupsen/logrus"
@@ -17,7 +22,11 @@ type RegistrationService struct {
 	jsonrpcRepo      repositories.JSONRPCServerRepository
 	grpcChecker      *GRPCChecker
 	jsonrpcMonitor   *JSONRPCMonitorService
-	geoService       *GeoLocationService
+	verifier         *OwnershipVerifier
+	autoApprove      bool
+	mailer           *mail.Mailer
+	publicURL        string
+	eventBus         *events.Bus
 	logger           *logrus.Logger
 }
 
@@ -28,7 +37,11 @@ func NewRegistrationService(
 	jsonrpcRepo repositories.JSONRPCServerRepository,
 	grpcChecker *GRPCChecker,
 	jsonrpcMonitor *JSONRPCMonitorService,
-	geoService *GeoLocationService,
+	verifier *OwnershipVerifier,
+	autoApproveVerified bool,
+	mailer *mail.Mailer,
+	publicURL string,
+	eventBus *events.Bus,
 	logger *logrus.Logger,
 ) *RegistrationService {
 	return &RegistrationService{
@@ -37,12 +50,18 @@ func NewRegistrationService(
 		jsonrpcRepo:      jsonrpcRepo,
 		grpcChecker:      grpcChecker,
 		jsonrpcMonitor:   jsonrpcMonitor,
-		geoService:       geoService,
+		verifier:         verifier,
+		autoApprove:      autoApproveVerified,
+		mailer:           mailer,
+		publicURL:        strings.TrimRight(publicURL, "/"),
+		eventBus:         eventBus,
 		logger:           logger,
 	}
 }
 
-// SubmitRegistration handles new node registration submission
+// SubmitRegistration handles new node registration submission. With a
+// mailer configured the registration stays unconfirmed until the link
+// emailed to the submitter is opened; otherwise it goes straight to review.
 func (s *RegistrationService) SubmitRegistration(ctx context.Context, req *models.RegistrationRequest) (*models.RegistrationResponse, error) {
 	// Validate the node is reachable
 	isReachable, err := s.validateNode(ctx, req.NodeType, req.Address)
@@ -50,7 +69,7 @@ func (s *RegistrationService) SubmitRegistration(ctx context.Context, req *model
 		return nil, fmt.Errorf("failed to validate node: %w", err)
 	}
 	if !isReachable {
-		return nil, fmt.Errorf("node at %s is not reachable", req.Address)
+		return nil, models.NewNodeNotReachableError(req.Address, nil)
 	}
 
 	// Check for duplicates in existing servers
@@ -59,7 +78,7 @@ func (s *RegistrationService) SubmitRegistration(ctx context.Context, req *model
 		return nil, err
 	}
 	if exists {
-		return nil, fmt.Errorf("a node with address %s is already registered", req.Address)
+		return nil, models.NewConflictError(fmt.Sprintf("a node with address %s is already registered", req.Address))
 	}
 
 	// Check for pending registration
@@ -68,18 +87,32 @@ func (s *RegistrationService) SubmitRegistration(ctx context.Context, req *model
 		return nil, err
 	}
 	if pendingExists {
-		return nil, fmt.Errorf("a registration for address %s is already pending", req.Address)
+		return nil, models.NewConflictError(fmt.Sprintf("a registration for address %s is already pending", req.Address))
+	}
+
+	token, err := NewVerificationToken()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create registration
 	registration := &models.NodeRegistration{
-		NodeType: req.NodeType,
-		Name:     req.Name,
-		Address:  req.Address,
-		Network:  req.Network,
-		Email:    req.Email,
-		Website:  req.Website,
-		Status:   "pending",
+		NodeType:          req.NodeType,
+		Name:              req.Name,
+		Address:           req.Address,
+		Network:           req.Network,
+		Email:             req.Email,
+		Website:           req.Website,
+		Status:            "pending",
+		VerificationToken: token,
+	}
+	if s.mailer != nil {
+		confirmationToken, err := NewVerificationToken()
+		if err != nil {
+			return nil, err
+		}
+		registration.Status = "unconfirmed"
+		registration.ConfirmationToken = confirmationToken
 	}
 
 	if err := s.registrationRepo.Create(ctx, registration); err != nil {
@@ -90,13 +123,80 @@ func (s *RegistrationService) SubmitRegistration(ctx context.Context, req *model
 		"type":    req.NodeType,
 		"address": req.Address,
 		"email":   req.Email,
+		"status":  registration.Status,
 	}).Info("New node registration submitted")
 
-	return &models.RegistrationResponse{
-		ID:      registration.ID,
-		Status:  "pending",
-		Message: "Your node registration has been submitted and is pending review.",
-	}, nil
+	response := &models.RegistrationResponse{
+		ID:           registration.ID,
+		Status:       registration.Status,
+		Message:      "Your node registration has been submitted and is pending review. Prove ownership of the endpoint to speed up approval.",
+		Verification: s.verifier.Challenge(registration.NodeType, registration.Address, token),
+	}
+
+	if registration.Status == "unconfirmed" {
+		if err := s.sendConfirmation(ctx, registration); err != nil {
+			return nil, err
+		}
+		response.Message = "Check your email and open the confirmation link to send your registration for review. Prove ownership of the endpoint to speed up approval."
+		return response, nil
+	}
+
+	s.publish(ctx, events.RegistrationSubmitted, registration, 0, "pending")
+	return response, nil
+}
+
+// sendConfirmation emails the confirmation link. A registration whose
+// link could not be sent is removed so that the address can be submitted
+// again.
+func (s *RegistrationService) sendConfirmation(ctx context.Context, registration *models.NodeRegistration) error {
+	err := s.mailer.Send(ctx, registration.Email, mail.TemplateRegistrationConfirm, registrationMail{
+		NodeType:   registration.NodeType,
+		Name:       registration.Name,
+		Address:    registration.Address,
+		Network:    registration.Network,
+		ConfirmURL: s.publicURL + "/api/v1/registrations/confirm?token=" + url.QueryEscape(registration.ConfirmationToken),
+		ExpiresIn:  fmt.Sprintf("%d hours", int(models.RegistrationConfirmationTTL.Hours())),
+	})
+	if err == nil {
+		return nil
+	}
+
+	if deleteErr := s.registrationRepo.Delete(ctx, registration.ID); deleteErr != nil {
+		s.logger.WithError(deleteErr).WithField("registration_id", registration.ID).Error("Failed to remove unconfirmed registration")
+	}
+	if errors.Is(err, mail.ErrRateLimited) {
+		return models.NewRateLimitError("too many confirmation emails, try again later")
+	}
+	s.logger.WithError(err).WithField("registration_id", registration.ID).Error("Failed to send confirmation email")
+	return models.NewServiceUnavailableError("confirmation email could not be sent")
+}
+
+// ConfirmRegistration sends the registration holding token to review
+func (s *RegistrationService) ConfirmRegistration(ctx context.Context, token string) (*models.NodeRegistration, error) {
+	issuedAfter := time.Now().Add(-models.RegistrationConfirmationTTL)
+	id, err := s.registrationRepo.Confirm(ctx, token, issuedAfter)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, models.NewNotFoundError("confirmation link is invalid or has expired")
+	}
+
+	registration, err := s.registrationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registration == nil {
+		return nil, models.NewNotFoundError(fmt.Sprintf("registration not found: %d", id))
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"registration_id": id,
+		"address":         registration.Address,
+	}).Info("Node registration confirmed")
+
+	s.publish(ctx, events.RegistrationSubmitted, registration, 0, "pending")
+	return registration, nil
 }
 
 // validateNode checks if the node is reachable
@@ -109,7 +209,7 @@ func (s *RegistrationService) validateNode(ctx context.Context, nodeType, addres
 		result := s.jsonrpcMonitor.ValidateJSONRPCEndpoint(ctx, address)
 		return result.Success, nil
 	default:
-		return false, fmt.Errorf("unknown node type: %s", nodeType)
+		return false, models.NewValidationError("unknown node type", nodeType)
 	}
 }
 
@@ -131,65 +231,125 @@ func (s *RegistrationService) ApproveRegistration(ctx context.Context, id int, r
 		return err
 	}
 	if registration == nil {
-		return fmt.Errorf("registration not found: %d", id)
+		return models.NewNotFoundError(fmt.Sprintf("registration not found: %d", id))
 	}
 
-	if registration.Status != "pending" {
-		return fmt.Errorf("registration is not pending")
+	// Claim the registration before adding the node, so concurrent
+	// reviews cannot add it twice
+	approved, err := s.registrationRepo.Approve(ctx, id, reviewedBy, time.Now())
+	if err != nil {
+		return err
 	}
-
-	// Resolve geo location
-	ip := s.geoService.ExtractIPFromAddress(registration.Address)
-	var geo *models.GeoLocation
-	if ip != "" {
-		geo, _ = s.geoService.GetLocation(ctx, ip)
+	if !approved {
+		return models.NewConflictError("registration is not pending")
 	}
 
-	// Add to appropriate server table
+	// Add to appropriate server table. Geo location is resolved by the
+	// NodeAdded handlers.
+	var nodeID int
 	switch registration.NodeType {
 	case "grpc":
 		server := &models.GRPCServer{
-			Name:     registration.Name,
-			Address:  registration.Address,
-			Network:  registration.Network,
-			Email:    registration.Email,
-			Website:  registration.Website,
-			IsActive: true,
-		}
-		if geo != nil && geo.IsValid() {
-			server.Country = geo.Country
-			server.CountryCode = geo.CountryCode
-			server.City = geo.City
-			server.Latitude = geo.Latitude
-			server.Longitude = geo.Longitude
+			Name:       registration.Name,
+			Address:    registration.Address,
+			Network:    registration.Network,
+			Email:      registration.Email,
+			Website:    registration.Website,
+			IsActive:   true,
+			IsVerified: registration.IsVerified,
 		}
 		if err := s.grpcRepo.CreateServer(ctx, server); err != nil {
-			return err
+			return s.reopen(ctx, id, err)
 		}
+		nodeID = server.ID
 	case "jsonrpc":
 		server := &models.JSONRPCServer{
-			Name:     registration.Name,
-			Address:  registration.Address,
-			Network:  registration.Network,
-			Email:    registration.Email,
-			Website:  registration.Website,
-			IsActive: true,
-		}
-		if geo != nil && geo.IsValid() {
-			server.Country = geo.Country
-			server.CountryCode = geo.CountryCode
-			server.City = geo.City
-			server.Latitude = geo.Latitude
-			server.Longitude = geo.Longitude
+			Name:       registration.Name,
+			Address:    registration.Address,
+			Network:    registration.Network,
+			Email:      registration.Email,
+			Website:    registration.Website,
+			IsActive:   true,
+			IsVerified: registration.IsVerified,
 		}
 		if err := s.jsonrpcRepo.CreateServer(ctx, server); err != nil {
-			return err
+			return s.reopen(ctx, id, err)
 		}
+		nodeID = server.ID
 	}
 
-	// Update registration status
-	now := time.Now()
-	return s.registrationRepo.UpdateStatus(ctx, id, "approved", "", reviewedBy, &now)
+	if nodeID != 0 {
+		publishNode(ctx, s.eventBus, events.NodeAdded, registration.NodeType, nodeID, events.Node{
+			Name:    registration.Name,
+			Address: registration.Address,
+			Network: registration.Network,
+		})
+	}
+	s.publish(ctx, events.RegistrationApproved, registration, nodeID, "approved")
+	return nil
+}
+
+// reopen puts a registration whose node could not be added back up for
+// review and returns the original error
+func (s *RegistrationService) reopen(ctx context.Context, id int, cause error) error {
+	if err := s.registrationRepo.UpdateStatus(ctx, id, "pending", "", "", nil); err != nil {
+		s.logger.WithError(err).WithField("registration_id", id).Error("Failed to reopen registration")
+	}
+	return cause
+}
+
+// VerifyRegistration checks the ownership proof of a pending registration
+// and, when auto-approval is enabled, approves it once verified
+func (s *RegistrationService) VerifyRegistration(ctx context.Context, id int, method string, proof OwnershipProof) (*models.VerificationResult, error) {
+	registration, err := s.registrationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registration == nil {
+		return nil, models.NewNotFoundError(fmt.Sprintf("registration not found: %d", id))
+	}
+	if registration.Status != "pending" {
+		return nil, models.NewConflictError("registration is not pending")
+	}
+
+	if !registration.IsVerified {
+		if err := s.verifier.Verify(ctx, registration, method, proof); err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"registration_id": id,
+				"method":          method,
+			}).Info("Registration ownership verification failed")
+			return nil, models.NewValidationError("ownership verification failed", err.Error())
+		}
+
+		now := time.Now()
+		if err := s.registrationRepo.MarkVerified(ctx, id, method, now); err != nil {
+			return nil, err
+		}
+		registration.IsVerified = true
+		registration.VerificationMethod = method
+
+		s.logger.WithFields(logrus.Fields{
+			"registration_id": id,
+			"address":         registration.Address,
+			"method":          method,
+		}).Info("Registration ownership verified")
+	}
+
+	result := &models.VerificationResult{
+		ID:       id,
+		Verified: true,
+		Method:   registration.VerificationMethod,
+		Status:   registration.Status,
+	}
+
+	if s.autoApprove {
+		if err := s.ApproveRegistration(ctx, id, "ownership-verification"); err != nil {
+			return nil, err
+		}
+		result.Status = "approved"
+	}
+
+	return result, nil
 }
 
 // RejectRegistration rejects a pending registration
@@ -199,15 +359,38 @@ func (s *RegistrationService) RejectRegistration(ctx context.Context, id int, re
 		return err
 	}
 	if registration == nil {
-		return fmt.Errorf("registration not found: %d", id)
+		return models.NewNotFoundError(fmt.Sprintf("registration not found: %d", id))
 	}
 
 	if registration.Status != "pending" {
-		return fmt.Errorf("registration is not pending")
+		return models.NewConflictError("registration is not pending")
 	}
 
 	now := time.Now()
-	return s.registrationRepo.UpdateStatus(ctx, id, "rejected", reason, reviewedBy, &now)
+	if err := s.registrationRepo.UpdateStatus(ctx, id, "rejected", reason, reviewedBy, &now); err != nil {
+		return err
+	}
+
+	s.publish(ctx, events.RegistrationRejected, registration, 0, "rejected")
+	return nil
+}
+
+// publish announces a registration event, carrying the id of the node an
+// approval created. Contact details are left out because events are
+// streamed to the public.
+func (s *RegistrationService) publish(ctx context.Context, eventType events.Type, registration *models.NodeRegistration, nodeID int, status string) {
+	s.eventBus.Publish(ctx, events.Event{
+		Type:     eventType,
+		NodeType: registration.NodeType,
+		NodeID:   nodeID,
+		Data: events.Registration{
+			ID:       registration.ID,
+			NodeType: registration.NodeType,
+			Name:     registration.Name,
+			Address:  registration.Address,
+			Status:   status,
+		},
+	})
 }
 
 // GetPendingRegistrations returns all pending registrations
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// RetentionService deletes monitoring data once it is older than its
+// retention period, and expired probe request nonces. A zero retention
+// keeps that data forever.
+type RetentionService struct {
+	probeRepo        repositories.ProbeRepository
+	latencyRepo      repositories.LatencyRepository
+	probeRetention   time.Duration
+	attemptRetention time.Duration
+	logger           *logrus.Logger
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(
+	probeRepo repositories.ProbeRepository,
+	latencyRepo repositories.LatencyRepository,
+	probeRetention time.Duration,
+	attemptRetention time.Duration,
+	logger *logrus.Logger,
+) *RetentionService {
+	return &RetentionService{
+		probeRepo:        probeRepo,
+		latencyRepo:      latencyRepo,
+		probeRetention:   probeRetention,
+		attemptRetention: attemptRetention,
+		logger:           logger,
+	}
+}
+
+// Cleanup deletes the data that has aged out of its retention period
+func (s *RetentionService) Cleanup(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	if s.probeRepo != nil && s.probeRetention > 0 {
+		// Probe results are kept by day
+		before := now.Add(-s.probeRetention).Truncate(24 * time.Hour)
+		if err := s.probeRepo.DeleteOldResults(ctx, before); err != nil {
+			return err
+		}
+		s.logger.WithField("before", before).Info("Deleted old probe results")
+	}
+
+	if s.probeRepo != nil {
+		if err := s.probeRepo.DeleteExpiredNonces(ctx); err != nil {
+			return err
+		}
+	}
+
+	if s.latencyRepo != nil && s.attemptRetention > 0 {
+		// Daily latency rollups outlive the attempts they are computed from
+		before := now.Add(-s.attemptRetention)
+		if err := s.latencyRepo.DeleteOldAttempts(ctx, before); err != nil {
+			return err
+		}
+		s.logger.WithField("before", before).Info("Deleted old check attempts")
+	}
+
+	return nil
+}
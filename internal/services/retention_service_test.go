@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// cutoffLatencyRepository records the cutoff of attempt cleanups
+type cutoffLatencyRepository struct {
+	repositories.LatencyRepository
+	before time.Time
+}
+
+func (r *cutoffLatencyRepository) DeleteOldAttempts(ctx context.Context, before time.Time) error {
+	r.before = before
+	return nil
+}
+
+func TestRetentionService_Cleanup(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	latencyRepo := &cutoffLatencyRepository{}
+	svc := NewRetentionService(&memoryProbeRepository{}, latencyRepo, 0, 48*time.Hour, logger)
+
+	if err := svc.Cleanup(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := time.Now().Add(-48 * time.Hour)
+	if diff := latencyRepo.before.Sub(expected); diff < -time.Minute || diff > time.Minute {
+		t.Errorf("Expected attempts before %v to be deleted, got %v", expected, latencyRepo.before)
+	}
+
+	// A zero retention keeps the data
+	latencyRepo.before = time.Time{}
+	svc = NewRetentionService(nil, latencyRepo, 0, 0, logger)
+	if err := svc.Cleanup(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !latencyRepo.before.IsZero() {
+		t.Error("Expected no attempts to be deleted without a retention")
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+)
+
+// CertificateExpiryWarning is how close to expiry a certificate gets flagged
+const CertificateExpiryWarning = 14 * 24 * time.Hour
+
+// InspectTLS performs a TLS handshake with address (host:port) and describes
+// the certificate it presents. Verification is done separately from the
+// handshake so that untrusted certificates are still recorded. An error
+// means the endpoint did not complete a TLS handshake.
+func InspectTLS(ctx context.Context, address string, timeout time.Duration) (*models.CertificateInfo, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, &AddressError{Address: address, Reason: err.Error()}
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config: &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2", "http/1.1"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("tls handshake: %w", err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("tls handshake: no peer certificate")
+	}
+
+	cert := describeCertificate(state.PeerCertificates, host, time.Now())
+	cert.Address = address
+	return cert, nil
+}
+
+// describeCertificate builds certificate info for the leaf of a peer chain
+func describeCertificate(chain []*x509.Certificate, host string, now time.Time) *models.CertificateInfo {
+	leaf := chain[0]
+	fingerprint := sha256.Sum256(leaf.Raw)
+
+	info := &models.CertificateInfo{
+		Subject:           leaf.Subject.String(),
+		Issuer:            leaf.Issuer.String(),
+		SANs:              certificateSANs(leaf),
+		SerialNumber:      leaf.SerialNumber.String(),
+		FingerprintSHA256: hex.EncodeToString(fingerprint[:]),
+		NotBefore:         leaf.NotBefore.UTC(),
+		NotAfter:          leaf.NotAfter.UTC(),
+		DaysRemaining:     int(leaf.NotAfter.Sub(now).Hours() / 24),
+		IsSelfSigned:      isSelfSigned(leaf),
+		IsExpired:         now.After(leaf.NotAfter) || now.Before(leaf.NotBefore),
+		CheckedAt:         now.UTC(),
+	}
+	info.ExpiresSoon = !info.IsExpired && leaf.NotAfter.Sub(now) < CertificateExpiryWarning
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	if err != nil {
+		info.VerifyError = err.Error()
+	} else {
+		info.IsTrusted = true
+	}
+
+	return info
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		return false
+	}
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
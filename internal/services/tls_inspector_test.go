@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func selfSignedCertificate(t *testing.T, notBefore, notAfter time.Time) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "node.example.org"},
+		DNSNames:     []string{"node.example.org"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func TestDescribeCertificate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		notBefore    time.Time
+		notAfter     time.Time
+		expectExpiry bool
+		expectSoon   bool
+	}{
+		{name: "Valid for months", notBefore: now.Add(-time.Hour), notAfter: now.AddDate(0, 3, 0)},
+		{name: "Expires within 14 days", notBefore: now.Add(-time.Hour), notAfter: now.AddDate(0, 0, 5), expectSoon: true},
+		{name: "Already expired", notBefore: now.AddDate(0, -3, 0), notAfter: now.AddDate(0, 0, -1), expectExpiry: true},
+		{name: "Not yet valid", notBefore: now.Add(time.Hour), notAfter: now.AddDate(0, 3, 0), expectExpiry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := selfSignedCertificate(t, tt.notBefore, tt.notAfter)
+			info := describeCertificate([]*x509.Certificate{cert}, "node.example.org", now)
+
+			if info.IsExpired != tt.expectExpiry {
+				t.Errorf("Expected expired %v, got %v", tt.expectExpiry, info.IsExpired)
+			}
+			if info.ExpiresSoon != tt.expectSoon {
+				t.Errorf("Expected expires soon %v, got %v", tt.expectSoon, info.ExpiresSoon)
+			}
+			if !info.IsSelfSigned {
+				t.Error("Expected certificate to be flagged as self-signed")
+			}
+			if info.IsTrusted || info.VerifyError == "" {
+				t.Error("Expected self-signed certificate to be untrusted")
+			}
+			if !info.HasProblem() {
+				t.Error("Expected certificate to have a problem")
+			}
+			if len(info.SANs) != 2 || info.SANs[0] != "node.example.org" || info.SANs[1] != "127.0.0.1" {
+				t.Errorf("Unexpected SANs: %v", info.SANs)
+			}
+		})
+	}
+}
+
+func TestInspectTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	t.Run("TLS endpoint", func(t *testing.T) {
+		info, err := InspectTLS(context.Background(), address, 2*time.Second)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.Address != address || info.FingerprintSHA256 == "" || info.NotAfter.IsZero() {
+			t.Errorf("Incomplete certificate info: %+v", info)
+		}
+		if info.IsTrusted {
+			t.Error("Expected test certificate to be untrusted")
+		}
+	})
+
+	t.Run("Plaintext endpoint", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer plain.Close()
+
+		if _, err := InspectTLS(context.Background(), strings.TrimPrefix(plain.URL, "http://"), 2*time.Second); err == nil {
+			t.Error("Expected plaintext endpoint to fail the TLS handshake")
+		}
+	})
+}
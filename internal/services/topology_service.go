@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/events"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// DefaultTopologyWindowDays is how recently a connection must have been
+// seen to be part of the topology
+const DefaultTopologyWindowDays = 7
+
+// MaxTopologyWindowDays caps how far back getTopology looks
+const MaxTopologyWindowDays = 90
+
+// TopologyService records the peer connections found by the crawler and
+// analyses the resulting network graph
+type TopologyService struct {
+	topologyRepo   repositories.TopologyRepository
+	peerRepo       repositories.PeerRepository
+	bootstrapRepo  repositories.BootstrapRepository
+	networkService *NetworkService
+	eventBus       *events.Bus
+	logger         *logrus.Logger
+}
+
+// NewTopologyService creates a new topology service
+func NewTopologyService(
+	topologyRepo repositories.TopologyRepository,
+	peerRepo repositories.PeerRepository,
+	bootstrapRepo repositories.BootstrapRepository,
+	networkService *NetworkService,
+	eventBus *events.Bus,
+	logger *logrus.Logger,
+) *TopologyService {
+	return &TopologyService{
+		topologyRepo:   topologyRepo,
+		peerRepo:       peerRepo,
+		bootstrapRepo:  bootstrapRepo,
+		networkService: networkService,
+		eventBus:       eventBus,
+		logger:         logger,
+	}
+}
+
+// RecordCrawl stores the peers a node reported as connected and an edge
+// from the node to each of them. Peers seen for the first time are
+// published as added so their location is resolved. A nil source only
+// records the peers.
+func (s *TopologyService) RecordCrawl(ctx context.Context, source *models.ReachablePeer, peers []*models.ReachablePeer) error {
+	targetIDs := make([]int, 0, len(peers))
+	for _, peer := range peers {
+		if err := s.recordPeer(ctx, peer); err != nil {
+			return err
+		}
+		targetIDs = append(targetIDs, peer.ID)
+	}
+
+	if source == nil || source.PeerID == "" {
+		return nil
+	}
+	if err := s.recordPeer(ctx, source); err != nil {
+		return err
+	}
+	if err := s.topologyRepo.RecordEdges(ctx, source.ID, targetIDs, source.LastSeen); err != nil {
+		return fmt.Errorf("failed to record peer edges: %w", err)
+	}
+
+	return nil
+}
+
+// recordPeer stores a crawled peer and sets its ID
+func (s *TopologyService) recordPeer(ctx context.Context, peer *models.ReachablePeer) error {
+	created, err := s.peerRepo.RecordSeenPeer(ctx, peer)
+	if err != nil {
+		return fmt.Errorf("failed to record peer: %w", err)
+	}
+
+	if created && peer.Address != "" {
+		publishNode(ctx, s.eventBus, events.NodeAdded, models.NodeTypePeer, peer.ID, events.Node{
+			Name:    peer.PeerID,
+			Address: peer.Address,
+			Network: peer.Network,
+		})
+	}
+	return nil
+}
+
+// GetTopology returns the graph of the connections of a network seen in the
+// last days. A country code or ASN limits the graph to the peers located
+// there. Bootstrap betweenness is read from the values RefreshCentrality
+// stored, since computing it takes time quadratic in the peers.
+func (s *TopologyService) GetTopology(ctx context.Context, network, countryCode, asn string, days int) (*models.Topology, error) {
+	if days <= 0 {
+		days = DefaultTopologyWindowDays
+	}
+	if days > MaxTopologyWindowDays {
+		days = MaxTopologyWindowDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	nodes, edges, bootstrap, err := s.loadGraph(ctx, network, since)
+	if err != nil {
+		return nil, err
+	}
+	betweenness, computedAt, err := s.topologyRepo.GetCentrality(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+
+	match := func(n *models.TopologyNode) bool {
+		if countryCode != "" && !strings.EqualFold(n.CountryCode, countryCode) {
+			return false
+		}
+		return asn == "" || matchASN(n.ASN, asn)
+	}
+
+	topology, _ := buildTopology(nodes, edges, bootstrap, match)
+	for _, b := range topology.Bootstrap {
+		b.Betweenness = betweenness[b.BootstrapID]
+	}
+	if !computedAt.IsZero() {
+		topology.CentralityComputedAt = &computedAt
+	}
+	topology.Network = network
+	topology.Since = since
+	return topology, nil
+}
+
+// RefreshCentrality computes the betweenness of the bootstrap nodes of
+// every active network over the default window and stores it
+func (s *TopologyService) RefreshCentrality(ctx context.Context) error {
+	networks, err := activeNetworks(ctx, s.networkService)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().AddDate(0, 0, -DefaultTopologyWindowDays)
+	for _, network := range networks {
+		nodes, edges, bootstrap, err := s.loadGraph(ctx, network.Name, since)
+		if err != nil {
+			return err
+		}
+
+		all := func(*models.TopologyNode) bool { return true }
+		topology, adjacency := buildTopology(nodes, edges, bootstrap, all)
+		betweenness := bootstrapBetweenness(topology.Nodes, adjacency)
+
+		if err := s.topologyRepo.ReplaceCentrality(ctx, network.Name, betweenness, time.Now().UTC()); err != nil {
+			return err
+		}
+		s.logger.WithFields(logrus.Fields{
+			"network":   network.Name,
+			"peers":     len(topology.Nodes),
+			"bootstrap": len(betweenness),
+		}).Info("Computed bootstrap centrality")
+	}
+	return nil
+}
+
+// loadGraph loads the peers and edges of a network seen since the given
+// time and its bootstrap nodes
+func (s *TopologyService) loadGraph(ctx context.Context, network string, since time.Time) ([]*models.TopologyNode, []*models.TopologyEdge, []*models.BootstrapNode, error) {
+	nodes, err := s.topologyRepo.GetNodes(ctx, network, since)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	edges, err := s.topologyRepo.GetEdges(ctx, network, since)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bootstrap, err := s.bootstrapRepo.GetNodesByNetwork(ctx, network)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get bootstrap nodes: %w", err)
+	}
+	return nodes, edges, bootstrap, nil
+}
+
+// buildTopology builds the undirected graph of the matching nodes and the
+// edges between them, then computes degrees, connected components and where
+// the bootstrap nodes sit. It also returns the adjacency lists of the graph.
+func buildTopology(nodes []*models.TopologyNode, edges []*models.TopologyEdge, bootstrap []*models.BootstrapNode, match func(*models.TopologyNode) bool) (*models.Topology, [][]int) {
+	topology := &models.Topology{
+		Nodes:      []*models.TopologyNode{},
+		Edges:      []*models.TopologyEdge{},
+		Components: []*models.TopologyComponent{},
+		Bootstrap:  []*models.BootstrapCentrality{},
+	}
+
+	bootstrapByPeer := make(map[string]*models.BootstrapNode, len(bootstrap))
+	for _, node := range bootstrap {
+		if peerID := multiaddrPeerID(node.Address); peerID != "" {
+			bootstrapByPeer[peerID] = node
+		}
+	}
+
+	// Index the matching nodes
+	index := make(map[int]int)
+	for _, node := range nodes {
+		if !match(node) {
+			continue
+		}
+		if b, ok := bootstrapByPeer[node.PeerID]; ok {
+			node.BootstrapID = b.ID
+		}
+		index[node.ID] = len(topology.Nodes)
+		topology.Nodes = append(topology.Nodes, node)
+	}
+
+	// Connections reported from both ends count once
+	neighbors := make([]map[int]bool, len(topology.Nodes))
+	for i := range neighbors {
+		neighbors[i] = make(map[int]bool)
+	}
+	for _, edge := range edges {
+		from, ok := index[edge.Source]
+		if !ok {
+			continue
+		}
+		to, ok := index[edge.Target]
+		if !ok || from == to {
+			continue
+		}
+		topology.Edges = append(topology.Edges, edge)
+		neighbors[from][to] = true
+		neighbors[to][from] = true
+	}
+
+	adjacency := make([][]int, len(topology.Nodes))
+	for i, set := range neighbors {
+		for j := range set {
+			adjacency[i] = append(adjacency[i], j)
+		}
+		sort.Ints(adjacency[i])
+		topology.Nodes[i].Degree = len(adjacency[i])
+	}
+
+	topology.Degree = degreeStats(adjacency)
+	topology.Components = assignComponents(topology.Nodes, adjacency)
+
+	for _, b := range bootstrap {
+		centrality := &models.BootstrapCentrality{
+			BootstrapID: b.ID,
+			Name:        b.Name,
+			PeerID:      multiaddrPeerID(b.Address),
+		}
+		if i, ok := findBootstrapNode(topology.Nodes, b.ID); ok {
+			centrality.InGraph = true
+			centrality.Degree = topology.Nodes[i].Degree
+			centrality.Component = topology.Nodes[i].Component
+		}
+		topology.Bootstrap = append(topology.Bootstrap, centrality)
+	}
+
+	return topology, adjacency
+}
+
+// bootstrapBetweenness returns the betweenness of the bootstrap nodes of a
+// graph, keyed by bootstrap node ID
+func bootstrapBetweenness(nodes []*models.TopologyNode, adjacency [][]int) map[int]float64 {
+	betweenness := betweennessCentrality(adjacency)
+
+	values := make(map[int]float64)
+	for i, node := range nodes {
+		if node.BootstrapID != 0 {
+			values[node.BootstrapID] = math.Round(betweenness[i]*10000) / 10000
+		}
+	}
+	return values
+}
+
+// findBootstrapNode returns the position of the node of a bootstrap node
+func findBootstrapNode(nodes []*models.TopologyNode, bootstrapID int) (int, bool) {
+	for i, node := range nodes {
+		if node.BootstrapID == bootstrapID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// degreeStats summarizes the degrees of an adjacency list
+func degreeStats(adjacency [][]int) models.DegreeStats {
+	stats := models.DegreeStats{}
+	if len(adjacency) == 0 {
+		return stats
+	}
+
+	degrees := make([]int, len(adjacency))
+	total := 0
+	for i, neighbors := range adjacency {
+		degrees[i] = len(neighbors)
+		total += degrees[i]
+		if degrees[i] == 0 {
+			stats.Isolated++
+		}
+	}
+	sort.Ints(degrees)
+
+	n := len(degrees)
+	stats.Min = degrees[0]
+	stats.Max = degrees[n-1]
+	stats.Mean = math.Round(float64(total)/float64(n)*100) / 100
+	if n%2 == 1 {
+		stats.Median = float64(degrees[n/2])
+	} else {
+		stats.Median = float64(degrees[n/2-1]+degrees[n/2]) / 2
+	}
+	return stats
+}
+
+// assignComponents finds the connected components of the graph, numbers
+// them from the largest down and sets the component of every node
+func assignComponents(nodes []*models.TopologyNode, adjacency [][]int) []*models.TopologyComponent {
+	var groups [][]int
+	seen := make([]bool, len(adjacency))
+	for start := range adjacency {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		group := []int{start}
+		for queue := []int{start}; len(queue) > 0; queue = queue[1:] {
+			for _, next := range adjacency[queue[0]] {
+				if !seen[next] {
+					seen[next] = true
+					group = append(group, next)
+					queue = append(queue, next)
+				}
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	// Largest first; equal sizes keep discovery order
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+
+	components := make([]*models.TopologyComponent, 0, len(groups))
+	for i, group := range groups {
+		component := &models.TopologyComponent{ID: i + 1, Size: len(group)}
+		for _, member := range group {
+			nodes[member].Component = component.ID
+			if nodes[member].BootstrapID != 0 {
+				component.BootstrapNodes++
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// betweennessCentrality computes the normalized betweenness of every node
+// of an unweighted undirected graph with Brandes' algorithm
+func betweennessCentrality(adjacency [][]int) []float64 {
+	n := len(adjacency)
+	centrality := make([]float64, n)
+
+	for s := 0; s < n; s++ {
+		var stack []int
+		predecessors := make([][]int, n)
+		paths := make([]float64, n)
+		distance := make([]int, n)
+		for i := range distance {
+			distance[i] = -1
+		}
+		paths[s] = 1
+		distance[s] = 0
+
+		for queue := []int{s}; len(queue) > 0; queue = queue[1:] {
+			v := queue[0]
+			stack = append(stack, v)
+			for _, w := range adjacency[v] {
+				if distance[w] < 0 {
+					distance[w] = distance[v] + 1
+					queue = append(queue, w)
+				}
+				if distance[w] == distance[v]+1 {
+					paths[w] += paths[v]
+					predecessors[w] = append(predecessors[w], v)
+				}
+			}
+		}
+
+		dependency := make([]float64, n)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range predecessors[w] {
+				dependency[v] += paths[v] / paths[w] * (1 + dependency[w])
+			}
+			if w != s {
+				centrality[w] += dependency[w]
+			}
+		}
+	}
+
+	// Every pair was counted from both ends
+	if n > 2 {
+		scale := 1 / float64((n-1)*(n-2))
+		for i := range centrality {
+			centrality[i] *= scale
+		}
+	}
+	return centrality
+}
+
+// matchASN reports whether a stored ASN such as "AS13335 Cloudflare, Inc."
+// is the requested one, given as "AS13335" or "13335"
+func matchASN(stored, asn string) bool {
+	want := strings.ToUpper(strings.TrimSpace(asn))
+	if !strings.HasPrefix(want, "AS") {
+		want = "AS" + want
+	}
+	fields := strings.Fields(strings.ToUpper(stored))
+	return len(fields) > 0 && fields[0] == want
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+func TestBetweennessCentrality(t *testing.T) {
+	// 0 - 1 - 2 - 3 with 4 hanging off 1
+	adjacency := [][]int{{1}, {0, 2, 4}, {1, 3}, {2}, {1}}
+
+	got := betweennessCentrality(adjacency)
+	// Node 1 lies on the paths 0-2, 0-3, 0-4, 2-4 and 3-4, node 2 on 0-3,
+	// 1-3 and 3-4, out of 6 pairs of other nodes
+	want := []float64{0, 5.0 / 6, 3.0 / 6, 0, 0}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("Node %d: expected betweenness %.4f, got %.4f", i, want[i], got[i])
+		}
+	}
+}
+
+func TestBuildTopology(t *testing.T) {
+	nodes := []*models.TopologyNode{
+		{ID: 1, PeerID: "12D3KooWBoot", CountryCode: "DE", ASN: "AS24940 Hetzner Online GmbH"},
+		{ID: 2, PeerID: "12D3KooWA", CountryCode: "DE", ASN: "AS24940 Hetzner Online GmbH"},
+		{ID: 3, PeerID: "12D3KooWB", CountryCode: "US", ASN: "AS16509 Amazon.com, Inc."},
+		{ID: 4, PeerID: "12D3KooWC", CountryCode: "DE", ASN: "AS16509 Amazon.com, Inc."},
+		{ID: 5, PeerID: "12D3KooWD", CountryCode: "FR", ASN: "AS16276 OVH SAS"},
+		{ID: 6, PeerID: "12D3KooWE", CountryCode: "FR", ASN: "AS16276 OVH SAS"},
+	}
+	edges := []*models.TopologyEdge{
+		{Source: 1, Target: 2},
+		{Source: 2, Target: 1}, // Reported from both ends
+		{Source: 1, Target: 3},
+		{Source: 3, Target: 4},
+		{Source: 5, Target: 6},
+	}
+	bootstrap := []*models.BootstrapNode{
+		{ID: 10, Name: "boot-1", Address: "/ip4/1.2.3.4/tcp/21888/p2p/12D3KooWBoot"},
+		{ID: 11, Name: "boot-2", Address: "/ip4/5.6.7.8/tcp/21888/p2p/12D3KooWGone"},
+	}
+	all := func(*models.TopologyNode) bool { return true }
+
+	topology, adjacency := buildTopology(nodes, edges, bootstrap, all)
+
+	if len(topology.Nodes) != 6 || len(topology.Edges) != 5 {
+		t.Fatalf("Expected 6 nodes and 5 edges, got %d and %d", len(topology.Nodes), len(topology.Edges))
+	}
+	if topology.Nodes[0].BootstrapID != 10 || topology.Nodes[0].Degree != 2 {
+		t.Errorf("Unexpected bootstrap node: %+v", topology.Nodes[0])
+	}
+
+	if len(topology.Components) != 2 {
+		t.Fatalf("Expected 2 components, got %d", len(topology.Components))
+	}
+	if c := topology.Components[0]; c.ID != 1 || c.Size != 4 || c.BootstrapNodes != 1 {
+		t.Errorf("Unexpected main component: %+v", c)
+	}
+	if c := topology.Components[1]; c.Size != 2 || c.BootstrapNodes != 0 {
+		t.Errorf("Unexpected partition: %+v", c)
+	}
+	if topology.Nodes[4].Component != 2 {
+		t.Errorf("Expected the FR peers in component 2, got %d", topology.Nodes[4].Component)
+	}
+
+	wantDegree := models.DegreeStats{Min: 1, Max: 2, Mean: 1.33, Median: 1, Isolated: 0}
+	if topology.Degree != wantDegree {
+		t.Errorf("Expected degree stats %+v, got %+v", wantDegree, topology.Degree)
+	}
+
+	if len(topology.Bootstrap) != 2 {
+		t.Fatalf("Expected 2 bootstrap entries, got %d", len(topology.Bootstrap))
+	}
+	if b := topology.Bootstrap[0]; !b.InGraph || b.Degree != 2 || b.Component != 1 {
+		t.Errorf("Unexpected centrality of boot-1: %+v", b)
+	}
+	// boot-1 is on the paths 2-3 and 2-4 out of 10 pairs of other nodes
+	if betweenness := bootstrapBetweenness(topology.Nodes, adjacency); len(betweenness) != 1 || betweenness[10] != 0.2 {
+		t.Errorf("Expected a betweenness of 0.2 for boot-1 only, got %v", betweenness)
+	}
+	if b := topology.Bootstrap[1]; b.InGraph || b.PeerID != "12D3KooWGone" {
+		t.Errorf("Expected boot-2 outside the graph, got %+v", b)
+	}
+
+	// Only the German peers, which leaves 3 and its edges out
+	germany, _ := buildTopology(nodes, edges, bootstrap, func(n *models.TopologyNode) bool { return n.CountryCode == "DE" })
+	if len(germany.Nodes) != 3 || len(germany.Edges) != 2 || germany.Degree.Isolated != 1 {
+		t.Errorf("Unexpected filtered topology: %d nodes, %d edges, %+v", len(germany.Nodes), len(germany.Edges), germany.Degree)
+	}
+}
+
+func TestMatchASN(t *testing.T) {
+	tests := []struct {
+		stored string
+		asn    string
+		want   bool
+	}{
+		{"AS24940 Hetzner Online GmbH", "AS24940", true},
+		{"AS24940 Hetzner Online GmbH", "24940", true},
+		{"AS24940 Hetzner Online GmbH", "as24940", true},
+		{"AS249401 Someone Else", "AS24940", false},
+		{"", "AS24940", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchASN(tt.stored, tt.asn); got != tt.want {
+			t.Errorf("matchASN(%q, %q) = %v, want %v", tt.stored, tt.asn, got, tt.want)
+		}
+	}
+}
+
+// memoryPeerRepository assigns IDs to peers in the order they are first seen
+type memoryPeerRepository struct {
+	repositories.PeerRepository
+	ids map[string]int
+}
+
+func (r *memoryPeerRepository) RecordSeenPeer(ctx context.Context, peer *models.ReachablePeer) (bool, error) {
+	_, known := r.ids[peer.PeerID]
+	if !known {
+		r.ids[peer.PeerID] = len(r.ids) + 1
+	}
+	peer.ID = r.ids[peer.PeerID]
+	return !known, nil
+}
+
+// memoryTopologyRepository keeps recorded edges, a fixed graph and the
+// stored centrality in memory
+type memoryTopologyRepository struct {
+	repositories.TopologyRepository
+	edges      [][2]int
+	graphNodes []*models.TopologyNode
+	graphEdges []*models.TopologyEdge
+	centrality map[string]map[int]float64
+	computedAt time.Time
+}
+
+func (r *memoryTopologyRepository) GetNodes(ctx context.Context, network string, since time.Time) ([]*models.TopologyNode, error) {
+	nodes := make([]*models.TopologyNode, len(r.graphNodes))
+	for i, node := range r.graphNodes {
+		copied := *node
+		nodes[i] = &copied
+	}
+	return nodes, nil
+}
+
+func (r *memoryTopologyRepository) GetEdges(ctx context.Context, network string, since time.Time) ([]*models.TopologyEdge, error) {
+	return r.graphEdges, nil
+}
+
+func (r *memoryTopologyRepository) ReplaceCentrality(ctx context.Context, network string, betweenness map[int]float64, computedAt time.Time) error {
+	if r.centrality == nil {
+		r.centrality = make(map[string]map[int]float64)
+	}
+	r.centrality[network] = betweenness
+	r.computedAt = computedAt
+	return nil
+}
+
+func (r *memoryTopologyRepository) GetCentrality(ctx context.Context, network string) (map[int]float64, time.Time, error) {
+	if r.centrality[network] == nil {
+		return map[int]float64{}, time.Time{}, nil
+	}
+	return r.centrality[network], r.computedAt, nil
+}
+
+// networkBootstrapRepository lists fixed bootstrap nodes for every network
+type networkBootstrapRepository struct {
+	repositories.BootstrapRepository
+	nodes []*models.BootstrapNode
+}
+
+func (r *networkBootstrapRepository) GetNodesByNetwork(ctx context.Context, network string) ([]*models.BootstrapNode, error) {
+	return r.nodes, nil
+}
+
+func (r *memoryTopologyRepository) RecordEdges(ctx context.Context, sourceID int, targetIDs []int, seenAt time.Time) error {
+	for _, target := range targetIDs {
+		r.edges = append(r.edges, [2]int{sourceID, target})
+	}
+	return nil
+}
+
+func TestTopologyService_RecordCrawl(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	topologyRepo := &memoryTopologyRepository{}
+	peerRepo := &memoryPeerRepository{ids: map[string]int{"12D3KooWKnown": 1}}
+	service := NewTopologyService(topologyRepo, peerRepo, nil, nil, nil, logger)
+	ctx := context.Background()
+
+	seenAt := time.Now()
+	source := &models.ReachablePeer{PeerID: "12D3KooWSource", LastSeen: seenAt}
+	peers := []*models.ReachablePeer{
+		{PeerID: "12D3KooWKnown", LastSeen: seenAt},
+		{PeerID: "12D3KooWNew", LastSeen: seenAt},
+	}
+	if err := service.RecordCrawl(ctx, source, peers); err != nil {
+		t.Fatalf("RecordCrawl: %v", err)
+	}
+
+	if peers[0].ID != 1 || peers[1].ID != 2 || source.ID != 3 {
+		t.Errorf("Unexpected peer IDs: %d, %d, source %d", peers[0].ID, peers[1].ID, source.ID)
+	}
+	want := [][2]int{{3, 1}, {3, 2}}
+	if len(topologyRepo.edges) != len(want) || topologyRepo.edges[0] != want[0] || topologyRepo.edges[1] != want[1] {
+		t.Errorf("Expected edges %v, got %v", want, topologyRepo.edges)
+	}
+
+	// Without the node behind the server only the peers are stored
+	if err := service.RecordCrawl(ctx, nil, []*models.ReachablePeer{{PeerID: "12D3KooWOther"}}); err != nil {
+		t.Fatalf("RecordCrawl without source: %v", err)
+	}
+	if len(topologyRepo.edges) != 2 || peerRepo.ids["12D3KooWOther"] != 4 {
+		t.Errorf("Expected the peer without edges, got edges %v and ids %v", topologyRepo.edges, peerRepo.ids)
+	}
+}
+
+func TestTopologyService_ServesStoredCentrality(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// boot-1 (peer 2) sits between peers 1 and 3
+	topologyRepo := &memoryTopologyRepository{
+		graphNodes: []*models.TopologyNode{
+			{ID: 1, PeerID: "12D3KooWA"},
+			{ID: 2, PeerID: "12D3KooWBoot"},
+			{ID: 3, PeerID: "12D3KooWC"},
+		},
+		graphEdges: []*models.TopologyEdge{{Source: 1, Target: 2}, {Source: 2, Target: 3}},
+	}
+	bootstrapRepo := &networkBootstrapRepository{nodes: []*models.BootstrapNode{
+		{ID: 10, Name: "boot-1", Address: "/ip4/1.2.3.4/tcp/21888/p2p/12D3KooWBoot"},
+	}}
+	service := NewTopologyService(topologyRepo, nil, bootstrapRepo, nil, nil, logger)
+	ctx := context.Background()
+
+	// Nothing is computed on request before the job has run
+	topology, err := service.GetTopology(ctx, "mainnet", "", "", 0)
+	if err != nil {
+		t.Fatalf("GetTopology: %v", err)
+	}
+	if topology.Bootstrap[0].Betweenness != 0 || topology.CentralityComputedAt != nil {
+		t.Errorf("Expected no centrality before the first run, got %+v", topology.Bootstrap[0])
+	}
+
+	if err := service.RefreshCentrality(ctx); err != nil {
+		t.Fatalf("RefreshCentrality: %v", err)
+	}
+	if got := topologyRepo.centrality["testnet"][10]; got != 1 {
+		t.Errorf("Expected a stored testnet betweenness of 1, got %v", got)
+	}
+
+	topology, err = service.GetTopology(ctx, "mainnet", "", "", 0)
+	if err != nil {
+		t.Fatalf("GetTopology: %v", err)
+	}
+	if topology.Bootstrap[0].Betweenness != 1 || topology.CentralityComputedAt == nil {
+		t.Errorf("Expected the stored betweenness of 1, got %+v", topology.Bootstrap[0])
+	}
+}
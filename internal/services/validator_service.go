@@ -0,0 +1,374 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// MaxBlocksPerSync caps the blocks fetched by one committee sync. At ten
+// seconds per block it covers several hours, so a sync that fell behind
+// skips the oldest blocks rather than running past its job timeout.
+const MaxBlocksPerSync = 2000
+
+// DefaultValidatorPageSize is the page size of getValidators
+const DefaultValidatorPageSize = 100
+
+// MaxValidatorPageSize caps the page size of getValidators
+const MaxValidatorPageSize = 1000
+
+// DefaultValidatorUptimeDays is the window of getValidatorUptime by default
+const DefaultValidatorUptimeDays = 7
+
+// MaxValidatorUptimeDays caps the window of getValidatorUptime
+const MaxValidatorUptimeDays = 90
+
+// ValidatorService follows the validators, committee and block proposers
+// of a network through a trusted gRPC server of that network and serves
+// their history
+type ValidatorService struct {
+	validatorRepo repositories.ValidatorRepository
+	grpcChecker   *GRPCChecker
+	address       string
+	network       string
+	logger        *logrus.Logger
+}
+
+// NewValidatorService creates a new validator service that syncs the given
+// network. An empty address disables syncing; stored data is still served.
+func NewValidatorService(
+	validatorRepo repositories.ValidatorRepository,
+	grpcChecker *GRPCChecker,
+	address string,
+	network string,
+	logger *logrus.Logger,
+) *ValidatorService {
+	return &ValidatorService{
+		validatorRepo: validatorRepo,
+		grpcChecker:   grpcChecker,
+		address:       address,
+		network:       models.NetworkOrDefault(network),
+		logger:        logger,
+	}
+}
+
+// Enabled reports whether a trusted gRPC server is configured
+func (s *ValidatorService) Enabled() bool {
+	return s.address != ""
+}
+
+// SyncCommittee stores the current committee, the blocks proposed since
+// the last sync and the peers validators are announced by
+func (s *ValidatorService) SyncCommittee(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	conn, err := s.grpcChecker.Connect(ctx, s.address)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", s.address, err)
+	}
+	defer conn.Close()
+
+	return s.syncCommittee(ctx, pactus.NewBlockchainClient(conn), pactus.NewNetworkClient(conn))
+}
+
+// RefreshValidators stores the state of every validator
+func (s *ValidatorService) RefreshValidators(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	conn, err := s.grpcChecker.Connect(ctx, s.address)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", s.address, err)
+	}
+	defer conn.Close()
+
+	return s.refreshValidators(ctx, pactus.NewBlockchainClient(conn))
+}
+
+func (s *ValidatorService) syncCommittee(ctx context.Context, chain pactus.BlockchainClient, network pactus.NetworkClient) error {
+	now := time.Now()
+
+	info, err := chain.GetBlockchainInfo(ctx, &pactus.GetBlockchainInfoRequest{})
+	if err != nil {
+		return fmt.Errorf("get blockchain info: %w", err)
+	}
+
+	committee := committeeFromInfo(info, now)
+	if err := s.validatorRepo.UpsertValidators(ctx, s.network, committee.Members); err != nil {
+		return err
+	}
+	if err := s.validatorRepo.RecordCommittee(ctx, s.network, committee); err != nil {
+		return err
+	}
+
+	// Peer links are best effort; the committee and blocks stand without them
+	if err := s.linkPeers(ctx, chain, network, now); err != nil {
+		s.logger.WithError(err).Warn("Failed to link validators to peers")
+	}
+
+	return s.syncBlocks(ctx, chain, info.LastBlockHeight)
+}
+
+// syncBlocks stores the blocks after the last synced one up to tip. Blocks
+// fetched before a failure are still stored.
+func (s *ValidatorService) syncBlocks(ctx context.Context, chain pactus.BlockchainClient, tip uint32) error {
+	last, err := s.validatorRepo.GetLastBlockHeight(ctx, s.network)
+	if err != nil {
+		return err
+	}
+
+	from, to := blockRange(last, tip, MaxBlocksPerSync)
+	if from > last+1 && last > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"last_height": last,
+			"from_height": from,
+		}).Warn("Validator block sync fell behind, skipping blocks")
+	}
+
+	var blocks []*models.ValidatorBlock
+	var fetchErr error
+	for height := from; height <= to; height++ {
+		block, err := chain.GetBlock(ctx, &pactus.GetBlockRequest{
+			Height:    height,
+			Verbosity: pactus.BlockVerbosity_BLOCK_VERBOSITY_INFO,
+		})
+		if err != nil {
+			fetchErr = fmt.Errorf("get block %d: %w", height, err)
+			break
+		}
+		blocks = append(blocks, validatorBlock(block))
+	}
+
+	if len(blocks) > 0 {
+		if err := s.validatorRepo.RecordBlocks(ctx, s.network, blocks); err != nil {
+			return err
+		}
+	}
+	return fetchErr
+}
+
+// linkPeers records the peer each validator is announced by: the
+// connected peers' consensus keys and the trusted node's own validators
+func (s *ValidatorService) linkPeers(ctx context.Context, chain pactus.BlockchainClient, network pactus.NetworkClient, seenAt time.Time) error {
+	info, err := network.GetNetworkInfo(ctx, &pactus.GetNetworkInfoRequest{OnlyConnected: true})
+	if err != nil {
+		return fmt.Errorf("get network info: %w", err)
+	}
+	peers := consensusPeers(info)
+
+	node, err := network.GetNodeInfo(ctx, &pactus.GetNodeInfoRequest{})
+	if err != nil {
+		return fmt.Errorf("get node info: %w", err)
+	}
+	consensus, err := chain.GetConsensusInfo(ctx, &pactus.GetConsensusInfoRequest{})
+	if err != nil {
+		return fmt.Errorf("get consensus info: %w", err)
+	}
+	for _, instance := range consensus.Instances {
+		peers[instance.Address] = node.PeerId
+	}
+
+	if len(peers) == 0 {
+		return nil
+	}
+	return s.validatorRepo.RecordValidatorPeers(ctx, s.network, peers, seenAt)
+}
+
+func (s *ValidatorService) refreshValidators(ctx context.Context, chain pactus.BlockchainClient) error {
+	now := time.Now()
+
+	info, err := chain.GetBlockchainInfo(ctx, &pactus.GetBlockchainInfoRequest{})
+	if err != nil {
+		return fmt.Errorf("get blockchain info: %w", err)
+	}
+	inCommittee := make(map[string]bool, len(info.CommitteeValidators))
+	for _, v := range info.CommitteeValidators {
+		inCommittee[v.Address] = true
+	}
+
+	addresses, err := chain.GetValidatorAddresses(ctx, &pactus.GetValidatorAddressesRequest{})
+	if err != nil {
+		return fmt.Errorf("get validator addresses: %w", err)
+	}
+
+	validators := make([]*models.Validator, 0, len(addresses.Addresses))
+	for _, address := range addresses.Addresses {
+		resp, err := chain.GetValidator(ctx, &pactus.GetValidatorRequest{Address: address})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.WithError(err).WithField("validator", address).Warn("Failed to get validator")
+			continue
+		}
+		validators = append(validators, validatorFromInfo(resp.Validator, inCommittee[address], now))
+	}
+
+	s.logger.WithField("validators", len(validators)).Info("Refreshed validators")
+	return s.validatorRepo.UpsertValidators(ctx, s.network, validators)
+}
+
+// GetValidators returns a page of the validators of a network ordered by
+// stake
+func (s *ValidatorService) GetValidators(ctx context.Context, network string, committeeOnly bool, limit, offset int) (*models.ValidatorList, error) {
+	if limit <= 0 {
+		limit = DefaultValidatorPageSize
+	}
+	if limit > MaxValidatorPageSize {
+		limit = MaxValidatorPageSize
+	}
+
+	validators, total, err := s.validatorRepo.ListValidators(ctx, network, committeeOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if validators == nil {
+		validators = []*models.Validator{}
+	}
+	return &models.ValidatorList{Network: network, Validators: validators, Total: total}, nil
+}
+
+// GetCommittee returns the committee of a network at the latest sync
+func (s *ValidatorService) GetCommittee(ctx context.Context, network string) (*models.Committee, error) {
+	committee, err := s.validatorRepo.GetLatestCommittee(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	if committee == nil {
+		return nil, models.NewNotFoundError("no committee has been synced yet")
+	}
+	committee.Network = network
+	return committee, nil
+}
+
+// GetValidatorUptime returns how many committee certificates a validator
+// of a network signed over the last days, the blocks it proposed and its
+// daily history
+func (s *ValidatorService) GetValidatorUptime(ctx context.Context, network, address string, days int) (*models.ValidatorUptime, error) {
+	if days <= 0 {
+		days = DefaultValidatorUptimeDays
+	}
+	if days > MaxValidatorUptimeDays {
+		days = MaxValidatorUptimeDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	validator, err := s.validatorRepo.GetValidator(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		return nil, models.NewNotFoundError("validator not found")
+	}
+
+	certificates, missed, proposed, err := s.validatorRepo.GetCertificateCounts(ctx, network, validator.Number, validator.Address, since)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.validatorRepo.GetValidatorHistory(ctx, validator.ID, since)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []*models.ValidatorDay{}
+	}
+
+	return &models.ValidatorUptime{
+		Validator:    validator,
+		Since:        since,
+		Certificates: certificates,
+		Signed:       certificates - missed,
+		Missed:       missed,
+		Uptime:       percentOf(certificates-missed, certificates),
+		Proposed:     proposed,
+		History:      history,
+	}, nil
+}
+
+// validatorFromInfo converts a validator reported over gRPC
+func validatorFromInfo(info *pactus.ValidatorInfo, inCommittee bool, seenAt time.Time) *models.Validator {
+	return &models.Validator{
+		Address:             info.Address,
+		Number:              info.Number,
+		PublicKey:           info.PublicKey,
+		Stake:               info.Stake,
+		AvailabilityScore:   info.AvailabilityScore,
+		LastBondingHeight:   info.LastBondingHeight,
+		LastSortitionHeight: info.LastSortitionHeight,
+		UnbondingHeight:     info.UnbondingHeight,
+		ProtocolVersion:     info.ProtocolVersion,
+		InCommittee:         inCommittee,
+		FirstSeen:           seenAt,
+		LastSeen:            seenAt,
+	}
+}
+
+// committeeFromInfo converts the committee of a blockchain info response
+func committeeFromInfo(info *pactus.GetBlockchainInfoResponse, seenAt time.Time) *models.Committee {
+	committee := &models.Committee{
+		Height:           info.LastBlockHeight,
+		TotalValidators:  info.TotalValidators,
+		ActiveValidators: info.ActiveValidators,
+		TotalPower:       info.TotalPower,
+		CommitteePower:   info.CommitteePower,
+		Members:          make([]*models.Validator, 0, len(info.CommitteeValidators)),
+		RecordedAt:       seenAt,
+	}
+	for _, v := range info.CommitteeValidators {
+		committee.Members = append(committee.Members, validatorFromInfo(v, true, seenAt))
+	}
+	return committee
+}
+
+// validatorBlock converts a block fetched with BLOCK_VERBOSITY_INFO
+func validatorBlock(block *pactus.GetBlockResponse) *models.ValidatorBlock {
+	b := &models.ValidatorBlock{
+		Height:         block.Height,
+		BlockTime:      time.Unix(int64(block.BlockTime), 0),
+		CertCommitters: []int32{},
+		CertAbsentees:  []int32{},
+	}
+	if block.Header != nil {
+		b.ProposerAddress = block.Header.ProposerAddress
+	}
+	if cert := block.PrevCert; cert != nil {
+		b.CertRound = cert.Round
+		b.CertCommitters = append(b.CertCommitters, cert.Committers...)
+		b.CertAbsentees = append(b.CertAbsentees, cert.Absentees...)
+	}
+	return b
+}
+
+// blockRange returns the heights to sync after last up to tip, keeping the
+// newest max blocks. from > to means there is nothing to sync.
+func blockRange(last, tip uint32, max uint32) (uint32, uint32) {
+	from := last + 1
+	if tip >= max && from < tip-max+1 {
+		from = tip - max + 1
+	}
+	return from, tip
+}
+
+// consensusPeers maps the consensus addresses connected peers announce to
+// their peer IDs
+func consensusPeers(info *pactus.GetNetworkInfoResponse) map[string]string {
+	peers := make(map[string]string)
+	for _, peer := range info.ConnectedPeers {
+		if peer == nil || peer.PeerId == "" {
+			continue
+		}
+		for _, address := range peer.ConsensusAddresses {
+			peers[address] = peer.PeerId
+		}
+	}
+	return peers
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pactus "github.com/pactus-project/pactus/www/grpc/gen/go"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+func TestBlockRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		last, tip      uint32
+		wantFrom, want uint32
+	}{
+		{"First sync keeps the newest blocks", 0, 5000, 3001, 5000},
+		{"First sync of a short chain", 0, 10, 1, 10},
+		{"Caught up", 100, 100, 101, 100},
+		{"A few new blocks", 100, 160, 101, 160},
+		{"Fell behind", 100, 9000, 7001, 9000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := blockRange(tt.last, tt.tip, 2000)
+			if from != tt.wantFrom || to != tt.want {
+				t.Errorf("blockRange(%d, %d) = %d..%d, want %d..%d", tt.last, tt.tip, from, to, tt.wantFrom, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorBlock(t *testing.T) {
+	block := validatorBlock(&pactus.GetBlockResponse{
+		Height:    42,
+		BlockTime: 1760000000,
+		Header:    &pactus.BlockHeaderInfo{ProposerAddress: "pc1pproposer"},
+		PrevCert:  &pactus.CertificateInfo{Round: 1, Committers: []int32{1, 2, 3}, Absentees: []int32{3}},
+	})
+
+	if block.Height != 42 || block.ProposerAddress != "pc1pproposer" || block.CertRound != 1 {
+		t.Errorf("Unexpected block: %+v", block)
+	}
+	if !block.BlockTime.Equal(time.Unix(1760000000, 0)) {
+		t.Errorf("Unexpected block time %v", block.BlockTime)
+	}
+	if len(block.CertCommitters) != 3 || len(block.CertAbsentees) != 1 || block.CertAbsentees[0] != 3 {
+		t.Errorf("Unexpected certificate: %v absent %v", block.CertCommitters, block.CertAbsentees)
+	}
+
+	genesis := validatorBlock(&pactus.GetBlockResponse{Height: 1})
+	if genesis.CertCommitters == nil || genesis.CertAbsentees == nil {
+		t.Error("Expected empty certificate lists for a block without a certificate")
+	}
+}
+
+func TestConsensusPeers(t *testing.T) {
+	peers := consensusPeers(&pactus.GetNetworkInfoResponse{
+		ConnectedPeers: []*pactus.PeerInfo{
+			{PeerId: "12D3KooWA", ConsensusAddresses: []string{"pc1pa1", "pc1pa2"}},
+			{PeerId: "12D3KooWB"},
+			{ConsensusAddresses: []string{"pc1porphan"}},
+			nil,
+		},
+	})
+
+	if len(peers) != 2 || peers["pc1pa1"] != "12D3KooWA" || peers["pc1pa2"] != "12D3KooWA" {
+		t.Errorf("Unexpected consensus peers: %v", peers)
+	}
+}
+
+// fakeChain serves a fixed chain of blocks and validators
+type fakeChain struct {
+	pactus.BlockchainClient
+	pactus.NetworkClient
+	tip        uint32
+	committee  []*pactus.ValidatorInfo
+	failHeight uint32
+}
+
+func (c *fakeChain) GetBlockchainInfo(ctx context.Context, in *pactus.GetBlockchainInfoRequest, opts ...grpc.CallOption) (*pactus.GetBlockchainInfoResponse, error) {
+	return &pactus.GetBlockchainInfoResponse{
+		LastBlockHeight:     c.tip,
+		TotalValidators:     10,
+		ActiveValidators:    8,
+		TotalPower:          1000,
+		CommitteePower:      300,
+		CommitteeValidators: c.committee,
+	}, nil
+}
+
+func (c *fakeChain) GetBlock(ctx context.Context, in *pactus.GetBlockRequest, opts ...grpc.CallOption) (*pactus.GetBlockResponse, error) {
+	if in.Height == c.failHeight {
+		return nil, fmt.Errorf("block %d unavailable", in.Height)
+	}
+	return &pactus.GetBlockResponse{
+		Height:    in.Height,
+		BlockTime: uint32(1760000000 + in.Height*10),
+		Header:    &pactus.BlockHeaderInfo{ProposerAddress: "pc1pa1"},
+		PrevCert:  &pactus.CertificateInfo{Committers: []int32{1, 2}, Absentees: []int32{2}},
+	}, nil
+}
+
+func (c *fakeChain) GetConsensusInfo(ctx context.Context, in *pactus.GetConsensusInfoRequest, opts ...grpc.CallOption) (*pactus.GetConsensusInfoResponse, error) {
+	return &pactus.GetConsensusInfoResponse{Instances: []*pactus.ConsensusInfo{{Address: "pc1pself"}}}, nil
+}
+
+func (c *fakeChain) GetNetworkInfo(ctx context.Context, in *pactus.GetNetworkInfoRequest, opts ...grpc.CallOption) (*pactus.GetNetworkInfoResponse, error) {
+	return &pactus.GetNetworkInfoResponse{
+		ConnectedPeers: []*pactus.PeerInfo{{PeerId: "12D3KooWA", ConsensusAddresses: []string{"pc1pa1"}}},
+	}, nil
+}
+
+func (c *fakeChain) GetNodeInfo(ctx context.Context, in *pactus.GetNodeInfoRequest, opts ...grpc.CallOption) (*pactus.GetNodeInfoResponse, error) {
+	return &pactus.GetNodeInfoResponse{PeerId: "12D3KooWSelf"}, nil
+}
+
+// memoryValidatorRepository keeps synced validators and blocks in memory
+type memoryValidatorRepository struct {
+	repositories.ValidatorRepository
+	validators map[string]*models.Validator
+	committees []*models.Committee
+	blocks     []*models.ValidatorBlock
+	peers      map[string]string
+}
+
+func newMemoryValidatorRepository() *memoryValidatorRepository {
+	return &memoryValidatorRepository{
+		validators: make(map[string]*models.Validator),
+		peers:      make(map[string]string),
+	}
+}
+
+func (r *memoryValidatorRepository) UpsertValidators(ctx context.Context, network string, validators []*models.Validator) error {
+	for _, v := range validators {
+		r.validators[v.Address] = v
+	}
+	return nil
+}
+
+func (r *memoryValidatorRepository) RecordCommittee(ctx context.Context, network string, committee *models.Committee) error {
+	r.committees = append(r.committees, committee)
+	return nil
+}
+
+func (r *memoryValidatorRepository) GetLastBlockHeight(ctx context.Context, network string) (uint32, error) {
+	if len(r.blocks) == 0 {
+		return 0, nil
+	}
+	return r.blocks[len(r.blocks)-1].Height, nil
+}
+
+func (r *memoryValidatorRepository) RecordBlocks(ctx context.Context, network string, blocks []*models.ValidatorBlock) error {
+	r.blocks = append(r.blocks, blocks...)
+	return nil
+}
+
+func (r *memoryValidatorRepository) RecordValidatorPeers(ctx context.Context, network string, peers map[string]string, seenAt time.Time) error {
+	for address, peerID := range peers {
+		r.peers[address] = peerID
+	}
+	return nil
+}
+
+func (r *memoryValidatorRepository) GetValidator(ctx context.Context, network, address string) (*models.Validator, error) {
+	return r.validators[address], nil
+}
+
+func (r *memoryValidatorRepository) GetCertificateCounts(ctx context.Context, network string, number int32, address string, since time.Time) (int, int, int, error) {
+	var certificates, missed, proposed int
+	for _, b := range r.blocks {
+		for _, n := range b.CertCommitters {
+			if n == number {
+				certificates++
+			}
+		}
+		for _, n := range b.CertAbsentees {
+			if n == number {
+				missed++
+			}
+		}
+		if b.ProposerAddress == address {
+			proposed++
+		}
+	}
+	return certificates, missed, proposed, nil
+}
+
+func (r *memoryValidatorRepository) GetValidatorHistory(ctx context.Context, validatorID int, since time.Time) ([]*models.ValidatorDay, error) {
+	return nil, nil
+}
+
+func TestValidatorService_SyncCommittee(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	chain := &fakeChain{
+		tip: 5,
+		committee: []*pactus.ValidatorInfo{
+			{Address: "pc1pa1", Number: 1, Stake: 200, AvailabilityScore: 0.9},
+			{Address: "pc1pa2", Number: 2, Stake: 100, AvailabilityScore: 0.5},
+		},
+	}
+	validatorRepo := newMemoryValidatorRepository()
+	service := NewValidatorService(validatorRepo, nil, "trusted:50051", "", logger)
+	ctx := context.Background()
+
+	if err := service.syncCommittee(ctx, chain, chain); err != nil {
+		t.Fatalf("syncCommittee: %v", err)
+	}
+
+	if len(validatorRepo.validators) != 2 || !validatorRepo.validators["pc1pa1"].InCommittee {
+		t.Errorf("Expected both committee members stored, got %v", validatorRepo.validators)
+	}
+	if len(validatorRepo.committees) != 1 || validatorRepo.committees[0].CommitteePower != 300 {
+		t.Errorf("Unexpected committee snapshots: %v", validatorRepo.committees)
+	}
+	if len(validatorRepo.blocks) != 5 || validatorRepo.blocks[0].Height != 1 {
+		t.Errorf("Expected blocks 1..5, got %d blocks", len(validatorRepo.blocks))
+	}
+	if validatorRepo.peers["pc1pa1"] != "12D3KooWA" || validatorRepo.peers["pc1pself"] != "12D3KooWSelf" {
+		t.Errorf("Unexpected validator peers: %v", validatorRepo.peers)
+	}
+
+	// The next sync continues after the last block and keeps what it
+	// fetched before a failure
+	chain.tip = 9
+	chain.failHeight = 8
+	if err := service.syncCommittee(ctx, chain, chain); err == nil {
+		t.Error("Expected an error for the unavailable block")
+	}
+	if len(validatorRepo.blocks) != 7 || validatorRepo.blocks[6].Height != 7 {
+		t.Errorf("Expected blocks 1..7, got %d blocks", len(validatorRepo.blocks))
+	}
+
+	uptime, err := service.GetValidatorUptime(ctx, models.DefaultNetwork, "pc1pa2", 0)
+	if err != nil {
+		t.Fatalf("GetValidatorUptime: %v", err)
+	}
+	if uptime.Certificates != 7 || uptime.Missed != 7 || uptime.Signed != 0 || uptime.Uptime != 0 || uptime.Proposed != 0 {
+		t.Errorf("Unexpected uptime of pc1pa2: %+v", uptime)
+	}
+	uptime, err = service.GetValidatorUptime(ctx, models.DefaultNetwork, "pc1pa1", 0)
+	if err != nil {
+		t.Fatalf("GetValidatorUptime: %v", err)
+	}
+	if uptime.Signed != 7 || uptime.Uptime != 100 || uptime.Proposed != 7 || uptime.History == nil {
+		t.Errorf("Unexpected uptime of pc1pa1: %+v", uptime)
+	}
+
+	if _, err := service.GetValidatorUptime(ctx, models.DefaultNetwork, "pc1punknown", 0); err == nil {
+		t.Error("Expected an error for an unknown validator")
+	}
+}
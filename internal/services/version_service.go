@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pactus-project/pactus/version"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+// DefaultVersionWindowDays is how recently a node must have been seen to
+// count in the version distribution
+const DefaultVersionWindowDays = 7
+
+// MaxVersionWindowDays caps how far back getVersionDistribution looks
+const MaxVersionWindowDays = 90
+
+// VersionService records the software versions nodes report and serves
+// their distribution
+type VersionService struct {
+	versionRepo   repositories.VersionRepository
+	bootstrapRepo repositories.BootstrapRepository
+	logger        *logrus.Logger
+}
+
+// NewVersionService creates a new version service
+func NewVersionService(
+	versionRepo repositories.VersionRepository,
+	bootstrapRepo repositories.BootstrapRepository,
+	logger *logrus.Logger,
+) *VersionService {
+	return &VersionService{
+		versionRepo:   versionRepo,
+		bootstrapRepo: bootstrapRepo,
+		logger:        logger,
+	}
+}
+
+// Record stores the agent a node reported at seenAt. Nodes that did not
+// report an agent are skipped.
+func (s *VersionService) Record(ctx context.Context, nodeType string, nodeID int, agent string, seenAt time.Time) error {
+	if agent == "" {
+		return nil
+	}
+
+	v := &models.NodeVersion{
+		NodeType: nodeType,
+		NodeID:   nodeID,
+		Agent:    agent,
+		Version:  agentVersion(agent),
+		LastSeen: seenAt,
+	}
+	if err := s.versionRepo.RecordVersion(ctx, v); err != nil {
+		return fmt.Errorf("failed to record version: %w", err)
+	}
+
+	return nil
+}
+
+// RecordPeers stores the agents run by crawled peers, which must have been
+// recorded already. A peer that is a bootstrap node also records the agent
+// of that bootstrap node, whose own check cannot see it.
+func (s *VersionService) RecordPeers(ctx context.Context, peers []*models.ReachablePeer) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	bootstrap, err := s.bootstrapPeerIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if peer.ID == 0 {
+			continue
+		}
+		if err := s.Record(ctx, models.NodeTypePeer, peer.ID, peer.UserAgent, peer.LastSeen); err != nil {
+			return err
+		}
+		if nodeID, ok := bootstrap[peer.PeerID]; ok {
+			if err := s.Record(ctx, models.NodeTypeBootstrap, nodeID, peer.UserAgent, peer.LastSeen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// bootstrapPeerIDs maps the peer ID in the address of every active
+// bootstrap node to the node's ID
+func (s *VersionService) bootstrapPeerIDs(ctx context.Context) (map[string]int, error) {
+	nodes, err := s.bootstrapRepo.GetActiveNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bootstrap nodes: %w", err)
+	}
+
+	ids := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		if peerID := multiaddrPeerID(node.Address); peerID != "" {
+			ids[peerID] = node.ID
+		}
+	}
+	return ids, nil
+}
+
+// GetHistory returns the agents a node has run, newest first
+func (s *VersionService) GetHistory(ctx context.Context, nodeType string, nodeID int) (*models.VersionHistoryResponse, error) {
+	history, err := s.versionRepo.GetHistory(ctx, nodeType, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []*models.NodeVersion{}
+	}
+
+	return &models.VersionHistoryResponse{
+		NodeType: nodeType,
+		NodeID:   nodeID,
+		History:  history,
+	}, nil
+}
+
+// GetDistribution returns the versions run by the nodes of a network seen
+// in the last days, optionally limited to one node type
+func (s *VersionService) GetDistribution(ctx context.Context, network, nodeType string, days int) (*models.VersionDistribution, error) {
+	if days <= 0 {
+		days = DefaultVersionWindowDays
+	}
+	if days > MaxVersionWindowDays {
+		days = MaxVersionWindowDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	current, err := s.versionRepo.GetCurrentVersions(ctx, network, since)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := current[:0]
+	for _, v := range current {
+		if nodeType == "" || v.NodeType == nodeType {
+			nodes = append(nodes, v)
+		}
+	}
+
+	distribution := buildVersionDistribution(nodes)
+	distribution.Network = network
+	distribution.Since = since
+	return distribution, nil
+}
+
+// buildVersionDistribution counts the nodes per version. The latest version
+// is the newest release any node runs; pre-releases only count as latest
+// when no node runs a release. Public servers behind the latest version are
+// listed as outdated.
+func buildVersionDistribution(nodes []*models.NodeVersion) *models.VersionDistribution {
+	distribution := &models.VersionDistribution{
+		TotalNodes:      len(nodes),
+		Versions:        []*models.VersionCount{},
+		OutdatedServers: []*models.NodeVersion{},
+	}
+
+	parsed := make(map[string]version.Version)
+	var latest *version.Version
+	for _, node := range nodes {
+		if node.Version == "" {
+			continue
+		}
+		v, err := version.ParseVersion(node.Version)
+		if err != nil {
+			continue
+		}
+		parsed[node.Version] = v
+		if latest == nil || isNewerRelease(v, *latest) {
+			latest = &v
+		}
+	}
+
+	counts := make(map[string]*models.VersionCount)
+	for _, node := range nodes {
+		label := node.Version
+		if _, ok := parsed[label]; !ok {
+			label = models.UnknownVersion
+		}
+
+		count, ok := counts[label]
+		if !ok {
+			count = &models.VersionCount{Version: label, ByType: make(map[string]int)}
+			if v, ok := parsed[label]; ok && latest != nil {
+				count.Outdated = compareVersions(v, *latest) < 0
+			}
+			counts[label] = count
+			distribution.Versions = append(distribution.Versions, count)
+		}
+		count.Nodes++
+		count.ByType[node.NodeType]++
+
+		if count.Outdated && node.NodeType != models.NodeTypePeer {
+			distribution.OutdatedServers = append(distribution.OutdatedServers, node)
+		}
+	}
+
+	for _, count := range distribution.Versions {
+		count.Share = percentOf(count.Nodes, distribution.TotalNodes)
+	}
+	if latest != nil {
+		distribution.LatestVersion = latest.String()
+		if count, ok := counts[latest.String()]; ok {
+			distribution.Adoption = count.Share
+		}
+	}
+
+	// Newest version first with unparseable agents last
+	sort.Slice(distribution.Versions, func(i, j int) bool {
+		vi, iok := parsed[distribution.Versions[i].Version]
+		vj, jok := parsed[distribution.Versions[j].Version]
+		if iok != jok {
+			return iok
+		}
+		return compareVersions(vi, vj) > 0
+	})
+	// Oldest version first, so the servers furthest behind lead the list
+	sort.SliceStable(distribution.OutdatedServers, func(i, j int) bool {
+		vi := parsed[distribution.OutdatedServers[i].Version]
+		vj := parsed[distribution.OutdatedServers[j].Version]
+		return compareVersions(vi, vj) < 0
+	})
+
+	return distribution
+}
+
+// isNewerRelease reports whether v should replace current as the latest
+// version. Any release beats a pre-release.
+func isNewerRelease(v, current version.Version) bool {
+	if (v.Meta == "") != (current.Meta == "") {
+		return v.Meta == ""
+	}
+	return compareVersions(v, current) > 0
+}
+
+// compareVersions orders versions like Version.Compare, except that a
+// pre-release comes before the release it leads up to
+func compareVersions(a, b version.Version) int {
+	if c := a.Compare(b); c != 0 {
+		return c
+	}
+	switch {
+	case a.Meta == b.Meta:
+		return 0
+	case a.Meta == "":
+		return 1
+	case b.Meta == "":
+		return -1
+	}
+	return strings.Compare(a.Meta, b.Meta)
+}
+
+// agentVersion returns the node version in a Pactus agent string such as
+// "node=pactus/node-version=1.7.0/protocol-version=1/os=linux/arch=amd64",
+// or "" when the agent cannot be parsed
+func agentVersion(agent string) string {
+	// ParseVersion panics on an empty version, which a remote node can send
+	if strings.Contains(agent+"/", "node-version=/") {
+		return ""
+	}
+	parsed, err := version.ParseAgent(agent)
+	if err != nil {
+		return ""
+	}
+	return parsed.Version.String()
+}
+
+// multiaddrPeerID returns the peer ID at the end of a multiaddr such as
+// "/ip4/1.2.3.4/tcp/21888/p2p/12D3KooW...", or "" when it has none
+func multiaddrPeerID(address string) string {
+	_, peerID, ok := strings.Cut(address, "/p2p/")
+	if !ok {
+		return ""
+	}
+	peerID, _, _ = strings.Cut(peerID, "/")
+	return peerID
+}
+
+// percentOf returns n as a percentage of total rounded to two decimals
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(total)*10000) / 100
+}
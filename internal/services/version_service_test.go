@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/models"
+	"github.com/kyvra-tech/pactus-nodes-tracker-backend/internal/repositories"
+)
+
+func TestAgentVersion(t *testing.T) {
+	tests := []struct {
+		agent string
+		want  string
+	}{
+		{"node=pactus/node-version=1.7.0/protocol-version=1/os=linux/arch=amd64", "1.7.0"},
+		{"node=pactus/node-version=v1.8.0-rc1/protocol-version=1/os=darwin/arch=arm64", "1.8.0-rc1"},
+		{"node=pactus/node-version=/protocol-version=1/os=linux/arch=amd64", ""},
+		{"node=pactus/node-version=", ""},
+		{"pactus/1.7.0", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := agentVersion(tt.agent); got != tt.want {
+			t.Errorf("agentVersion(%q) = %q, want %q", tt.agent, got, tt.want)
+		}
+	}
+}
+
+func TestMultiaddrPeerID(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"/ip4/1.2.3.4/tcp/21888/p2p/12D3KooWTest", "12D3KooWTest"},
+		{"/dns/bootstrap.example.org/tcp/21888/p2p/12D3KooWTest/p2p-circuit", "12D3KooWTest"},
+		{"/ip4/1.2.3.4/tcp/21888", ""},
+	}
+
+	for _, tt := range tests {
+		if got := multiaddrPeerID(tt.address); got != tt.want {
+			t.Errorf("multiaddrPeerID(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestBuildVersionDistribution(t *testing.T) {
+	nodes := []*models.NodeVersion{
+		{NodeType: models.NodeTypeGRPC, NodeID: 1, Version: "1.7.0"},
+		{NodeType: models.NodeTypeGRPC, NodeID: 2, Version: "1.6.2"},
+		{NodeType: models.NodeTypeJSONRPC, NodeID: 1, Version: "1.5.0"},
+		{NodeType: models.NodeTypePeer, NodeID: 1, Version: "1.6.2"},
+		{NodeType: models.NodeTypePeer, NodeID: 2, Version: "1.8.0-rc1"},
+		{NodeType: models.NodeTypePeer, NodeID: 3, Version: ""},
+		{NodeType: models.NodeTypeBootstrap, NodeID: 1, Version: "1.7.0"},
+		{NodeType: models.NodeTypePeer, NodeID: 4, Version: "1.7.0"},
+	}
+
+	d := buildVersionDistribution(nodes)
+
+	if d.LatestVersion != "1.7.0" {
+		t.Errorf("Expected the newest release 1.7.0 as latest, got %s", d.LatestVersion)
+	}
+	if d.TotalNodes != 8 {
+		t.Errorf("Expected 8 nodes, got %d", d.TotalNodes)
+	}
+	if d.Adoption != 37.5 {
+		t.Errorf("Expected 37.5%% adoption, got %v", d.Adoption)
+	}
+
+	wantOrder := []string{"1.8.0-rc1", "1.7.0", "1.6.2", "1.5.0", models.UnknownVersion}
+	if len(d.Versions) != len(wantOrder) {
+		t.Fatalf("Expected %d versions, got %d", len(wantOrder), len(d.Versions))
+	}
+	for i, want := range wantOrder {
+		if d.Versions[i].Version != want {
+			t.Errorf("Expected version %d to be %s, got %s", i, want, d.Versions[i].Version)
+		}
+	}
+
+	byVersion := make(map[string]*models.VersionCount)
+	for _, count := range d.Versions {
+		byVersion[count.Version] = count
+	}
+	if c := byVersion["1.6.2"]; !c.Outdated || c.Nodes != 2 || c.ByType[models.NodeTypePeer] != 1 {
+		t.Errorf("Unexpected count for 1.6.2: %+v", c)
+	}
+	if byVersion["1.8.0-rc1"].Outdated || byVersion["1.7.0"].Outdated || byVersion[models.UnknownVersion].Outdated {
+		t.Error("Expected only versions behind the latest release to be outdated")
+	}
+
+	// Peers are not public servers and the oldest server leads the list
+	if len(d.OutdatedServers) != 2 {
+		t.Fatalf("Expected 2 outdated servers, got %d", len(d.OutdatedServers))
+	}
+	if d.OutdatedServers[0].NodeType != models.NodeTypeJSONRPC || d.OutdatedServers[1].NodeType != models.NodeTypeGRPC {
+		t.Errorf("Unexpected outdated servers: %+v, %+v", d.OutdatedServers[0], d.OutdatedServers[1])
+	}
+}
+
+func TestBuildVersionDistribution_PreReleasesOnly(t *testing.T) {
+	d := buildVersionDistribution([]*models.NodeVersion{
+		{NodeType: models.NodeTypeGRPC, NodeID: 1, Version: "1.8.0-rc1"},
+		{NodeType: models.NodeTypeGRPC, NodeID: 2, Version: "1.8.0-rc2"},
+	})
+
+	if d.LatestVersion != "1.8.0-rc2" {
+		t.Errorf("Expected 1.8.0-rc2 as latest, got %s", d.LatestVersion)
+	}
+	if len(d.OutdatedServers) != 1 || d.OutdatedServers[0].NodeID != 1 {
+		t.Errorf("Expected the rc1 server to be outdated, got %+v", d.OutdatedServers)
+	}
+
+	empty := buildVersionDistribution(nil)
+	if empty.LatestVersion != "" || empty.Adoption != 0 || empty.Versions == nil {
+		t.Errorf("Unexpected empty distribution: %+v", empty)
+	}
+}
+
+// memoryVersionRepository keeps node versions in memory
+type memoryVersionRepository struct {
+	repositories.VersionRepository
+	recorded []*models.NodeVersion
+}
+
+func (r *memoryVersionRepository) RecordVersion(ctx context.Context, v *models.NodeVersion) error {
+	r.recorded = append(r.recorded, v)
+	return nil
+}
+
+// staticBootstrapRepository serves a fixed list of bootstrap nodes
+type staticBootstrapRepository struct {
+	repositories.BootstrapRepository
+	nodes []*models.BootstrapNode
+}
+
+func (r *staticBootstrapRepository) GetActiveNodes(ctx context.Context) ([]*models.BootstrapNode, error) {
+	return r.nodes, nil
+}
+
+func TestVersionService_RecordPeers(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	versionRepo := &memoryVersionRepository{}
+	bootstrapRepo := &staticBootstrapRepository{nodes: []*models.BootstrapNode{
+		{ID: 7, Address: "/ip4/1.2.3.4/tcp/21888/p2p/12D3KooWBootstrap"},
+	}}
+	service := NewVersionService(versionRepo, bootstrapRepo, logger)
+
+	seenAt := time.Now()
+	agent := "node=pactus/node-version=1.7.0/protocol-version=1/os=linux/arch=amd64"
+	peers := []*models.ReachablePeer{
+		{ID: 1, PeerID: "12D3KooWPeer", UserAgent: agent, LastSeen: seenAt},
+		{ID: 2, PeerID: "12D3KooWBootstrap", UserAgent: agent, LastSeen: seenAt},
+		{ID: 3, PeerID: "12D3KooWSilent", LastSeen: seenAt},
+		{PeerID: "12D3KooWUnrecorded", UserAgent: agent, LastSeen: seenAt},
+	}
+	if err := service.RecordPeers(context.Background(), peers); err != nil {
+		t.Fatalf("RecordPeers: %v", err)
+	}
+
+	want := []struct {
+		nodeType string
+		nodeID   int
+	}{
+		{models.NodeTypePeer, 1},
+		{models.NodeTypePeer, 2},
+		{models.NodeTypeBootstrap, 7},
+	}
+	if len(versionRepo.recorded) != len(want) {
+		t.Fatalf("Expected %d recorded versions, got %d", len(want), len(versionRepo.recorded))
+	}
+	for i, w := range want {
+		got := versionRepo.recorded[i]
+		if got.NodeType != w.nodeType || got.NodeID != w.nodeID || got.Version != "1.7.0" {
+			t.Errorf("Recorded version %d = %+v, want %s/%d on 1.7.0", i, got, w.nodeType, w.nodeID)
+		}
+	}
+}
@@ -146,6 +146,27 @@ var (
 		[]string{"job_name"},
 	)
 
+	// TLS certificate metrics
+	TLSCertificateExpiryDays = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pactus_tracker",
+			Subsystem: "tls",
+			Name:      "certificate_expiry_days",
+			Help:      "Days until the endpoint certificate expires",
+		},
+		[]string{"node_type", "address"},
+	)
+
+	TLSCertificateProblem = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pactus_tracker",
+			Subsystem: "tls",
+			Name:      "certificate_problem",
+			Help:      "Whether the endpoint certificate has a problem (1) or not (0)",
+		},
+		[]string{"node_type", "address", "problem"},
+	)
+
 	// Rate limiter metrics
 	RateLimitRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -213,6 +234,18 @@ func (m *Metrics) RecordSchedulerJob(jobName string, success bool, duration time
 	LastSchedulerJobTime.WithLabelValues(jobName).SetToCurrentTime()
 }
 
+// UpdateCertificate records the state of an endpoint certificate
+func (m *Metrics) UpdateCertificate(nodeType, address string, daysRemaining int, problems map[string]bool) {
+	TLSCertificateExpiryDays.WithLabelValues(nodeType, address).Set(float64(daysRemaining))
+	for problem, present := range problems {
+		value := 0.0
+		if present {
+			value = 1
+		}
+		TLSCertificateProblem.WithLabelValues(nodeType, address, problem).Set(value)
+	}
+}
+
 // Handler returns the Prometheus HTTP handler
 func Handler() http.Handler {
 	return promhttp.Handler()
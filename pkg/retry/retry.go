@@ -0,0 +1,121 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Policy describes how many times an operation is attempted and how long to
+// wait between attempts. Delays grow exponentially from InitialDelay by
+// Multiplier up to MaxDelay, with up to Jitter (a fraction of the delay)
+// randomly added or removed to avoid synchronized retries.
+type Policy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       float64
+}
+
+// DefaultPolicy returns the policy used for node checks
+func DefaultPolicy(maxAttempts int) Policy {
+	return Policy{
+		MaxAttempts:  maxAttempts,
+		InitialDelay: time.Second,
+		MaxDelay:     30 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.2,
+	}
+}
+
+// Backoff returns the delay to wait after the given attempt (1-based)
+func (p Policy) Backoff(attempt int) time.Duration {
+	if attempt < 1 || p.InitialDelay <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Do stops retrying immediately
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls fn until it succeeds, returns a permanent error, the attempts are
+// exhausted or ctx is done. It returns the number of attempts made and the
+// last error, with any Permanent wrapper removed.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context, attempt int) error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = fn(ctx, attempt)
+		if err == nil {
+			return attempt, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return attempt, permanent.err
+		}
+
+		if attempt == maxAttempts {
+			return attempt, err
+		}
+
+		if waitErr := Sleep(ctx, p.Backoff(attempt)); waitErr != nil {
+			return attempt, err
+		}
+	}
+
+	return maxAttempts, err
+}
+
+// Sleep waits for d or until ctx is done, whichever comes first
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+
+	tests := []struct {
+		attempt int
+		expect  time.Duration
+	}{
+		{attempt: 0, expect: 0},
+		{attempt: 1, expect: 100 * time.Millisecond},
+		{attempt: 2, expect: 200 * time.Millisecond},
+		{attempt: 4, expect: 800 * time.Millisecond},
+		{attempt: 5, expect: time.Second},
+		{attempt: 10, expect: time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := p.Backoff(tt.attempt); got != tt.expect {
+			t.Errorf("Backoff(%d): expected %s, got %s", tt.attempt, tt.expect, got)
+		}
+	}
+}
+
+func TestPolicy_BackoffJitter(t *testing.T) {
+	p := Policy{InitialDelay: time.Second, Multiplier: 2, Jitter: 0.2}
+
+	for i := 0; i < 100; i++ {
+		got := p.Backoff(1)
+		if got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("Backoff with jitter out of range: %s", got)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	fast := Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, Multiplier: 2}
+	errFail := errors.New("fail")
+
+	t.Run("Succeeds after retries", func(t *testing.T) {
+		attempts, err := Do(context.Background(), fast, func(ctx context.Context, attempt int) error {
+			if attempt < 3 {
+				return errFail
+			}
+			return nil
+		})
+		if err != nil || attempts != 3 {
+			t.Errorf("Expected success on attempt 3, got attempts=%d err=%v", attempts, err)
+		}
+	})
+
+	t.Run("Returns last error when exhausted", func(t *testing.T) {
+		attempts, err := Do(context.Background(), fast, func(ctx context.Context, attempt int) error {
+			return errFail
+		})
+		if !errors.Is(err, errFail) || attempts != 3 {
+			t.Errorf("Expected 3 failed attempts, got attempts=%d err=%v", attempts, err)
+		}
+	})
+
+	t.Run("Stops on permanent error", func(t *testing.T) {
+		attempts, err := Do(context.Background(), fast, func(ctx context.Context, attempt int) error {
+			return Permanent(errFail)
+		})
+		if err != errFail || attempts != 1 {
+			t.Errorf("Expected unwrapped error after 1 attempt, got attempts=%d err=%v", attempts, err)
+		}
+	})
+
+	t.Run("Stops waiting when context is cancelled", func(t *testing.T) {
+		slow := Policy{MaxAttempts: 5, InitialDelay: time.Hour}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		attempts, err := Do(ctx, slow, func(ctx context.Context, attempt int) error {
+			return errFail
+		})
+		if !errors.Is(err, errFail) || attempts != 1 {
+			t.Errorf("Expected 1 attempt, got attempts=%d err=%v", attempts, err)
+		}
+		if time.Since(start) > time.Second {
+			t.Error("Do did not return promptly after cancellation")
+		}
+	})
+}